@@ -1,18 +1,38 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/maintc/wipe-cli/internal/calendar"
 	"github.com/maintc/wipe-cli/internal/carbon"
 	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/discord"
 	"github.com/maintc/wipe-cli/internal/executor"
+	"github.com/maintc/wipe-cli/internal/framework"
+	"github.com/maintc/wipe-cli/internal/notify"
+	"github.com/maintc/wipe-cli/internal/oxide"
+	"github.com/maintc/wipe-cli/internal/scheduler"
+	"github.com/maintc/wipe-cli/internal/service"
+	"github.com/maintc/wipe-cli/internal/settings"
+	"github.com/maintc/wipe-cli/internal/slack"
 	"github.com/maintc/wipe-cli/internal/steamcmd"
 	"github.com/maintc/wipe-cli/internal/version"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var rootCmd = &cobra.Command{
@@ -20,18 +40,95 @@ var rootCmd = &cobra.Command{
 	Short:   "Wipe CLI - Configure the wipe monitoring service",
 	Long:    `A CLI tool to configure Rust server calendars for the wipe daemon to monitor.`,
 	Version: version.GetVersion(),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		p, err := newPrinter(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		console = p
+
+		// Resolve the profile before loading config: --profile wins, then 'wipe profile use'
+		profile, _ := cmd.Flags().GetString("profile")
+		if profile == "" {
+			profile = config.ActiveProfile()
+		}
+		if err := config.UseProfile(profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Initialize config
+		config.InitConfig()
+		printConfigResolution(profile)
+	},
 }
 
 var addCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add a Rust server to monitor",
-	Long:  `Add a Rust server with its calendar URL to the monitoring configuration.`,
+	Long: `Add a Rust server with its calendar URL to the monitoring configuration.
+
+The calendar is fetched and parsed before the server is saved, so a typo in the
+URL is caught now rather than by the daemon. Use --skip-validation for offline setups.
+
+Run without flags (or with --interactive) to be prompted for each setting.
+
+Example:
+  wipe add --path /srv/us-weekly --calendar https://example.com/us-weekly.ics
+  wipe add --path /srv/eu/us-weekly --name eu-weekly --calendar https://example.com/eu-weekly.ics
+  wipe add  # Interactive`,
 	Run: func(cmd *cobra.Command, args []string) {
+		interactive, _ := cmd.Flags().GetBool("interactive")
 		path, _ := cmd.Flags().GetString("path")
+		name, _ := cmd.Flags().GetString("name")
 		calendarURL, _ := cmd.Flags().GetString("calendar")
+		extraCalendars, _ := cmd.Flags().GetStringArray("extra-calendar")
 		branch, _ := cmd.Flags().GetString("branch")
+		frameworkName, _ := cmd.Flags().GetString("framework")
+		carbonVersion, _ := cmd.Flags().GetString("carbon-version")
+		rustBuildID, _ := cmd.Flags().GetString("rust-build-id")
 		wipeBlueprints, _ := cmd.Flags().GetBool("wipe-blueprints")
 		generateMap, _ := cmd.Flags().GetBool("generate-map")
+		identity, _ := cmd.Flags().GetString("identity")
+		matchPatterns, _ := cmd.Flags().GetStringArray("match-pattern")
+		skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+		authHeader, _ := cmd.Flags().GetString("calendar-auth-header")
+		authToken, _ := cmd.Flags().GetString("calendar-auth-token")
+		rconAddress, _ := cmd.Flags().GetString("rcon-address")
+		rconPassword, _ := cmd.Flags().GetString("rcon-password")
+
+		if interactive || !localFlagsChanged(cmd) {
+			defaults := config.Server{
+				Name:               name,
+				Path:               path,
+				CalendarURL:        calendarURL,
+				CalendarURLs:       extraCalendars,
+				Branch:             branch,
+				Framework:          frameworkName,
+				CarbonVersion:      carbonVersion,
+				RustBuildID:        rustBuildID,
+				WipeBlueprints:     wipeBlueprints,
+				GenerateMap:        generateMap,
+				Identity:           identity,
+				MatchPatterns:      matchPatterns,
+				CalendarAuthHeader: authHeader,
+				CalendarAuthToken:  authToken,
+				RconAddress:        rconAddress,
+				RconPassword:       rconPassword,
+			}
+			server, err := runAddWizard(bufio.NewReader(cmd.InOrStdin()), defaults, skipValidation)
+			if errors.Is(err, errAddCancelled) {
+				console.Println("❌ Add cancelled")
+				os.Exit(0)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error adding server: %v\n", err)
+				os.Exit(1)
+			}
+			saveAddedServer(server)
+			return
+		}
 
 		// Validate required flags
 		if path == "" {
@@ -43,96 +140,596 @@ var addCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// Derive name from path basename
-		name := filepath.Base(path)
+		// Derive name from path basename unless one was given
+		if name == "" {
+			name = filepath.Base(path)
+		}
 
 		// Default to main branch
 		if branch == "" {
 			branch = "main"
 		}
 
-		if err := config.AddServer(name, path, calendarURL, branch, wipeBlueprints, generateMap); err != nil {
-			fmt.Fprintf(os.Stderr, "Error adding server: %v\n", err)
-			os.Exit(1)
+		server := config.Server{
+			Name:               name,
+			Path:               path,
+			CalendarURL:        calendarURL,
+			CalendarURLs:       extraCalendars,
+			Branch:             branch,
+			Framework:          frameworkName,
+			CarbonVersion:      carbonVersion,
+			RustBuildID:        rustBuildID,
+			WipeBlueprints:     wipeBlueprints,
+			GenerateMap:        generateMap,
+			Identity:           identity,
+			MatchPatterns:      matchPatterns,
+			CalendarAuthHeader: authHeader,
+			CalendarAuthToken:  authToken,
+			RconAddress:        rconAddress,
+			RconPassword:       rconPassword,
+		}
+
+		for _, url := range server.AllCalendarURLs() {
+			if err := config.ValidateCalendarURL(url); err != nil {
+				fmt.Fprintf(os.Stderr, "Error adding server: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if !skipValidation {
+			if _, err := calendar.FetchCalendarWithAuth(calendarURL, authHeader, authToken); err != nil {
+				fmt.Fprintf(os.Stderr, "Error adding server: calendar %s could not be loaded: %v\n", server.Redacted().CalendarURL, err)
+				fmt.Fprintf(os.Stderr, "Use --skip-validation to add it anyway.\n")
+				os.Exit(1)
+			}
+			if _, err := calendar.FetchCalendarsWithAuth(extraCalendars, authHeader, authToken); err != nil {
+				fmt.Fprintf(os.Stderr, "Error adding server: extra %v\n", err)
+				fmt.Fprintf(os.Stderr, "Use --skip-validation to add it anyway.\n")
+				os.Exit(1)
+			}
 		}
 
-		fmt.Printf("✓ Added server: %s\n", name)
-		fmt.Printf("  Path: %s\n", path)
-		fmt.Printf("  Branch: %s\n", branch)
-		fmt.Printf("  Calendar: %s\n", calendarURL)
-		fmt.Printf("  Wipe blueprints: %v\n", wipeBlueprints)
-		fmt.Printf("  Generate map: %v\n", generateMap)
+		saveAddedServer(server)
 	},
 }
 
+// confirmRemoval shows the settings of the servers about to be removed and asks to go ahead
+func confirmRemoval(cmd *cobra.Command, servers []config.Server) bool {
+	console.Printf("⚠️  This removes %d server(s) from monitoring:\n", len(servers))
+	for _, server := range servers {
+		console.Printf("\n%s\n", server.Name)
+		printServerSummary(server)
+	}
+	confirmed, err := promptYesNo(bufio.NewReader(cmd.InOrStdin()), "\nRemove these servers?", false)
+	return err == nil && confirmed
+}
+
+// localFlagsChanged reports whether any of cmd's own flags were set; inherited flags such as
+// --profile and --quiet don't count
+func localFlagsChanged(cmd *cobra.Command) bool {
+	changed := false
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		changed = changed || f.Changed
+	})
+	return changed
+}
+
+// saveAddedServer saves a new server and prints what was added, exiting on failure
+func saveAddedServer(server config.Server) {
+	if err := config.AddServer(server); err != nil {
+		fmt.Fprintf(os.Stderr, "Error adding server: %v\n", err)
+		if errors.Is(err, config.ErrServerNameTaken) {
+			fmt.Fprintf(os.Stderr, "Use --name to give it a different name.\n")
+		}
+		os.Exit(1)
+	}
+
+	console.Printf("✓ Added server: %s\n", server.Name)
+	printServerSummary(server)
+}
+
+// printServerSummary prints a server's settings, indented under a heading
+func printServerSummary(server config.Server) {
+	console.Printf("  Path: %s\n", server.Path)
+	console.Printf("  Identity: %s\n", server.GetIdentity())
+	console.Printf("  Branch: %s\n", server.Branch)
+	console.Printf("  Framework: %s\n", server.GetFramework())
+	if server.CarbonVersion != "" {
+		console.Printf("  Carbon version: %s (pinned)\n", server.CarbonVersion)
+	}
+	if server.RustBuildID != "" {
+		console.Printf("  Rust build: %s (pinned)\n", server.RustBuildID)
+	}
+	console.Printf("  Calendar: %s\n", server.Redacted().CalendarURL)
+	for _, url := range server.Redacted().CalendarURLs {
+		console.Printf("  Extra calendar: %s\n", url)
+	}
+	if server.CalendarAuthToken != "" {
+		console.Printf("  Calendar auth: %s header\n", calendarAuthHeader(server))
+	}
+	if server.RconAddress != "" {
+		console.Printf("  RCON: %s\n", server.RconAddress)
+	}
+	console.Printf("  Wipe blueprints: %v\n", server.WipeBlueprints)
+	console.Printf("  Generate map: %v\n", server.GenerateMap)
+	if len(server.MatchPatterns) > 0 {
+		console.Printf("  Match patterns: %s\n", strings.Join(server.MatchPatterns, ", "))
+	}
+}
+
+// errAddCancelled is returned by runAddWizard when the summary isn't confirmed
+var errAddCancelled = errors.New("add cancelled")
+
+// runAddWizard prompts on stdout for each setting of a new server, reading answers from in.
+// Values in defaults are offered as the answer to an empty response. The calendar is
+// fetched as soon as it's entered (unless skipValidation) and re-prompted if it can't be loaded.
+func runAddWizard(in *bufio.Reader, defaults config.Server, skipValidation bool) (config.Server, error) {
+	server := defaults
+
+	var err error
+	for {
+		if server.Path, err = prompt(in, "Server path", defaults.Path); err != nil {
+			return server, err
+		}
+		if server.Path != "" {
+			break
+		}
+		console.Println("  A server path is required")
+	}
+
+	name := defaults.Name
+	if name == "" {
+		name = filepath.Base(server.Path)
+	}
+	if server.Name, err = prompt(in, "Server name", name); err != nil {
+		return server, err
+	}
+
+	for {
+		if server.CalendarURL, err = prompt(in, "Calendar URL (.ics)", defaults.CalendarURL); err != nil {
+			return server, err
+		}
+		if server.CalendarAuthToken, err = prompt(in, "Calendar auth token (empty for none)", defaults.CalendarAuthToken); err != nil {
+			return server, err
+		}
+		if server.CalendarAuthToken != "" {
+			if server.CalendarAuthHeader, err = prompt(in, "Calendar auth header (empty for "+calendar.DefaultAuthHeader+")", defaults.CalendarAuthHeader); err != nil {
+				return server, err
+			}
+		}
+
+		if err := config.ValidateCalendarURL(server.CalendarURL); err != nil {
+			console.Printf("  %v\n", err)
+			continue
+		}
+		if !skipValidation {
+			if _, err := calendar.FetchCalendarWithAuth(server.CalendarURL, server.CalendarAuthHeader, server.CalendarAuthToken); err != nil {
+				console.Printf("  Calendar could not be loaded: %v\n", err)
+				continue
+			}
+			console.Println("  ✓ Calendar loaded")
+		}
+		break
+	}
+
+	for {
+		extra, err := prompt(in, "Extra calendar URLs, comma-separated (empty for none)", strings.Join(defaults.CalendarURLs, ","))
+		if err != nil {
+			return server, err
+		}
+		server.CalendarURLs = splitList(extra)
+		if err := validateExtraCalendars(server, skipValidation); err != nil {
+			console.Printf("  %v\n", err)
+			continue
+		}
+		break
+	}
+
+	branch := defaults.Branch
+	if branch == "" {
+		branch = "main"
+	}
+	if server.Branch, err = prompt(in, "Rust branch", branch); err != nil {
+		return server, err
+	}
+	for {
+		if server.Framework, err = prompt(in, "Mod framework: carbon or oxide (empty for carbon)", defaults.Framework); err != nil {
+			return server, err
+		}
+		if _, err := framework.For(server.Framework); err != nil {
+			console.Printf("  %v\n", err)
+			continue
+		}
+		break
+	}
+	if server.WipeBlueprints, err = promptYesNo(in, "Delete blueprints on wipe?", defaults.WipeBlueprints); err != nil {
+		return server, err
+	}
+	if server.GenerateMap, err = promptYesNo(in, "Generate maps via generate-maps.sh?", defaults.GenerateMap); err != nil {
+		return server, err
+	}
+	identity := defaults.Identity
+	if identity == "" {
+		identity = filepath.Base(server.Path)
+	}
+	if server.Identity, err = prompt(in, "Server identity", identity); err != nil {
+		return server, err
+	}
+
+	console.Printf("\nNew server: %s\n", server.Name)
+	printServerSummary(server)
+	confirmed, err := promptYesNo(in, "\nSave this server?", true)
+	if err != nil {
+		return server, err
+	}
+	if !confirmed {
+		return server, errAddCancelled
+	}
+	return server, nil
+}
+
+// validateExtraCalendars checks a server's extra calendar URLs and, unless skipValidation, that they load
+func validateExtraCalendars(server config.Server, skipValidation bool) error {
+	for _, url := range server.CalendarURLs {
+		if err := config.ValidateCalendarURL(url); err != nil {
+			return err
+		}
+	}
+	if skipValidation || len(server.CalendarURLs) == 0 {
+		return nil
+	}
+	if _, err := calendar.FetchCalendarsWithAuth(server.CalendarURLs, server.CalendarAuthHeader, server.CalendarAuthToken); err != nil {
+		return fmt.Errorf("extra calendar could not be loaded: %w", err)
+	}
+	console.Println("  ✓ Extra calendars loaded")
+	return nil
+}
+
+// splitList splits a comma-separated answer into its trimmed, non-empty items
+func splitList(answer string) []string {
+	var items []string
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// prompt asks a question and returns the trimmed answer, or def if the answer is empty
+func prompt(in *bufio.Reader, question, def string) (string, error) {
+	if def != "" {
+		console.Printf("%s [%s]: ", question, def)
+	} else {
+		console.Printf("%s: ", question)
+	}
+
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("no answer to %q: %w", question, err)
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// promptYesNo asks a yes/no question, returning def for an empty answer and re-asking on anything else
+func promptYesNo(in *bufio.Reader, question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		answer, err := prompt(in, question+" ("+hint+")", "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all configured servers",
-	Long:  `Display all Rust servers currently being monitored.`,
+	Long: `Display all Rust servers currently being monitored.
+
+Use -o json to print the servers as JSON for scripts.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		output := outputFormat(cmd)
+
 		servers, err := config.ListServers()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error listing servers: %v\n", err)
 			os.Exit(1)
 		}
 
+		if output == "json" {
+			redacted := []config.Server{}
+			for _, s := range servers {
+				redacted = append(redacted, s.Redacted())
+			}
+			printJSON(cmd, redacted)
+			return
+		}
+
 		if len(servers) == 0 {
-			fmt.Println("No servers configured.")
-			fmt.Println("\nAdd a server with: wipe add --path /path/to/server --calendar https://...")
+			console.Println("No servers configured.")
+			console.Println("\nAdd a server with: wipe add --path /path/to/server --calendar https://...")
 			return
 		}
 
-		fmt.Printf("Configured servers (%d):\n\n", len(servers))
+		console.Printf("Configured servers (%d):\n\n", len(servers))
 		for i, s := range servers {
-			fmt.Printf("%d. %s\n", i+1, s.Name)
-			fmt.Printf("   Path: %s\n", s.Path)
-			fmt.Printf("   Branch: %s\n", s.Branch)
-			fmt.Printf("   Wipe blueprints: %v\n", s.WipeBlueprints)
-			fmt.Printf("   Generate map: %v\n", s.GenerateMap)
-			fmt.Printf("   Calendar: %s\n", s.CalendarURL)
+			console.Printf("%d. %s\n", i+1, s.Name)
+			console.Printf("   Path: %s\n", s.Path)
+			console.Printf("   Identity: %s\n", s.GetIdentity())
+			console.Printf("   Branch: %s\n", s.Branch)
+			console.Printf("   Framework: %s\n", s.GetFramework())
+			if s.CarbonVersion != "" {
+				console.Printf("   Carbon version: %s (pinned)\n", s.CarbonVersion)
+			}
+			if s.RustBuildID != "" {
+				console.Printf("   Rust build: %s (pinned)\n", s.RustBuildID)
+			}
+			console.Printf("   Wipe blueprints: %v\n", s.WipeBlueprints)
+			console.Printf("   Generate map: %v\n", s.GenerateMap)
+			if len(s.MatchPatterns) > 0 {
+				console.Printf("   Match patterns: %s\n", strings.Join(s.MatchPatterns, ", "))
+			}
+			console.Printf("   Calendar: %s\n", s.Redacted().CalendarURL)
+			for _, url := range s.Redacted().CalendarURLs {
+				console.Printf("   Extra calendar: %s\n", url)
+			}
+			if s.CalendarAuthToken != "" {
+				console.Printf("   Calendar auth: %s header\n", calendarAuthHeader(s))
+			}
+			if s.RconAddress != "" {
+				console.Printf("   RCON: %s\n", s.RconAddress)
+			}
 			if i < len(servers)-1 {
-				fmt.Println()
+				console.Println()
 			}
 		}
 	},
 }
 
+// calendarAuthHeader returns the header a server's calendar token is sent in
+func calendarAuthHeader(server config.Server) string {
+	if server.CalendarAuthHeader == "" {
+		return calendar.DefaultAuthHeader
+	}
+	return server.CalendarAuthHeader
+}
+
+// currentCalendarAuth returns the saved calendar credentials of a server, by name or path
+func currentCalendarAuth(identifier string) (string, string) {
+	servers, err := config.ListServers()
+	if err != nil {
+		return "", ""
+	}
+	for _, s := range servers {
+		if s.Name == identifier || s.Path == identifier {
+			return s.CalendarAuthHeader, s.CalendarAuthToken
+		}
+	}
+	return "", ""
+}
+
 var removeCmd = &cobra.Command{
-	Use:   "remove [name or path]",
-	Short: "Remove a server from monitoring",
-	Long:  `Remove a Rust server from the monitoring configuration by its name or path.`,
-	Args:  cobra.ExactArgs(1),
+	Use:   "remove [name or path...]",
+	Short: "Remove servers from monitoring",
+	Long: `Remove Rust servers from the monitoring configuration by name or path.
+
+Each server's settings are shown and you're asked to confirm first; --force skips this.
+Servers that can't be found are reported without stopping the others from being removed.
+
+Example:
+  wipe remove us-weekly
+  wipe remove us-weekly eu-monthly /srv/test
+  wipe remove us-weekly --force  # Skip confirmation prompt
+  wipe remove --all          # Remove every server (asks for confirmation)
+  wipe remove --all --force  # Skip confirmation prompt`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			if len(args) > 0 {
+				return fmt.Errorf("--all can't be combined with server names")
+			}
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		identifier := args[0]
+		all, _ := cmd.Flags().GetBool("all")
+		force, _ := cmd.Flags().GetBool("force")
+
+		if all {
+			servers, err := config.ListServers()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading servers: %v\n", err)
+				os.Exit(1)
+			}
+			if len(servers) == 0 {
+				console.Println("No servers configured")
+				return
+			}
 
-		if err := config.RemoveServer(identifier); err != nil {
-			fmt.Fprintf(os.Stderr, "Error removing server: %v\n", err)
+			if !force && !confirmRemoval(cmd, servers) {
+				console.Println("❌ Remove cancelled")
+				return
+			}
+
+			removed, err := config.RemoveAllServers()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error removing servers: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Removed %d server(s)\n", removed)
+			return
+		}
+
+		if !force {
+			servers, err := config.ListServers()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading servers: %v\n", err)
+				os.Exit(1)
+			}
+			if matched := matchServers(servers, args); len(matched) > 0 && !confirmRemoval(cmd, matched) {
+				console.Println("❌ Remove cancelled")
+				return
+			}
+		}
+
+		notFound, err := config.RemoveServers(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error removing servers: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("✓ Removed server: %s\n", identifier)
+		missing := make(map[string]bool)
+		for _, identifier := range notFound {
+			missing[identifier] = true
+		}
+		for _, identifier := range args {
+			if !missing[identifier] {
+				console.Printf("✓ Removed server: %s\n", identifier)
+			}
+		}
+		if len(notFound) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: server(s) not found (try name or path): %s\n", strings.Join(notFound, ", "))
+			os.Exit(1)
+		}
 	},
 }
 
+// matchServers returns the servers whose name or path is one of identifiers, in config order
+func matchServers(servers []config.Server, identifiers []string) []config.Server {
+	var matched []config.Server
+	for _, server := range servers {
+		for _, identifier := range identifiers {
+			if server.Name == identifier || server.Path == identifier {
+				matched = append(matched, server)
+				break
+			}
+		}
+	}
+	return matched
+}
+
 var updateCmd = &cobra.Command{
 	Use:   "update [name or path]",
 	Short: "Update a server's configuration",
-	Long:  `Update configuration settings for an existing server by name or path. Only provide flags for settings you want to change.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Update configuration settings for an existing server by name or path. Only provide flags for settings you want to change.
+
+Renaming with --name keeps the server's path, identity and scheduled events; only how
+it's referred to changes.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		identifier := args[0]
 
 		updates := make(map[string]interface{})
 
 		// Check which flags were provided and add them to updates map
+		if cmd.Flags().Changed("name") {
+			name, _ := cmd.Flags().GetString("name")
+			if name == "" {
+				fmt.Fprintf(os.Stderr, "Error: --name can't be empty\n")
+				os.Exit(1)
+			}
+			updates["name"] = name
+		}
+		if cmd.Flags().Changed("calendar-auth-header") {
+			authHeader, _ := cmd.Flags().GetString("calendar-auth-header")
+			updates["calendar_auth_header"] = authHeader
+		}
+		if cmd.Flags().Changed("calendar-auth-token") {
+			authToken, _ := cmd.Flags().GetString("calendar-auth-token")
+			updates["calendar_auth_token"] = authToken
+		}
+		if cmd.Flags().Changed("rcon-address") {
+			rconAddress, _ := cmd.Flags().GetString("rcon-address")
+			updates["rcon_address"] = rconAddress
+		}
+		if cmd.Flags().Changed("rcon-password") {
+			rconPassword, _ := cmd.Flags().GetString("rcon-password")
+			updates["rcon_password"] = rconPassword
+		}
 		if cmd.Flags().Changed("calendar") {
 			calendarURL, _ := cmd.Flags().GetString("calendar")
+			skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+			if err := config.ValidateCalendarURL(calendarURL); err != nil {
+				fmt.Fprintf(os.Stderr, "Error updating server: %v\n", err)
+				os.Exit(1)
+			}
+			if !skipValidation {
+				// Validate with the credentials the server will have after the update
+				authHeader, authToken := currentCalendarAuth(identifier)
+				if header, ok := updates["calendar_auth_header"].(string); ok {
+					authHeader = header
+				}
+				if token, ok := updates["calendar_auth_token"].(string); ok {
+					authToken = token
+				}
+				if _, err := calendar.FetchCalendarWithAuth(calendarURL, authHeader, authToken); err != nil {
+					fmt.Fprintf(os.Stderr, "Error updating server: calendar %s could not be loaded: %v\n", config.Server{CalendarURL: calendarURL}.Redacted().CalendarURL, err)
+					fmt.Fprintf(os.Stderr, "Use --skip-validation to update it anyway.\n")
+					os.Exit(1)
+				}
+			}
 			updates["calendar_url"] = calendarURL
 		}
+		if cmd.Flags().Changed("extra-calendar") {
+			extraCalendars, _ := cmd.Flags().GetStringArray("extra-calendar")
+			skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+			calendarURLs := []string{}
+			for _, calendarURL := range extraCalendars {
+				if calendarURL == "" {
+					continue
+				}
+				if err := config.ValidateCalendarURL(calendarURL); err != nil {
+					fmt.Fprintf(os.Stderr, "Error updating server: %v\n", err)
+					os.Exit(1)
+				}
+				calendarURLs = append(calendarURLs, calendarURL)
+			}
+			if !skipValidation {
+				authHeader, authToken := currentCalendarAuth(identifier)
+				if header, ok := updates["calendar_auth_header"].(string); ok {
+					authHeader = header
+				}
+				if token, ok := updates["calendar_auth_token"].(string); ok {
+					authToken = token
+				}
+				if _, err := calendar.FetchCalendarsWithAuth(calendarURLs, authHeader, authToken); err != nil {
+					fmt.Fprintf(os.Stderr, "Error updating server: extra %v\n", err)
+					fmt.Fprintf(os.Stderr, "Use --skip-validation to update it anyway.\n")
+					os.Exit(1)
+				}
+			}
+			updates["calendar_urls"] = calendarURLs
+		}
 		if cmd.Flags().Changed("branch") {
 			branch, _ := cmd.Flags().GetString("branch")
 			updates["branch"] = branch
 		}
+		if cmd.Flags().Changed("carbon-version") {
+			carbonVersion, _ := cmd.Flags().GetString("carbon-version")
+			updates["carbon_version"] = carbonVersion
+		}
+		if cmd.Flags().Changed("rust-build-id") {
+			rustBuildID, _ := cmd.Flags().GetString("rust-build-id")
+			updates["rust_build_id"] = rustBuildID
+		}
+		if cmd.Flags().Changed("framework") {
+			frameworkName, _ := cmd.Flags().GetString("framework")
+			updates["framework"] = frameworkName
+		}
 		if cmd.Flags().Changed("wipe-blueprints") {
 			wipeBlueprints, _ := cmd.Flags().GetBool("wipe-blueprints")
 			updates["wipe_blueprints"] = wipeBlueprints
@@ -141,6 +738,21 @@ var updateCmd = &cobra.Command{
 			generateMap, _ := cmd.Flags().GetBool("generate-map")
 			updates["generate_map"] = generateMap
 		}
+		if cmd.Flags().Changed("identity") {
+			identity, _ := cmd.Flags().GetString("identity")
+			updates["identity"] = identity
+		}
+		if cmd.Flags().Changed("match-pattern") {
+			matchPatterns, _ := cmd.Flags().GetStringArray("match-pattern")
+			// Passing --match-pattern "" clears the patterns
+			nonEmpty := []string{}
+			for _, pattern := range matchPatterns {
+				if pattern != "" {
+					nonEmpty = append(nonEmpty, pattern)
+				}
+			}
+			updates["match_patterns"] = nonEmpty
+		}
 
 		if len(updates) == 0 {
 			fmt.Fprintf(os.Stderr, "Error: No settings to update. Provide at least one flag to change.\n")
@@ -152,79 +764,450 @@ var updateCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		fmt.Printf("✓ Updated server: %s\n", identifier)
-		fmt.Println("  Changes:")
+		console.Printf("✓ Updated server: %s\n", identifier)
+		console.Println("  Changes:")
 		for key := range updates {
 			switch key {
+			case "name":
+				console.Printf("    - name: %s\n", updates[key])
 			case "calendar_url":
-				fmt.Println("    - calendar URL updated")
+				console.Println("    - calendar URL updated")
+			case "calendar_auth_header":
+				if updates[key] == "" {
+					console.Printf("    - calendar auth header: reset to %s\n", calendar.DefaultAuthHeader)
+				} else {
+					console.Printf("    - calendar auth header: %s\n", updates[key])
+				}
+			case "calendar_auth_token":
+				if updates[key] == "" {
+					console.Println("    - calendar auth token: cleared")
+				} else {
+					console.Println("    - calendar auth token updated")
+				}
+			case "calendar_urls":
+				if urls := updates[key].([]string); len(urls) == 0 {
+					console.Println("    - extra calendars: cleared")
+				} else {
+					redacted := config.Server{CalendarURLs: urls}.Redacted().CalendarURLs
+					console.Printf("    - extra calendars: %s\n", strings.Join(redacted, ", "))
+				}
 			case "branch":
-				fmt.Printf("    - branch: %s\n", updates[key])
+				console.Printf("    - branch: %s\n", updates[key])
+			case "carbon_version":
+				if updates[key] == "" {
+					console.Println("    - Carbon version: following latest")
+				} else {
+					console.Printf("    - Carbon version: %s (pinned)\n", updates[key])
+				}
+			case "rust_build_id":
+				if updates[key] == "" {
+					console.Println("    - Rust build: following latest")
+				} else {
+					console.Printf("    - Rust build: %s (pinned)\n", updates[key])
+				}
+			case "framework":
+				if updates[key] == "" {
+					console.Printf("    - framework: reset to %s\n", config.FrameworkCarbon)
+				} else {
+					console.Printf("    - framework: %s\n", updates[key])
+				}
 			case "wipe_blueprints":
-				fmt.Printf("    - wipe blueprints: %v\n", updates[key])
+				console.Printf("    - wipe blueprints: %v\n", updates[key])
 			case "generate_map":
-				fmt.Printf("    - generate map: %v\n", updates[key])
+				console.Printf("    - generate map: %v\n", updates[key])
+			case "identity":
+				if updates[key] == "" {
+					console.Println("    - identity: reset to path basename")
+				} else {
+					console.Printf("    - identity: %s\n", updates[key])
+				}
+			case "rcon_address":
+				if updates[key] == "" {
+					console.Println("    - RCON address: cleared (no in-game warnings)")
+				} else {
+					console.Printf("    - RCON address: %s\n", updates[key])
+				}
+			case "rcon_password":
+				console.Println("    - RCON password updated")
+			case "match_patterns":
+				if patterns := updates[key].([]string); len(patterns) == 0 {
+					console.Println("    - match patterns: cleared (exact summary match)")
+				} else {
+					console.Printf("    - match patterns: %s\n", strings.Join(patterns, ", "))
+				}
 			}
 		}
 	},
 }
 
-var configCmd = &cobra.Command{
-	Use:   "config",
-	Short: "View or modify configuration settings",
-	Long:  `View or modify global configuration settings like check interval and lookahead hours.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.GetConfig()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-			os.Exit(1)
-		}
-
-		fmt.Println("Current configuration:")
-		fmt.Printf("  Check interval: %d seconds (refresh calendars every %ds)\n", cfg.CheckInterval, cfg.CheckInterval)
-		fmt.Printf("  Lookahead hours: %d hours (schedule events up to %dh ahead)\n", cfg.LookaheadHours, cfg.LookaheadHours)
-		fmt.Printf("  Event delay: %d seconds (wait %ds after event time before executing)\n", cfg.EventDelay, cfg.EventDelay)
-		fmt.Printf("  Map generation hours: %d hours (generate maps %dh before wipe)\n", cfg.MapGenerationHours, cfg.MapGenerationHours)
-		if cfg.DiscordWebhook != "" {
-			fmt.Printf("  Discord webhook: configured\n")
-		} else {
-			fmt.Printf("  Discord webhook: not configured\n")
-		}
-		fmt.Printf("  Discord mention users: %d configured\n", len(cfg.DiscordMentionUsers))
-		if len(cfg.DiscordMentionUsers) > 0 {
-			for _, userID := range cfg.DiscordMentionUsers {
-				fmt.Printf("    - %s\n", userID)
-			}
-		}
-		fmt.Printf("  Discord mention roles: %d configured\n", len(cfg.DiscordMentionRoles))
-		if len(cfg.DiscordMentionRoles) > 0 {
-			for _, roleID := range cfg.DiscordMentionRoles {
-				fmt.Printf("    - %s\n", roleID)
-			}
-		}
-		fmt.Printf("  Servers configured: %d\n", len(cfg.Servers))
-	},
+// outputFormat returns the validated --output flag, exiting on an unknown format
+func outputFormat(cmd *cobra.Command) string {
+	output, _ := cmd.Flags().GetString("output")
+	if output != "text" && output != "json" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --output '%s' (use text or json)\n", output)
+		os.Exit(1)
+	}
+	return output
 }
 
-var configSetCmd = &cobra.Command{
-	Use:   "set",
-	Short: "Set a configuration value",
-	Long:  `Set configuration values like check-interval or lookahead-hours.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		checkInterval, _ := cmd.Flags().GetInt("check-interval")
-		lookaheadHours, _ := cmd.Flags().GetInt("lookahead-hours")
-		eventDelay, _ := cmd.Flags().GetInt("event-delay")
-		mapGenerationHours, _ := cmd.Flags().GetInt("map-generation-hours")
-		discordWebhook, _ := cmd.Flags().GetString("discord-webhook")
+// printJSON writes v as indented JSON to the command's output
+// outputLevel is how much the CLI prints, chosen with --quiet or --verbose
+type outputLevel int
 
-		changed := false
+const (
+	levelQuiet   outputLevel = iota // Errors only
+	levelNormal                     // The default output
+	levelVerbose                    // The default output plus how the config was resolved
+)
+
+// printer writes a command's human-readable output at the level chosen with --quiet or --verbose.
+// Errors go to stderr and JSON output to the command's writer regardless of the level.
+type printer struct {
+	w     io.Writer
+	level outputLevel
+}
+
+// console prints the running command's output; rootCmd's PersistentPreRun sets it from the flags
+var console = &printer{w: os.Stdout, level: levelNormal}
+
+// newPrinter returns a printer for cmd's --quiet and --verbose flags, writing to cmd's output
+func newPrinter(cmd *cobra.Command) (*printer, error) {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	if quiet && verbose {
+		return nil, fmt.Errorf("--quiet and --verbose can't be used together")
+	}
+
+	p := &printer{w: cmd.OutOrStdout(), level: levelNormal}
+	if quiet {
+		p.level = levelQuiet
+	} else if verbose {
+		p.level = levelVerbose
+	}
+	return p, nil
+}
+
+// Printf prints normal output, suppressed by --quiet
+func (p *printer) Printf(format string, a ...interface{}) {
+	if p.level >= levelNormal {
+		fmt.Fprintf(p.w, format, a...)
+	}
+}
+
+// Println prints normal output, suppressed by --quiet
+func (p *printer) Println(a ...interface{}) {
+	if p.level >= levelNormal {
+		fmt.Fprintln(p.w, a...)
+	}
+}
+
+// Verbosef prints details only shown with --verbose
+func (p *printer) Verbosef(format string, a ...interface{}) {
+	if p.level >= levelVerbose {
+		fmt.Fprintf(p.w, format, a...)
+	}
+}
+
+// printConfigResolution shows with --verbose which profile and config file were loaded,
+// and how many settings came from the file rather than the built-in defaults
+func printConfigResolution(profile string) {
+	if console.level < levelVerbose {
+		return
+	}
+
+	if profile == "" {
+		profile = "(default)"
+	}
+	console.Verbosef("Profile: %s\n", profile)
+
+	configFile := config.ConfigFileUsed()
+	if configFile == "" {
+		configFile = "(none)"
+	}
+	console.Verbosef("Config file: %s\n", configFile)
+
+	settings, err := config.ExplainSettings()
+	if err != nil {
+		console.Verbosef("Config source: unreadable (%v)\n", err)
+		return
+	}
+	fromFile, fromEnv := 0, 0
+	for _, s := range settings {
+		switch s.Source {
+		case config.SourceFile:
+			fromFile++
+		case config.SourceEnv:
+			fromEnv++
+		}
+	}
+	console.Verbosef("Config source: %d of %d settings from the file, %d from the environment, the rest from built-in defaults\n\n",
+		fromFile, len(settings), fromEnv)
+}
+
+func printJSON(cmd *cobra.Command, v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(data))
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View or modify configuration settings",
+	Long: `View or modify global configuration settings like check interval and lookahead hours.
+
+Use --explain to show every setting with its effective value, its default,
+and whether it came from the config file, a WIPE_<SETTING> environment
+variable (e.g. WIPE_CHECK_INTERVAL) or the built-in default. Only settings
+you set are written to the file; the rest keep following their defaults.
+
+Use -o json to print the full configuration as JSON. The Discord, Slack and
+event webhooks are redacted unless --show-secrets is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		explain, _ := cmd.Flags().GetBool("explain")
+		showSecrets, _ := cmd.Flags().GetBool("show-secrets")
+		output := outputFormat(cmd)
+
+		if output == "json" {
+			cfg, err := config.GetConfig()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+				os.Exit(1)
+			}
+			if !showSecrets {
+				*cfg = cfg.Redacted()
+			}
+			printJSON(cmd, cfg)
+			return
+		}
+
+		if explain {
+			settings, err := config.ExplainSettings()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+				os.Exit(1)
+			}
+
+			console.Printf("Effective configuration (%s):\n", config.ConfigFileUsed())
+			for _, setting := range settings {
+				console.Printf("  %-24s %-20v [%s, default: %v]\n", setting.Key, setting.Value, setting.Source, setting.Default)
+			}
+			return
+		}
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		console.Println("Current configuration:")
+		console.Printf("  Check interval: %d seconds (refresh calendars every %ds)\n", cfg.CheckInterval, cfg.CheckInterval)
+		console.Printf("  Lookahead hours: %d hours (schedule events up to %dh ahead)\n", cfg.LookaheadHours, cfg.LookaheadHours)
+		console.Printf("  Max lookahead hours: %d hours (lookahead hours can't be set higher)\n", cfg.MaxLookaheadHours)
+		console.Printf("  Event delay: %d seconds (wait %ds after event time before executing)\n", cfg.EventDelay, cfg.EventDelay)
+		console.Printf("  Map generation hours: %d hours (generate maps %dh before wipe)\n", cfg.MapGenerationHours, cfg.MapGenerationHours)
+		if cfg.StartStagger > 0 && cfg.StartStaggerSize > 1 {
+			console.Printf("  Start stagger: %d seconds (wait %ds between starting each group of %d servers)\n", cfg.StartStagger, cfg.StartStagger, cfg.StartStaggerSize)
+		} else if cfg.StartStagger > 0 {
+			console.Printf("  Start stagger: %d seconds (wait %ds between starting each server)\n", cfg.StartStagger, cfg.StartStagger)
+		} else {
+			console.Printf("  Start stagger: disabled (start all servers at once)\n")
+		}
+		console.Printf("  Keep previous install: %v (allow 'wipe rollback' after Rust updates)\n", cfg.KeepPreviousInstall)
+		if cfg.HealthCheckInterval > 0 {
+			console.Printf("  Health check interval: %d seconds (probe servers with healthcheck.sh)\n", cfg.HealthCheckInterval)
+		} else {
+			console.Printf("  Health check interval: disabled\n")
+		}
+		if cfg.HeartbeatHours > 0 {
+			console.Printf("  Heartbeat: every %d hours (notify that the daemon is healthy)\n", cfg.HeartbeatHours)
+		} else {
+			console.Printf("  Heartbeat: disabled\n")
+		}
+		if cfg.MinFreeMemoryMB > 0 {
+			console.Printf("  Min free memory: %d MB per server (wait before starting servers)\n", cfg.MinFreeMemoryMB)
+		} else {
+			console.Printf("  Min free memory: disabled\n")
+		}
+		if cfg.BatchSize > 0 {
+			console.Printf("  Batch size: %d servers per wave\n", cfg.BatchSize)
+		} else {
+			console.Printf("  Batch size: disabled (whole batch at once)\n")
+		}
+		console.Printf("  Discord max attempts: %d (retry rate-limited or failed notifications)\n", cfg.DiscordMaxAttempts)
+		console.Printf("  HTTP timeout: %d seconds (calendar fetches, Discord, downloads)\n", cfg.HTTPTimeout)
+		console.Printf("  Max concurrent syncs: %d\n", cfg.MaxConcurrentSyncs)
+		console.Printf("  Safe wipe: %v (keep %d backup(s) per server for 'wipe restore')\n", cfg.SafeWipe, cfg.WipeBackupRetention)
+		if cfg.MinFreeDiskGB > 0 {
+			console.Printf("  Min free disk: %d GB (before Rust installs)\n", cfg.MinFreeDiskGB)
+		} else {
+			console.Printf("  Min free disk: disabled\n")
+		}
+		console.Printf("  Config reload interval: %d seconds (pick up config changes every %ds)\n", cfg.ConfigReloadInterval, cfg.ConfigReloadInterval)
+		console.Printf("  Update check interval: %d seconds (poll steamcmd and Carbon for updates)\n", cfg.UpdateCheckInterval)
+		if cfg.UpdateCheckSource == config.UpdateCheckHTTP {
+			checkURL := cfg.UpdateCheckURL
+			if checkURL == "" {
+				checkURL = steamcmd.DefaultBuildIDURL
+			}
+			console.Printf("  Update check source: http (%s, falls back to steamcmd)\n", checkURL)
+		} else {
+			console.Printf("  Update check source: steamcmd\n")
+		}
+		if pins, err := cfg.RustBuildIDPins(); len(pins) > 0 || err != nil {
+			branches := make([]string, 0, len(pins))
+			for branch := range pins {
+				branches = append(branches, branch)
+			}
+			sort.Strings(branches)
+			for _, branch := range branches {
+				console.Printf("  Rust build pin: %s held at %s\n", branch, pins[branch])
+			}
+			if err != nil {
+				console.Printf("  ⚠️  %v\n", err)
+			}
+		}
+		if cfg.UpdateDeferMinutes > 0 {
+			console.Printf("  Update defer window: %d minutes (installs wait for events starting sooner)\n", cfg.UpdateDeferMinutes)
+		} else {
+			console.Println("  Update defer window: disabled")
+		}
+		if historyFile, err := cfg.GetHistoryFile(); err == nil {
+			console.Printf("  History file: %s\n", historyFile)
+		}
+		if cfg.StepTimeoutMinutes > 0 {
+			console.Printf("  Step timeout: %d minutes (stuck stop, sync, hook or start steps are killed)\n", cfg.StepTimeoutMinutes)
+		} else {
+			console.Println("  Step timeout: disabled")
+		}
+		if cfg.SteamCMDTimeoutMinutes > 0 {
+			console.Printf("  SteamCMD timeout: %d minutes (a stalled Rust install attempt is killed and retried)\n", cfg.SteamCMDTimeoutMinutes)
+		} else {
+			console.Println("  SteamCMD timeout: disabled")
+		}
+		console.Printf("  Re-check calendar before wipe: %v (deleted wipe events are cancelled during the event delay)\n", cfg.RecheckCalendarBeforeWipe)
+		if len(cfg.RconWarnings) > 0 {
+			console.Printf("  RCON warnings: %v seconds before stopping, %q (servers with an RCON address, within the event delay)\n", cfg.RconWarnings, cfg.RconWarningMessage)
+		} else {
+			console.Println("  RCON warnings: disabled")
+		}
+		if cfg.MetricsAddr != "" {
+			console.Printf("  Metrics: http://%s/metrics\n", cfg.MetricsAddr)
+		} else {
+			console.Printf("  Metrics: disabled\n")
+		}
+		if cfg.HealthAddr != "" {
+			console.Printf("  Health endpoint: http://%s/healthz\n", cfg.HealthAddr)
+		} else {
+			console.Printf("  Health endpoint: disabled\n")
+		}
+		if cfg.WipeConfirmationMinutes > 0 {
+			console.Printf("  Wipe confirmation: %d minutes (wipes wait for 'wipe confirm' or abort)\n", cfg.WipeConfirmationMinutes)
+		} else {
+			console.Printf("  Wipe confirmation: disabled\n")
+		}
+		console.Printf("  Notifier: %s\n", cfg.Notifier)
+		if cfg.DiscordWebhook != "" {
+			console.Printf("  Discord webhook: configured\n")
+		} else {
+			console.Printf("  Discord webhook: not configured\n")
+		}
+		if cfg.SlackWebhook != "" {
+			console.Printf("  Slack webhook: configured\n")
+		} else {
+			console.Printf("  Slack webhook: not configured\n")
+		}
+		if cfg.EventWebhookURL != "" {
+			console.Printf("  Event webhook: configured\n")
+		} else {
+			console.Printf("  Event webhook: not configured\n")
+		}
+		console.Printf("  Discord mention users: %d configured\n", len(cfg.DiscordMentionUsers))
+		if len(cfg.DiscordMentionUsers) > 0 {
+			for _, userID := range cfg.DiscordMentionUsers {
+				console.Printf("    - %s\n", userID)
+			}
+		}
+		console.Printf("  Discord mention roles: %d configured\n", len(cfg.DiscordMentionRoles))
+		if len(cfg.DiscordMentionRoles) > 0 {
+			for _, roleID := range cfg.DiscordMentionRoles {
+				console.Printf("    - %s\n", roleID)
+			}
+		}
+		console.Printf("  Discord mentions on: %s\n", mentionLevels(cfg.DiscordMentionOn))
+		if len(cfg.SlackMentionUsers) > 0 {
+			console.Printf("  Slack mention users: %d configured\n", len(cfg.SlackMentionUsers))
+			for _, userID := range cfg.SlackMentionUsers {
+				console.Printf("    - %s\n", userID)
+			}
+		}
+		console.Printf("  Servers configured: %d\n", len(cfg.Servers))
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set a configuration value",
+	Long:  `Set configuration values like check-interval or lookahead-hours.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkInterval, _ := cmd.Flags().GetInt("check-interval")
+		lookaheadHours, _ := cmd.Flags().GetInt("lookahead-hours")
+		maxLookaheadHours, _ := cmd.Flags().GetInt("max-lookahead-hours")
+		eventDelay, _ := cmd.Flags().GetInt("event-delay")
+		mapGenerationHours, _ := cmd.Flags().GetInt("map-generation-hours")
+		discordWebhook, _ := cmd.Flags().GetString("discord-webhook")
+		historyFile, _ := cmd.Flags().GetString("history-file")
+		notifier, _ := cmd.Flags().GetString("notifier")
+		slackWebhook, _ := cmd.Flags().GetString("slack-webhook")
+		eventWebhookURL, _ := cmd.Flags().GetString("event-webhook-url")
+		startStagger, _ := cmd.Flags().GetInt("start-stagger")
+		startStaggerSize, _ := cmd.Flags().GetInt("start-stagger-size")
+		keepPreviousInstall, _ := cmd.Flags().GetBool("keep-previous-install")
+		wipeConfirmation, _ := cmd.Flags().GetInt("wipe-confirmation-minutes")
+		healthCheckInterval, _ := cmd.Flags().GetInt("health-check-interval")
+		heartbeatHours, _ := cmd.Flags().GetInt("heartbeat-hours")
+		minFreeMemory, _ := cmd.Flags().GetInt("min-free-memory-mb")
+		batchSize, _ := cmd.Flags().GetInt("batch-size")
+		discordMaxAttempts, _ := cmd.Flags().GetInt("discord-max-attempts")
+		httpTimeout, _ := cmd.Flags().GetInt("http-timeout")
+		maxConcurrentSyncs, _ := cmd.Flags().GetInt("max-concurrent-syncs")
+		safeWipe, _ := cmd.Flags().GetBool("safe-wipe")
+		wipeBackupRetention, _ := cmd.Flags().GetInt("wipe-backup-retention")
+		minFreeDiskGB, _ := cmd.Flags().GetInt("min-free-disk-gb")
+		configReloadInterval, _ := cmd.Flags().GetInt("config-reload-interval")
+		updateCheckInterval, _ := cmd.Flags().GetInt("update-check-interval")
+		updateCheckSource, _ := cmd.Flags().GetString("update-check-source")
+		updateCheckURL, _ := cmd.Flags().GetString("update-check-url")
+		updateDeferMinutes, _ := cmd.Flags().GetInt("update-defer-minutes")
+		stepTimeoutMinutes, _ := cmd.Flags().GetInt("step-timeout-minutes")
+		steamcmdTimeoutMinutes, _ := cmd.Flags().GetInt("steamcmd-timeout-minutes")
+		rconWarnings, _ := cmd.Flags().GetIntSlice("rcon-warnings")
+		rconWarningMessage, _ := cmd.Flags().GetString("rcon-warning-message")
+		recheckCalendarBeforeWipe, _ := cmd.Flags().GetBool("recheck-calendar-before-wipe")
+		metricsAddr, _ := cmd.Flags().GetString("metrics-addr")
+		healthAddr, _ := cmd.Flags().GetString("health-addr")
+
+		changed := false
 
 		if cmd.Flags().Changed("check-interval") {
 			if err := config.SetCheckInterval(checkInterval); err != nil {
 				fmt.Fprintf(os.Stderr, "Error setting check interval: %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Printf("✓ Check interval set to %d seconds\n", checkInterval)
+			console.Printf("✓ Check interval set to %d seconds\n", checkInterval)
+			changed = true
+		}
+
+		// Raise the bound before the lookahead it allows, when both are given
+		if cmd.Flags().Changed("max-lookahead-hours") {
+			if err := config.SetMaxLookaheadHours(maxLookaheadHours); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting max lookahead hours: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Max lookahead hours set to %d hours\n", maxLookaheadHours)
 			changed = true
 		}
 
@@ -233,7 +1216,7 @@ var configSetCmd = &cobra.Command{
 				fmt.Fprintf(os.Stderr, "Error setting lookahead hours: %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Printf("✓ Lookahead hours set to %d hours\n", lookaheadHours)
+			console.Printf("✓ Lookahead hours set to %d hours\n", lookaheadHours)
 			changed = true
 		}
 
@@ -242,7 +1225,7 @@ var configSetCmd = &cobra.Command{
 				fmt.Fprintf(os.Stderr, "Error setting event delay: %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Printf("✓ Event delay set to %d seconds\n", eventDelay)
+			console.Printf("✓ Event delay set to %d seconds\n", eventDelay)
 			changed = true
 		}
 
@@ -252,9 +1235,9 @@ var configSetCmd = &cobra.Command{
 				os.Exit(1)
 			}
 			if discordWebhook == "" {
-				fmt.Println("✓ Discord webhook disabled")
+				console.Println("✓ Discord webhook disabled")
 			} else {
-				fmt.Println("✓ Discord webhook configured")
+				console.Println("✓ Discord webhook configured")
 			}
 			changed = true
 		}
@@ -264,117 +1247,468 @@ var configSetCmd = &cobra.Command{
 				fmt.Fprintf(os.Stderr, "Error setting map generation hours: %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Printf("✓ Map generation hours set to %d hours\n", mapGenerationHours)
+			console.Printf("✓ Map generation hours set to %d hours\n", mapGenerationHours)
 			changed = true
 		}
 
-		if !changed {
-			fmt.Println("No settings changed. Use --check-interval, --lookahead-hours, --event-delay, --discord-webhook, or --map-generation-hours")
+		if cmd.Flags().Changed("start-stagger") {
+			if err := config.SetStartStagger(startStagger); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting start stagger: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Start stagger set to %d seconds\n", startStagger)
+			changed = true
 		}
-	},
-}
 
-var callScriptCmd = &cobra.Command{
-	Use:   "call-script [server-names...] --script <script-name>",
-	Short: "Call a management script with server paths",
-	Long: `Call one of the management scripts with the paths of specified servers.
+		if cmd.Flags().Changed("start-stagger-size") {
+			if err := config.SetStartStaggerSize(startStaggerSize); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting start stagger size: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Start stagger size set to %d server(s)\n", startStaggerSize)
+			changed = true
+		}
 
-Available scripts:
-  - stop-servers
-  - start-servers
-  - generate-maps
+		if cmd.Flags().Changed("keep-previous-install") {
+			if err := config.SetKeepPreviousInstall(keepPreviousInstall); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting keep previous install: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Keep previous install set to %v\n", keepPreviousInstall)
+			changed = true
+		}
 
-Example:
-  wipe call-script us-weekly eu-monthly --script stop-servers`,
-	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		scriptName, _ := cmd.Flags().GetString("script")
+		if cmd.Flags().Changed("wipe-confirmation-minutes") {
+			if err := config.SetWipeConfirmationMinutes(wipeConfirmation); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting wipe confirmation: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Wipe confirmation set to %d minutes\n", wipeConfirmation)
+			changed = true
+		}
 
-		if scriptName == "" {
-			fmt.Fprintf(os.Stderr, "Error: --script flag is required\n")
-			os.Exit(1)
+		if cmd.Flags().Changed("health-check-interval") {
+			if err := config.SetHealthCheckInterval(healthCheckInterval); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting health check interval: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Health check interval set to %d seconds\n", healthCheckInterval)
+			changed = true
 		}
 
-		// Trim .sh extension if provided
-		scriptName = filepath.Base(scriptName)
-		if filepath.Ext(scriptName) == ".sh" {
-			scriptName = scriptName[:len(scriptName)-3]
+		if cmd.Flags().Changed("heartbeat-hours") {
+			if err := config.SetHeartbeatHours(heartbeatHours); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting heartbeat hours: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Heartbeat set to every %d hours\n", heartbeatHours)
+			changed = true
 		}
 
-		// Validate script name
-		validScripts := map[string]string{
-			"stop-servers":  "/opt/wiped/stop-servers.sh",
-			"start-servers": "/opt/wiped/start-servers.sh",
-			"generate-maps": "/opt/wiped/generate-maps.sh",
+		if cmd.Flags().Changed("min-free-memory-mb") {
+			if err := config.SetMinFreeMemoryMB(minFreeMemory); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting minimum free memory: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Minimum free memory set to %d MB per server\n", minFreeMemory)
+			changed = true
 		}
 
-		scriptPath, ok := validScripts[scriptName]
-		if !ok {
-			fmt.Fprintf(os.Stderr, "Error: Invalid script name '%s'\n", scriptName)
-			fmt.Fprintf(os.Stderr, "Valid scripts: stop-servers, start-servers, generate-maps\n")
-			os.Exit(1)
+		if cmd.Flags().Changed("batch-size") {
+			if err := config.SetBatchSize(batchSize); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting batch size: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Batch size set to %d servers per wave\n", batchSize)
+			changed = true
 		}
 
-		// Check if script exists
-		if _, err := os.Stat(scriptPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Script not found at %s\n", scriptPath)
-			fmt.Fprintf(os.Stderr, "Restart the wiped service to generate scripts: sudo systemctl restart wiped@$USER.service\n")
-			os.Exit(1)
+		if cmd.Flags().Changed("discord-max-attempts") {
+			if err := config.SetDiscordMaxAttempts(discordMaxAttempts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting Discord max attempts: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Discord max attempts set to %d\n", discordMaxAttempts)
+			changed = true
 		}
 
-		// Get config to look up server paths
-		cfg, err := config.GetConfig()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-			os.Exit(1)
+		if cmd.Flags().Changed("http-timeout") {
+			if err := config.SetHTTPTimeout(httpTimeout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting HTTP timeout: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ HTTP timeout set to %d seconds\n", httpTimeout)
+			changed = true
 		}
 
-		// Map server names to paths
-		serverPaths := []string{}
-		for _, serverName := range args {
-			found := false
-			for _, server := range cfg.Servers {
-				if server.Name == serverName {
-					serverPaths = append(serverPaths, server.Path)
-					found = true
-					break
-				}
+		if cmd.Flags().Changed("max-concurrent-syncs") {
+			if err := config.SetMaxConcurrentSyncs(maxConcurrentSyncs); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting max concurrent syncs: %v\n", err)
+				os.Exit(1)
 			}
-			if !found {
-				fmt.Fprintf(os.Stderr, "Error: Server '%s' not found in configuration\n", serverName)
+			console.Printf("✓ Max concurrent syncs set to %d\n", maxConcurrentSyncs)
+			changed = true
+		}
+
+		if cmd.Flags().Changed("safe-wipe") {
+			if err := config.SetSafeWipe(safeWipe); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting safe wipe: %v\n", err)
 				os.Exit(1)
 			}
+			console.Printf("✓ Safe wipe set to %v\n", safeWipe)
+			changed = true
 		}
 
-		if len(serverPaths) == 0 {
-			fmt.Fprintf(os.Stderr, "Error: No valid servers found\n")
-			os.Exit(1)
+		if cmd.Flags().Changed("wipe-backup-retention") {
+			if err := config.SetWipeBackupRetention(wipeBackupRetention); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting wipe backup retention: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Wipe backup retention set to %d\n", wipeBackupRetention)
+			changed = true
 		}
 
-		// Call the script
-		fmt.Printf("📞 Calling %s with %d server(s)...\n", scriptName, len(serverPaths))
-		fmt.Printf("   Script: %s\n", scriptPath)
-		fmt.Printf("   Servers: %v\n\n", args)
+		if cmd.Flags().Changed("min-free-disk-gb") {
+			if err := config.SetMinFreeDiskGB(minFreeDiskGB); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting min free disk: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Min free disk set to %d GB\n", minFreeDiskGB)
+			changed = true
+		}
 
-		// Use exec to run the script with streaming output
-		fmt.Println("--- Script Output ---")
-		cmdExec := exec.Command(scriptPath, serverPaths...)
-		cmdExec.Stdout = os.Stdout
-		cmdExec.Stderr = os.Stderr
+		if cmd.Flags().Changed("config-reload-interval") {
+			if err := config.SetConfigReloadInterval(configReloadInterval); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting config reload interval: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Config reload interval set to %d seconds\n", configReloadInterval)
+			changed = true
+		}
 
-		if err := cmdExec.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "\n❌ Script failed: %v\n", err)
-			os.Exit(1)
+		if cmd.Flags().Changed("update-check-interval") {
+			if err := config.SetUpdateCheckInterval(updateCheckInterval); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting update check interval: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Update check interval set to %d seconds\n", updateCheckInterval)
+			changed = true
 		}
 
-		fmt.Println("--- End Output ---")
-		fmt.Println("\n✓ Script completed successfully")
-	},
-}
+		if cmd.Flags().Changed("update-check-source") {
+			if err := config.SetUpdateCheckSource(updateCheckSource); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting update check source: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Rust updates will be checked with %s\n", updateCheckSource)
+			changed = true
+		}
 
-var syncCmd = &cobra.Command{
-	Use:   "sync [server-names...]",
-	Short: "Update Rust and Carbon on servers",
+		if cmd.Flags().Changed("update-check-url") {
+			if err := config.SetUpdateCheckURL(updateCheckURL); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting update check URL: %v\n", err)
+				os.Exit(1)
+			}
+			if updateCheckURL == "" {
+				console.Printf("✓ Update check URL reset to %s\n", steamcmd.DefaultBuildIDURL)
+			} else {
+				console.Printf("✓ Update check URL set to %s\n", updateCheckURL)
+			}
+			changed = true
+		}
+
+		if cmd.Flags().Changed("update-defer-minutes") {
+			if err := config.SetUpdateDeferMinutes(updateDeferMinutes); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting update defer window: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Update defer window set to %d minutes\n", updateDeferMinutes)
+			changed = true
+		}
+
+		if cmd.Flags().Changed("notifier") {
+			if err := config.SetNotifier(notifier); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting notifier: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Notifications will be sent to %s\n", notifier)
+			changed = true
+		}
+
+		if cmd.Flags().Changed("slack-webhook") {
+			if err := config.SetSlackWebhook(slackWebhook); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting slack webhook: %v\n", err)
+				os.Exit(1)
+			}
+			if slackWebhook == "" {
+				console.Println("✓ Slack webhook disabled")
+			} else {
+				console.Println("✓ Slack webhook configured")
+			}
+			changed = true
+		}
+
+		if cmd.Flags().Changed("event-webhook-url") {
+			if err := config.SetEventWebhookURL(eventWebhookURL); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting event webhook URL: %v\n", err)
+				os.Exit(1)
+			}
+			if eventWebhookURL == "" {
+				console.Println("✓ Event webhook disabled")
+			} else {
+				console.Println("✓ Event webhook configured")
+			}
+			changed = true
+		}
+
+		if cmd.Flags().Changed("history-file") {
+			if err := config.SetHistoryFile(historyFile); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting history file: %v\n", err)
+				os.Exit(1)
+			}
+			if historyFile == "" {
+				console.Println("✓ History file reset to default")
+			} else {
+				console.Printf("✓ History file set to %s\n", historyFile)
+			}
+			changed = true
+		}
+
+		if cmd.Flags().Changed("step-timeout-minutes") {
+			if err := config.SetStepTimeoutMinutes(stepTimeoutMinutes); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting step timeout: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Step timeout set to %d minutes\n", stepTimeoutMinutes)
+			changed = true
+		}
+
+		if cmd.Flags().Changed("steamcmd-timeout-minutes") {
+			if err := config.SetSteamCMDTimeoutMinutes(steamcmdTimeoutMinutes); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting steamcmd timeout: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ SteamCMD timeout set to %d minutes\n", steamcmdTimeoutMinutes)
+			changed = true
+		}
+
+		if cmd.Flags().Changed("rcon-warnings") {
+			if err := config.SetRconWarnings(rconWarnings); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting RCON warnings: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ RCON warnings set to %v seconds before stopping\n", rconWarnings)
+			changed = true
+		}
+
+		if cmd.Flags().Changed("rcon-warning-message") {
+			if err := config.SetRconWarningMessage(rconWarningMessage); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting RCON warning message: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ RCON warning message set to %q\n", rconWarningMessage)
+			changed = true
+		}
+
+		if cmd.Flags().Changed("recheck-calendar-before-wipe") {
+			if err := config.SetRecheckCalendarBeforeWipe(recheckCalendarBeforeWipe); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting calendar re-check: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Re-check calendar before wipe set to %v\n", recheckCalendarBeforeWipe)
+			changed = true
+		}
+
+		if cmd.Flags().Changed("metrics-addr") {
+			if err := config.SetMetricsAddr(metricsAddr); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting metrics address: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Metrics address set to %q (restart the daemon to apply)\n", metricsAddr)
+			changed = true
+		}
+
+		if cmd.Flags().Changed("health-addr") {
+			if err := config.SetHealthAddr(healthAddr); err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting health address: %v\n", err)
+				os.Exit(1)
+			}
+			console.Printf("✓ Health address set to %q (restart the daemon to apply)\n", healthAddr)
+			changed = true
+		}
+
+		if !changed {
+			console.Println("No settings changed. Use --check-interval, --lookahead-hours, --max-lookahead-hours, --event-delay, --discord-webhook, --map-generation-hours, --start-stagger, --start-stagger-size, --keep-previous-install, --wipe-confirmation-minutes, --health-check-interval, --heartbeat-hours, --min-free-memory-mb, --batch-size, --discord-max-attempts, --http-timeout, --max-concurrent-syncs, --safe-wipe, --wipe-backup-retention, --min-free-disk-gb, --config-reload-interval, --update-check-interval, --update-check-source, --update-check-url, --update-defer-minutes, --history-file, --notifier, --slack-webhook, --event-webhook-url, --step-timeout-minutes, --steamcmd-timeout-minutes, --rcon-warnings, --rcon-warning-message, --recheck-calendar-before-wipe, --metrics-addr, or --health-addr")
+		}
+	},
+}
+
+var configBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the current configuration",
+	Long: `Writes the current configuration, including defaults, to a YAML file.
+
+Without --file the backup goes to a timestamped file in the backups directory
+next to the config file, e.g. ~/.config/wiped/backups/config-20250601-200000.yaml.
+
+Example:
+  wipe config backup
+  wipe config backup --file /root/wiped-config.yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+
+		path, err := config.BackupConfig(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error backing up config: %v\n", err)
+			os.Exit(1)
+		}
+		console.Printf("✓ Config backed up to %s\n", path)
+	},
+}
+
+var configRestoreCmd = &cobra.Command{
+	Use:   "restore --file <path>",
+	Short: "Replace the configuration with a backup",
+	Long: `Validates a config file and replaces the live config file with it.
+
+The file is parsed before anything is overwritten; a malformed file is rejected.
+A running daemon picks up the restored config on its next reload.
+
+Example:
+  wipe config restore --file ~/.config/wiped/backups/config-20250601-200000.yaml
+  wipe config restore --file backup.yaml --force  # Skip confirmation prompt`,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+		force, _ := cmd.Flags().GetBool("force")
+
+		backup, err := config.ValidateConfigFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid config file: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !force {
+			console.Printf("⚠️  WARNING: This replaces %s with %s (%d server(s)).\n", config.ConfigFileUsed(), file, len(backup.Servers))
+			fmt.Print("\nDo you want to continue? (yes/no): ")
+
+			var response string
+			fmt.Scanln(&response)
+
+			if response != "yes" && response != "y" {
+				console.Println("❌ Restore cancelled")
+				os.Exit(0)
+			}
+		}
+
+		if err := config.RestoreConfig(file); err != nil {
+			fmt.Fprintf(os.Stderr, "Error restoring config: %v\n", err)
+			os.Exit(1)
+		}
+		console.Printf("✓ Config restored from %s\n", file)
+	},
+}
+
+var callScriptCmd = &cobra.Command{
+	Use:   "call-script [server-names...] --script <script-name>",
+	Short: "Call a management script with server paths",
+	Long: `Call one of the management scripts with the paths of specified servers.
+
+Available scripts:
+  - stop-servers
+  - start-servers
+  - generate-maps
+
+Example:
+  wipe call-script us-weekly eu-monthly --script stop-servers`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		scriptName, _ := cmd.Flags().GetString("script")
+
+		if scriptName == "" {
+			fmt.Fprintf(os.Stderr, "Error: --script flag is required\n")
+			os.Exit(1)
+		}
+
+		// Trim .sh extension if provided
+		scriptName = filepath.Base(scriptName)
+		if filepath.Ext(scriptName) == ".sh" {
+			scriptName = scriptName[:len(scriptName)-3]
+		}
+
+		// Validate script name
+		validScripts := map[string]string{
+			"stop-servers":  "/opt/wiped/stop-servers.sh",
+			"start-servers": "/opt/wiped/start-servers.sh",
+			"generate-maps": "/opt/wiped/generate-maps.sh",
+		}
+
+		scriptPath, ok := validScripts[scriptName]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Invalid script name '%s'\n", scriptName)
+			fmt.Fprintf(os.Stderr, "Valid scripts: stop-servers, start-servers, generate-maps\n")
+			os.Exit(1)
+		}
+
+		// Check if script exists
+		if _, err := os.Stat(scriptPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Script not found at %s\n", scriptPath)
+			fmt.Fprintf(os.Stderr, "Restart the wiped service to generate scripts: sudo systemctl restart wiped@$USER.service\n")
+			os.Exit(1)
+		}
+
+		// Get config to look up server paths
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Map server names to paths
+		serverPaths := []string{}
+		for _, serverName := range args {
+			found := false
+			for _, server := range cfg.Servers {
+				if server.Name == serverName {
+					serverPaths = append(serverPaths, server.Path)
+					found = true
+					break
+				}
+			}
+			if !found {
+				fmt.Fprintf(os.Stderr, "Error: Server '%s' not found in configuration\n", serverName)
+				os.Exit(1)
+			}
+		}
+
+		if len(serverPaths) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: No valid servers found\n")
+			os.Exit(1)
+		}
+
+		// Call the script
+		console.Printf("📞 Calling %s with %d server(s)...\n", scriptName, len(serverPaths))
+		console.Printf("   Script: %s\n", scriptPath)
+		console.Printf("   Servers: %v\n\n", args)
+
+		// Use exec to run the script with streaming output
+		console.Println("--- Script Output ---")
+		cmdExec := exec.Command(scriptPath, serverPaths...)
+		cmdExec.Stdout = os.Stdout
+		cmdExec.Stderr = os.Stderr
+
+		if err := cmdExec.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "\n❌ Script failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		console.Println("--- End Output ---")
+		console.Println("\n✓ Script completed successfully")
+	},
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [server-names...]",
+	Short: "Update Rust and Carbon on servers",
 	Long: `Updates Rust and Carbon installations from /opt/rust and /opt/carbon on the specified servers.
 
 This command:
@@ -427,317 +1761,2057 @@ Example:
 
 		// Show warning and get confirmation (unless --force is used)
 		if !force {
-			fmt.Printf("⚠️  WARNING: You are about to update Rust and Carbon on %d server(s):\n\n", len(serversToSync))
+			console.Printf("⚠️  WARNING: You are about to update Rust and Carbon on %d server(s):\n\n", len(serversToSync))
 			for _, server := range serversToSync {
-				fmt.Printf("  • %s (%s, branch: %s)\n", server.Name, server.Path, server.Branch)
+				console.Printf("  • %s (%s, branch: %s)\n", server.Name, server.Path, server.Branch)
 			}
-			fmt.Println("\n⚠️  IMPORTANT: These servers should be STOPPED before updating!")
-			fmt.Println("   Updating files while servers are running may cause issues.")
+			console.Println("\n⚠️  IMPORTANT: These servers should be STOPPED before updating!")
+			console.Println("   Updating files while servers are running may cause issues.")
 			fmt.Print("\nDo you want to continue? (yes/no): ")
 
-			var response string
-			fmt.Scanln(&response)
+			var response string
+			fmt.Scanln(&response)
+
+			if response != "yes" && response != "y" {
+				console.Println("❌ Update cancelled")
+				os.Exit(0)
+			}
+		}
+
+		// Update servers
+		applyRuntimeSettings(cfg)
+		console.Printf("\n🔄 Updating %d server(s)...\n\n", len(serversToSync))
+		if err := executor.SyncServers(context.Background(), serversToSync); err != nil {
+			fmt.Fprintf(os.Stderr, "\n❌ Update failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		console.Println("\n✓ All servers updated successfully")
+	},
+}
+
+var cancelCmd = &cobra.Command{
+	Use:   "cancel <server-name> <time>",
+	Short: "Cancel a server's scheduled restart or wipe",
+	Long: `Removes a server's event at the given time from the daemon's schedule without
+editing the calendar. The time matches the event to the minute; use 'wipe next'
+or 'wipe preview' to find it. The server's calendars (and events added with
+'wipe trigger') are checked first, and nothing is cancelled when no event matches.
+
+The cancellation is saved to the config file and applied by the running daemon
+within a few seconds. Other servers in the same batch still run; a batch left
+with no servers is cancelled. An event that has already started is never stopped.
+
+Example:
+  wipe cancel us-weekly "2025-06-05T19:00:00Z"
+  wipe cancel us-weekly "2025-06-05T15:00:00-04:00"`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		serverName, atStr := args[0], args[1]
+
+		at, err := time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid time '%s' (use RFC3339, e.g. 2025-06-01T20:00:00Z)\n", atStr)
+			os.Exit(1)
+		}
+		if !at.After(time.Now()) {
+			fmt.Fprintf(os.Stderr, "Error: time %s is in the past\n", at.Format(time.RFC3339))
+			os.Exit(1)
+		}
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		var server *config.Server
+		for i := range cfg.Servers {
+			if cfg.Servers[i].Name == serverName {
+				server = &cfg.Servers[i]
+				break
+			}
+		}
+		if server == nil {
+			fmt.Fprintf(os.Stderr, "Error: server '%s' not found\n", serverName)
+			os.Exit(1)
+		}
+
+		eventType, err := findEventAt(cfg.OneOffEvents, *server, at)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Keyed by path, which stays the same when the server is renamed
+		if err := config.AddCancelledEvent(config.CancelledEvent{Server: server.Path, At: at.Format(time.RFC3339)}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error cancelling event: %v\n", err)
+			os.Exit(1)
+		}
+
+		console.Printf("✓ Cancelled the %s for %s at %s\n", eventType, serverName, at.Local().Format("Mon Jan 02 15:04 MST"))
+		console.Println("\nℹ️  The running daemon will remove it from the schedule within a few seconds")
+	},
+}
+
+// errNoEventAt is returned by findEventAt when a server has nothing scheduled at the time
+var errNoEventAt = errors.New("no restart or wipe scheduled")
+
+// findEventAt returns the type of a server's event at the given time, matched to the minute like
+// the daemon does: a one-off event added with 'wipe trigger', or an event in the server's calendars
+func findEventAt(oneOffs []config.OneOffEvent, server config.Server, at time.Time) (calendar.EventType, error) {
+	minute := at.Truncate(time.Minute)
+	for _, event := range oneOffs {
+		if event.Server != server.Name && event.Server != server.Path {
+			continue
+		}
+		if eventAt, err := time.Parse(time.RFC3339, event.At); err == nil && eventAt.Truncate(time.Minute).Equal(minute) {
+			return calendar.EventType(event.Type), nil
+		}
+	}
+
+	cals, err := calendar.FetchCalendarsWithAuth(server.AllCalendarURLs(), server.CalendarAuthHeader, server.CalendarAuthToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch calendars for %s: %w", server.Name, err)
+	}
+	events, err := calendar.GetEventsInCalendars(cals, minute.Add(-time.Minute), minute.Add(2*time.Minute), server.MatchPatterns)
+	if err != nil {
+		return "", fmt.Errorf("failed to read calendars for %s: %w", server.Name, err)
+	}
+	for _, event := range events {
+		if event.StartTime.Truncate(time.Minute).Equal(minute) {
+			return event.Type, nil
+		}
+	}
+	return "", fmt.Errorf("%w for %s at %s (list its events with: wipe preview %s)", errNoEventAt, server.Name, at.Format(time.RFC3339), server.Name)
+}
+
+var triggerCmd = &cobra.Command{
+	Use:   "trigger [server-names...] --type <type> --at <time>",
+	Short: "Schedule a one-off event without adding it to the calendar",
+	Long: `Schedules a one-off restart or wipe for the specified servers at a future time.
+
+The event is saved to the config file and picked up by the running daemon
+within a few seconds. It is armed and announced like any calendar event.
+Events further out than lookahead_hours are armed once they enter the window.
+
+Event types:
+  restart         Stop, sync Rust/Carbon, start
+  restart-nosync  Stop and start without syncing
+  wipe            Stop, sync, wipe map data, start
+  wipe-bp         Wipe map data and blueprints
+  full-wipe       Alias for wipe-bp (deletes the same files)
+  map-only        Wipe only the map and save files, keep player data
+
+Example:
+  wipe trigger us-weekly --type wipe --at "2025-06-01T20:00:00Z"
+  wipe trigger us-weekly eu-monthly --type restart --at "2025-06-01T20:00:00-04:00"`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		eventType, _ := cmd.Flags().GetString("type")
+		atStr, _ := cmd.Flags().GetString("at")
+
+		parsedType, ok := calendar.ParseEventType(eventType)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: invalid event type '%s' (use restart, restart-nosync, wipe, wipe-bp, full-wipe, or map-only)\n", eventType)
+			os.Exit(1)
+		}
+		eventType = string(parsedType)
+
+		at, err := time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --at time '%s' (use RFC3339, e.g. 2025-06-01T20:00:00Z)\n", atStr)
+			os.Exit(1)
+		}
+		if !at.After(time.Now()) {
+			fmt.Fprintf(os.Stderr, "Error: --at time %s is in the past\n", at.Format(time.RFC3339))
+			os.Exit(1)
+		}
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Find servers by name
+		var events []config.OneOffEvent
+		for _, serverName := range args {
+			found := false
+			for _, server := range cfg.Servers {
+				if server.Name == serverName {
+					found = true
+					break
+				}
+			}
+			if !found {
+				fmt.Fprintf(os.Stderr, "Error: server '%s' not found\n", serverName)
+				os.Exit(1)
+			}
+			events = append(events, config.OneOffEvent{
+				Server: serverName,
+				Type:   eventType,
+				At:     at.Format(time.RFC3339),
+			})
+		}
+
+		if err := config.AddOneOffEvents(events); err != nil {
+			fmt.Fprintf(os.Stderr, "Error scheduling event: %v\n", err)
+			os.Exit(1)
+		}
+
+		console.Printf("✓ Scheduled %s for %d server(s) at %s\n", eventType, len(events), at.Local().Format("Mon Jan 02 15:04 MST"))
+		for _, e := range events {
+			console.Printf("  • %s\n", e.Server)
+		}
+		console.Println("\nℹ️  The running daemon will arm this event within a few seconds")
+	},
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run <server-name> --type <type>",
+	Short: "Run a restart or wipe on a server immediately",
+	Long: `Runs a restart or wipe on a server right now, bypassing the calendar.
+
+The server is stopped, synced, wiped (for wipe types) and started exactly as
+a scheduled event would be, including Discord notifications and the execution
+history. A plain wipe honors the server's wipe_blueprints setting.
+
+Event types:
+  restart         Stop, sync Rust/Carbon, start
+  restart-nosync  Stop and start without syncing
+  wipe            Stop, sync, wipe map data, start
+  wipe-bp         Wipe map data and blueprints
+  full-wipe       Alias for wipe-bp (deletes the same files)
+  map-only        Wipe only the map and save files, keep player data
+
+Use --no-wipe-maps to keep the server's *.map file on a wipe, so it reuses its
+current map instead of generating or downloading a new one.
+
+Example:
+  wipe run us-weekly --type restart
+  wipe run us-weekly --type wipe --no-wipe-maps
+  wipe run us-weekly --type wipe --force  # Skip confirmation prompt`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		eventType, _ := cmd.Flags().GetString("type")
+		force, _ := cmd.Flags().GetBool("force")
+		noWipeMaps, _ := cmd.Flags().GetBool("no-wipe-maps")
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		server, wipeServers, noSyncServers, err := buildRunBatch(cfg, args[0], eventType, noWipeMaps)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Show warning and get confirmation (unless --force is used)
+		if !force {
+			console.Printf("⚠️  WARNING: This BYPASSES THE CALENDAR and runs a %s on %s NOW:\n\n", eventType, server.Name)
+			console.Printf("  • %s (%s, branch: %s)\n", server.Name, server.Path, server.Branch)
+			console.Println("\n  The server will be stopped and players disconnected.")
+			if mode, wipe := wipeServers[server.Path]; wipe {
+				if noWipeMaps {
+					console.Println("  Map files: kept (--no-wipe-maps)")
+				} else {
+					console.Println("  Map files: deleted")
+				}
+				console.Println("  Save files: deleted")
+				if mode != executor.WipeMapOnly {
+					console.Println("  Player data: deleted")
+				}
+				if mode == executor.WipeWithBlueprints || (mode == executor.WipeStandard && server.WipeBlueprints) {
+					console.Println("  Blueprints: deleted")
+				} else {
+					console.Println("  Blueprints: kept")
+				}
+			}
+			fmt.Print("\nDo you want to continue? (yes/no): ")
+
+			var response string
+			fmt.Scanln(&response)
+
+			if response != "yes" && response != "y" {
+				console.Println("❌ Run cancelled")
+				os.Exit(0)
+			}
+		}
+
+		// Initialize logger for executor output
+		log.SetOutput(os.Stdout)
+		log.SetFlags(log.LstdFlags)
+
+		applyRuntimeSettings(cfg)
+
+		opts := executor.BatchOptions{
+			Notifier:        notify.New(cfg),
+			MinFreeMemoryMB: cfg.MinFreeMemoryMB,
+			KeepMaps:        noWipeMaps,
+			StepTimeout:     time.Duration(cfg.StepTimeoutMinutes) * time.Minute,
+		}
+		console.Printf("\n🔄 Running %s on %s...\n\n", eventType, server.Name)
+		if err := executor.ExecuteEventBatch(context.Background(), []config.Server{server}, wipeServers, noSyncServers, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "\n❌ Error running %s: %v\n", eventType, err)
+			os.Exit(1)
+		}
+
+		console.Printf("\n✓ %s completed on %s\n", eventType, server.Name)
+	},
+}
+
+// buildRunBatch resolves the server and event type for 'wipe run' into the arguments for executor.ExecuteEventBatch
+func buildRunBatch(cfg *config.Config, serverName, eventType string, noWipeMaps bool) (config.Server, map[string]executor.WipeMode, map[string]bool, error) {
+	var server config.Server
+	found := false
+	for _, s := range cfg.Servers {
+		if s.Name == serverName {
+			server = s
+			found = true
+			break
+		}
+	}
+	if !found {
+		return config.Server{}, nil, nil, fmt.Errorf("server '%s' not found", serverName)
+	}
+
+	parsedType, ok := calendar.ParseEventType(eventType)
+	if !ok {
+		return config.Server{}, nil, nil, fmt.Errorf("invalid event type '%s' (use restart, restart-nosync, wipe, wipe-bp, full-wipe, or map-only)", eventType)
+	}
+
+	wipeServers := make(map[string]executor.WipeMode)
+	noSyncServers := make(map[string]bool)
+	switch parsedType {
+	case calendar.EventTypeWipe:
+		wipeServers[server.Path] = executor.WipeStandard
+	case calendar.EventTypeWipeBlueprints, calendar.EventTypeFullWipe: // full-wipe is an alias for wipe-bp
+		wipeServers[server.Path] = executor.WipeWithBlueprints
+	case calendar.EventTypeMapOnly:
+		wipeServers[server.Path] = executor.WipeMapOnly
+	case calendar.EventTypeRestartNoSync:
+		noSyncServers[server.Path] = true
+	}
+
+	if noWipeMaps && len(wipeServers) == 0 {
+		return config.Server{}, nil, nil, fmt.Errorf("--no-wipe-maps only applies to wipe events, not %s", parsedType)
+	}
+	return server, wipeServers, noSyncServers, nil
+}
+
+var resetScriptsCmd = &cobra.Command{
+	Use:   "reset-scripts",
+	Short: "Reset management scripts to defaults",
+	Long: `Deletes and regenerates the management scripts in /opt/wiped:
+  - stop-servers.sh
+  - start-servers.sh
+  - pre-start-hook.sh
+  - post-start-hook.sh
+  - generate-maps.sh
+
+WARNING: This will overwrite any customizations you've made to these scripts.
+
+Use --check to report which scripts are missing, default, or customized
+without changing anything.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		check, _ := cmd.Flags().GetBool("check")
+
+		if check {
+			drifts, err := executor.CheckScriptDrift()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error checking scripts: %v\n", err)
+				os.Exit(1)
+			}
+
+			console.Println("Management script status:")
+			customized := 0
+			for _, d := range drifts {
+				switch d.Status {
+				case executor.ScriptMissing:
+					console.Printf("  ✗ %s: missing\n", filepath.Base(d.Path))
+				case executor.ScriptDefault:
+					console.Printf("  ✓ %s: matches default\n", filepath.Base(d.Path))
+				case executor.ScriptCustomized:
+					customized++
+					console.Printf("  ✎ %s: customized (+%d/-%d lines vs default)\n", filepath.Base(d.Path), d.LinesAdded, d.LinesRemoved)
+				}
+			}
+
+			if customized > 0 {
+				console.Printf("\n⚠️  %d customized script(s) would be overwritten by 'wipe reset-scripts'\n", customized)
+			}
+			return
+		}
+
+		if !force {
+			console.Println("⚠️  WARNING: This will delete and regenerate the following scripts:")
+			console.Println("   - /opt/wiped/stop-servers.sh")
+			console.Println("   - /opt/wiped/start-servers.sh")
+			console.Println("   - /opt/wiped/pre-start-hook.sh")
+			console.Println("   - /opt/wiped/post-start-hook.sh")
+			console.Println("   - /opt/wiped/generate-maps.sh")
+			console.Println()
+			console.Println("Any customizations you've made will be LOST!")
+			console.Println()
+			fmt.Print("Are you sure you want to continue? (yes/no): ")
+
+			var response string
+			fmt.Scanln(&response)
+
+			if response != "yes" {
+				console.Println("❌ Operation cancelled")
+				os.Exit(0)
+			}
+		}
+
+		console.Println("🔄 Resetting scripts...")
+
+		scriptsRemoved := 0
+		scriptsToRemove := []string{
+			executor.HookScriptPath,
+			executor.PostStartHookScriptPath,
+			executor.StopServersScriptPath,
+			executor.StartServersScriptPath,
+			executor.GenerateMapsScriptPath,
+		}
+
+		for _, script := range scriptsToRemove {
+			if _, err := os.Stat(script); err == nil {
+				if err := os.Remove(script); err != nil {
+					fmt.Fprintf(os.Stderr, "Error removing %s: %v\n", script, err)
+					os.Exit(1)
+				}
+				scriptsRemoved++
+				console.Printf("  ✓ Removed %s\n", filepath.Base(script))
+			}
+		}
+
+		if scriptsRemoved > 0 {
+			console.Printf("\n✓ Removed %d script(s)\n", scriptsRemoved)
+		} else {
+			console.Println("ℹ️  No scripts found to remove")
+		}
+
+		// Regenerate scripts immediately
+		console.Println("\n🔄 Regenerating scripts...")
+
+		if err := executor.EnsureHookScript(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating pre-start-hook.sh: %v\n", err)
+			os.Exit(1)
+		}
+		console.Println("  ✓ Created pre-start-hook.sh")
+
+		if err := executor.EnsureWipeScripts(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating management scripts: %v\n", err)
+			os.Exit(1)
+		}
+		console.Println("  ✓ Created stop-servers.sh")
+		console.Println("  ✓ Created start-servers.sh")
+		console.Println("  ✓ Created generate-maps.sh")
+		console.Println("  ✓ Created post-start-hook.sh")
+
+		console.Println("\n✓ All scripts reset to defaults")
+	},
+}
+
+var scriptsCmd = &cobra.Command{
+	Use:   "scripts",
+	Short: "View and edit the management scripts",
+	Long: `View and edit the management scripts the daemon runs from /opt/wiped, e.g. to check that
+customizations survived a 'wipe reset-scripts'.
+
+Example:
+  wipe scripts list
+  wipe scripts show stop-servers
+  wipe scripts edit pre-start-hook`,
+}
+
+var scriptsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the management scripts and whether they're customized",
+	Run: func(cmd *cobra.Command, args []string) {
+		drifts, err := executor.CheckScriptDrift()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking scripts: %v\n", err)
+			os.Exit(1)
+		}
+		status := make(map[string]executor.ScriptStatus, len(drifts))
+		for _, d := range drifts {
+			status[d.Path] = d.Status
+		}
+
+		console.Println("Management scripts:")
+		for _, script := range executor.ManagedScripts() {
+			console.Printf("  %-16s %s (%s)\n", script.Name, script.Path, status[script.Path])
+		}
+	},
+}
+
+var scriptsShowCmd = &cobra.Command{
+	Use:   "show <stop-servers|pre-start-hook|start-servers|post-start-hook|generate-maps>",
+	Short: "Print a management script",
+	Long: `Print the current contents of a management script, or its default template
+if the daemon hasn't generated it yet.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		script, err := executor.LookupScript(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		contents, installed, err := executor.ReadScript(script)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !installed {
+			fmt.Fprintf(os.Stderr, "Note: %s doesn't exist yet, showing the default template\n", script.Path)
+		}
+		fmt.Fprint(cmd.OutOrStdout(), contents)
+	},
+}
+
+var scriptsEditCmd = &cobra.Command{
+	Use:   "edit <stop-servers|pre-start-hook|start-servers|post-start-hook|generate-maps>",
+	Short: "Edit a management script in $EDITOR",
+	Long: `Open a management script in $EDITOR (falling back to vi, then nano).
+
+A script that doesn't exist yet is generated from its default template first, and
+the script is kept executable after saving.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		script, err := executor.LookupScript(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := editScript(script, scriptsEditor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error editing %s: %v\n", script.Name, err)
+			os.Exit(1)
+		}
+		console.Printf("✓ Saved %s\n", script.Path)
+	},
+}
+
+// Editor opens a file for the user to edit, returning once they're done (replaced in tests)
+type Editor interface {
+	Edit(path string) error
+}
+
+// execEditor runs $EDITOR, or vi or nano when it isn't set
+type execEditor struct{}
+
+func (execEditor) Edit(path string) error {
+	command := strings.Fields(os.Getenv("EDITOR"))
+	if len(command) == 0 {
+		for _, fallback := range []string{"vi", "nano"} {
+			if _, err := exec.LookPath(fallback); err == nil {
+				command = []string{fallback}
+				break
+			}
+		}
+	}
+	if len(command) == 0 {
+		return fmt.Errorf("no editor found: set $EDITOR")
+	}
+
+	editor := exec.Command(command[0], append(command[1:], path)...)
+	editor.Stdin = os.Stdin
+	editor.Stdout = os.Stdout
+	editor.Stderr = os.Stderr
+	if err := editor.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", command[0], err)
+	}
+	return nil
+}
+
+// scriptsEditor opens scripts for 'wipe scripts edit'
+var scriptsEditor Editor = execEditor{}
+
+// editScript generates script if it's missing, opens it in editor and restores the
+// executable bits if the editor dropped them (e.g. by replacing the file on save)
+func editScript(script executor.ManagedScript, editor Editor) error {
+	created, err := executor.EnsureScript(script)
+	if err != nil {
+		return err
+	}
+	if created {
+		console.Printf("Generated %s from the default template\n", script.Path)
+	}
+
+	before, err := os.Stat(script.Path)
+	if err != nil {
+		return err
+	}
+
+	if err := editor.Edit(script.Path); err != nil {
+		return err
+	}
+
+	after, err := os.Stat(script.Path)
+	if err != nil {
+		return fmt.Errorf("%s is gone after editing: %w", script.Path, err)
+	}
+	if want := after.Mode().Perm() | (before.Mode().Perm() & 0111); want != after.Mode().Perm() {
+		if err := os.Chmod(script.Path, want); err != nil {
+			return fmt.Errorf("failed to keep %s executable: %w", script.Path, err)
+		}
+	}
+	return nil
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage config profiles",
+	Long: `Profiles are separate config files in ~/.config/wiped, e.g. for staging and production fleets.
+
+The default profile is config.yaml; a profile named "staging" is staging.yaml.
+Create a profile with 'wipe profile create', select it for one command with --profile,
+or set the default with 'wipe profile use'.
+The daemon reads the default profile at startup (or takes a matching --profile flag),
+so restart it after 'wipe profile use'.
+
+Example:
+  wipe profile create staging
+  wipe --profile staging add --path /var/www/servers/test --calendar https://...
+  wipe profile use staging`,
+	// Profile commands manage the config files themselves, so they don't load one.
+	// This keeps 'wipe profile use default' working when the active profile was deleted.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		p, err := newPrinter(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		console = p
+	},
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create [profile]",
+	Short: "Create an empty config profile",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := config.CreateProfile(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating profile: %v\n", err)
+			os.Exit(1)
+		}
+		console.Printf("✓ Created profile %s (%s)\n", args[0], path)
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List config profiles",
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles, err := config.ListProfiles()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing profiles: %v\n", err)
+			os.Exit(1)
+		}
+
+		active := config.ActiveProfile()
+		console.Println("Config profiles:")
+		for _, profile := range profiles {
+			path, _ := config.ProfilePath(profile)
+			if profile == active {
+				console.Printf("  * %s (%s)\n", profile, path)
+			} else {
+				console.Printf("    %s (%s)\n", profile, path)
+			}
+		}
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use [profile]",
+	Short: "Set the default config profile",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.SetActiveProfile(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error setting profile: %v\n", err)
+			os.Exit(1)
+		}
+		console.Printf("✓ Now using profile: %s\n", args[0])
+		console.Println("  Restart the daemon to switch it too: sudo systemctl restart wiped@$USER.service")
+	},
+}
+
+var mentionCmd = &cobra.Command{
+	Use:   "mention",
+	Short: "Manage Discord mention lists",
+	Long:  `Add, remove, or list Discord user and role IDs to mention in notifications.`,
+}
+
+var mentionAddUserCmd = &cobra.Command{
+	Use:   "add-user [user-id]",
+	Short: "Add a Discord user ID to mention in notifications",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userID, err := config.NormalizeDiscordID(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding user: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		for _, id := range cfg.DiscordMentionUsers {
+			if id == userID {
+				console.Printf("ℹ️  Discord user ID %s is already in the mention list\n", userID)
+				return
+			}
+		}
+
+		if err := config.AddDiscordMentionUser(userID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding user: %v\n", err)
+			os.Exit(1)
+		}
+
+		console.Printf("✓ Added Discord user ID: %s\n", userID)
+		console.Printf("  This user will be mentioned in Discord notifications as <@%s>\n", userID)
+	},
+}
+
+var mentionRemoveUserCmd = &cobra.Command{
+	Use:   "remove-user [user-id]",
+	Short: "Remove a Discord user ID from mentions",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userID := args[0]
+
+		if err := config.RemoveDiscordMentionUser(userID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error removing user: %v\n", err)
+			os.Exit(1)
+		}
+
+		console.Printf("✓ Removed Discord user ID: %s\n", userID)
+	},
+}
+
+var mentionAddRoleCmd = &cobra.Command{
+	Use:   "add-role [role-id]",
+	Short: "Add a Discord role ID to mention in notifications",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		roleID, err := config.NormalizeDiscordID(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding role: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		for _, id := range cfg.DiscordMentionRoles {
+			if id == roleID {
+				console.Printf("ℹ️  Discord role ID %s is already in the mention list\n", roleID)
+				return
+			}
+		}
+
+		if err := config.AddDiscordMentionRole(roleID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding role: %v\n", err)
+			os.Exit(1)
+		}
+
+		console.Printf("✓ Added Discord role ID: %s\n", roleID)
+		console.Printf("  This role will be mentioned in Discord notifications as <@&%s>\n", roleID)
+	},
+}
+
+var mentionRemoveRoleCmd = &cobra.Command{
+	Use:   "remove-role [role-id]",
+	Short: "Remove a Discord role ID from mentions",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		roleID := args[0]
+
+		if err := config.RemoveDiscordMentionRole(roleID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error removing role: %v\n", err)
+			os.Exit(1)
+		}
+
+		console.Printf("✓ Removed Discord role ID: %s\n", roleID)
+	},
+}
+
+var mentionSetUsersCmd = &cobra.Command{
+	Use:   "set-users [user-ids...]",
+	Short: "Replace the Discord user IDs mentioned in notifications",
+	Long: `Replaces the whole user mention list. IDs can be separated by commas or spaces.
+
+Example:
+  wipe mention set-users 123456789012345678,987654321098765432`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.SetDiscordMentionUsers(splitIDs(args)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error setting users: %v\n", err)
+			os.Exit(1)
+		}
+		printMentionList("user")
+	},
+}
+
+var mentionSetRolesCmd = &cobra.Command{
+	Use:   "set-roles [role-ids...]",
+	Short: "Replace the Discord role IDs mentioned in notifications",
+	Long: `Replaces the whole role mention list. IDs can be separated by commas or spaces.
+
+Example:
+  wipe mention set-roles 111222333444555666,777888999000111222`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.SetDiscordMentionRoles(splitIDs(args)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error setting roles: %v\n", err)
+			os.Exit(1)
+		}
+		printMentionList("role")
+	},
+}
+
+var mentionSetLevelsCmd = &cobra.Command{
+	Use:   "set-levels [levels...]",
+	Short: "Choose which notification levels mention the users and roles",
+	Long: `Sets the notification levels (success, info, warning, error) that mention the configured
+users and roles. Other notifications are still sent, just without pinging anyone.
+Levels can be separated by commas or spaces; "none" never mentions anyone.
+
+Example:
+  wipe mention set-levels error,warning  # The default
+  wipe mention set-levels error`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		levels := splitIDs(args)
+		if len(levels) == 1 && levels[0] == "none" {
+			levels = nil
+		}
+		if err := config.SetDiscordMentionOn(levels); err != nil {
+			fmt.Fprintf(os.Stderr, "Error setting levels: %v\n", err)
+			os.Exit(1)
+		}
+		console.Printf("✓ Discord mentions on: %s\n", mentionLevels(levels))
+	},
+}
+
+// mentionLevels describes the levels that mention users and roles
+func mentionLevels(levels []string) string {
+	if len(levels) == 0 {
+		return "no notifications"
+	}
+	return strings.Join(levels, ", ")
+}
+
+var mentionClearUsersCmd = &cobra.Command{
+	Use:   "clear-users",
+	Short: "Remove all Discord user IDs from mentions",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.SetDiscordMentionUsers(nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error clearing users: %v\n", err)
+			os.Exit(1)
+		}
+		console.Println("✓ Cleared Discord mention users")
+	},
+}
+
+var mentionClearRolesCmd = &cobra.Command{
+	Use:   "clear-roles",
+	Short: "Remove all Discord role IDs from mentions",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.SetDiscordMentionRoles(nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error clearing roles: %v\n", err)
+			os.Exit(1)
+		}
+		console.Println("✓ Cleared Discord mention roles")
+	},
+}
+
+// splitIDs splits comma-separated arguments into individual IDs, dropping empty entries
+func splitIDs(args []string) []string {
+	ids := []string{}
+	for _, arg := range args {
+		for _, id := range strings.Split(arg, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// printMentionList prints the saved user or role mention list after it was replaced
+func printMentionList(kind string) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	ids, format := cfg.DiscordMentionUsers, "  - <@%s>\n"
+	if kind == "role" {
+		ids, format = cfg.DiscordMentionRoles, "  - <@&%s>\n"
+	}
+	console.Printf("✓ Discord mention %ss set (%d):\n", kind, len(ids))
+	for _, id := range ids {
+		console.Printf(format, id)
+	}
+}
+
+var mentionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Discord users and roles mentioned in notifications",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(cfg.DiscordMentionUsers) == 0 && len(cfg.DiscordMentionRoles) == 0 {
+			console.Println("No Discord mentions configured.")
+			console.Println("\nAdd one with: wipe mention add-user <user-id> or wipe mention add-role <role-id>")
+			return
+		}
+
+		console.Printf("Discord mention users (%d):\n", len(cfg.DiscordMentionUsers))
+		for _, userID := range cfg.DiscordMentionUsers {
+			console.Printf("  - <@%s>\n", userID)
+		}
+
+		console.Printf("\nDiscord mention roles (%d):\n", len(cfg.DiscordMentionRoles))
+		for _, roleID := range cfg.DiscordMentionRoles {
+			console.Printf("  - <@&%s>\n", roleID)
+		}
+
+		console.Printf("\nMentioned on: %s\n", mentionLevels(cfg.DiscordMentionOn))
+	},
+}
+
+var updateSourceCmd = &cobra.Command{
+	Use:   "update-source",
+	Short: "Download latest Rust and Carbon/Oxide versions",
+	Long: `Manually download and install the latest Rust server files and mod framework
+(Carbon, or Oxide for servers with framework: oxide).
+
+This command updates the source installations in /opt/rust and /opt/carbon (or /opt/oxide).
+It does NOT sync these files to your servers - use 'wipe sync' for that.
+
+By default, updates all branches configured in your servers. Use --branch to
+update a specific branch only.
+
+Examples:
+  wipe update-source                    # Update all configured branches
+  wipe update-source --branch main      # Update only the main branch
+  wipe update-source --rust-only        # Only update Rust (skip Carbon/Oxide)
+  wipe update-source --carbon-only      # Only update Carbon/Oxide (skip Rust)`,
+	Run: func(cmd *cobra.Command, args []string) {
+		branch, _ := cmd.Flags().GetString("branch")
+		rustOnly, _ := cmd.Flags().GetBool("rust-only")
+		carbonOnly, _ := cmd.Flags().GetBool("carbon-only")
+
+		// Initialize logger
+		log.SetOutput(os.Stdout)
+		log.SetFlags(log.LstdFlags)
+
+		// Get config for webhook and branches
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Determine which branches to update
+		branches := make(map[string]bool)
+		if branch != "" {
+			branches[branch] = true
+		} else {
+			// Collect unique branches from configured servers
+			for _, server := range cfg.Servers {
+				if server.Branch != "" {
+					branches[server.Branch] = true
+				}
+			}
+			// Default to main if no servers configured
+			if len(branches) == 0 {
+				branches["main"] = true
+			}
+		}
+
+		notifier := notify.New(cfg)
+		applyRuntimeSettings(cfg)
+
+		console.Printf("🔄 Updating source installations for %d branch(es)...\n\n", len(branches))
+
+		hasErrors := false
+
+		// Update Rust for each branch
+		if !carbonOnly {
+			for b := range branches {
+				console.Printf("📦 Checking Rust updates for branch '%s'...\n", b)
+				hasUpdate, buildID, err := steamcmd.CheckForUpdates(b, notifier)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "   ❌ Error checking Rust updates: %v\n", err)
+					hasErrors = true
+					continue
+				}
+
+				if hasUpdate {
+					console.Printf("   ⬇️  Update available (build: %s), downloading...\n", buildID)
+					if err := steamcmd.InstallRustBranch(b, notifier); err != nil {
+						fmt.Fprintf(os.Stderr, "   ❌ Error installing Rust: %v\n", err)
+						hasErrors = true
+					} else {
+						console.Printf("   ✓ Rust branch '%s' updated to build %s\n", b, buildID)
+					}
+				} else if buildID != "" {
+					console.Printf("   ✓ Rust branch '%s' is up to date (build: %s)\n", b, buildID)
+				} else {
+					console.Printf("   ℹ️  Rust branch '%s' not installed yet, installing...\n", b)
+					if err := steamcmd.InstallRustBranch(b, notifier); err != nil {
+						fmt.Fprintf(os.Stderr, "   ❌ Error installing Rust: %v\n", err)
+						hasErrors = true
+					} else {
+						console.Printf("   ✓ Rust branch '%s' installed\n", b)
+					}
+				}
+			}
+			console.Println()
+		}
+
+		// Update the mod framework(s) the servers on each branch run
+		if !rustOnly {
+			for b := range branches {
+				for _, fw := range framework.ForBranch(cfg.Servers, b) {
+					name := fw.Name()
+					console.Printf("📦 Checking %s updates for branch '%s'...\n", name, b)
+					hasUpdate, version, err := fw.CheckForUpdates(b, notifier)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "   ❌ Error checking %s updates: %v\n", name, err)
+						hasErrors = true
+						continue
+					}
+
+					if hasUpdate {
+						console.Printf("   ⬇️  Update available (version: %s), downloading...\n", version)
+						if err := fw.Install(b, notifier); err != nil {
+							fmt.Fprintf(os.Stderr, "   ❌ Error installing %s: %v\n", name, err)
+							hasErrors = true
+						} else {
+							console.Printf("   ✓ %s for branch '%s' updated to version %s\n", name, b, version)
+						}
+					} else if version != "" {
+						console.Printf("   ✓ %s for branch '%s' is up to date (version: %s)\n", name, b, version)
+					} else {
+						console.Printf("   ℹ️  %s for branch '%s' not installed yet, installing...\n", name, b)
+						if err := fw.Install(b, notifier); err != nil {
+							fmt.Fprintf(os.Stderr, "   ❌ Error installing %s: %v\n", name, err)
+							hasErrors = true
+						} else {
+							console.Printf("   ✓ %s for branch '%s' installed\n", name, b)
+						}
+					}
+				}
+			}
+			console.Println()
+		}
+
+		if hasErrors {
+			console.Println("⚠️  Update completed with errors")
+			os.Exit(1)
+		}
+
+		console.Println("✓ All source updates complete")
+		console.Println("\nℹ️  To sync these updates to your servers, run: wipe sync <server-names>")
+	},
+}
+
+// applyRuntimeSettings applies the config's install, notification and execution settings the
+// same way the daemon does, before a command installs, syncs or runs anything
+func applyRuntimeSettings(cfg *config.Config) {
+	if err := settings.Apply(cfg); err != nil {
+		console.Printf("⚠️  WARNING: %v\n\n", err)
+	}
+}
+
+// Installers run by update-now; variables so tests can stub them
+var (
+	installRustBranch = steamcmd.InstallRustBranch
+	installFramework  = func(fw framework.Framework, branch string, notifier notify.Notifier) error {
+		return fw.Install(branch, notifier)
+	}
+)
+
+var updateNowCmd = &cobra.Command{
+	Use:   "update-now [branch...]",
+	Short: "Install the latest Rust and Carbon/Oxide builds immediately",
+	Long: `Installs the latest Rust server files and mod framework (Carbon, or Oxide for
+servers with framework: oxide) right away, instead of waiting for the daemon's
+next update check. With no branches, every branch a configured server uses is updated.
+
+Unlike 'wipe update-source', the builds are reinstalled even when the update check
+reports them up to date, and steamcmd's download progress is shown as it runs.
+Branches held with rust_build_id or carbon_version stay on their pinned build.
+
+The daemon can keep running: installs and server syncs take a lock file per branch
+in /opt/rust, /opt/carbon and /opt/oxide, so the daemon's syncs wait for this
+command's installs and the other way round. Servers aren't synced; run 'wipe sync'
+afterwards.
+
+Examples:
+  wipe update-now                   # Every configured branch
+  wipe update-now main staging      # Only these branches
+  wipe update-now --rust-only       # Only Rust (skip Carbon/Oxide)
+  wipe update-now --carbon-only     # Only Carbon/Oxide (skip Rust)`,
+	Run: func(cmd *cobra.Command, args []string) {
+		rustOnly, _ := cmd.Flags().GetBool("rust-only")
+		carbonOnly, _ := cmd.Flags().GetBool("carbon-only")
+		if rustOnly && carbonOnly {
+			fmt.Fprintf(os.Stderr, "Error: --rust-only and --carbon-only can't be used together\n")
+			os.Exit(1)
+		}
+
+		// Stream install logs, including steamcmd's progress, to the terminal
+		log.SetOutput(os.Stdout)
+		log.SetFlags(log.LstdFlags)
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		notifier := notify.New(cfg)
+		applyRuntimeSettings(cfg)
+
+		branches := updateNowBranches(cfg.Servers, args)
+		console.Printf("🔄 Installing the latest builds for %d branch(es): %s\n\n", len(branches), strings.Join(branches, ", "))
+
+		hasErrors := false
+		for _, b := range branches {
+			if !carbonOnly {
+				if pinned := steamcmd.PinnedBuildID(b); pinned != "" {
+					console.Printf("⏸️  Rust branch '%s' is pinned to build %s, skipping\n", b, pinned)
+				} else {
+					console.Printf("📦 Installing Rust for branch '%s'...\n", b)
+					if err := installRustBranch(b, notifier); err != nil {
+						fmt.Fprintf(os.Stderr, "   ❌ Error installing Rust: %v\n", err)
+						hasErrors = true
+					} else {
+						console.Printf("   ✓ Rust branch '%s' installed\n", b)
+					}
+				}
+			}
+
+			if !rustOnly {
+				for _, fw := range framework.ForBranch(cfg.Servers, b) {
+					console.Printf("📦 Installing %s for branch '%s'...\n", fw.Name(), b)
+					if err := installFramework(fw, b, notifier); err != nil {
+						fmt.Fprintf(os.Stderr, "   ❌ Error installing %s: %v\n", fw.Name(), err)
+						hasErrors = true
+					} else {
+						console.Printf("   ✓ %s for branch '%s' installed\n", fw.Name(), b)
+					}
+				}
+			}
+		}
+		console.Println()
+
+		if hasErrors {
+			console.Println("⚠️  Update completed with errors")
+			os.Exit(1)
+		}
+
+		console.Println("✓ All installs complete")
+		console.Println("\nℹ️  To sync these updates to your servers, run: wipe sync <server-names>")
+	},
+}
+
+// updateNowBranches returns the branches given in args, or else every branch the servers use
+// (main when none set one), in order and without duplicates
+func updateNowBranches(servers []config.Server, args []string) []string {
+	var branches []string
+	seen := make(map[string]bool)
+	add := func(branch string) {
+		if branch != "" && !seen[branch] {
+			seen[branch] = true
+			branches = append(branches, branch)
+		}
+	}
+
+	if len(args) > 0 {
+		for _, branch := range args {
+			add(branch)
+		}
+		return branches
+	}
+
+	for _, server := range servers {
+		add(server.Branch)
+	}
+	sort.Strings(branches)
+	if len(branches) == 0 {
+		branches = []string{"main"}
+	}
+	return branches
+}
+
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Show the latest server health probe results",
+	Long: `Shows the results of the daemon's most recent healthcheck.sh probes.
+
+Health probes are opt-in:
+  wipe config set --health-check-interval 60
+
+The daemon then creates /opt/wiped/healthcheck.sh (edit it to match your setup)
+and runs it for each server on that interval.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		results, err := executor.ReadHealthStatus()
+		if os.IsNotExist(err) {
+			console.Println("No health data yet. Enable probes with: wipe config set --health-check-interval 60")
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading health status: %v\n", err)
+			os.Exit(1)
+		}
+
+		console.Println("Server health:")
+		for _, h := range results {
+			lastHealthy := "never"
+			if !h.LastHealthy.IsZero() {
+				lastHealthy = h.LastHealthy.Local().Format("Mon Jan 02 15:04:05 MST")
+			}
+			if h.Healthy {
+				console.Printf("  ✓ %s: healthy (checked %s)\n", h.Name, h.LastChecked.Local().Format("15:04:05"))
+			} else {
+				console.Printf("  ✗ %s: unhealthy (last healthy: %s)\n", h.Name, lastHealthy)
+				if h.Error != "" {
+					console.Printf("      %s\n", h.Error)
+				}
+			}
+		}
+	},
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check every server's calendar and configuration",
+	Long: `Fetches each server's calendar and checks its configuration before you trust the daemon.
+
+For each server it reports:
+  FAIL  the calendar can't be fetched or parsed
+  WARN  the server path doesn't exist, or its branch isn't installed in /opt/rust
+  OK    everything checks out
+
+It also counts the restart and wipe events found in the lookahead window.
+Exits non-zero if any server fails, so it can gate a deploy.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(cfg.Servers) == 0 {
+			console.Println("No servers configured.")
+			return
+		}
+
+		failed := 0
+		console.Printf("%-20s %-6s %s\n", "SERVER", "STATUS", "DETAILS")
+		for _, server := range cfg.Servers {
+			status := "OK"
+			var details []string
+
+			cals, err := calendar.FetchCalendarsWithAuth(server.AllCalendarURLs(), server.CalendarAuthHeader, server.CalendarAuthToken)
+			if err == nil {
+				var events []calendar.Event
+				now := time.Now()
+				events, err = calendar.GetEventsInCalendars(cals, now, now.Add(time.Duration(cfg.LookaheadHours)*time.Hour), server.MatchPatterns)
+				if err == nil {
+					restarts, wipes := 0, 0
+					for _, event := range events {
+						if event.Type.IsWipe() {
+							wipes++
+						} else {
+							restarts++
+						}
+					}
+					details = append(details, fmt.Sprintf("%d restart(s), %d wipe(s) in next %dh", restarts, wipes, cfg.LookaheadHours))
+				}
+			}
+			if err != nil {
+				status = "FAIL"
+				details = append(details, fmt.Sprintf("calendar: %v", err))
+			}
+
+			if _, err := os.Stat(server.Path); err != nil {
+				if status == "OK" {
+					status = "WARN"
+				}
+				details = append(details, fmt.Sprintf("path %s does not exist", server.Path))
+			}
+			if !steamcmd.IsBranchInstalled(server.Branch) {
+				if status == "OK" {
+					status = "WARN"
+				}
+				details = append(details, fmt.Sprintf("branch '%s' not installed in %s", server.Branch, steamcmd.RustInstallBase))
+			}
+
+			if status == "FAIL" {
+				failed++
+			}
+			console.Printf("%-20s %-6s %s\n", server.Name, status, strings.Join(details, "; "))
+		}
+
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "\n✗ %d of %d server(s) failed validation\n", failed, len(cfg.Servers))
+			os.Exit(1)
+		}
+		console.Printf("\n✓ All %d server(s) passed validation\n", len(cfg.Servers))
+	},
+}
+
+var installServiceCmd = &cobra.Command{
+	Use:   "install-service",
+	Short: "Install the wiped systemd unit",
+	Long: `Writes the wiped@.service template unit, pointing at the installed wiped
+binary, and reloads systemd. The unit is then enabled and started per user as
+wiped@<user>.service. With --profile the unit runs the daemon against that
+profile's config file; otherwise the daemon follows 'wipe profile use'.
+
+By default the unit is installed for the system manager in /etc/systemd/system,
+which needs sudo. With --user it is installed for your own systemd manager in
+~/.config/systemd/user instead; /opt/rust, /opt/carbon (or /opt/oxide) and /opt/wiped must then
+already be writable by you.
+
+Example:
+  sudo wipe install-service
+  wipe install-service --user`,
+	Run: func(cmd *cobra.Command, args []string) {
+		userUnit, _ := cmd.Flags().GetBool("user")
+
+		username, err := service.CurrentUser()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		home := ""
+		if userUnit {
+			if home, err = os.UserHomeDir(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error getting home directory: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		opts := service.UnitOptions{
+			DaemonPath: service.DaemonPath(),
+			User:       userUnit,
+		}
+		if cmd.Flags().Changed("profile") {
+			opts.ConfigPath = config.ConfigFileUsed()
+		}
+		path, err := service.Install(service.UnitDir(userUnit, home), opts, service.NewSystemctl(userUnit))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error installing service: %v\n", err)
+			if errors.Is(err, os.ErrPermission) {
+				fmt.Fprintf(os.Stderr, "Run with sudo, or use --user to install for your own systemd manager\n")
+			}
+			os.Exit(1)
+		}
+
+		console.Printf("✓ Installed %s (runs %s)\n", path, opts.DaemonPath)
+		if opts.ConfigPath != "" {
+			console.Printf("  Config: %s\n", opts.ConfigPath)
+		}
+		console.Println()
+		console.Println("To enable and start the daemon, run:")
+		if userUnit {
+			console.Printf("  systemctl --user enable --now %s\n", service.InstanceName(username))
+		} else {
+			console.Printf("  sudo systemctl enable --now %s\n", service.InstanceName(username))
+		}
+	},
+}
+
+var uninstallServiceCmd = &cobra.Command{
+	Use:   "uninstall-service",
+	Short: "Stop the daemon and remove the wiped systemd unit",
+	Long: `Stops and disables wiped@<user>.service, removes the wiped@.service unit
+written by 'wipe install-service' and reloads systemd. Use --user for a unit
+installed with 'wipe install-service --user'.
+
+Example:
+  sudo wipe uninstall-service
+  wipe uninstall-service --user`,
+	Run: func(cmd *cobra.Command, args []string) {
+		userUnit, _ := cmd.Flags().GetBool("user")
+
+		username, err := service.CurrentUser()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		home := ""
+		if userUnit {
+			if home, err = os.UserHomeDir(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error getting home directory: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		dir := service.UnitDir(userUnit, home)
+		if err := service.Uninstall(dir, service.InstanceName(username), service.NewSystemctl(userUnit)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error uninstalling service: %v\n", err)
+			os.Exit(1)
+		}
+		console.Printf("✓ Removed %s\n", filepath.Join(dir, service.UnitFile))
+	},
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the daemon's logs from the systemd journal",
+	Long: `Shows the journal of wiped@<user>.service (the user is the one who invoked
+sudo, or you), like 'journalctl -u wiped@$USER.service'. Use --user for a unit
+installed with 'wipe install-service --user'.
+
+Example:
+  wipe logs
+  wipe logs --follow
+  wipe logs --lines 500`,
+	Run: func(cmd *cobra.Command, args []string) {
+		follow, _ := cmd.Flags().GetBool("follow")
+		lines, _ := cmd.Flags().GetInt("lines")
+		userUnit, _ := cmd.Flags().GetBool("user")
+
+		username, err := service.CurrentUser()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		instance := service.InstanceName(username)
+		err = logsJournal.Stream(service.JournalArgs(instance, lines, follow, userUnit), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		if errors.Is(err, service.ErrJournalctlNotFound) {
+			fmt.Fprintf(os.Stderr, "Error: journalctl not found. The daemon logs to stdout and stderr; check wherever %s's output is captured.\n", instance)
+			os.Exit(1)
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading logs: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// logsJournal runs journalctl for 'wipe logs' (replaced in tests)
+var logsJournal = service.NewJournal()
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment the daemon needs",
+	Long: `Checks the tools, scripts and directories the daemon depends on:
+  - rsync and tar on PATH, and steamcmd.sh in /opt/rust/steamcmd
+  - the management scripts in /opt/wiped exist and are executable
+  - /opt/rust, /opt/carbon and /opt/wiped are writable
+  - unzip on PATH and /opt/oxide writable, when a server runs Oxide
+  - the config file in use
+
+Each check prints PASS or FAIL with a hint on how to fix it.
+Exits non-zero if any check fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		failed := 0
+		check := func(name string, err error, hint string) {
+			if err == nil {
+				console.Printf("PASS  %s\n", name)
+				return
+			}
+			failed++
+			console.Printf("FAIL  %s: %v\n", name, err)
+			console.Printf("      → %s\n", hint)
+		}
+
+		// Oxide is only checked for when a server runs it
+		usesOxide := false
+		if cfg, err := config.GetConfig(); err == nil {
+			_, usesOxide = framework.Branches(cfg.Servers)[config.FrameworkOxide]
+		}
+
+		// Tools
+		tools := []string{"rsync", "tar"}
+		if usesOxide {
+			tools = append(tools, "unzip")
+		}
+		for _, tool := range tools {
+			_, err := exec.LookPath(tool)
+			check(tool+" on PATH", err, fmt.Sprintf("install it, e.g. sudo apt install %s", tool))
+		}
+		steamcmdPath := filepath.Join(steamcmd.SteamCMDBase, "steamcmd.sh")
+		check(steamcmdPath, checkExecutable(steamcmdPath), "start the daemon once (it installs steamcmd) or run: wipe update-source")
+
+		// Management scripts
+		for _, script := range []string{
+			executor.StopServersScriptPath,
+			executor.StartServersScriptPath,
+			executor.GenerateMapsScriptPath,
+			executor.HookScriptPath,
+			executor.PostStartHookScriptPath,
+		} {
+			check(script, checkExecutable(script), "run 'wipe reset-scripts' to recreate it, or chmod +x it")
+		}
+
+		// Directories
+		dirs := []string{steamcmd.RustInstallBase, carbon.CarbonBase, filepath.Dir(executor.HookScriptPath)}
+		if usesOxide {
+			dirs = append(dirs, oxide.OxideBase)
+		}
+		for _, dir := range dirs {
+			check(dir+" writable", checkWritable(dir), fmt.Sprintf("sudo mkdir -p %s && sudo chown $USER %s", dir, dir))
+		}
+
+		// Config
+		configPath := config.ConfigFileUsed()
+		if configPath == "" {
+			// The file was only just created with defaults, so viper hasn't read it yet
+			if dir, err := config.GetConfigDir(); err == nil {
+				configPath = filepath.Join(dir, config.ConfigFile)
+			}
+		}
+		_, err := config.GetConfig()
+		if err == nil {
+			_, err = os.Stat(configPath)
+		}
+		check("config file "+configPath, err, "run any 'wipe' command to create it, or fix the YAML syntax")
+
+		if failed > 0 {
+			console.Printf("\n%d check(s) failed\n", failed)
+			os.Exit(1)
+		}
+		console.Println("\n✓ All checks passed")
+	},
+}
+
+// checkExecutable returns an error if path is missing or not executable
+func checkExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("is a directory")
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("not executable")
+	}
+	return nil
+}
+
+// checkWritable returns an error if dir is missing or files can't be created in it
+func checkWritable(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("not a directory")
+	}
+	f, err := os.CreateTemp(dir, ".wipe-doctor-*")
+	if err != nil {
+		return fmt.Errorf("not writable: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+var nextCmd = &cobra.Command{
+	Use:   "next [server-names...]",
+	Short: "Show the next restart or wipe for each server",
+	Long: `Fetches each server's calendar and shows its soonest restart or wipe within the lookahead window.
+
+With no arguments every configured server is shown. This reads the calendars
+directly, so it works whether or not the daemon is running.
+
+Example:
+  wipe next
+  wipe next us-weekly eu-monthly`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		servers := cfg.Servers
+		if len(args) > 0 {
+			servers = nil
+			for _, name := range args {
+				found := false
+				for _, server := range cfg.Servers {
+					if server.Name == name {
+						servers = append(servers, server)
+						found = true
+						break
+					}
+				}
+				if !found {
+					fmt.Fprintf(os.Stderr, "Error: server '%s' not found\n", name)
+					os.Exit(1)
+				}
+			}
+		}
+
+		if len(servers) == 0 {
+			console.Println("No servers configured.")
+			return
+		}
+
+		failed := false
+		for _, server := range servers {
+			cals, err := calendar.FetchCalendarsWithAuth(server.AllCalendarURLs(), server.CalendarAuthHeader, server.CalendarAuthToken)
+			if err != nil {
+				console.Printf("%-20s error: %v\n", server.Name, err)
+				failed = true
+				continue
+			}
+			now := time.Now()
+			events, err := calendar.GetEventsInCalendars(cals, now, now.Add(time.Duration(cfg.LookaheadHours)*time.Hour), server.MatchPatterns)
+			if err != nil {
+				console.Printf("%-20s error: %v\n", server.Name, err)
+				failed = true
+				continue
+			}
+			if len(events) == 0 {
+				console.Printf("%-20s no upcoming events in the next %dh\n", server.Name, cfg.LookaheadHours)
+				continue
+			}
+
+			next := events[0]
+			for _, event := range events[1:] {
+				if event.StartTime.Before(next.StartTime) {
+					next = event
+				}
+			}
+			console.Printf("%-20s %-15s %s (%s)\n", server.Name, next.Type, next.StartTime.Local().Format("Mon Jan 02 15:04 MST"), formatUntil(time.Until(next.StartTime)))
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+var previewCmd = &cobra.Command{
+	Use:   "preview <server>",
+	Short: "List every event the parser finds in a server's calendars",
+	Long: `Fetches a server's calendars and prints every restart and wipe the parser
+produces within the window, with recurring events (RRULE, RDATE, EXDATE)
+expanded, sorted by time. Each start time is shown in local time and UTC.
+
+Use it to check an RRULE or timezone before relying on it. This reads the
+calendars directly, so it works whether or not the daemon is running.
+
+Example:
+  wipe preview us-weekly
+  wipe preview us-weekly --hours 720   # The next 30 days`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		hours, _ := cmd.Flags().GetInt("hours")
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
 
-			if response != "yes" && response != "y" {
-				fmt.Println("❌ Update cancelled")
-				os.Exit(0)
+		var server *config.Server
+		for i := range cfg.Servers {
+			if cfg.Servers[i].Name == args[0] || cfg.Servers[i].Path == args[0] {
+				server = &cfg.Servers[i]
+				break
 			}
 		}
+		if server == nil {
+			fmt.Fprintf(os.Stderr, "Error: server '%s' not found\n", args[0])
+			os.Exit(1)
+		}
+		if hours <= 0 {
+			hours = server.GetLookaheadHours(cfg.LookaheadHours)
+		}
 
-		// Update servers
-		fmt.Printf("\n🔄 Updating %d server(s)...\n\n", len(serversToSync))
-		if err := executor.SyncServers(serversToSync); err != nil {
-			fmt.Fprintf(os.Stderr, "\n❌ Update failed: %v\n", err)
+		cals, err := calendar.FetchCalendarsWithAuth(server.AllCalendarURLs(), server.CalendarAuthHeader, server.CalendarAuthToken)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching calendar: %v\n", err)
+			os.Exit(1)
+		}
+		now := time.Now()
+		events, err := calendar.GetEventsInCalendars(cals, now, now.Add(time.Duration(hours)*time.Hour), server.MatchPatterns)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing events: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("\n✓ All servers updated successfully")
+		printEventPreview(cmd.OutOrStdout(), server.Name, hours, events)
 	},
 }
 
-var resetScriptsCmd = &cobra.Command{
-	Use:   "reset-scripts",
-	Short: "Reset management scripts to defaults",
-	Long: `Deletes and regenerates the management scripts in /opt/wiped:
-  - stop-servers.sh
-  - start-servers.sh
-  - pre-start-hook.sh
-  - generate-maps.sh
+// printEventPreview prints events sorted by start time, in local time and UTC
+func printEventPreview(w io.Writer, serverName string, hours int, events []calendar.Event) {
+	if len(events) == 0 {
+		fmt.Fprintf(w, "No events for %s in the next %dh\n", serverName, hours)
+		return
+	}
 
-WARNING: This will overwrite any customizations you've made to these scripts.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		force, _ := cmd.Flags().GetBool("force")
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+
+	fmt.Fprintf(w, "Events for %s in the next %dh (%d):\n\n", serverName, hours, len(events))
+	fmt.Fprintf(w, "%-15s %-22s %-22s %s\n", "TYPE", "LOCAL", "UTC", "SUMMARY")
+	for _, event := range events {
+		fmt.Fprintf(w, "%-15s %-22s %-22s %s\n", event.Type,
+			event.StartTime.Local().Format("Mon Jan 02 15:04 MST"),
+			event.StartTime.UTC().Format("2006-01-02 15:04 MST"),
+			event.Summary)
+	}
+}
 
-		if !force {
-			fmt.Println("⚠️  WARNING: This will delete and regenerate the following scripts:")
-			fmt.Println("   - /opt/wiped/stop-servers.sh")
-			fmt.Println("   - /opt/wiped/start-servers.sh")
-			fmt.Println("   - /opt/wiped/pre-start-hook.sh")
-			fmt.Println("   - /opt/wiped/generate-maps.sh")
-			fmt.Println()
-			fmt.Println("Any customizations you've made will be LOST!")
-			fmt.Println()
-			fmt.Print("Are you sure you want to continue? (yes/no): ")
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recently executed restarts and wipes",
+	Long: `Shows the most recent batches the daemon executed, newest last, with the servers,
+restart and wipe counts, outcome and duration of each.
 
-			var response string
-			fmt.Scanln(&response)
+The history is read from history_file (default: history.jsonl in the config directory).
+Use -o json to print the records as JSON.
 
-			if response != "yes" {
-				fmt.Println("❌ Operation cancelled")
-				os.Exit(0)
-			}
+Example:
+  wipe history
+  wipe history --limit 50`,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		output := outputFormat(cmd)
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
 		}
 
-		fmt.Println("🔄 Resetting scripts...")
+		historyFile, err := cfg.GetHistoryFile()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error locating history file: %v\n", err)
+			os.Exit(1)
+		}
 
-		scriptsRemoved := 0
-		scriptsToRemove := []string{
-			executor.HookScriptPath,
-			executor.StopServersScriptPath,
-			executor.StartServersScriptPath,
-			executor.GenerateMapsScriptPath,
+		records, err := executor.ReadHistory(historyFile, limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+			os.Exit(1)
 		}
 
-		for _, script := range scriptsToRemove {
-			if _, err := os.Stat(script); err == nil {
-				if err := os.Remove(script); err != nil {
-					fmt.Fprintf(os.Stderr, "Error removing %s: %v\n", script, err)
-					os.Exit(1)
-				}
-				scriptsRemoved++
-				fmt.Printf("  ✓ Removed %s\n", filepath.Base(script))
+		if output == "json" {
+			if records == nil {
+				records = []executor.HistoryRecord{}
 			}
+			printJSON(cmd, records)
+			return
 		}
 
-		if scriptsRemoved > 0 {
-			fmt.Printf("\n✓ Removed %d script(s)\n", scriptsRemoved)
-		} else {
-			fmt.Println("ℹ️  No scripts found to remove")
+		if len(records) == 0 {
+			console.Printf("No executed events recorded in %s\n", historyFile)
+			return
 		}
 
-		// Regenerate scripts immediately
-		fmt.Println("\n🔄 Regenerating scripts...")
+		for _, record := range records {
+			status := "✓ success"
+			if !record.Success {
+				status = "✗ failed "
+			}
+			duration := time.Duration(record.DurationSeconds * float64(time.Second)).Round(time.Second)
+			console.Printf("%s  %s  %d restart(s), %d wipe(s)  %-8v  %s\n",
+				record.Time.Local().Format("2006-01-02 15:04:05 MST"), status,
+				record.Restarts, record.Wipes, duration, strings.Join(record.Servers, ", "))
+			if record.Error != "" {
+				console.Printf("    error: %s\n", record.Error)
+			}
+		}
+	},
+}
 
-		if err := executor.EnsureHookScript(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating pre-start-hook.sh: %v\n", err)
+var testNotifyCmd = &cobra.Command{
+	Use:   "test-notify",
+	Short: "Send a test notification through the configured Discord or Slack webhook",
+	Long: `Sends a sample success notification through the configured notifier
+(Discord or Slack), including the configured mentions (whatever discord_mention_on says).
+
+Use it to check the webhook URL and mention IDs before a real event fires.
+Exits non-zero if no webhook is configured or the service rejects the message.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println("  ✓ Created pre-start-hook.sh")
 
-		if err := executor.EnsureWipeScripts(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating management scripts: %v\n", err)
+		title := "🧪 Test Notification"
+		description := "This is a test notification from `wipe test-notify`. If you can read this, the webhook works."
+
+		service := "Discord"
+		var statusCode int
+		if cfg.Notifier == config.NotifierSlack {
+			service = "Slack"
+			if cfg.SlackWebhook == "" {
+				fmt.Fprintln(os.Stderr, "Error: no Slack webhook configured. Set one with: wipe config set --slack-webhook <url>")
+				os.Exit(1)
+			}
+			printMentions(slack.MentionContent(cfg.SlackMentionUsers))
+			err = slack.SendNotification(cfg.SlackWebhook, title, description, slack.ColorSuccess, cfg.SlackMentionUsers)
+			var statusErr *slack.StatusError
+			if errors.As(err, &statusErr) {
+				statusCode = statusErr.StatusCode
+			}
+		} else {
+			if cfg.DiscordWebhook == "" {
+				fmt.Fprintln(os.Stderr, "Error: no Discord webhook configured. Set one with: wipe config set --discord-webhook <url>")
+				os.Exit(1)
+			}
+			printMentions(discord.MentionContent(cfg.DiscordMentionUsers, cfg.DiscordMentionRoles))
+			err = discord.SendNotificationWithMentions(cfg.DiscordWebhook, title, description, discord.ColorSuccess, cfg.DiscordMentionUsers, cfg.DiscordMentionRoles)
+			var statusErr *discord.StatusError
+			if errors.As(err, &statusErr) {
+				statusCode = statusErr.StatusCode
+			}
+		}
+
+		if err != nil {
+			if statusCode != 0 {
+				fmt.Fprintf(os.Stderr, "Error: %s responded with HTTP %d (%s)\n", service, statusCode, http.StatusText(statusCode))
+			}
+			fmt.Fprintf(os.Stderr, "Error sending test notification: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println("  ✓ Created stop-servers.sh")
-		fmt.Println("  ✓ Created start-servers.sh")
-		fmt.Println("  ✓ Created generate-maps.sh")
 
-		fmt.Println("\n✓ All scripts reset to defaults")
+		console.Printf("✓ Test notification sent (%s responded with HTTP 2xx)\n", service)
 	},
 }
 
-var mentionCmd = &cobra.Command{
-	Use:   "mention",
-	Short: "Manage Discord mention lists",
-	Long:  `Add or remove Discord user and role IDs to mention in notifications.`,
+// printMentions shows the mention text a test notification will carry
+func printMentions(mentions string) {
+	if mentions == "" {
+		console.Println("Mentions: (none configured)")
+	} else {
+		console.Printf("Mentions: %s\n", mentions)
+	}
 }
 
-var mentionAddUserCmd = &cobra.Command{
-	Use:   "add-user [user-id]",
-	Short: "Add a Discord user ID to mention in notifications",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		userID := args[0]
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the daemon's upcoming scheduled events",
+	Long: `Shows the events the running daemon has scheduled, grouped by time.
 
-		if err := config.AddDiscordMentionUser(userID); err != nil {
-			fmt.Fprintf(os.Stderr, "Error adding user: %v\n", err)
+The daemon writes its schedule to /opt/wiped/status.json after each calendar update.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		status, err := scheduler.ReadStatus()
+		if os.IsNotExist(err) {
+			console.Println("Daemon not running (no status file). Start it with: sudo systemctl start wiped@$USER.service")
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading status: %v\n", err)
 			os.Exit(1)
 		}
+		if status.PID > 0 && syscall.Kill(status.PID, 0) == syscall.ESRCH {
+			console.Printf("Daemon not running (last seen %s)\n", status.UpdatedAt.Local().Format("Mon Jan 02 15:04:05 MST"))
+			return
+		}
 
-		fmt.Printf("✓ Added Discord user ID: %s\n", userID)
-		fmt.Printf("  This user will be mentioned in Discord notifications as <@%s>\n", userID)
+		console.Printf("Daemon running (pid %d, schedule updated %s)\n", status.PID, status.UpdatedAt.Local().Format("15:04:05"))
+		if len(status.Events) == 0 {
+			console.Println("\nNo upcoming events")
+			return
+		}
+
+		var current time.Time
+		for _, event := range status.Events {
+			scheduled := event.Scheduled.Truncate(time.Minute)
+			if !scheduled.Equal(current) {
+				current = scheduled
+				console.Printf("\n%s (%s):\n", current.Local().Format("Mon Jan 02 15:04 MST"), formatUntil(time.Until(current)))
+			}
+			console.Printf("  - %s: %s\n", event.Server, event.Type)
+		}
 	},
 }
 
-var mentionRemoveUserCmd = &cobra.Command{
-	Use:   "remove-user [user-id]",
-	Short: "Remove a Discord user ID from mentions",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		userID := args[0]
+// formatUntil renders a duration as a short human "in X" phrase
+func formatUntil(d time.Duration) string {
+	if d < time.Minute {
+		return "now"
+	}
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("in %dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("in %dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("in %d minutes", minutes)
+	}
+}
 
-		if err := config.RemoveDiscordMentionUser(userID); err != nil {
-			fmt.Fprintf(os.Stderr, "Error removing user: %v\n", err)
+var confirmCmd = &cobra.Command{
+	Use:   "confirm",
+	Short: "Confirm a pending wipe",
+	Long: `Approves the wipe batch the daemon is currently holding for confirmation.
+
+Only used when wipe_confirmation_minutes is set:
+  wipe config set --wipe-confirmation-minutes 15
+
+If no one confirms within that window, the batch is aborted and no servers are stopped.
+
+Example:
+  wipe confirm`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := executor.ConfirmWipe(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error confirming wipe: %v\n", err)
 			os.Exit(1)
 		}
-
-		fmt.Printf("✓ Removed Discord user ID: %s\n", userID)
+		console.Println("✓ Wipe confirmed - the daemon will proceed within a few seconds")
 	},
 }
 
-var mentionAddRoleCmd = &cobra.Command{
-	Use:   "add-role [role-id]",
-	Short: "Add a Discord role ID to mention in notifications",
-	Args:  cobra.ExactArgs(1),
+var restoreCmd = &cobra.Command{
+	Use:   "restore <server-name>",
+	Short: "Restore a server's files from its most recent wipe backup",
+	Long: `Moves the files from the server's most recent wipe backup back into its identity folder,
+overwriting files with the same name.
+
+Backups are only kept when safe_wipe is enabled:
+  wipe config set --safe-wipe
+
+Stop the server before restoring. The restored backup is removed, so running
+restore again brings back the backup before it.
+
+Example:
+  wipe restore us-weekly
+  wipe restore us-weekly --force  # Skip confirmation prompt`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		roleID := args[0]
+		force, _ := cmd.Flags().GetBool("force")
 
-		if err := config.AddDiscordMentionRole(roleID); err != nil {
-			fmt.Fprintf(os.Stderr, "Error adding role: %v\n", err)
+		cfg, err := config.GetConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("✓ Added Discord role ID: %s\n", roleID)
-		fmt.Printf("  This role will be mentioned in Discord notifications as <@&%s>\n", roleID)
-	},
-}
+		var server *config.Server
+		for i := range cfg.Servers {
+			if cfg.Servers[i].Name == args[0] {
+				server = &cfg.Servers[i]
+				break
+			}
+		}
+		if server == nil {
+			fmt.Fprintf(os.Stderr, "Error: server '%s' not found\n", args[0])
+			os.Exit(1)
+		}
 
-var mentionRemoveRoleCmd = &cobra.Command{
-	Use:   "remove-role [role-id]",
-	Short: "Remove a Discord role ID from mentions",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		roleID := args[0]
+		if !force {
+			console.Printf("⚠️  WARNING: This overwrites the current map and save files of %s (%s).\n", server.Name, server.Path)
+			console.Println("   The server should be STOPPED before restoring.")
+			fmt.Print("\nDo you want to continue? (yes/no): ")
 
-		if err := config.RemoveDiscordMentionRole(roleID); err != nil {
-			fmt.Fprintf(os.Stderr, "Error removing role: %v\n", err)
+			var response string
+			fmt.Scanln(&response)
+
+			if response != "yes" && response != "y" {
+				console.Println("❌ Restore cancelled")
+				os.Exit(0)
+			}
+		}
+
+		backup, restored, err := executor.RestoreWipeBackup(*server)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Restore failed: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("✓ Removed Discord role ID: %s\n", roleID)
+		for _, file := range restored {
+			console.Printf("  Restored: %s\n", file)
+		}
+		console.Printf("\n✓ Restored %d file(s) for %s from %s\n", len(restored), server.Name, filepath.Base(backup))
 	},
 }
 
-var updateSourceCmd = &cobra.Command{
-	Use:   "update-source",
-	Short: "Download latest Rust and Carbon versions",
-	Long: `Manually download and install the latest Rust server files and Carbon mod.
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Restore the previous Rust install for a branch",
+	Long: `Swaps /opt/rust/<branch> with the /opt/rust/<branch>.prev snapshot kept by the last update.
 
-This command updates the source installations in /opt/rust and /opt/carbon.
-It does NOT sync these files to your servers - use 'wipe sync' for that.
+Snapshots are only kept when keep_previous_install is enabled:
+  wipe config set --keep-previous-install
 
-By default, updates all branches configured in your servers. Use --branch to
-update a specific branch only.
+The replaced install becomes the new snapshot, so running rollback again undoes it.
+This does NOT sync the restored files to your servers - use 'wipe sync' for that.
 
-Examples:
-  wipe update-source                    # Update all configured branches
-  wipe update-source --branch main      # Update only the main branch
-  wipe update-source --rust-only        # Only update Rust (skip Carbon)
-  wipe update-source --carbon-only      # Only update Carbon (skip Rust)`,
+So the daemon's next update check doesn't reinstall the build you rolled back from,
+the branch's servers are pinned to the restored build (rust_build_id). To follow
+the latest build again:
+  wipe update <server> --rust-build-id ""
+
+Example:
+  wipe rollback --branch main`,
 	Run: func(cmd *cobra.Command, args []string) {
 		branch, _ := cmd.Flags().GetString("branch")
-		rustOnly, _ := cmd.Flags().GetBool("rust-only")
-		carbonOnly, _ := cmd.Flags().GetBool("carbon-only")
 
 		// Initialize logger
 		log.SetOutput(os.Stdout)
 		log.SetFlags(log.LstdFlags)
 
-		// Get config for webhook and branches
 		cfg, err := config.GetConfig()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Determine which branches to update
-		branches := make(map[string]bool)
-		if branch != "" {
-			branches[branch] = true
-		} else {
-			// Collect unique branches from configured servers
-			for _, server := range cfg.Servers {
-				if server.Branch != "" {
-					branches[server.Branch] = true
-				}
-			}
-			// Default to main if no servers configured
-			if len(branches) == 0 {
-				branches["main"] = true
-			}
-		}
-
-		webhookURL := cfg.DiscordWebhook
-
-		fmt.Printf("🔄 Updating source installations for %d branch(es)...\n\n", len(branches))
-
-		hasErrors := false
-
-		// Update Rust for each branch
-		if !carbonOnly {
-			for b := range branches {
-				fmt.Printf("📦 Checking Rust updates for branch '%s'...\n", b)
-				hasUpdate, buildID, err := steamcmd.CheckForUpdates(b, webhookURL)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "   ❌ Error checking Rust updates: %v\n", err)
-					hasErrors = true
-					continue
-				}
-
-				if hasUpdate {
-					fmt.Printf("   ⬇️  Update available (build: %s), downloading...\n", buildID)
-					if err := steamcmd.InstallRustBranch(b, webhookURL); err != nil {
-						fmt.Fprintf(os.Stderr, "   ❌ Error installing Rust: %v\n", err)
-						hasErrors = true
-					} else {
-						fmt.Printf("   ✓ Rust branch '%s' updated to build %s\n", b, buildID)
-					}
-				} else if buildID != "" {
-					fmt.Printf("   ✓ Rust branch '%s' is up to date (build: %s)\n", b, buildID)
-				} else {
-					fmt.Printf("   ℹ️  Rust branch '%s' not installed yet, installing...\n", b)
-					if err := steamcmd.InstallRustBranch(b, webhookURL); err != nil {
-						fmt.Fprintf(os.Stderr, "   ❌ Error installing Rust: %v\n", err)
-						hasErrors = true
-					} else {
-						fmt.Printf("   ✓ Rust branch '%s' installed\n", b)
-					}
-				}
-			}
-			fmt.Println()
+		if err := steamcmd.RollbackRustBranch(branch, notify.New(cfg)); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Rollback failed: %v\n", err)
+			os.Exit(1)
 		}
 
-		// Update Carbon for each branch
-		if !rustOnly {
-			for b := range branches {
-				fmt.Printf("📦 Checking Carbon updates for branch '%s'...\n", b)
-				hasUpdate, version, err := carbon.CheckForCarbonUpdates(b, webhookURL)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "   ❌ Error checking Carbon updates: %v\n", err)
-					hasErrors = true
-					continue
-				}
-
-				if hasUpdate {
-					fmt.Printf("   ⬇️  Update available (version: %s), downloading...\n", version)
-					if err := carbon.InstallCarbon(b, webhookURL); err != nil {
-						fmt.Fprintf(os.Stderr, "   ❌ Error installing Carbon: %v\n", err)
-						hasErrors = true
-					} else {
-						fmt.Printf("   ✓ Carbon for branch '%s' updated to version %s\n", b, version)
-					}
-				} else if version != "" {
-					fmt.Printf("   ✓ Carbon for branch '%s' is up to date (version: %s)\n", b, version)
-				} else {
-					fmt.Printf("   ℹ️  Carbon for branch '%s' not installed yet, installing...\n", b)
-					if err := carbon.InstallCarbon(b, webhookURL); err != nil {
-						fmt.Fprintf(os.Stderr, "   ❌ Error installing Carbon: %v\n", err)
-						hasErrors = true
-					} else {
-						fmt.Printf("   ✓ Carbon for branch '%s' installed\n", b)
-					}
-				}
-			}
-			fmt.Println()
-		}
+		console.Printf("\n✓ Rust branch '%s' rolled back\n", branch)
 
-		if hasErrors {
-			fmt.Println("⚠️  Update completed with errors")
-			os.Exit(1)
+		// Hold the branch at the restored build, or the daemon's next update check reinstalls the latest
+		buildID := steamcmd.InstalledBuildID(branch)
+		if buildID == "unknown" {
+			fmt.Fprintf(os.Stderr, "⚠️  WARNING: The restored install has no build ID, so it can't be pinned; the daemon will update it on its next check\n")
+		} else if names, err := config.SetBranchRustBuildID(branch, buildID); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  WARNING: Failed to pin branch '%s' to build %s, the daemon will update it on its next check: %v\n", branch, buildID, err)
+		} else if len(names) > 0 {
+			console.Printf("📌 Pinned %s to build %s (undo with: wipe update <server> --rust-build-id \"\")\n", strings.Join(names, ", "), buildID)
 		}
-
-		fmt.Println("✓ All source updates complete")
-		fmt.Println("\nℹ️  To sync these updates to your servers, run: wipe sync <server-names>")
+		console.Println("\nℹ️  To sync the restored files to your servers, run: wipe sync <server-names>")
 	},
 }
 
@@ -749,34 +3823,131 @@ func main() {
 }
 
 func init() {
-	// Initialize config
-	config.InitConfig()
+	// Config is initialized in rootCmd's PersistentPreRun once --profile is parsed
+	rootCmd.PersistentFlags().String("profile", "", "Config profile to use (default: set by 'wipe profile use')")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Only print errors (and requested JSON output)")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Also print how the config was resolved")
 
 	// Add flags for add command
+	addCmd.Flags().BoolP("interactive", "i", false, "Prompt for each setting (the default when no flags are given)")
 	addCmd.Flags().StringP("path", "p", "", "Full path to Rust server (required)")
+	addCmd.Flags().String("name", "", "Server name (default: basename of path)")
 	addCmd.Flags().StringP("calendar", "c", "", "Google Calendar .ics URL (required)")
+	addCmd.Flags().StringArray("extra-calendar", nil, "Additional .ics URL whose events are merged in, e.g. a separate wipe calendar (repeatable)")
 	addCmd.Flags().StringP("branch", "b", "main", "Rust server branch (main, staging, etc.)")
+	addCmd.Flags().String("framework", "", "Mod framework: carbon or oxide (default: carbon)")
+	addCmd.Flags().String("carbon-version", "", "Pin the server's branch to this Carbon release instead of auto-updating")
+	addCmd.Flags().String("rust-build-id", "", "Hold the server's branch at this Rust build instead of auto-updating")
 	addCmd.Flags().Bool("wipe-blueprints", false, "Delete blueprints on wipe events")
 	addCmd.Flags().Bool("generate-map", false, "Generate custom maps via generate-maps.sh")
+	addCmd.Flags().String("identity", "", "Rust server identity (default: basename of path)")
+	addCmd.Flags().StringArray("match-pattern", nil, "Regex matching the event keyword in calendar summaries (repeatable)")
+	addCmd.Flags().Bool("skip-validation", false, "Don't fetch the calendar to check it before adding (for offline setups)")
+	addCmd.Flags().String("calendar-auth-header", "", "Header to send the calendar auth token in (default: Authorization)")
+	addCmd.Flags().String("calendar-auth-token", "", "Value of the calendar auth header, e.g. \"Bearer abc123\" (for calendars that need credentials)")
+	addCmd.Flags().String("rcon-address", "", "WebRCON address (host:port) used to warn players in-game during the event delay")
+	addCmd.Flags().String("rcon-password", "", "The server's rcon.password")
+
+	// Add flags for remove command
+	removeCmd.Flags().Bool("all", false, "Remove every server")
+	removeCmd.Flags().BoolP("force", "f", false, "Skip the confirmation prompt")
+
+	// Add flags for config command
+	configCmd.Flags().Bool("explain", false, "Show each setting's effective value, default, and source")
+	configCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+	configCmd.Flags().Bool("show-secrets", false, "Include webhook URLs in JSON output instead of redacting them")
+	listCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+
+	// Add flags for install-service and uninstall-service commands
+	installServiceCmd.Flags().Bool("user", false, "Install for your own systemd manager (~/.config/systemd/user) instead of the system one")
+	uninstallServiceCmd.Flags().Bool("user", false, "Remove the unit installed with --user")
+
+	// Add flags for logs command
+	logsCmd.Flags().BoolP("follow", "f", false, "Keep streaming new log lines")
+	logsCmd.Flags().IntP("lines", "n", 100, "Number of recent lines to show")
+	logsCmd.Flags().Bool("user", false, "Read the unit run by your own systemd manager (installed with --user)")
+
+	// Add flags for preview command
+	previewCmd.Flags().Int("hours", 0, "How far ahead to list events (in hours, default: the server's lookahead)")
+
+	// Add flags for history command
+	historyCmd.Flags().IntP("limit", "n", 20, "Number of recent events to show (0 for all)")
+	historyCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
 
 	// Add flags for config set command
 	configSetCmd.Flags().Int("check-interval", 0, "How often to refresh calendars (in seconds)")
 	configSetCmd.Flags().Int("lookahead-hours", 0, "How far ahead to schedule events (in hours)")
+	configSetCmd.Flags().Int("max-lookahead-hours", 0, "Largest allowed --lookahead-hours (default: 720, 30 days)")
 	configSetCmd.Flags().Int("event-delay", 0, "How long to wait after event time before executing (in seconds)")
 	configSetCmd.Flags().Int("map-generation-hours", 0, "How many hours before a wipe to generate maps")
 	configSetCmd.Flags().String("discord-webhook", "", "Discord webhook URL for notifications (empty to disable)")
+	configSetCmd.Flags().String("notifier", "", "Where notifications are sent: discord or slack")
+	configSetCmd.Flags().String("slack-webhook", "", "Slack incoming webhook URL, used when the notifier is slack (empty to disable)")
+	configSetCmd.Flags().String("event-webhook-url", "", "URL that receives a JSON post when a batch starts, completes or fails (empty to disable)")
+	configSetCmd.Flags().Int("start-stagger", 0, "Seconds to wait between starting each server (0 to start all at once)")
+	configSetCmd.Flags().Int("start-stagger-size", 0, "Servers to start together between each --start-stagger wait (default: 1)")
+	configSetCmd.Flags().Bool("keep-previous-install", false, "Keep the previous Rust install as <branch>.prev for rollback")
+	configSetCmd.Flags().Int("wipe-confirmation-minutes", 0, "Minutes a wipe waits for 'wipe confirm' before aborting (0 to disable)")
+	configSetCmd.Flags().Int("health-check-interval", 0, "Seconds between healthcheck.sh probes of each server (0 to disable)")
+	configSetCmd.Flags().Int("heartbeat-hours", 0, "Hours between daemon heartbeat notifications (0 to disable)")
+	configSetCmd.Flags().Int("min-free-memory-mb", 0, "Free memory in MB required per server before starting (0 to disable)")
+	configSetCmd.Flags().Int("batch-size", 0, "Servers stopped, synced, wiped and started per wave of a batch (0 for the whole batch at once)")
+	configSetCmd.Flags().Int("discord-max-attempts", 0, "Attempts per Discord notification on rate limits or server errors (1 disables retries)")
+	configSetCmd.Flags().Int("http-timeout", 0, "Seconds before calendar, Discord and download requests time out")
+	configSetCmd.Flags().Int("max-concurrent-syncs", 0, "Maximum servers to update with rsync at once")
+	configSetCmd.Flags().Bool("safe-wipe", false, "Move wiped files into a .wipe-backup directory instead of deleting them (allows 'wipe restore')")
+	configSetCmd.Flags().Int("wipe-backup-retention", 0, "Wipe backups kept per server when safe wipe is enabled")
+	configSetCmd.Flags().Int("min-free-disk-gb", 0, "Free space (GB) required in /opt/rust before installing Rust (0 = disabled)")
+	configSetCmd.Flags().Int("config-reload-interval", 0, "How often the daemon reloads the config file (seconds)")
+	configSetCmd.Flags().Int("update-check-interval", 0, "How often the daemon checks for Rust and Carbon updates (seconds)")
+	configSetCmd.Flags().String("update-check-source", "", "Where Rust build IDs are checked: steamcmd or http")
+	configSetCmd.Flags().String("update-check-url", "", "Web API for build IDs with the http source (empty for api.steamcmd.net)")
+	configSetCmd.Flags().Int("update-defer-minutes", 0, "Minutes before a scheduled event during which update installs wait (0 to disable)")
+	configSetCmd.Flags().String("history-file", "", "Absolute path of the execution history file (empty for history.jsonl in the config directory)")
+
+	// Add flags for config backup and restore commands
+	configBackupCmd.Flags().String("file", "", "Backup file path, ending in .yaml (default: timestamped file in the backups directory)")
+	configRestoreCmd.Flags().String("file", "", "Config file to restore (required)")
+	configRestoreCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	configRestoreCmd.MarkFlagRequired("file")
+	configSetCmd.Flags().Int("step-timeout-minutes", 0, "Minutes each stop, sync, hook and start step may run before it is killed (0 to disable)")
+	configSetCmd.Flags().Int("steamcmd-timeout-minutes", 0, "Minutes each steamcmd install attempt may run before it is killed and retried (0 to disable)")
+	configSetCmd.Flags().IntSlice("rcon-warnings", nil, "Seconds before servers are stopped to warn players over RCON, e.g. 300,60,10 (only those within the event delay are sent)")
+	configSetCmd.Flags().String("rcon-warning-message", "", "In-game RCON warning; {event} becomes Wipe or Restart and {time} the time left")
+	configSetCmd.Flags().Bool("recheck-calendar-before-wipe", false, "Fetch the calendar again after the event delay and cancel wipes whose event was deleted")
+	configSetCmd.Flags().String("metrics-addr", "", "Address (host:port) to serve Prometheus metrics on at /metrics, e.g. 127.0.0.1:9100 (empty to disable; restart the daemon to apply)")
+	configSetCmd.Flags().String("health-addr", "", "Address (host:port) to serve the /healthz probe on, e.g. 127.0.0.1:9101 (empty to disable; restart the daemon to apply)")
 
 	// Add flags for update command
+	updateCmd.Flags().String("name", "", "Rename the server (its path stays the same)")
 	updateCmd.Flags().StringP("calendar", "c", "", "Google Calendar .ics URL")
+	updateCmd.Flags().StringArray("extra-calendar", nil, "Additional .ics URL whose events are merged in (repeatable; replaces the current list, \"\" clears it)")
 	updateCmd.Flags().StringP("branch", "b", "", "Rust server branch (main, staging, etc.)")
+	updateCmd.Flags().String("framework", "", "Mod framework: carbon or oxide")
+	updateCmd.Flags().String("carbon-version", "", "Pin the server's branch to this Carbon release (\"\" to follow the latest again)")
+	updateCmd.Flags().String("rust-build-id", "", "Hold the server's branch at this Rust build (\"\" to follow the latest again)")
 	updateCmd.Flags().Bool("wipe-blueprints", false, "Delete blueprints on wipe events")
 	updateCmd.Flags().Bool("generate-map", false, "Generate custom maps via generate-maps.sh")
+	updateCmd.Flags().String("identity", "", "Rust server identity (empty to use basename of path)")
+	updateCmd.Flags().StringArray("match-pattern", nil, "Regex matching the event keyword in calendar summaries (repeatable, \"\" to clear)")
+	updateCmd.Flags().Bool("skip-validation", false, "Don't fetch a new --calendar to check it (for offline setups)")
+	updateCmd.Flags().String("calendar-auth-header", "", "Header to send the calendar auth token in (empty for Authorization)")
+	updateCmd.Flags().String("calendar-auth-token", "", "Value of the calendar auth header (\"\" to stop sending credentials)")
+	updateCmd.Flags().String("rcon-address", "", "WebRCON address (host:port) for in-game warnings (\"\" to disable)")
+	updateCmd.Flags().String("rcon-password", "", "The server's rcon.password")
 
 	// Add flags for sync command
 	syncCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
 
+	// Add flags for run command
+	runCmd.Flags().StringP("type", "t", "", "Event type: restart, restart-nosync, wipe, wipe-bp, full-wipe, or map-only (required)")
+	runCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	runCmd.Flags().Bool("no-wipe-maps", false, "Keep the server's *.map file on a wipe (reuse the current map)")
+	runCmd.MarkFlagRequired("type")
+
 	// Add flags for reset-scripts command
 	resetScriptsCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	resetScriptsCmd.Flags().Bool("check", false, "Report which scripts differ from defaults without changing anything")
 
 	// Add flags for call-script command
 	callScriptCmd.Flags().StringP("script", "s", "", "Script name to call (required): stop-servers, start-servers, generate-maps")
@@ -784,8 +3955,20 @@ func init() {
 
 	// Add flags for update-source command
 	updateSourceCmd.Flags().StringP("branch", "b", "", "Update only a specific branch (default: all configured branches)")
-	updateSourceCmd.Flags().Bool("rust-only", false, "Only update Rust (skip Carbon)")
-	updateSourceCmd.Flags().Bool("carbon-only", false, "Only update Carbon (skip Rust)")
+	updateSourceCmd.Flags().Bool("rust-only", false, "Only update Rust (skip Carbon/Oxide)")
+	updateSourceCmd.Flags().Bool("carbon-only", false, "Only update the mod framework, Carbon or Oxide (skip Rust)")
+	updateNowCmd.Flags().Bool("rust-only", false, "Only install Rust (skip Carbon/Oxide)")
+	updateNowCmd.Flags().Bool("carbon-only", false, "Only install the mod framework, Carbon or Oxide (skip Rust)")
+
+	// Add flags for trigger command
+	triggerCmd.Flags().StringP("type", "t", "", "Event type: restart, restart-nosync, wipe, wipe-bp, full-wipe, or map-only (required)")
+	triggerCmd.Flags().String("at", "", "Event time in RFC3339 format, e.g. 2025-06-01T20:00:00Z (required)")
+	triggerCmd.MarkFlagRequired("type")
+	triggerCmd.MarkFlagRequired("at")
+
+	// Add flags for rollback command
+	rollbackCmd.Flags().StringP("branch", "b", "main", "Rust branch to roll back")
+	restoreCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
 
 	// Add subcommands
 	rootCmd.AddCommand(addCmd)
@@ -795,12 +3978,46 @@ func init() {
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(resetScriptsCmd)
+	rootCmd.AddCommand(scriptsCmd)
+	scriptsCmd.AddCommand(scriptsListCmd)
+	scriptsCmd.AddCommand(scriptsShowCmd)
+	scriptsCmd.AddCommand(scriptsEditCmd)
 	rootCmd.AddCommand(callScriptCmd)
 	rootCmd.AddCommand(mentionCmd)
 	rootCmd.AddCommand(updateSourceCmd)
+	rootCmd.AddCommand(updateNowCmd)
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(triggerCmd)
+	rootCmd.AddCommand(cancelCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(confirmCmd)
+	rootCmd.AddCommand(healthCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(testNotifyCmd)
+	rootCmd.AddCommand(nextCmd)
+	rootCmd.AddCommand(previewCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(installServiceCmd)
+	rootCmd.AddCommand(uninstallServiceCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileUseCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configBackupCmd)
+	configCmd.AddCommand(configRestoreCmd)
 	mentionCmd.AddCommand(mentionAddUserCmd)
 	mentionCmd.AddCommand(mentionRemoveUserCmd)
 	mentionCmd.AddCommand(mentionAddRoleCmd)
 	mentionCmd.AddCommand(mentionRemoveRoleCmd)
+	mentionCmd.AddCommand(mentionSetUsersCmd)
+	mentionCmd.AddCommand(mentionSetRolesCmd)
+	mentionCmd.AddCommand(mentionSetLevelsCmd)
+	mentionCmd.AddCommand(mentionClearUsersCmd)
+	mentionCmd.AddCommand(mentionClearRolesCmd)
+	mentionCmd.AddCommand(mentionListCmd)
 }
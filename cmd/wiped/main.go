@@ -4,19 +4,23 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/maintc/wipe-cli/internal/config"
 	"github.com/maintc/wipe-cli/internal/daemon"
+	"github.com/maintc/wipe-cli/internal/logging"
 	"github.com/maintc/wipe-cli/internal/version"
 )
 
 func main() {
 	// Parse command-line flags
 	configPath := flag.String("config", "", "Path to config file (default: ~/.config/wiped/config.yaml)")
+	profile := flag.String("profile", "", "Config profile to use, e.g. staging for ~/.config/wiped/staging.yaml (default: the profile set with 'wipe profile use')")
+	dryRun := flag.Bool("dry-run", false, "Fetch calendars and schedule events, but only log what batches would do")
+	logLevel := flag.String("log-level", "info", "Lowest level to log: debug, info, warn or error")
+	jsonLogs := flag.Bool("json-logs", false, "Log one JSON object per line (for log aggregators)")
 	showVersion := flag.Bool("version", false, "Show version information")
 	flag.Parse()
 
@@ -26,12 +30,35 @@ func main() {
 		os.Exit(0)
 	}
 
-	log.Printf("Starting wipe daemon (%s)...", version.GetVersion())
+	level, err := logging.ParseLevel(*logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	logging.SetLevel(level)
+	logging.SetJSON(*jsonLogs)
+
+	logging.Infof("Starting wipe daemon (%s)...", version.GetVersion())
+
+	// Resolve the profile like the CLI does: --profile wins, then 'wipe profile use'.
+	// An explicit --config takes precedence over both.
+	if *configPath == "" {
+		if *profile == "" {
+			*profile = config.ActiveProfile()
+		}
+		if err := config.UseProfile(*profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *profile != config.DefaultProfile {
+			logging.Infof("Using profile: %s", *profile)
+		}
+	}
 
 	// Set custom config path if provided
 	if *configPath != "" {
 		config.CustomConfigPath = *configPath
-		log.Printf("Using custom config: %s", *configPath)
+		logging.Infof("Using custom config: %s", *configPath)
 	}
 
 	// Initialize config
@@ -39,6 +66,10 @@ func main() {
 
 	// Create daemon instance
 	d := daemon.New()
+	if *dryRun {
+		logging.Infof("Dry run: servers will not be stopped, synced, wiped or started")
+		d.SetDryRun(true)
+	}
 
 	// Setup signal handling for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -49,15 +80,26 @@ func main() {
 
 	go func() {
 		<-sigChan
-		log.Println("Received shutdown signal")
+		logging.Infof("Received shutdown signal")
 		cancel()
 	}()
 
+	// SIGHUP refreshes calendars and checks for updates without restarting
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
+	go func() {
+		for range hupChan {
+			logging.Infof("Received SIGHUP, requesting refresh")
+			d.RequestRefresh()
+		}
+	}()
+
 	// Run the daemon
 	if err := d.Run(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Daemon error: %v\n", err)
 		os.Exit(1)
 	}
 
-	log.Println("Wipe daemon stopped")
+	logging.Infof("Wipe daemon stopped")
 }
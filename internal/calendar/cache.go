@@ -0,0 +1,58 @@
+package calendar
+
+import (
+	"sync"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// cacheEntry is a parsed calendar and the validators its host sent with it
+type cacheEntry struct {
+	cal          *ics.Calendar
+	etag         string
+	lastModified string
+}
+
+// Cache remembers parsed calendars by URL and refetches them with conditional requests,
+// so an unchanged calendar costs a 304 instead of a full download and parse.
+// Cached calendars are shared between callers and must not be modified.
+type Cache struct {
+	entries map[string]*cacheEntry
+	mutex   sync.Mutex
+}
+
+// NewCache creates an empty calendar cache
+func NewCache() *Cache {
+	return &Cache{entries: make(map[string]*cacheEntry)}
+}
+
+// Fetch is FetchCalendarWithAuth, sending If-None-Match/If-Modified-Since for a calendar
+// fetched before and returning the cached calendar if the host answers 304 Not Modified
+func (c *Cache) Fetch(url, authHeader, token string) (*ics.Calendar, error) {
+	c.mutex.Lock()
+	cached := c.entries[url]
+	c.mutex.Unlock()
+
+	entry, err := fetch(url, authHeader, token, cached)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only keep calendars the host gave us a validator for; others can never be revalidated
+	c.mutex.Lock()
+	if entry.etag != "" || entry.lastModified != "" {
+		c.entries[url] = entry
+	} else {
+		delete(c.entries, url)
+	}
+	c.mutex.Unlock()
+
+	return entry.cal, nil
+}
+
+// FetchAll is FetchCalendarsWithAuth through the cache
+func (c *Cache) FetchAll(urls []string, authHeader, token string) ([]*ics.Calendar, error) {
+	return fetchAll(urls, func(url string) (*ics.Calendar, error) {
+		return c.Fetch(url, authHeader, token)
+	})
+}
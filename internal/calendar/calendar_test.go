@@ -1,8 +1,15 @@
 package calendar
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	ics "github.com/arran4/golang-ical"
+	"github.com/maintc/wipe-cli/internal/httpclient"
 )
 
 func TestEventTypeConstants(t *testing.T) {
@@ -107,3 +114,706 @@ func TestEventTypeString(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUpcomingEvents_EventTypes(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).UTC().Format("20060102T150405Z")
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:Restart\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:2\r\nSUMMARY:restart-nosync\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:3\r\nSUMMARY:wipe\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:4\r\nSUMMARY:maintenance\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := ics.ParseCalendar(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseCalendar() returned error: %v", err)
+	}
+
+	events, err := GetUpcomingEvents(cal, 24)
+	if err != nil {
+		t.Fatalf("GetUpcomingEvents() returned error: %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("len(events) = %d, want 3", len(events))
+	}
+
+	want := []EventType{EventTypeRestart, EventTypeRestartNoSync, EventTypeWipe}
+	for i, event := range events {
+		if event.Type != want[i] {
+			t.Errorf("events[%d].Type = %s, want %s", i, event.Type, want[i])
+		}
+	}
+}
+
+func TestParseEventType(t *testing.T) {
+	tests := []struct {
+		summary string
+		want    EventType
+		wantOK  bool
+	}{
+		{"restart", EventTypeRestart, true},
+		{"restart-nosync", EventTypeRestartNoSync, true},
+		{"wipe", EventTypeWipe, true},
+		{"wipe-bp", EventTypeWipeBlueprints, true},
+		{"WIPE-BP", EventTypeWipeBlueprints, true},
+		{"full-wipe", EventTypeFullWipe, true},
+		{"  Full-Wipe  ", EventTypeFullWipe, true},
+		{"map-only", EventTypeMapOnly, true},
+		{"Map-Only", EventTypeMapOnly, true},
+		{"map only", "", false},
+		{"blueprint wipe", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.summary, func(t *testing.T) {
+			got, ok := ParseEventType(tt.summary)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ParseEventType(%q) = (%q, %v), want (%q, %v)", tt.summary, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetUpcomingEvents_WipeVariants(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).UTC().Format("20060102T150405Z")
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:Wipe-BP\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:2\r\nSUMMARY:FULL-WIPE\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:3\r\nSUMMARY: map-only \r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:4\r\nSUMMARY:bp-wipe\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := ics.ParseCalendar(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseCalendar() returned error: %v", err)
+	}
+
+	events, err := GetUpcomingEvents(cal, 24)
+	if err != nil {
+		t.Fatalf("GetUpcomingEvents() returned error: %v", err)
+	}
+
+	want := []EventType{EventTypeWipeBlueprints, EventTypeFullWipe, EventTypeMapOnly}
+	if len(events) != len(want) {
+		t.Fatalf("len(events) = %d, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Type != want[i] {
+			t.Errorf("events[%d].Type = %s, want %s", i, event.Type, want[i])
+		}
+		if !event.Type.IsWipe() {
+			t.Errorf("events[%d].Type.IsWipe() = false, want true", i)
+		}
+	}
+
+	if EventTypeRestart.IsWipe() || EventTypeRestartNoSync.IsWipe() {
+		t.Error("restart types should not be wipes")
+	}
+}
+
+func TestMatchEventType(t *testing.T) {
+	keyword := []string{`\b(restart-nosync|restart|wipe)\b`}
+	bracketed := []string{`^\[(\w+)\]`}
+
+	tests := []struct {
+		name     string
+		summary  string
+		patterns []string
+		want     EventType
+		wantOK   bool
+	}{
+		{"no patterns exact match", "wipe", nil, EventTypeWipe, true},
+		{"no patterns rejects titles", "US Weekly — wipe", nil, "", false},
+		{"trailing keyword", "US Weekly — wipe", keyword, EventTypeWipe, true},
+		{"leading keyword", "Restart for patch day", keyword, EventTypeRestart, true},
+		{"trailing punctuation", "weekly restart!!", keyword, EventTypeRestart, true},
+		{"mixed case", "WiPe Time", keyword, EventTypeWipe, true},
+		{"bracketed prefix", "[WIPE] train", bracketed, EventTypeWipe, true},
+		{"bracketed prefix not an event", "[INFO] train", bracketed, "", false},
+		{"restart and wipe prefers wipe", "restart then wipe", keyword, EventTypeWipe, true},
+		{"wipe and restart prefers wipe", "wipe + restart", keyword, EventTypeWipe, true},
+		{"nosync keyword", "Restart-NoSync tonight", keyword, EventTypeRestartNoSync, true},
+		{"no keyword", "community event", keyword, "", false},
+		{"whole match without group", "full-wipe friday", []string{`full-wipe`}, EventTypeFullWipe, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns, err := CompileMatchPatterns(tt.patterns)
+			if err != nil {
+				t.Fatalf("CompileMatchPatterns() returned error: %v", err)
+			}
+			got, ok := MatchEventType(tt.summary, patterns)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("MatchEventType(%q) = (%q, %v), want (%q, %v)", tt.summary, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetUpcomingEventsMatching(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).UTC().Format("20060102T150405Z")
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:US Weekly — Wipe.\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:2\r\nSUMMARY:[RESTART] train\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:3\r\nSUMMARY:movie night\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := ics.ParseCalendar(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseCalendar() returned error: %v", err)
+	}
+
+	events, err := GetUpcomingEventsMatching(cal, 24, []string{`\b(restart|wipe)\b`})
+	if err != nil {
+		t.Fatalf("GetUpcomingEventsMatching() returned error: %v", err)
+	}
+
+	want := []EventType{EventTypeWipe, EventTypeRestart}
+	if len(events) != len(want) {
+		t.Fatalf("len(events) = %d, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Type != want[i] {
+			t.Errorf("events[%d].Type = %s, want %s", i, event.Type, want[i])
+		}
+	}
+
+	if _, err := GetUpcomingEventsMatching(cal, 24, []string{"(wipe"}); err == nil {
+		t.Error("GetUpcomingEventsMatching() should fail on an invalid pattern")
+	}
+}
+
+func TestGetEventsInWindow_TypeSources(t *testing.T) {
+	at := time.Date(2026, time.March, 5, 19, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		props    string
+		patterns []string
+		want     EventType
+		wantOK   bool
+	}{
+		{"summary", "SUMMARY:wipe\r\n", nil, EventTypeWipe, true},
+		{"category", "SUMMARY:Big Thursday reset\r\nCATEGORIES:wipe\r\n", nil, EventTypeWipe, true},
+		{"one of several categories", "SUMMARY:Patch night\r\nCATEGORIES:community,Restart\r\n", nil, EventTypeRestart, true},
+		{"category without summary", "CATEGORIES:map-only\r\n", nil, EventTypeMapOnly, true},
+		{"description line", "SUMMARY:Monthly reset\r\nDESCRIPTION:Fresh map for everyone\\ntype=full-wipe\\nSee you there\r\n", nil, EventTypeFullWipe, true},
+		{"description with HTML breaks", "SUMMARY:Monthly reset\r\nDESCRIPTION:Fresh map<br>Type = wipe-bp\r\n", nil, EventTypeWipeBlueprints, true},
+		{"summary before category", "SUMMARY:restart\r\nCATEGORIES:wipe\r\n", nil, EventTypeRestart, true},
+		{"summary pattern before category", "SUMMARY:US Weekly restart\r\nCATEGORIES:wipe\r\n", []string{`\b(restart|wipe)\b`}, EventTypeRestart, true},
+		{"category before description", "SUMMARY:Reset\r\nCATEGORIES:wipe\r\nDESCRIPTION:type=restart\r\n", nil, EventTypeWipe, true},
+		{"unknown category falls through to description", "SUMMARY:Reset\r\nCATEGORIES:community\r\nDESCRIPTION:type=restart\r\n", nil, EventTypeRestart, true},
+		{"description without type line", "SUMMARY:Reset\r\nDESCRIPTION:we will wipe soon\r\n", nil, "", false},
+		{"no source names a type", "SUMMARY:Community event\r\nCATEGORIES:fun\r\n", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+				"BEGIN:VEVENT\r\nUID:1\r\n" + tt.props +
+				"DTSTART:" + at.Format("20060102T150405Z") + "\r\n" +
+				"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+			cal, err := ics.ParseCalendar(strings.NewReader(data))
+			if err != nil {
+				t.Fatalf("ParseCalendar() returned error: %v", err)
+			}
+
+			events, err := GetEventsInWindow(cal, at.Add(-time.Hour), at.Add(time.Hour), tt.patterns)
+			if err != nil {
+				t.Fatalf("GetEventsInWindow() returned error: %v", err)
+			}
+			if !tt.wantOK {
+				if len(events) != 0 {
+					t.Errorf("GetEventsInWindow() = %v, want none", events)
+				}
+				return
+			}
+			if len(events) != 1 || events[0].Type != tt.want {
+				t.Errorf("GetEventsInWindow() = %v, want one %s event", events, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetEventsInWindow_Past(t *testing.T) {
+	at := time.Now().Add(-30 * time.Second).Truncate(time.Second)
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:wipe\r\nDTSTART:" + at.UTC().Format("20060102T150405Z") + "\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := ics.ParseCalendar(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseCalendar() returned error: %v", err)
+	}
+
+	// GetUpcomingEvents only looks ahead, so an event that just started is gone
+	if events, _ := GetUpcomingEvents(cal, 24); len(events) != 0 {
+		t.Errorf("GetUpcomingEvents() = %v, want none", events)
+	}
+
+	events, err := GetEventsInWindow(cal, at.Add(-time.Minute), at.Add(time.Minute), nil)
+	if err != nil {
+		t.Fatalf("GetEventsInWindow() returned error: %v", err)
+	}
+	if len(events) != 1 || !events[0].StartTime.Equal(at) || events[0].Type != EventTypeWipe {
+		t.Errorf("GetEventsInWindow() = %v, want one wipe at %v", events, at)
+	}
+}
+
+func TestGetEventsInWindow_Recurring(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+	at := func(month time.Month, day int) time.Time { return time.Date(2026, month, day, 19, 0, 0, 0, newYork) }
+
+	// Weekly on Thursdays at 19:00 New York time, first occurrence Thursday 2026-01-01
+	event := func(rule string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+			"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:wipe\r\n" +
+			"DTSTART;TZID=America/New_York:20260101T190000\r\n" +
+			"RRULE:" + rule + "\r\n" +
+			"END:VEVENT\r\nEND:VCALENDAR\r\n"
+	}
+
+	tests := []struct {
+		name       string
+		rule       string
+		start, end time.Time
+		want       []time.Time
+	}{
+		{
+			// Clocks go forward on 2026-03-08; occurrences stay at 19:00 local time
+			name:  "across a DST change",
+			rule:  "FREQ=WEEKLY;BYDAY=TH",
+			start: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2026, time.March, 20, 0, 0, 0, 0, time.UTC),
+			want:  []time.Time{at(time.March, 5), at(time.March, 12), at(time.March, 19)},
+		},
+		{
+			name:  "window bounds are exclusive",
+			rule:  "FREQ=WEEKLY;BYDAY=TH",
+			start: at(time.January, 8),
+			end:   at(time.January, 22),
+			want:  []time.Time{at(time.January, 15)},
+		},
+		{
+			name:  "COUNT ends the series",
+			rule:  "FREQ=WEEKLY;COUNT=3",
+			start: time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+			want:  []time.Time{at(time.January, 1), at(time.January, 8), at(time.January, 15)},
+		},
+		{
+			name:  "UNTIL ends the series",
+			rule:  "FREQ=WEEKLY;UNTIL=20260115T000000Z",
+			start: time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+			want:  []time.Time{at(time.January, 8)},
+		},
+		{
+			name:  "window before the first occurrence",
+			rule:  "FREQ=WEEKLY;BYDAY=TH",
+			start: time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal, err := ics.ParseCalendar(strings.NewReader(event(tt.rule)))
+			if err != nil {
+				t.Fatalf("ParseCalendar() returned error: %v", err)
+			}
+
+			events, err := GetEventsInWindow(cal, tt.start, tt.end, nil)
+			if err != nil {
+				t.Fatalf("GetEventsInWindow() returned error: %v", err)
+			}
+			if len(events) != len(tt.want) {
+				t.Fatalf("len(events) = %d, want %d: %v", len(events), len(tt.want), events)
+			}
+			for i, event := range events {
+				if !event.StartTime.Equal(tt.want[i]) {
+					t.Errorf("events[%d].StartTime = %v, want %v", i, event.StartTime, tt.want[i])
+				}
+				if got := event.EndTime.Sub(event.StartTime); got != time.Hour {
+					t.Errorf("events[%d] lasts %v, want 1h", i, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUpcomingEvents_AllDay(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	tomorrow := time.Now().Add(24 * time.Hour)
+	date := tomorrow.Format("20060102")
+
+	tests := []struct {
+		name      string
+		calHeader string
+		dtstart   string
+		loc       *time.Location
+	}{
+		{"VALUE=DATE without X-WR-TIMEZONE", "", "DTSTART;VALUE=DATE:" + date, time.UTC},
+		{"VALUE=DATE with X-WR-TIMEZONE", "X-WR-TIMEZONE:America/New_York\r\n", "DTSTART;VALUE=DATE:" + date, ny},
+		{"bare date with X-WR-TIMEZONE", "X-WR-TIMEZONE:America/New_York\r\n", "DTSTART:" + date, ny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" + tt.calHeader +
+				"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:wipe\r\n" + tt.dtstart + "\r\nEND:VEVENT\r\n" +
+				"END:VCALENDAR\r\n"
+
+			cal, err := ics.ParseCalendar(strings.NewReader(data))
+			if err != nil {
+				t.Fatalf("ParseCalendar() returned error: %v", err)
+			}
+
+			events, err := GetUpcomingEvents(cal, 72)
+			if err != nil {
+				t.Fatalf("GetUpcomingEvents() returned error: %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("len(events) = %d, want 1", len(events))
+			}
+
+			want := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tt.loc)
+			if !events[0].StartTime.Equal(want) {
+				t.Errorf("StartTime = %v, want %v", events[0].StartTime, want)
+			}
+		})
+	}
+}
+
+func TestGetUpcomingEvents_Exdate(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	start := time.Now().In(berlin).Add(24 * time.Hour).Truncate(time.Minute)
+	week := func(n int) time.Time { return start.AddDate(0, 0, 7*n) }
+	format := func(t time.Time) string { return t.Format("20060102T150405") }
+
+	// Weekly for 6 weeks; week 1 is canceled on its own line, weeks 3 and 4 on one comma-separated line
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:wipe\r\n" +
+		"DTSTART;TZID=Europe/Berlin:" + format(start) + "\r\n" +
+		"RRULE:FREQ=WEEKLY;COUNT=6\r\n" +
+		"EXDATE;TZID=Europe/Berlin:" + format(week(1)) + "\r\n" +
+		"EXDATE;TZID=Europe/Berlin:" + format(week(3)) + "," + format(week(4)) + "\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	cal, err := ics.ParseCalendar(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseCalendar() returned error: %v", err)
+	}
+
+	events, err := GetUpcomingEvents(cal, 7*24*7)
+	if err != nil {
+		t.Fatalf("GetUpcomingEvents() returned error: %v", err)
+	}
+
+	want := []time.Time{week(0), week(2), week(5)}
+	if len(events) != len(want) {
+		t.Fatalf("len(events) = %d, want %d: %v", len(events), len(want), events)
+	}
+	for i, event := range events {
+		if !event.StartTime.Equal(want[i]) {
+			t.Errorf("events[%d].StartTime = %v, want %v", i, event.StartTime, want[i])
+		}
+	}
+}
+
+func TestGetUpcomingEvents_Rdate(t *testing.T) {
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Minute)
+	week := func(n int) time.Time { return start.AddDate(0, 0, 7*n) }
+	extra := start.AddDate(0, 0, 3)
+	format := func(t time.Time) string { return t.Format("20060102T150405Z") }
+
+	tests := []struct {
+		name  string
+		rrule string
+		rdate string
+		want  []time.Time
+	}{
+		{
+			name:  "RRULE plus RDATE, one RDATE repeating a rule occurrence",
+			rrule: "RRULE:FREQ=WEEKLY;COUNT=3\r\n",
+			rdate: "RDATE:" + format(extra) + "," + format(week(1)) + "\r\n",
+			want:  []time.Time{week(0), extra, week(1), week(2)},
+		},
+		{
+			name:  "RDATE without RRULE",
+			rdate: "RDATE:" + format(extra) + "\r\n",
+			want:  []time.Time{week(0), extra},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+				"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:wipe\r\n" +
+				"DTSTART:" + format(start) + "\r\n" + tt.rrule + tt.rdate +
+				"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+			cal, err := ics.ParseCalendar(strings.NewReader(data))
+			if err != nil {
+				t.Fatalf("ParseCalendar() returned error: %v", err)
+			}
+
+			events, err := GetUpcomingEvents(cal, 7*24*4)
+			if err != nil {
+				t.Fatalf("GetUpcomingEvents() returned error: %v", err)
+			}
+			if len(events) != len(tt.want) {
+				t.Fatalf("len(events) = %d, want %d: %v", len(events), len(tt.want), events)
+			}
+			for i, event := range events {
+				if !event.StartTime.Equal(tt.want[i]) {
+					t.Errorf("events[%d].StartTime = %v, want %v", i, event.StartTime, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseTimeWithTimezone_VTimezone(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Microsoft Corporation//Outlook 16.0 MIMEDIR//EN\r\n" +
+		"BEGIN:VTIMEZONE\r\nTZID:Pacific Standard Time\r\n" +
+		"BEGIN:STANDARD\r\nDTSTART:16011104T020000\r\nRRULE:FREQ=YEARLY;BYDAY=1SU;BYMONTH=11\r\n" +
+		"TZOFFSETFROM:-0700\r\nTZOFFSETTO:-0800\r\nEND:STANDARD\r\n" +
+		"BEGIN:DAYLIGHT\r\nDTSTART:16010311T020000\r\nRRULE:FREQ=YEARLY;BYDAY=2SU;BYMONTH=3\r\n" +
+		"TZOFFSETFROM:-0800\r\nTZOFFSETTO:-0700\r\nEND:DAYLIGHT\r\n" +
+		"END:VTIMEZONE\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := ics.ParseCalendar(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseCalendar() returned error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Time
+	}{
+		// DST starts 2025-03-09 and ends 2025-11-02 in the Pacific zone
+		{"winter (PST)", "20250301T190000", time.Date(2025, 3, 2, 3, 0, 0, 0, time.UTC)},
+		{"after spring forward (PDT)", "20250310T190000", time.Date(2025, 3, 11, 2, 0, 0, 0, time.UTC)},
+		{"summer (PDT)", "20250701T120000", time.Date(2025, 7, 1, 19, 0, 0, 0, time.UTC)},
+		{"after fall back (PST)", "20251103T190000", time.Date(2025, 11, 4, 3, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop := &ics.IANAProperty{BaseProperty: ics.BaseProperty{
+				IANAToken:      "DTSTART",
+				Value:          tt.value,
+				ICalParameters: map[string][]string{"TZID": {"Pacific Standard Time"}},
+			}}
+
+			got, err := parseTimeWithTimezone(prop, cal)
+			if err != nil {
+				t.Fatalf("parseTimeWithTimezone() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTimeWithTimezone(%s) = %v, want %v", tt.value, got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGetEventsInWindow_VTimezoneRecurringAcrossDST(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Microsoft Corporation//Outlook 16.0 MIMEDIR//EN\r\n" +
+		"BEGIN:VTIMEZONE\r\nTZID:Pacific Standard Time\r\n" +
+		"BEGIN:STANDARD\r\nDTSTART:16011104T020000\r\nRRULE:FREQ=YEARLY;BYDAY=1SU;BYMONTH=11\r\n" +
+		"TZOFFSETFROM:-0700\r\nTZOFFSETTO:-0800\r\nEND:STANDARD\r\n" +
+		"BEGIN:DAYLIGHT\r\nDTSTART:16010311T020000\r\nRRULE:FREQ=YEARLY;BYDAY=2SU;BYMONTH=3\r\n" +
+		"TZOFFSETFROM:-0800\r\nTZOFFSETTO:-0700\r\nEND:DAYLIGHT\r\n" +
+		"END:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\nUID:weekly-restart\r\nSUMMARY:Restart\r\n" +
+		"DTSTART;TZID=Pacific Standard Time:20250223T200000\r\n" +
+		"DTEND;TZID=Pacific Standard Time:20250223T210000\r\n" +
+		"RRULE:FREQ=WEEKLY\r\n" +
+		"EXDATE;TZID=Pacific Standard Time:20250316T200000\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := ics.ParseCalendar(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseCalendar() returned error: %v", err)
+	}
+
+	// DST starts 2025-03-09: 20:00 PST is 04:00Z, 20:00 PDT is 03:00Z, and the
+	// EXDATE written in local (PDT) time still cancels its occurrence
+	events, err := GetEventsInWindow(cal, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Fatalf("GetEventsInWindow() returned error: %v", err)
+	}
+	want := []time.Time{
+		time.Date(2025, 3, 3, 4, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 24, 3, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 3, 0, 0, 0, time.UTC),
+	}
+	if len(events) != len(want) {
+		t.Fatalf("len(events) = %d, want %d: %v", len(events), len(want), events)
+	}
+	for i, event := range events {
+		if !event.StartTime.Equal(want[i]) {
+			t.Errorf("events[%d].StartTime = %v, want %v", i, event.StartTime.UTC(), want[i])
+		}
+		if event.EndTime.Sub(event.StartTime) != time.Hour {
+			t.Errorf("events[%d] duration = %v, want 1h", i, event.EndTime.Sub(event.StartTime))
+		}
+	}
+}
+
+func TestFetchCalendar_RedirectLoop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path, http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := FetchCalendar(server.URL + "/basic.ics")
+	if err == nil {
+		t.Fatal("FetchCalendar() should fail on a redirect loop")
+	}
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("FetchCalendar() error = %v, want ErrTooManyRedirects", err)
+	}
+	if !strings.Contains(err.Error(), server.URL) {
+		t.Errorf("FetchCalendar() error should mention the URL, got: %v", err)
+	}
+}
+
+func TestFetchCalendar_Timeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	httpclient.SetTimeout(100 * time.Millisecond)
+	defer httpclient.SetTimeout(httpclient.DefaultTimeout)
+
+	start := time.Now()
+	_, err := FetchCalendar(server.URL + "/slow.ics")
+	if err == nil {
+		t.Fatal("FetchCalendar() should fail when the server doesn't respond in time")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("FetchCalendar() took %v, want it to give up after the timeout", elapsed)
+	}
+}
+
+func TestFetchCalendarWithAuth(t *testing.T) {
+	const body = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nEND:VCALENDAR\r\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, hasBasic := r.BasicAuth()
+		switch {
+		case r.URL.Path == "/token.ics" && r.Header.Get("Authorization") == "Bearer secret":
+		case r.URL.Path == "/custom.ics" && r.Header.Get("X-Calendar-Token") == "secret":
+		case r.URL.Path == "/basic.ics" && hasBasic && user == "rust" && pass == "hunter2":
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	withUser := strings.Replace(server.URL, "http://", "http://rust:hunter2@", 1)
+
+	tests := []struct {
+		name    string
+		url     string
+		header  string
+		token   string
+		wantErr bool
+	}{
+		{"no credentials", server.URL + "/token.ics", "", "", true},
+		{"wrong token", server.URL + "/token.ics", "", "Bearer nope", true},
+		{"token in default header", server.URL + "/token.ics", "", "Bearer secret", false},
+		{"token in custom header", server.URL + "/custom.ics", "X-Calendar-Token", "secret", false},
+		{"credentials in URL", withUser + "/basic.ics", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FetchCalendarWithAuth(tt.url, tt.header, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchCalendarWithAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), "401") {
+				t.Errorf("FetchCalendarWithAuth() error = %v, want a 401", err)
+			}
+		})
+	}
+}
+
+func TestCacheFetch_ConditionalRequests(t *testing.T) {
+	const calendarA = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//A\r\nEND:VCALENDAR\r\n"
+	const calendarB = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//B\r\nEND:VCALENDAR\r\n"
+
+	body, etag := calendarA, `"v1"`
+	fullResponses := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses++
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cache := NewCache()
+	first, err := cache.Fetch(server.URL+"/cal.ics", "", "")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	second, err := cache.Fetch(server.URL+"/cal.ics", "", "")
+	if err != nil {
+		t.Fatalf("Fetch() after 304 error = %v", err)
+	}
+	if second != first {
+		t.Error("Fetch() after 304 should return the cached calendar")
+	}
+	if fullResponses != 1 {
+		t.Errorf("full responses = %d, want 1", fullResponses)
+	}
+
+	body, etag = calendarB, `"v2"`
+	third, err := cache.Fetch(server.URL+"/cal.ics", "", "")
+	if err != nil {
+		t.Fatalf("Fetch() after change error = %v", err)
+	}
+	if third == first {
+		t.Error("Fetch() should re-parse the calendar when the ETag changes")
+	}
+	if got := third.CalendarProperties[1].Value; got != "-//test//B" {
+		t.Errorf("PRODID = %q, want the updated calendar", got)
+	}
+	if fullResponses != 2 {
+		t.Errorf("full responses = %d, want 2", fullResponses)
+	}
+}
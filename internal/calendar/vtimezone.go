@@ -0,0 +1,199 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+	"github.com/teambition/rrule-go"
+)
+
+// observance is a STANDARD or DAYLIGHT block of a VTIMEZONE
+type observance struct {
+	offsetTo   int       // Seconds east of UTC while this observance is in effect
+	start      time.Time // First onset, as wall-clock time (stored in UTC)
+	rruleValue string
+}
+
+// vtimezone is a time zone defined inline in the ICS file, used when its TZID isn't an IANA name
+type vtimezone struct {
+	tzid        string
+	observances []observance
+}
+
+// findVTimezone returns the VTIMEZONE with the given TZID, or nil if the calendar doesn't define it
+func findVTimezone(cal *ics.Calendar, tzid string) *vtimezone {
+	if cal == nil {
+		return nil
+	}
+
+	for _, component := range cal.Components {
+		tz, ok := component.(*ics.VTimezone)
+		if !ok {
+			continue
+		}
+		if prop := tz.GetProperty(ics.ComponentPropertyTzid); prop == nil || prop.Value != tzid {
+			continue
+		}
+
+		z := &vtimezone{tzid: tzid}
+		for _, sub := range tz.SubComponents() {
+			var base *ics.ComponentBase
+			switch c := sub.(type) {
+			case *ics.Standard:
+				base = &c.ComponentBase
+			case *ics.Daylight:
+				base = &c.ComponentBase
+			default:
+				continue
+			}
+
+			offsetProp := base.GetProperty("TZOFFSETTO")
+			startProp := base.GetProperty(ics.ComponentPropertyDtStart)
+			if offsetProp == nil || startProp == nil {
+				continue
+			}
+			offset, err := parseUTCOffset(offsetProp.Value)
+			if err != nil {
+				continue
+			}
+			start, err := time.Parse("20060102T150405", startProp.Value)
+			if err != nil {
+				continue
+			}
+
+			obs := observance{offsetTo: offset, start: start}
+			if rruleProp := base.GetProperty("RRULE"); rruleProp != nil {
+				obs.rruleValue = rruleProp.Value
+			}
+			z.observances = append(z.observances, obs)
+		}
+
+		if len(z.observances) == 0 {
+			return nil
+		}
+		return z
+	}
+	return nil
+}
+
+// locationAt returns a fixed-offset location for the observance in effect at the given wall-clock time
+func (z *vtimezone) locationAt(wall time.Time) *time.Location {
+	// wall is the event's local time expressed in UTC; onsets are compared the same way
+	var latest time.Time
+	offset := z.observances[0].offsetTo
+	found := false
+
+	for _, obs := range z.observances {
+		onset, ok := obs.lastOnsetBefore(wall)
+		if !ok {
+			continue
+		}
+		if !found || onset.After(latest) {
+			latest = onset
+			offset = obs.offsetTo
+			found = true
+		}
+	}
+
+	return time.FixedZone(z.tzid, offset)
+}
+
+// localize reinterprets t's wall-clock time with the offset in effect at that wall time
+func (z *vtimezone) localize(t time.Time) time.Time {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, z.locationAt(wall))
+}
+
+// localizeOccurrences localizes occurrences expanded from a DTSTART in this zone, which all carry
+// DTSTART's offset, so each keeps its wall-clock time across DST changes. EXDATEs are removed
+// afterwards, since they're written with the offset of their own date, and so are duplicates
+func (z *vtimezone) localizeOccurrences(occurrences, exdates []time.Time) []time.Time {
+	excluded := make(map[int64]bool, len(exdates))
+	for _, exdate := range exdates {
+		excluded[exdate.Unix()] = true
+	}
+
+	var localized []time.Time
+	seen := make(map[int64]bool, len(occurrences))
+	for _, occurrence := range occurrences {
+		t := z.localize(occurrence)
+		if excluded[t.Unix()] || seen[t.Unix()] {
+			continue
+		}
+		seen[t.Unix()] = true
+		localized = append(localized, t)
+	}
+	sort.Slice(localized, func(i, j int) bool { return localized[i].Before(localized[j]) })
+	return localized
+}
+
+// lastOnsetBefore returns the most recent start of this observance at or before wall
+func (o observance) lastOnsetBefore(wall time.Time) (time.Time, bool) {
+	if o.start.After(wall) {
+		return time.Time{}, false
+	}
+	if o.rruleValue == "" {
+		return o.start, true
+	}
+
+	opt, err := rrule.StrToROption(o.rruleValue)
+	if err != nil {
+		return o.start, true
+	}
+	// Start the rule shortly before wall; rules anchored in 1601 (as Outlook writes them)
+	// would otherwise exhaust the iterator long before reaching the present
+	opt.Dtstart = o.start
+	if o.start.Year() < wall.Year()-1 {
+		month, day := o.start.Month(), o.start.Day()
+		if len(opt.Bymonth) > 0 {
+			month, day = time.January, 1
+		}
+		opt.Dtstart = time.Date(wall.Year()-1, month, day, o.start.Hour(), o.start.Minute(), o.start.Second(), 0, time.UTC)
+	}
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return o.start, true
+	}
+
+	onset := r.Before(wall, true)
+	if onset.IsZero() {
+		return o.start, true
+	}
+	return onset, true
+}
+
+// parseUTCOffset parses an iCalendar UTC offset like -0800 or +053000 into seconds east of UTC
+func parseUTCOffset(value string) (int, error) {
+	if len(value) != 5 && len(value) != 7 {
+		return 0, fmt.Errorf("invalid UTC offset: %s", value)
+	}
+
+	sign := 1
+	switch value[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return 0, fmt.Errorf("invalid UTC offset: %s", value)
+	}
+
+	hours, err := strconv.Atoi(value[1:3])
+	if err != nil {
+		return 0, fmt.Errorf("invalid UTC offset: %s", value)
+	}
+	minutes, err := strconv.Atoi(value[3:5])
+	if err != nil {
+		return 0, fmt.Errorf("invalid UTC offset: %s", value)
+	}
+	seconds := 0
+	if len(value) == 7 {
+		if seconds, err = strconv.Atoi(value[5:7]); err != nil {
+			return 0, fmt.Errorf("invalid UTC offset: %s", value)
+		}
+	}
+
+	return sign * (hours*3600 + minutes*60 + seconds), nil
+}
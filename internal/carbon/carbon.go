@@ -1,12 +1,9 @@
 package carbon
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"os/exec"
@@ -14,26 +11,60 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/maintc/wipe-cli/internal/discord"
+	"github.com/maintc/wipe-cli/internal/httpclient"
+	"github.com/maintc/wipe-cli/internal/installer"
+	"github.com/maintc/wipe-cli/internal/logging"
+	"github.com/maintc/wipe-cli/internal/notify"
 )
 
 const (
 	CarbonReleasesAPI = "https://api.carbonmod.gg/releases/"
-	CarbonBase        = "/opt/carbon"
 	CarbonMainURL     = "https://github.com/CarbonCommunity/Carbon/releases/download/production_build/Carbon.Linux.Release.tar.gz"
 	CarbonStagingURL  = "https://github.com/CarbonCommunity/Carbon/releases/download/rustbeta_staging_build/Carbon.Linux.Debug.tar.gz"
-	RustEditURL       = "https://github.com/k1lly0u/Oxide.Ext.RustEdit/raw/master/Oxide.Ext.RustEdit.dll"
 )
 
 var (
-	// installingMutex prevents concurrent Carbon installations
-	installingMutex    sync.Mutex
-	installingBranches = make(map[string]bool)
-	// branchLocks provides per-branch RW locks to coordinate installs vs syncs
-	branchLocks = make(map[string]*sync.RWMutex)
-	branchMutex sync.Mutex
+	// CarbonBase holds a Carbon install per branch
+	CarbonBase = "/opt/carbon"
+	// CarbonVersionURL is the download URL of a pinned Carbon release, formatted with its version tag
+	CarbonVersionURL = "https://github.com/CarbonCommunity/Carbon/releases/download/%s/Carbon.Linux.Release.tar.gz"
+	// RustEditURL is the RustEdit extension installed alongside Carbon
+	RustEditURL = "https://github.com/k1lly0u/Oxide.Ext.RustEdit/raw/master/Oxide.Ext.RustEdit.dll"
 )
 
+var (
+	// pinnedVersions maps each branch pinned with carbon_version to its version
+	pinnedVersions = make(map[string]string)
+	pinnedMutex    sync.Mutex
+	// branchLocks coordinates installs vs syncs of each branch
+	branchLocks = installer.NewBranchLocks("Carbon", func() string { return CarbonBase })
+)
+
+// SetPinnedVersions pins branches to a Carbon version (branch -> version), replacing any previous pins.
+// Pinned branches install that version and never offer updates.
+func SetPinnedVersions(pins map[string]string) {
+	pinnedMutex.Lock()
+	defer pinnedMutex.Unlock()
+
+	pinnedVersions = make(map[string]string, len(pins))
+	for branch, version := range pins {
+		if branch == "" {
+			branch = "main"
+		}
+		pinnedVersions[branch] = version
+	}
+}
+
+// PinnedVersion returns the Carbon version a branch is pinned to, or "" if it follows the latest build
+func PinnedVersion(branch string) string {
+	if branch == "" {
+		branch = "main"
+	}
+	pinnedMutex.Lock()
+	defer pinnedMutex.Unlock()
+	return pinnedVersions[branch]
+}
+
 // CarbonRelease represents a Carbon release from the API
 type CarbonRelease struct {
 	Name       string `json:"name"`
@@ -44,37 +75,22 @@ type CarbonRelease struct {
 	Prerelease bool   `json:"prerelease"`
 }
 
-// getBranchLock gets or creates an RWMutex for a specific branch
-func getBranchLock(branch string) *sync.RWMutex {
-	branchMutex.Lock()
-	defer branchMutex.Unlock()
-
-	if lock, exists := branchLocks[branch]; exists {
-		return lock
-	}
-
-	lock := &sync.RWMutex{}
-	branchLocks[branch] = lock
-	return lock
-}
-
 // AcquireReadLock acquires a read lock for a branch (used by syncServer)
 // Returns an unlock function that must be called when done reading
 func AcquireReadLock(branch string) func() {
-	if branch == "" || branch == "main" {
+	if branch == "" {
 		branch = "main"
 	}
-	lock := getBranchLock(branch)
-	lock.RLock()
-	log.Printf("Acquired Carbon read lock for branch '%s'", branch)
-	return func() {
-		lock.RUnlock()
-		log.Printf("Released Carbon read lock for branch '%s'", branch)
-	}
+	return branchLocks.AcquireRead(branch)
+}
+
+// IsBranchInstalled reports whether Carbon has an install for a branch under CarbonBase
+func IsBranchInstalled(branch string) bool {
+	return isCarbonInstalled(GetCarbonPath(branch))
 }
 
-// getCarbonPath returns the installation path for a branch
-func getCarbonPath(branch string) string {
+// GetCarbonPath returns the installation path for a branch
+func GetCarbonPath(branch string) string {
 	if branch == "" || branch == "main" {
 		return filepath.Join(CarbonBase, "main")
 	}
@@ -88,9 +104,11 @@ func isCarbonInstalled(path string) bool {
 	return err == nil
 }
 
-// CheckForCarbonUpdates checks if Carbon has updates available
-func CheckForCarbonUpdates(branch, webhookURL string) (bool, string, error) {
-	installPath := getCarbonPath(branch)
+// CheckForCarbonUpdates checks if Carbon has updates available.
+// A pinned branch never offers updates: it reports its pinned version (suffixed ", pinned") when
+// that is installed, or asks for the pinned version to be installed when it isn't.
+func CheckForCarbonUpdates(branch string, notifier notify.Notifier) (bool, string, error) {
+	installPath := GetCarbonPath(branch)
 
 	// Check if Carbon is installed
 	if !isCarbonInstalled(installPath) {
@@ -101,24 +119,32 @@ func CheckForCarbonUpdates(branch, webhookURL string) (bool, string, error) {
 	versionPath := filepath.Join(installPath, "version.txt")
 	currentVersionData, err := os.ReadFile(versionPath)
 	if err != nil {
-		log.Printf("Warning: Could not read current Carbon version for %s: %v", branch, err)
+		logging.Warnf("Warning: Could not read current Carbon version for %s: %v", branch, err)
 		return false, "", nil
 	}
 	currentVersion := strings.TrimSpace(string(currentVersionData))
 
+	if pinned := PinnedVersion(branch); pinned != "" {
+		if currentVersion != pinned {
+			logging.Infof("Carbon for branch %s is pinned to %s but %s is installed", branch, pinned, currentVersion)
+			return true, pinned, nil
+		}
+		return false, pinned + ", pinned", nil
+	}
+
 	// Get latest version from Carbon API
 	latestVersion, err := getLatestCarbonVersion(branch)
 	if err != nil {
-		log.Printf("Error checking for Carbon updates: %v", err)
+		logging.Errorf("Error checking for Carbon updates: %v", err)
 		return false, "", err
 	}
 
 	// Compare versions
 	if currentVersion != latestVersion {
-		log.Printf("Carbon update available for branch %s: %s -> %s", branch, currentVersion, latestVersion)
+		logging.Infof("Carbon update available for branch %s: %s -> %s", branch, currentVersion, latestVersion)
 
 		// Send notification
-		discord.SendInfo(webhookURL, "Carbon Update Available",
+		notifier.Info("Carbon Update Available",
 			fmt.Sprintf("Carbon has an update available\n\nCurrent: **%s**\nAvailable: **%s**",
 				currentVersion, latestVersion))
 
@@ -137,13 +163,13 @@ func getBuildNameForBranch(branch string) string {
 		return "rustbeta_staging_build"
 	}
 	// Default to production for unknown branches
-	log.Printf("Warning: Unknown Carbon branch '%s', defaulting to production_build", branch)
+	logging.Warnf("Warning: Unknown Carbon branch '%s', defaulting to production_build", branch)
 	return "production_build"
 }
 
 // getLatestCarbonVersion queries the Carbon API for the latest version of a branch
 func getLatestCarbonVersion(branch string) (string, error) {
-	resp, err := http.Get(CarbonReleasesAPI)
+	resp, err := httpclient.New().Get(CarbonReleasesAPI)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch Carbon API: %w", err)
 	}
@@ -187,44 +213,36 @@ func GetCarbonDownloadURL(branch string) string {
 		return CarbonStagingURL
 	}
 	// Default to main for unknown branches
-	log.Printf("Warning: Unknown Carbon branch '%s', defaulting to main", branch)
+	logging.Warnf("Warning: Unknown Carbon branch '%s', defaulting to main", branch)
 	return CarbonMainURL
 }
 
-// InstallCarbon installs Carbon for a specific branch
-func InstallCarbon(branch, webhookURL string) error {
-	// Check if this branch is already being installed
-	installingMutex.Lock()
-	if installingBranches[branch] {
-		installingMutex.Unlock()
-		log.Printf("Carbon for branch '%s' is already being installed, skipping", branch)
-		return nil
-	}
-	installingBranches[branch] = true
-	installingMutex.Unlock()
-
-	// Ensure we mark installation as complete when done
-	defer func() {
-		installingMutex.Lock()
-		delete(installingBranches, branch)
-		installingMutex.Unlock()
-	}()
-
+// InstallCarbon installs Carbon for a specific branch: its pinned version if it has one, otherwise the latest build
+func InstallCarbon(branch string, notifier notify.Notifier) error {
 	// Normalize branch for lock acquisition
 	lockBranch := branch
 	if lockBranch == "" {
 		lockBranch = "main"
 	}
 
+	// Check if this branch is already being installed
+	if !branchLocks.StartInstall(lockBranch) {
+		logging.Infof("Carbon for branch '%s' is already being installed, skipping", branch)
+		return nil
+	}
+	defer branchLocks.FinishInstall(lockBranch)
+
 	// Acquire WRITE lock for this branch to block syncServer reads during install
-	branchLock := getBranchLock(lockBranch)
-	branchLock.Lock()
-	defer branchLock.Unlock()
+	defer branchLocks.AcquireWrite(lockBranch)()
 
-	installPath := getCarbonPath(branch)
+	installPath := GetCarbonPath(branch)
+	pinned := PinnedVersion(branch)
 	downloadURL := GetCarbonDownloadURL(branch)
+	if pinned != "" {
+		downloadURL = fmt.Sprintf(CarbonVersionURL, pinned)
+	}
 
-	log.Printf("Installing Carbon for branch '%s' to %s", branch, installPath)
+	logging.Infof("Installing Carbon for branch '%s' to %s", branch, installPath)
 
 	// Read old tarball hash BEFORE wiping the directory
 	oldHash := ""
@@ -242,28 +260,37 @@ func InstallCarbon(branch, webhookURL string) error {
 
 	// Download Carbon to a temp file first so we can hash before committing
 	tmpTarPath := filepath.Join(os.TempDir(), fmt.Sprintf("carbon-%s.tar.gz", branch))
-	log.Printf("Downloading Carbon from %s...", downloadURL)
+	logging.Infof("Downloading Carbon from %s...", downloadURL)
 
-	if err := downloadFile(downloadURL, tmpTarPath); err != nil {
+	if err := installer.DownloadFile(downloadURL, tmpTarPath); err != nil {
 		errMsg := fmt.Sprintf("failed to download Carbon: %v", err)
-		discord.SendError(webhookURL, "Carbon Installation Failed",
+		notifier.Error("Carbon Installation Failed",
 			fmt.Sprintf("Failed to install Carbon for branch **%s**\n\n%s", branch, errMsg))
 		return fmt.Errorf("%s", errMsg)
 	}
 	defer os.Remove(tmpTarPath) // Clean up temp file
 
 	// Hash the downloaded tarball
-	newHash, err := hashFile(tmpTarPath)
+	newHash, err := installer.HashFile(tmpTarPath)
 	if err != nil {
-		log.Printf("Warning: Could not hash downloaded tarball: %v", err)
+		logging.Warnf("Warning: Could not hash downloaded tarball: %v", err)
 		// Continue anyway - better to install than skip
 	}
 
+	// A pinned version that is already installed only needs its version recorded
+	if pinned != "" && oldHash != "" && newHash == oldHash {
+		logging.Infof("Carbon for branch '%s' is already at pinned version %s", branch, pinned)
+		if err := os.WriteFile(versionPath, []byte(pinned), 0644); err != nil {
+			logging.Warnf("Warning: Could not write version file: %v", err)
+		}
+		return nil
+	}
+
 	// If hash matches the old install, the CDN served stale content
 	if oldHash != "" && newHash == oldHash {
-		log.Printf("Warning: Downloaded Carbon tarball hash matches previous install (hash: %s)", newHash[:12])
-		log.Printf("The download source may be serving cached/stale content — skipping update")
-		discord.SendWarning(webhookURL, "Carbon Update Stale",
+		logging.Warnf("Warning: Downloaded Carbon tarball hash matches previous install (hash: %s)", newHash[:12])
+		logging.Warnf("The download source may be serving cached/stale content — skipping update")
+		notifier.Warning("Carbon Update Stale",
 			fmt.Sprintf("Carbon update for branch **%s** was detected by the API, "+
 				"but the download source served identical content (possible CDN cache).\n\n"+
 				"The update will be retried on the next check cycle.", branch))
@@ -275,7 +302,7 @@ func InstallCarbon(branch, webhookURL string) error {
 	// Remove old branch directory
 	if err := os.RemoveAll(installPath); err != nil {
 		errMsg := fmt.Sprintf("failed to remove old Carbon directory: %v", err)
-		discord.SendError(webhookURL, "Carbon Installation Failed",
+		notifier.Error("Carbon Installation Failed",
 			fmt.Sprintf("Failed to install Carbon for branch **%s**\n\n%s", branch, errMsg))
 		return fmt.Errorf("%s", errMsg)
 	}
@@ -283,7 +310,7 @@ func InstallCarbon(branch, webhookURL string) error {
 	// Create fresh Carbon directory
 	if err := os.MkdirAll(installPath, 0755); err != nil {
 		errMsg := fmt.Sprintf("failed to create Carbon directory: %v", err)
-		discord.SendError(webhookURL, "Carbon Installation Failed",
+		notifier.Error("Carbon Installation Failed",
 			fmt.Sprintf("Failed to install Carbon for branch **%s**\n\n%s", branch, errMsg))
 		return fmt.Errorf("%s", errMsg)
 	}
@@ -292,134 +319,93 @@ func InstallCarbon(branch, webhookURL string) error {
 	tarPath := filepath.Join(installPath, "carbon.tar.gz")
 	if err := os.Rename(tmpTarPath, tarPath); err != nil {
 		// Rename may fail across filesystems, fall back to copy
-		if err := copyFile(tmpTarPath, tarPath); err != nil {
+		if err := installer.CopyFile(tmpTarPath, tarPath); err != nil {
 			errMsg := fmt.Sprintf("failed to move Carbon tarball: %v", err)
-			discord.SendError(webhookURL, "Carbon Installation Failed",
+			notifier.Error("Carbon Installation Failed",
 				fmt.Sprintf("Failed to install Carbon for branch **%s**\n\n%s", branch, errMsg))
 			return fmt.Errorf("%s", errMsg)
 		}
 	}
 
-	log.Printf("Extracting Carbon...")
+	logging.Infof("Extracting Carbon...")
 	if err := extractTarGz(tarPath, installPath); err != nil {
 		errMsg := fmt.Sprintf("failed to extract Carbon: %v", err)
-		discord.SendError(webhookURL, "Carbon Installation Failed",
+		notifier.Error("Carbon Installation Failed",
 			fmt.Sprintf("Failed to install Carbon for branch **%s**\n\n%s", branch, errMsg))
 		return fmt.Errorf("%s", errMsg)
 	}
 
 	// Download RustEdit extension
-	log.Printf("Downloading RustEdit extension...")
+	logging.Infof("Downloading RustEdit extension...")
 	rustEditPath := filepath.Join(installPath, "carbon", "extensions", "Oxide.Ext.RustEdit.dll")
 	if err := os.MkdirAll(filepath.Dir(rustEditPath), 0755); err == nil {
-		if err := downloadFile(RustEditURL, rustEditPath); err != nil {
-			log.Printf("Warning: Failed to download RustEdit extension: %v", err)
+		if err := installer.DownloadFile(RustEditURL, rustEditPath); err != nil {
+			logging.Warnf("Warning: Failed to download RustEdit extension: %v", err)
 			// Not critical, continue
 		}
 	}
 
-	// Get latest version from API and save it
-	version, err := getLatestCarbonVersion(branch)
-	if err != nil {
-		log.Printf("Warning: Could not get Carbon version: %v", err)
-		version = "unknown"
+	// Record the pinned version, or get the latest version from the API
+	version := pinned
+	if version == "" {
+		if version, err = getLatestCarbonVersion(branch); err != nil {
+			logging.Warnf("Warning: Could not get Carbon version: %v", err)
+			version = "unknown"
+		}
 	}
 
 	if err := os.WriteFile(versionPath, []byte(version), 0644); err != nil {
-		log.Printf("Warning: Could not write version file: %v", err)
+		logging.Warnf("Warning: Could not write version file: %v", err)
 	}
 
 	// Save tarball hash for future comparison
 	if newHash != "" {
 		if err := os.WriteFile(hashPath, []byte(newHash), 0644); err != nil {
-			log.Printf("Warning: Could not write hash file: %v", err)
+			logging.Warnf("Warning: Could not write hash file: %v", err)
 		}
 	}
 
 	// Clean up tar file
 	os.Remove(tarPath)
 
-	log.Printf("✓ Successfully installed Carbon for branch '%s' (version: %s)", branch, version)
+	logging.Infof("✓ Successfully installed Carbon for branch '%s' (version: %s)", branch, version)
 	if oldVersion != "" && oldVersion != version {
-		discord.SendSuccess(webhookURL, "Carbon Update Complete",
+		notifier.Success("Carbon Update Complete",
 			fmt.Sprintf("Carbon for branch **%s** updated\n\nFrom: **%s**\nTo: **%s**", branch, oldVersion, version))
 	} else {
-		discord.SendSuccess(webhookURL, "Carbon Installation Complete",
+		notifier.Success("Carbon Installation Complete",
 			fmt.Sprintf("Carbon for branch **%s** installed successfully\n\nVersion: **%s**", branch, version))
 	}
 
 	return nil
 }
 
-// EnsureCarbonInstalled checks if Carbon is installed and installs it if not
-func EnsureCarbonInstalled(branch, webhookURL string) error {
-	installPath := getCarbonPath(branch)
+// EnsureCarbonInstalled checks if Carbon is installed (at its pinned version, if any) and installs it if not
+func EnsureCarbonInstalled(branch string, notifier notify.Notifier) error {
+	installPath := GetCarbonPath(branch)
 
 	// Check if Carbon is already installed
 	if isCarbonInstalled(installPath) {
-		log.Printf("Carbon for branch '%s' already installed at %s", branch, installPath)
-		return nil
-	}
-
-	log.Printf("Carbon for branch '%s' not found at %s, installing...", branch, installPath)
-	return InstallCarbon(branch, webhookURL)
-}
-
-// hashFile computes the SHA-256 hash of a file
-func hashFile(path string) (string, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return "", err
-	}
-	defer f.Close()
-
-	h := sha256.New()
-	if _, err := io.Copy(h, f); err != nil {
-		return "", err
-	}
-	return hex.EncodeToString(h.Sum(nil)), nil
-}
-
-// copyFile copies a file from src to dst
-func copyFile(src, dst string) error {
-	in, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer in.Close()
-
-	out, err := os.Create(dst)
-	if err != nil {
-		return err
+		pinned := PinnedVersion(branch)
+		if pinned == "" || installedVersion(installPath) == pinned {
+			logging.Infof("Carbon for branch '%s' already installed at %s", branch, installPath)
+			return nil
+		}
+		logging.Infof("Carbon for branch '%s' is not at pinned version %s, installing...", branch, pinned)
+		return InstallCarbon(branch, notifier)
 	}
-	defer out.Close()
 
-	if _, err := io.Copy(out, in); err != nil {
-		return err
-	}
-	return out.Close()
+	logging.Infof("Carbon for branch '%s' not found at %s, installing...", branch, installPath)
+	return InstallCarbon(branch, notifier)
 }
 
-// downloadFile downloads a file from a URL
-func downloadFile(url, filepath string) error {
-	resp, err := http.Get(url)
+// installedVersion returns the version recorded in an install's version.txt, or "" if there is none
+func installedVersion(installPath string) string {
+	data, err := os.ReadFile(filepath.Join(installPath, "version.txt"))
 	if err != nil {
-		return err
+		return ""
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
-
-	out, err := os.Create(filepath)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-
-	_, err = io.Copy(out, resp.Body)
-	return err
+	return strings.TrimSpace(string(data))
 }
 
 // extractTarGz extracts a tar.gz file to a destination
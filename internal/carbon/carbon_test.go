@@ -0,0 +1,143 @@
+package carbon
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maintc/wipe-cli/internal/notify"
+)
+
+// useTestCarbonBase points CarbonBase at a temp dir and clears pins for the duration of a test
+func useTestCarbonBase(t *testing.T) string {
+	t.Helper()
+
+	origBase := CarbonBase
+	t.Cleanup(func() {
+		CarbonBase = origBase
+		SetPinnedVersions(nil)
+	})
+
+	CarbonBase = t.TempDir()
+	return CarbonBase
+}
+
+// writeInstall creates a fake Carbon install for branch with the given version.txt
+func writeInstall(t *testing.T, branch, version string) {
+	t.Helper()
+
+	managed := filepath.Join(GetCarbonPath(branch), "carbon", "managed")
+	if err := os.MkdirAll(managed, 0755); err != nil {
+		t.Fatalf("Failed to create install: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(managed, "Carbon.dll"), []byte("dll"), 0644); err != nil {
+		t.Fatalf("Failed to write Carbon.dll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(GetCarbonPath(branch), "version.txt"), []byte(version), 0644); err != nil {
+		t.Fatalf("Failed to write version.txt: %v", err)
+	}
+}
+
+// carbonTarball builds a Carbon release archive containing carbon/managed/Carbon.dll
+func carbonTarball(t *testing.T, contents string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, dir := range []string{"carbon/", "carbon/managed/"} {
+		if err := tw.WriteHeader(&tar.Header{Name: dir, Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+			t.Fatalf("Failed to write tar header: %v", err)
+		}
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "carbon/managed/Carbon.dll", Mode: 0644, Size: int64(len(contents))}); err != nil {
+		t.Fatalf("Failed to write tar header: %v", err)
+	}
+	if _, err := tw.Write([]byte(contents)); err != nil {
+		t.Fatalf("Failed to write tar contents: %v", err)
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestCheckForCarbonUpdates_Pinned(t *testing.T) {
+	useTestCarbonBase(t)
+	SetPinnedVersions(map[string]string{"main": "2.0.100"})
+
+	tests := []struct {
+		name        string
+		installed   string
+		wantUpdate  bool
+		wantVersion string
+	}{
+		{"at pinned version", "2.0.100", false, "2.0.100, pinned"},
+		{"newer than pinned", "2.0.200", true, "2.0.100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeInstall(t, "main", tt.installed)
+
+			// The Carbon API is never queried for a pinned branch
+			hasUpdate, version, err := CheckForCarbonUpdates("main", notify.Discard)
+			if err != nil {
+				t.Fatalf("CheckForCarbonUpdates() returned error: %v", err)
+			}
+			if hasUpdate != tt.wantUpdate || version != tt.wantVersion {
+				t.Errorf("CheckForCarbonUpdates() = %v, %q, want %v, %q", hasUpdate, version, tt.wantUpdate, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestInstallCarbon_Pinned(t *testing.T) {
+	useTestCarbonBase(t)
+
+	var requested []string
+	tarball := carbonTarball(t, "pinned build")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path == "/2.0.100/Carbon.Linux.Release.tar.gz" {
+			w.Write(tarball)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	origVersionURL, origRustEditURL := CarbonVersionURL, RustEditURL
+	defer func() { CarbonVersionURL, RustEditURL = origVersionURL, origRustEditURL }()
+	CarbonVersionURL = server.URL + "/%s/Carbon.Linux.Release.tar.gz"
+	RustEditURL = server.URL + "/rustedit.dll"
+
+	SetPinnedVersions(map[string]string{"staging": "2.0.100"})
+	if err := EnsureCarbonInstalled("staging", notify.Discard); err != nil {
+		t.Fatalf("EnsureCarbonInstalled() returned error: %v", err)
+	}
+
+	if !IsBranchInstalled("staging") {
+		t.Fatal("IsBranchInstalled() = false after installing pinned version")
+	}
+	if got := installedVersion(GetCarbonPath("staging")); got != "2.0.100" {
+		t.Errorf("version.txt = %q, want %q", got, "2.0.100")
+	}
+	if len(requested) == 0 || !strings.HasPrefix(requested[0], "/2.0.100/") {
+		t.Errorf("downloaded %v, want the pinned release first", requested)
+	}
+
+	// Already at the pinned version: nothing is downloaded again
+	requested = nil
+	if err := EnsureCarbonInstalled("staging", notify.Discard); err != nil {
+		t.Fatalf("EnsureCarbonInstalled() returned error: %v", err)
+	}
+	if len(requested) != 0 {
+		t.Errorf("downloaded %v for an install already at the pinned version", requested)
+	}
+}
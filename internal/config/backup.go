@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// BackupDir is the directory, next to the config file, that holds timestamped config backups
+const BackupDir = "backups"
+
+// BackupConfig writes the current resolved config (including defaults) to dest.
+// An empty dest writes a timestamped backup to the backups directory next to the config file.
+// Returns the path written.
+func BackupConfig(dest string) (string, error) {
+	if dest == "" {
+		configFile := ConfigFileUsed()
+		if configFile == "" {
+			return "", fmt.Errorf("no config file in use")
+		}
+		name := strings.TrimSuffix(filepath.Base(configFile), filepath.Ext(configFile))
+		dest = filepath.Join(filepath.Dir(configFile), BackupDir,
+			fmt.Sprintf("%s-%s.yaml", name, time.Now().Format("20060102-150405")))
+	}
+
+	// Viper picks the output format from the extension
+	if ext := filepath.Ext(dest); ext != ".yaml" && ext != ".yml" {
+		return "", fmt.Errorf("backup file %s must end in .yaml or .yml", dest)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	if err := viper.WriteConfigAs(dest); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+	return dest, nil
+}
+
+// ValidateConfigFile reads a config file without touching the live config and
+// returns the parsed config, or an error if it is malformed
+func ValidateConfigFile(path string) (*Config, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+
+	for i, server := range cfg.Servers {
+		if server.Name == "" || server.Path == "" {
+			return nil, fmt.Errorf("server %d in %s is missing a name or path", i+1, path)
+		}
+	}
+	return &cfg, nil
+}
+
+// RestoreConfig validates the config file at src and replaces the live config file with it
+func RestoreConfig(src string) error {
+	if _, err := ValidateConfigFile(src); err != nil {
+		return err
+	}
+
+	configFile := ConfigFileUsed()
+	if configFile == "" {
+		return fmt.Errorf("no config file in use")
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+
+	// Write to a temp file and rename so a failure never leaves a half-written config
+	tmp := configFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := os.Rename(tmp, configFile); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace config: %w", err)
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+	return nil
+}
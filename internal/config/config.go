@@ -1,10 +1,18 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,42 +20,389 @@ import (
 const (
 	ConfigDir  = ".config/wiped"
 	ConfigFile = "config.yaml"
+	// EnvPrefix starts the environment variables that override global settings, e.g. WIPE_CHECK_INTERVAL
+	EnvPrefix = "WIPE_"
+	// HistoryFile is the default execution history file, in the config directory
+	HistoryFile = "history.jsonl"
+)
+
+// Notification services selectable with the notifier setting
+const (
+	NotifierDiscord = "discord"
+	NotifierSlack   = "slack"
+)
+
+// Sources the daemon can check for Rust updates, selected with the update_check_source setting
+const (
+	UpdateCheckSteamCMD = "steamcmd"
+	UpdateCheckHTTP     = "http"
+)
+
+// Mod frameworks a server can run, selected with its framework field
+const (
+	FrameworkCarbon = "carbon"
+	FrameworkOxide  = "oxide"
 )
 
 var (
 	// CustomConfigPath allows overriding the default config path
 	// Useful for testing or alternative deployments
 	CustomConfigPath string
+
+	// discordSnowflakeRegex matches a Discord snowflake ID (17-20 digits)
+	discordSnowflakeRegex = regexp.MustCompile(`^[0-9]{17,20}$`)
+	rustBuildIDRegex      = regexp.MustCompile(`^[0-9]+$`)
 )
 
 // Server represents a Rust server to monitor
 type Server struct {
-	Name           string `mapstructure:"name" yaml:"name"`
-	Path           string `mapstructure:"path" yaml:"path"`
-	CalendarURL    string `mapstructure:"calendar_url" yaml:"calendar_url"`
-	Branch         string `mapstructure:"branch" yaml:"branch"`                   // Rust server branch (default: main)
-	WipeBlueprints bool   `mapstructure:"wipe_blueprints" yaml:"wipe_blueprints"` // Whether to delete blueprints on wipe (default: false)
-	GenerateMap    bool   `mapstructure:"generate_map" yaml:"generate_map"`       // Whether to generate maps via generate-maps.sh (default: false)
+	Name           string   `mapstructure:"name" json:"name" yaml:"name"`
+	Path           string   `mapstructure:"path" json:"path" yaml:"path"`
+	CalendarURL    string   `mapstructure:"calendar_url" json:"calendar_url" yaml:"calendar_url"`
+	CalendarURLs   []string `mapstructure:"calendar_urls" json:"calendar_urls,omitempty" yaml:"calendar_urls"`              // Extra calendars merged with calendar_url (e.g. separate restart and wipe calendars)
+	Branch         string   `mapstructure:"branch" json:"branch" yaml:"branch"`                                             // Rust server branch (default: main)
+	Framework      string   `mapstructure:"framework" json:"framework,omitempty" yaml:"framework,omitempty"`                // Mod framework: carbon or oxide (default: carbon)
+	CarbonVersion  string   `mapstructure:"carbon_version" json:"carbon_version,omitempty" yaml:"carbon_version,omitempty"` // Pin the server's branch to this Carbon release instead of auto-updating (empty follows the latest)
+	RustBuildID    string   `mapstructure:"rust_build_id" json:"rust_build_id,omitempty" yaml:"rust_build_id,omitempty"`    // Hold the server's branch at this Rust build instead of auto-updating (empty follows the latest)
+	WipeBlueprints bool     `mapstructure:"wipe_blueprints" json:"wipe_blueprints" yaml:"wipe_blueprints"`                  // Whether to delete blueprints on wipe (default: false)
+	GenerateMap    bool     `mapstructure:"generate_map" json:"generate_map" yaml:"generate_map"`                           // Whether to generate maps via generate-maps.sh (default: false)
+	Identity       string   `mapstructure:"identity" json:"identity" yaml:"identity"`                                       // Rust server identity (default: basename of path)
+	MatchPatterns  []string `mapstructure:"match_patterns" json:"match_patterns" yaml:"match_patterns"`                     // Regexes for event keywords in calendar summaries (default: exact match)
+	// Credentials for calendars that need them (alternatively, embed user:pass@ in calendar_url for basic auth)
+	CalendarAuthHeader string `mapstructure:"calendar_auth_header" json:"calendar_auth_header,omitempty" yaml:"calendar_auth_header"` // Header the token is sent in (default: Authorization)
+	CalendarAuthToken  string `mapstructure:"calendar_auth_token" json:"calendar_auth_token,omitempty" yaml:"calendar_auth_token"`    // Header value, e.g. "Bearer abc123" (empty for no auth)
+	// Overrides of the global settings for this server (unset uses the global value)
+	EventDelay     *int `mapstructure:"event_delay" json:"event_delay,omitempty" yaml:"event_delay,omitempty"`             // Seconds to wait after event time before executing
+	LookaheadHours *int `mapstructure:"lookahead_hours" json:"lookahead_hours,omitempty" yaml:"lookahead_hours,omitempty"` // How far ahead to look for events
+	// WebRCON used to warn players in-game during event_delay (empty address disables)
+	RconAddress  string `mapstructure:"rcon_address" json:"rcon_address,omitempty" yaml:"rcon_address,omitempty"`    // host:port of the server's WebRCON, e.g. 127.0.0.1:28016
+	RconPassword string `mapstructure:"rcon_password" json:"rcon_password,omitempty" yaml:"rcon_password,omitempty"` // The server's rcon.password
+}
+
+// Redacted returns a copy of the server with credentials (the calendar auth token, any
+// password in the calendar URL and the RCON password) replaced by RedactedValue
+func (s Server) Redacted() Server {
+	if s.CalendarAuthToken != "" {
+		s.CalendarAuthToken = RedactedValue
+	}
+	if s.RconPassword != "" {
+		s.RconPassword = RedactedValue
+	}
+	s.CalendarURL = redactURLPassword(s.CalendarURL)
+	if s.CalendarURLs != nil {
+		urls := make([]string, len(s.CalendarURLs))
+		for i, calendarURL := range s.CalendarURLs {
+			urls[i] = redactURLPassword(calendarURL)
+		}
+		s.CalendarURLs = urls
+	}
+	return s
+}
+
+// redactURLPassword replaces the password embedded in a URL, if any, with RedactedValue
+func redactURLPassword(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), RedactedValue)
+			return u.String()
+		}
+	}
+	return rawURL
+}
+
+// AllCalendarURLs returns calendar_url followed by calendar_urls, without blanks or repeats
+func (s Server) AllCalendarURLs() []string {
+	seen := make(map[string]bool)
+	var urls []string
+	for _, calendarURL := range append([]string{s.CalendarURL}, s.CalendarURLs...) {
+		if calendarURL == "" || seen[calendarURL] {
+			continue
+		}
+		seen[calendarURL] = true
+		urls = append(urls, calendarURL)
+	}
+	return urls
+}
+
+// GetEventDelay returns the server's event_delay, or global when it doesn't override it
+func (s Server) GetEventDelay(global int) int {
+	if s.EventDelay != nil {
+		return *s.EventDelay
+	}
+	return global
+}
+
+// GetLookaheadHours returns the server's lookahead_hours, or global when it doesn't override it
+func (s Server) GetLookaheadHours(global int) int {
+	if s.LookaheadHours != nil {
+		return *s.LookaheadHours
+	}
+	return global
+}
+
+// GetFramework returns the server's mod framework, defaulting to carbon
+func (s Server) GetFramework() string {
+	if s.Framework == "" {
+		return FrameworkCarbon
+	}
+	return s.Framework
+}
+
+// GetIdentity returns the Rust server identity, defaulting to the basename of the server path
+func (s Server) GetIdentity() string {
+	if s.Identity != "" {
+		return s.Identity
+	}
+	return filepath.Base(s.Path)
+}
+
+// OneOffEvent is an ad-hoc event scheduled with 'wipe trigger --at' instead of a calendar
+type OneOffEvent struct {
+	Server string `mapstructure:"server" json:"server" yaml:"server"` // Server name or path
+	Type   string `mapstructure:"type" json:"type" yaml:"type"`       // restart, restart-nosync, or wipe
+	At     string `mapstructure:"at" json:"at" yaml:"at"`             // Start time in RFC3339 format
+}
+
+// CancelledEvent is a scheduled event removed with 'wipe cancel'
+type CancelledEvent struct {
+	Server string `mapstructure:"server" json:"server" yaml:"server"` // Server path (older entries may hold its name)
+	At     string `mapstructure:"at" json:"at" yaml:"at"`             // Event time in RFC3339 format
 }
 
 // Config holds the application configuration
 type Config struct {
+	// Schema version of the config file (see ConfigVersion); older files are migrated on load
+	ConfigVersion int `mapstructure:"config_version" json:"config_version"`
 	// How far ahead to look for events (in hours)
-	LookaheadHours int `mapstructure:"lookahead_hours"`
+	LookaheadHours int `mapstructure:"lookahead_hours" json:"lookahead_hours"`
+	// Upper bound on lookahead_hours, so a huge window can't expand into thousands of events
+	MaxLookaheadHours int `mapstructure:"max_lookahead_hours" json:"max_lookahead_hours"`
 	// How often to check calendars (in seconds)
-	CheckInterval int `mapstructure:"check_interval"`
+	CheckInterval int `mapstructure:"check_interval" json:"check_interval"`
 	// How long to wait after event time before executing (in seconds)
-	EventDelay int `mapstructure:"event_delay"`
+	EventDelay int `mapstructure:"event_delay" json:"event_delay"`
 	// Discord webhook URL for notifications
-	DiscordWebhook string `mapstructure:"discord_webhook"`
+	DiscordWebhook string `mapstructure:"discord_webhook" json:"discord_webhook"`
 	// Discord user IDs to mention in notifications
-	DiscordMentionUsers []string `mapstructure:"discord_mention_users"`
+	DiscordMentionUsers []string `mapstructure:"discord_mention_users" json:"discord_mention_users"`
 	// Discord role IDs to mention in notifications
-	DiscordMentionRoles []string `mapstructure:"discord_mention_roles"`
+	DiscordMentionRoles []string `mapstructure:"discord_mention_roles" json:"discord_mention_roles"`
+	// Notification levels (success, info, warning, error) that mention the users and roles (default: error, warning)
+	DiscordMentionOn []string `mapstructure:"discord_mention_on" json:"discord_mention_on"`
+	// Where notifications are sent: discord or slack (default: discord)
+	Notifier string `mapstructure:"notifier" json:"notifier"`
+	// Slack incoming webhook URL for notifications (used when notifier is slack)
+	SlackWebhook string `mapstructure:"slack_webhook" json:"slack_webhook"`
+	// Slack member IDs (U…) to mention in notifications
+	SlackMentionUsers []string `mapstructure:"slack_mention_users" json:"slack_mention_users"`
+	// URL that receives a JSON post when a batch starts, completes or fails (empty to disable)
+	EventWebhookURL string `mapstructure:"event_webhook_url" json:"event_webhook_url"`
 	// How many hours before a wipe to generate the map (default: 24)
-	MapGenerationHours int `mapstructure:"map_generation_hours"`
+	MapGenerationHours int `mapstructure:"map_generation_hours" json:"map_generation_hours"`
+	// Seconds to wait between starting each server after a batch (default: 0, all at once)
+	StartStagger int `mapstructure:"start_stagger" json:"start_stagger"`
+	// How many servers to start together between each start_stagger wait (default: 1)
+	StartStaggerSize int `mapstructure:"start_stagger_size" json:"start_stagger_size"`
+	// Keep the previous Rust install as <branch>.prev for rollback (default: false)
+	KeepPreviousInstall bool `mapstructure:"keep_previous_install" json:"keep_previous_install"`
+	// Minutes to wait for 'wipe confirm' before running a wipe (default: 0, no confirmation)
+	WipeConfirmationMinutes int `mapstructure:"wipe_confirmation_minutes" json:"wipe_confirmation_minutes"`
+	// Seconds between healthcheck.sh probes of each server (default: 0, disabled)
+	HealthCheckInterval int `mapstructure:"health_check_interval" json:"health_check_interval"`
+	// Hours between "daemon healthy" heartbeat notifications, so silence means trouble (default: 0, disabled)
+	HeartbeatHours int `mapstructure:"heartbeat_hours" json:"heartbeat_hours"`
+	// Free memory in MB required per server before starting servers (default: 0, no check)
+	MinFreeMemoryMB int `mapstructure:"min_free_memory_mb" json:"min_free_memory_mb"`
+	// Servers stopped, synced, wiped and started per wave of a batch (default: 0, whole batch at once)
+	BatchSize int `mapstructure:"batch_size" json:"batch_size"`
+	// Attempts per Discord notification before giving up on 429/5xx responses (default: 3)
+	DiscordMaxAttempts int `mapstructure:"discord_max_attempts" json:"discord_max_attempts"`
+	// Seconds before an outbound HTTP request (calendars, Discord, downloads) gives up (default: 30)
+	HTTPTimeout int `mapstructure:"http_timeout" json:"http_timeout"`
+	// Maximum servers updated (rsync) at once during a batch or 'wipe sync' (default: 4)
+	MaxConcurrentSyncs int `mapstructure:"max_concurrent_syncs" json:"max_concurrent_syncs"`
+	// Move wiped files into <identity>/.wipe-backup/<timestamp> instead of deleting them, for 'wipe restore'
+	SafeWipe bool `mapstructure:"safe_wipe" json:"safe_wipe"`
+	// Wipe backups kept per server when safe_wipe is enabled; older ones are pruned (default: 3)
+	WipeBackupRetention int `mapstructure:"wipe_backup_retention" json:"wipe_backup_retention"`
+	// Free space (GB) required in /opt/rust before installing or updating a Rust branch (0 = disabled, default: 12)
+	MinFreeDiskGB int `mapstructure:"min_free_disk_gb" json:"min_free_disk_gb"`
+	// How often the daemon reloads the config file (in seconds, default: 10)
+	ConfigReloadInterval int `mapstructure:"config_reload_interval" json:"config_reload_interval"`
+	// How often the daemon checks steamcmd and Carbon for updates (in seconds, default: 120)
+	UpdateCheckInterval int `mapstructure:"update_check_interval" json:"update_check_interval"`
+	// Where the latest Rust build IDs come from: steamcmd or http (default: steamcmd)
+	UpdateCheckSource string `mapstructure:"update_check_source" json:"update_check_source"`
+	// Web API queried for build IDs when update_check_source is http (empty uses api.steamcmd.net)
+	UpdateCheckURL string `mapstructure:"update_check_url" json:"update_check_url"`
+	// Minutes before a scheduled event during which Rust and Carbon update installs are deferred (0 disables, default: 15)
+	UpdateDeferMinutes int `mapstructure:"update_defer_minutes" json:"update_defer_minutes"`
+	// JSONL file each executed batch is appended to (default: history.jsonl in the config directory)
+	HistoryFile string `mapstructure:"history_file" json:"history_file"`
+	// Minutes each stop, sync, pre-start hook and start step of a batch may run before it is killed (0 disables, default: 30)
+	StepTimeoutMinutes int `mapstructure:"step_timeout_minutes" json:"step_timeout_minutes"`
+	// Minutes each steamcmd install attempt may run before it is killed and retried (0 disables, default: 60)
+	SteamCMDTimeoutMinutes int `mapstructure:"steamcmd_timeout_minutes" json:"steamcmd_timeout_minutes"`
+	// Seconds before servers are stopped at which RCON warnings are sent; only those within event_delay are used (default: 300, 60, 10)
+	RconWarnings []int `mapstructure:"rcon_warnings" json:"rcon_warnings"`
+	// In-game RCON warning; {event} becomes Wipe or Restart and {time} the time left (default: "{event} in {time}")
+	RconWarningMessage string `mapstructure:"rcon_warning_message" json:"rcon_warning_message"`
+	// Fetch the calendar again after event_delay and cancel wipes whose event was removed in the meantime
+	RecheckCalendarBeforeWipe bool `mapstructure:"recheck_calendar_before_wipe" json:"recheck_calendar_before_wipe"`
+	// Address (host:port) the daemon serves Prometheus metrics on at /metrics (empty to disable)
+	MetricsAddr string `mapstructure:"metrics_addr" json:"metrics_addr"`
+	// Address (host:port) the daemon serves a /healthz liveness and readiness probe on (empty to disable)
+	HealthAddr string `mapstructure:"health_addr" json:"health_addr"`
 	// Servers to monitor
-	Servers []Server `mapstructure:"servers"`
+	Servers []Server `mapstructure:"servers" json:"servers"`
+	// One-off events injected with 'wipe trigger --at'
+	OneOffEvents []OneOffEvent `mapstructure:"one_off_events" json:"one_off_events"`
+	// Scheduled events removed with 'wipe cancel'
+	CancelledEvents []CancelledEvent `mapstructure:"cancelled_events" json:"cancelled_events"`
+}
+
+// RedactedValue replaces secrets in output that may be shared or logged
+const RedactedValue = "<redacted>"
+
+// Redacted returns a copy of the config with secrets (the Discord, Slack and event webhooks
+// and server credentials) replaced by RedactedValue
+func (c Config) Redacted() Config {
+	if c.Servers != nil {
+		servers := make([]Server, len(c.Servers))
+		for i, server := range c.Servers {
+			servers[i] = server.Redacted()
+		}
+		c.Servers = servers
+	}
+
+	if c.DiscordWebhook != "" {
+		c.DiscordWebhook = RedactedValue
+	}
+	if c.SlackWebhook != "" {
+		c.SlackWebhook = RedactedValue
+	}
+	if c.EventWebhookURL != "" {
+		c.EventWebhookURL = RedactedValue
+	}
+	return c
+}
+
+// settingDefaults lists global settings and their default values, in display order
+var settingDefaults = []struct {
+	key   string
+	value interface{}
+}{
+	{"lookahead_hours", 24},
+	{"max_lookahead_hours", 720},
+	{"check_interval", 30},
+	{"event_delay", 5},
+	{"discord_webhook", ""},
+	{"discord_mention_users", []string{}},
+	{"discord_mention_roles", []string{}},
+	{"discord_mention_on", []string{"error", "warning"}},
+	{"notifier", NotifierDiscord},
+	{"slack_webhook", ""},
+	{"slack_mention_users", []string{}},
+	{"event_webhook_url", ""},
+	{"map_generation_hours", 22},
+	{"start_stagger", 0},
+	{"start_stagger_size", 1},
+	{"keep_previous_install", false},
+	{"wipe_confirmation_minutes", 0},
+	{"health_check_interval", 0},
+	{"heartbeat_hours", 0},
+	{"min_free_memory_mb", 0},
+	{"batch_size", 0},
+	{"discord_max_attempts", 3},
+	{"http_timeout", 30},
+	{"max_concurrent_syncs", 4},
+	{"safe_wipe", false},
+	{"wipe_backup_retention", 3},
+	{"min_free_disk_gb", 12},
+	{"config_reload_interval", 10},
+	{"update_check_interval", 120},
+	{"update_check_source", UpdateCheckSteamCMD},
+	{"update_check_url", ""},
+	{"update_defer_minutes", 15},
+	{"history_file", ""},
+	{"step_timeout_minutes", 0},
+	{"steamcmd_timeout_minutes", 60},
+	{"rcon_warnings", []int{300, 60, 10}},
+	{"rcon_warning_message", "{event} in {time}"},
+	{"recheck_calendar_before_wipe", false},
+	{"metrics_addr", ""},
+	{"health_addr", ""},
+}
+
+// SettingSource describes where a setting's effective value came from
+type SettingSource string
+
+const (
+	SourceDefault SettingSource = "default"
+	SourceFile    SettingSource = "file"
+	SourceEnv     SettingSource = "env"
+)
+
+// SettingEnvVar returns the environment variable that overrides a global setting
+func SettingEnvVar(key string) string {
+	return EnvPrefix + strings.ToUpper(key)
+}
+
+var (
+	// explicitKeys holds the settings changed through this package since InitConfig.
+	// SaveConfig writes only these on top of what the file already holds.
+	explicitKeys  = make(map[string]bool)
+	explicitMutex sync.Mutex
+)
+
+// set changes a setting and records it as explicitly set, so SaveConfig writes it
+func set(key string, value interface{}) {
+	explicitMutex.Lock()
+	explicitKeys[key] = true
+	explicitMutex.Unlock()
+	viper.Set(key, value)
+}
+
+// isExplicit reports whether a setting was changed through this package since InitConfig
+func isExplicit(key string) bool {
+	explicitMutex.Lock()
+	defer explicitMutex.Unlock()
+	return explicitKeys[key]
+}
+
+// Setting is a global setting with its effective value and source
+type Setting struct {
+	Key     string
+	Value   interface{}
+	Default interface{}
+	Source  SettingSource
+}
+
+// ExplainSettings returns every global setting with its effective value and where it came from
+func ExplainSettings() ([]Setting, error) {
+	// Reload config from disk to pick up external changes
+	if _, err := GetConfig(); err != nil {
+		return nil, err
+	}
+
+	settings := make([]Setting, 0, len(settingDefaults))
+	for _, d := range settingDefaults {
+		// Precedence matches viper's: a value set (and saved) by this process, then the
+		// environment, then the file, then the default
+		source := SourceDefault
+		if _, ok := os.LookupEnv(SettingEnvVar(d.key)); ok && !isExplicit(d.key) {
+			source = SourceEnv
+		} else if isExplicit(d.key) || viper.InConfig(d.key) {
+			source = SourceFile
+		}
+		settings = append(settings, Setting{
+			Key:     d.key,
+			Value:   viper.Get(d.key),
+			Default: d.value,
+			Source:  source,
+		})
+	}
+	return settings, nil
 }
 
 // InitConfig initializes the configuration system
@@ -61,44 +416,56 @@ func InitConfig() {
 		configPath = filepath.Dir(CustomConfigPath)
 	} else {
 		// Default path
-		// When running under sudo, os.UserHomeDir() returns /root.
-		// Use SUDO_USER to resolve the original user's home directory.
-		home, err := getEffectiveHomeDir()
+		dir, err := GetConfigDir()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error getting home directory: %v\n", err)
 			return
 		}
 
-		configPath = filepath.Join(home, ConfigDir)
+		configPath = dir
 		viper.AddConfigPath(configPath)
 		viper.SetConfigName("config")
 		viper.SetConfigType("yaml")
 	}
 
-	// Set defaults
-	viper.SetDefault("lookahead_hours", 24)
-	viper.SetDefault("check_interval", 30)
-	viper.SetDefault("event_delay", 5)
-	viper.SetDefault("discord_webhook", "")
-	viper.SetDefault("discord_mention_users", []string{})
-	viper.SetDefault("discord_mention_roles", []string{})
-	viper.SetDefault("map_generation_hours", 22)
+	// Set defaults, and let a WIPE_<SETTING> environment variable override each one
+	for _, d := range settingDefaults {
+		viper.SetDefault(d.key, d.value)
+		_ = viper.BindEnv(d.key, SettingEnvVar(d.key))
+	}
 	viper.SetDefault("servers", []Server{})
+	viper.SetDefault("one_off_events", []OneOffEvent{})
+	viper.SetDefault("cancelled_events", []CancelledEvent{})
 
 	// Create config directory if it doesn't exist
 	if err := os.MkdirAll(configPath, 0755); err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating config directory: %v\n", err)
 	}
 
+	// Nothing has been set by this process yet
+	explicitMutex.Lock()
+	explicitKeys = make(map[string]bool)
+	explicitMutex.Unlock()
+
 	// Read config file if it exists
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			// Config file not found; create it with defaults
-			if err := viper.SafeWriteConfig(); err != nil {
+			// Config file not found; create it
+			if err := createConfigFile(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error creating config file: %v\n", err)
+			}
+		} else if CustomConfigPath != "" && os.IsNotExist(err) {
+			// Custom config file (--config) not found; create it.
+			// Profiles never get here: UseProfile rejects profiles that don't exist.
+			if err := createConfigFile(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error creating config file: %v\n", err)
 			}
 		}
 	}
+
+	if err := migrateConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error migrating config file: %v\n", err)
+	}
 }
 
 // getEffectiveHomeDir returns the home directory of the effective user.
@@ -125,6 +492,11 @@ func GetConfig() (*Config, error) {
 		}
 	}
 
+	// A restored or hand-replaced file may be from an older version
+	if err := migrateConfig(); err != nil {
+		return nil, err
+	}
+
 	var cfg Config
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
@@ -132,13 +504,76 @@ func GetConfig() (*Config, error) {
 	return &cfg, nil
 }
 
-// SaveConfig persists the configuration to disk
+// ConfigFileUsed returns the path of the config file in use
+func ConfigFileUsed() string {
+	return viper.ConfigFileUsed()
+}
+
+// configFilePath returns the config file in use, or where InitConfig creates it
+func configFilePath() (string, error) {
+	if configFile := viper.ConfigFileUsed(); configFile != "" {
+		return configFile, nil
+	}
+	if CustomConfigPath != "" {
+		return CustomConfigPath, nil
+	}
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ConfigFile), nil
+}
+
+// createConfigFile writes a new config file holding only its schema version, so every
+// setting keeps following its default until it is set, then loads it
+func createConfigFile() error {
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.Set("config_version", ConfigVersion)
+	if err := v.SafeWriteConfigAs(path); err != nil {
+		return err
+	}
+
+	viper.SetConfigFile(path)
+	return viper.ReadInConfig()
+}
+
+// SaveConfig persists the settings changed since InitConfig to disk.
+// Like migrateConfig it writes through a separate instance without defaults, so the file keeps
+// what it already held plus what was set explicitly, and settings left at their defaults (or
+// overridden from the environment) keep following them.
 func SaveConfig() error {
-	return viper.WriteConfig()
+	configFile, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	explicitMutex.Lock()
+	for key := range explicitKeys {
+		v.Set(key, viper.Get(key))
+	}
+	explicitMutex.Unlock()
+
+	return v.WriteConfigAs(configFile)
 }
 
+// ErrServerNameTaken is returned by AddServer when another server already has the name
+var ErrServerNameTaken = errors.New("server name already in use")
+
 // AddServer adds a new server to the configuration
-func AddServer(name, path, calendarURL, branch string, wipeBlueprints, generateMap bool) error {
+func AddServer(server Server) error {
 	cfg, err := GetConfig()
 	if err != nil {
 		return fmt.Errorf("failed to get config: %w", err)
@@ -146,56 +581,201 @@ func AddServer(name, path, calendarURL, branch string, wipeBlueprints, generateM
 
 	// Check if server path already exists
 	for _, s := range cfg.Servers {
-		if s.Path == path {
-			return fmt.Errorf("server with path %s already exists", path)
+		if s.Path == server.Path {
+			return fmt.Errorf("server with path %s already exists", server.Path)
+		}
+		if s.Name == server.Name {
+			return fmt.Errorf("%w: %s is used by the server at %s", ErrServerNameTaken, server.Name, s.Path)
 		}
 	}
 
 	// Default to main branch if not specified
-	if branch == "" {
-		branch = "main"
+	if server.Branch == "" {
+		server.Branch = "main"
+	}
+
+	if err := ValidateCalendarURL(server.CalendarURL); err != nil {
+		return err
+	}
+	for _, calendarURL := range server.CalendarURLs {
+		if err := ValidateCalendarURL(calendarURL); err != nil {
+			return err
+		}
+	}
+	if err := validateMatchPatterns(server.MatchPatterns); err != nil {
+		return err
+	}
+	if err := validateFramework(server.Framework); err != nil {
+		return err
+	}
+	if err := validateRustBuildID(server.RustBuildID); err != nil {
+		return err
 	}
 
 	// Add new server
-	cfg.Servers = append(cfg.Servers, Server{
-		Name:           name,
-		Path:           path,
-		CalendarURL:    calendarURL,
-		Branch:         branch,
-		WipeBlueprints: wipeBlueprints,
-		GenerateMap:    generateMap,
-	})
+	cfg.Servers = append(cfg.Servers, server)
 
 	// Update viper
-	viper.Set("servers", cfg.Servers)
+	set("servers", cfg.Servers)
 	return SaveConfig()
 }
 
-// RemoveServer removes a server from the configuration by path
-func RemoveServer(identifier string) error {
+// AddOneOffEvents appends one-off events to the configuration, dropping any that have already passed
+func AddOneOffEvents(events []OneOffEvent) error {
 	cfg, err := GetConfig()
 	if err != nil {
 		return fmt.Errorf("failed to get config: %w", err)
 	}
 
-	// Find and remove server (match by name or path)
-	found := false
+	now := time.Now()
+	kept := make([]OneOffEvent, 0, len(cfg.OneOffEvents)+len(events))
+	for _, e := range cfg.OneOffEvents {
+		at, err := time.Parse(time.RFC3339, e.At)
+		if err != nil || !at.After(now) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+
+	for _, e := range events {
+		if _, err := time.Parse(time.RFC3339, e.At); err != nil {
+			return fmt.Errorf("invalid time '%s' for server '%s': %w", e.At, e.Server, err)
+		}
+		kept = append(kept, e)
+	}
+
+	set("one_off_events", kept)
+	return SaveConfig()
+}
+
+// AddCancelledEvent records an event cancellation for the daemon, dropping any that have already passed
+func AddCancelledEvent(event CancelledEvent) error {
+	if _, err := time.Parse(time.RFC3339, event.At); err != nil {
+		return fmt.Errorf("invalid time '%s' for server '%s': %w", event.At, event.Server, err)
+	}
+
+	cfg, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	now := time.Now()
+	kept := make([]CancelledEvent, 0, len(cfg.CancelledEvents)+1)
+	for _, e := range cfg.CancelledEvents {
+		at, err := time.Parse(time.RFC3339, e.At)
+		if err != nil || !at.After(now) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	kept = append(kept, event)
+
+	set("cancelled_events", kept)
+	return SaveConfig()
+}
+
+// ValidateCalendarURL checks that a calendar URL is an absolute http(s) URL.
+// It doesn't fetch the calendar; the CLI does that unless --skip-validation is given.
+func ValidateCalendarURL(calendarURL string) error {
+	u, err := url.Parse(calendarURL)
+	if err != nil {
+		return fmt.Errorf("invalid calendar URL '%s': %w", calendarURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid calendar URL '%s': must start with http:// or https://", calendarURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid calendar URL '%s': missing host", calendarURL)
+	}
+	return nil
+}
+
+// validateFramework checks that a server's framework is one wipe can install (empty means carbon)
+func validateFramework(framework string) error {
+	if framework != "" && framework != FrameworkCarbon && framework != FrameworkOxide {
+		return fmt.Errorf("framework must be %s or %s", FrameworkCarbon, FrameworkOxide)
+	}
+	return nil
+}
+
+// validateRustBuildID checks that a Rust build pin is a Steam build ID (empty means unpinned)
+func validateRustBuildID(buildID string) error {
+	if buildID != "" && !rustBuildIDRegex.MatchString(buildID) {
+		return fmt.Errorf("invalid Rust build ID '%s': must be a number", buildID)
+	}
+	return nil
+}
+
+// validateMatchPatterns checks that each summary match pattern is a valid regular expression
+func validateMatchPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid match pattern '%s': %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// RemoveServer removes a server from the configuration by name or path
+func RemoveServer(identifier string) error {
+	notFound, err := RemoveServers([]string{identifier})
+	if err != nil {
+		return err
+	}
+	if len(notFound) > 0 {
+		return fmt.Errorf("server '%s' not found (try name or path)", identifier)
+	}
+	return nil
+}
+
+// RemoveServers removes every server matching one of identifiers (by name or path) in a single save.
+// Identifiers that match no server are returned rather than failing the others.
+func RemoveServers(identifiers []string) ([]string, error) {
+	cfg, err := GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	matched := make(map[string]bool)
 	newServers := make([]Server, 0)
 	for _, s := range cfg.Servers {
-		if s.Name != identifier && s.Path != identifier {
+		remove := false
+		for _, identifier := range identifiers {
+			if s.Name == identifier || s.Path == identifier {
+				matched[identifier] = true
+				remove = true
+			}
+		}
+		if !remove {
 			newServers = append(newServers, s)
-		} else {
-			found = true
 		}
 	}
 
-	if !found {
-		return fmt.Errorf("server '%s' not found (try name or path)", identifier)
+	var notFound []string
+	for _, identifier := range identifiers {
+		if !matched[identifier] {
+			notFound = append(notFound, identifier)
+		}
+	}
+
+	if len(newServers) == len(cfg.Servers) {
+		return notFound, nil
 	}
 
 	// Update viper
-	viper.Set("servers", newServers)
-	return SaveConfig()
+	set("servers", newServers)
+	return notFound, SaveConfig()
+}
+
+// RemoveAllServers removes every server and returns how many were removed
+func RemoveAllServers() (int, error) {
+	cfg, err := GetConfig()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	set("servers", []Server{})
+	return len(cfg.Servers), SaveConfig()
 }
 
 // UpdateServer updates an existing server's configuration
@@ -213,20 +793,72 @@ func UpdateServer(identifier string, updates map[string]interface{}) error {
 
 			// Apply updates
 			if name, ok := updates["name"].(string); ok && name != "" {
+				for j, other := range cfg.Servers {
+					if j != i && other.Name == name {
+						return fmt.Errorf("%w: %s is used by the server at %s", ErrServerNameTaken, name, other.Path)
+					}
+				}
 				cfg.Servers[i].Name = name
 			}
 			if calendarURL, ok := updates["calendar_url"].(string); ok && calendarURL != "" {
+				if err := ValidateCalendarURL(calendarURL); err != nil {
+					return err
+				}
 				cfg.Servers[i].CalendarURL = calendarURL
 			}
+			if calendarURLs, ok := updates["calendar_urls"].([]string); ok {
+				for _, calendarURL := range calendarURLs {
+					if err := ValidateCalendarURL(calendarURL); err != nil {
+						return err
+					}
+				}
+				cfg.Servers[i].CalendarURLs = calendarURLs
+			}
 			if branch, ok := updates["branch"].(string); ok && branch != "" {
 				cfg.Servers[i].Branch = branch
 			}
+			if framework, ok := updates["framework"].(string); ok && framework != "" {
+				if err := validateFramework(framework); err != nil {
+					return err
+				}
+				cfg.Servers[i].Framework = framework
+			}
 			if wipeBlueprints, ok := updates["wipe_blueprints"].(bool); ok {
 				cfg.Servers[i].WipeBlueprints = wipeBlueprints
 			}
 			if generateMap, ok := updates["generate_map"].(bool); ok {
 				cfg.Servers[i].GenerateMap = generateMap
 			}
+			if identity, ok := updates["identity"].(string); ok {
+				cfg.Servers[i].Identity = identity
+			}
+			if carbonVersion, ok := updates["carbon_version"].(string); ok {
+				cfg.Servers[i].CarbonVersion = carbonVersion
+			}
+			if rustBuildID, ok := updates["rust_build_id"].(string); ok {
+				if err := validateRustBuildID(rustBuildID); err != nil {
+					return err
+				}
+				cfg.Servers[i].RustBuildID = rustBuildID
+			}
+			if authHeader, ok := updates["calendar_auth_header"].(string); ok {
+				cfg.Servers[i].CalendarAuthHeader = authHeader
+			}
+			if authToken, ok := updates["calendar_auth_token"].(string); ok {
+				cfg.Servers[i].CalendarAuthToken = authToken
+			}
+			if rconAddress, ok := updates["rcon_address"].(string); ok {
+				cfg.Servers[i].RconAddress = rconAddress
+			}
+			if rconPassword, ok := updates["rcon_password"].(string); ok {
+				cfg.Servers[i].RconPassword = rconPassword
+			}
+			if matchPatterns, ok := updates["match_patterns"].([]string); ok {
+				if err := validateMatchPatterns(matchPatterns); err != nil {
+					return err
+				}
+				cfg.Servers[i].MatchPatterns = matchPatterns
+			}
 
 			break
 		}
@@ -237,10 +869,44 @@ func UpdateServer(identifier string, updates map[string]interface{}) error {
 	}
 
 	// Update viper
-	viper.Set("servers", cfg.Servers)
+	set("servers", cfg.Servers)
 	return SaveConfig()
 }
 
+// SetBranchRustBuildID holds every server on a branch at a Rust build (empty follows the latest again)
+// and returns the names of the servers on the branch
+func SetBranchRustBuildID(branch, buildID string) ([]string, error) {
+	if err := validateRustBuildID(buildID); err != nil {
+		return nil, err
+	}
+	if branch == "" {
+		branch = "main"
+	}
+
+	cfg, err := GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	var names []string
+	for i, s := range cfg.Servers {
+		serverBranch := s.Branch
+		if serverBranch == "" {
+			serverBranch = "main"
+		}
+		if serverBranch == branch {
+			cfg.Servers[i].RustBuildID = buildID
+			names = append(names, s.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	set("servers", cfg.Servers)
+	return names, SaveConfig()
+}
+
 // ListServers returns all configured servers
 func ListServers() ([]Server, error) {
 	cfg, err := GetConfig()
@@ -255,7 +921,7 @@ func SetCheckInterval(seconds int) error {
 	if seconds < 10 {
 		return fmt.Errorf("check interval must be at least 10 seconds")
 	}
-	viper.Set("check_interval", seconds)
+	set("check_interval", seconds)
 	return SaveConfig()
 }
 
@@ -264,13 +930,55 @@ func SetLookaheadHours(hours int) error {
 	if hours < 1 {
 		return fmt.Errorf("lookahead hours must be at least 1 hour")
 	}
-	viper.Set("lookahead_hours", hours)
+	if limit := viper.GetInt("max_lookahead_hours"); hours > limit {
+		return fmt.Errorf("lookahead hours can't exceed %d (max_lookahead_hours); raise it with --max-lookahead-hours first", limit)
+	}
+	set("lookahead_hours", hours)
+	return SaveConfig()
+}
+
+// SetMaxLookaheadHours sets the largest lookahead window SetLookaheadHours accepts
+func SetMaxLookaheadHours(hours int) error {
+	if hours < 1 {
+		return fmt.Errorf("max lookahead hours must be at least 1 hour")
+	}
+	if current := viper.GetInt("lookahead_hours"); hours < current {
+		return fmt.Errorf("max lookahead hours can't be below the current lookahead_hours (%d)", current)
+	}
+	set("max_lookahead_hours", hours)
 	return SaveConfig()
 }
 
 // SetDiscordWebhook sets the Discord webhook URL
 func SetDiscordWebhook(url string) error {
-	viper.Set("discord_webhook", url)
+	set("discord_webhook", url)
+	return SaveConfig()
+}
+
+// SetNotifier selects where notifications are sent (discord or slack)
+func SetNotifier(notifier string) error {
+	if notifier != NotifierDiscord && notifier != NotifierSlack {
+		return fmt.Errorf("notifier must be %s or %s", NotifierDiscord, NotifierSlack)
+	}
+	set("notifier", notifier)
+	return SaveConfig()
+}
+
+// SetSlackWebhook sets the Slack incoming webhook URL
+func SetSlackWebhook(url string) error {
+	set("slack_webhook", url)
+	return SaveConfig()
+}
+
+// SetEventWebhookURL sets the URL that receives batch start/complete/failed posts (empty to disable)
+func SetEventWebhookURL(webhookURL string) error {
+	if webhookURL != "" {
+		u, err := url.Parse(webhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid event webhook URL '%s': must be an http:// or https:// URL", webhookURL)
+		}
+	}
+	set("event_webhook_url", webhookURL)
 	return SaveConfig()
 }
 
@@ -279,7 +987,7 @@ func SetEventDelay(seconds int) error {
 	if seconds < 0 {
 		return fmt.Errorf("event delay must be at least 0 seconds")
 	}
-	viper.Set("event_delay", seconds)
+	set("event_delay", seconds)
 	return SaveConfig()
 }
 
@@ -288,31 +996,396 @@ func SetMapGenerationHours(hours int) error {
 	if hours < 1 {
 		return fmt.Errorf("map generation hours must be at least 1 hour")
 	}
-	viper.Set("map_generation_hours", hours)
+	set("map_generation_hours", hours)
+	return SaveConfig()
+}
+
+// NormalizeDiscordID strips any pasted mention wrapping (<@id>, <@!id>, <@&id>)
+// and validates that the result is a numeric Discord snowflake
+func NormalizeDiscordID(id string) (string, error) {
+	normalized := strings.TrimSpace(id)
+	if strings.HasPrefix(normalized, "<@") && strings.HasSuffix(normalized, ">") {
+		normalized = strings.TrimSuffix(strings.TrimPrefix(normalized, "<@"), ">")
+		normalized = strings.TrimLeft(normalized, "!&")
+	}
+
+	if !discordSnowflakeRegex.MatchString(normalized) {
+		return "", fmt.Errorf("invalid Discord ID %q: must be a numeric ID of 17-20 digits", id)
+	}
+	return normalized, nil
+}
+
+// normalizeDiscordIDs normalizes each ID and drops duplicates, keeping the first occurrence
+func normalizeDiscordIDs(ids []string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		n, err := NormalizeDiscordID(id)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			normalized = append(normalized, n)
+		}
+	}
+	return normalized, nil
+}
+
+// SetStartStagger sets the delay between starting each server in a batch
+func SetStartStagger(seconds int) error {
+	if seconds < 0 {
+		return fmt.Errorf("start stagger must be at least 0 seconds")
+	}
+	set("start_stagger", seconds)
+	return SaveConfig()
+}
+
+// SetStartStaggerSize sets how many servers are started together between start_stagger waits
+func SetStartStaggerSize(size int) error {
+	if size < 1 {
+		return fmt.Errorf("start stagger size must be at least 1 server")
+	}
+	set("start_stagger_size", size)
+	return SaveConfig()
+}
+
+// SetWipeConfirmationMinutes sets how long a wipe waits for manual confirmation (0 disables)
+func SetWipeConfirmationMinutes(minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("wipe confirmation minutes must be at least 0")
+	}
+	set("wipe_confirmation_minutes", minutes)
+	return SaveConfig()
+}
+
+// SetHealthCheckInterval sets how often servers are probed with healthcheck.sh (0 disables)
+func SetHealthCheckInterval(seconds int) error {
+	if seconds < 0 {
+		return fmt.Errorf("health check interval must be at least 0 seconds")
+	}
+	set("health_check_interval", seconds)
+	return SaveConfig()
+}
+
+// SetHeartbeatHours sets how often the daemon sends a heartbeat notification (0 disables)
+func SetHeartbeatHours(hours int) error {
+	if hours < 0 {
+		return fmt.Errorf("heartbeat hours must be at least 0 hours")
+	}
+	set("heartbeat_hours", hours)
+	return SaveConfig()
+}
+
+// SetMinFreeMemoryMB sets the free memory required per server before starting (0 disables)
+func SetMinFreeMemoryMB(mb int) error {
+	if mb < 0 {
+		return fmt.Errorf("minimum free memory must be at least 0 MB")
+	}
+	set("min_free_memory_mb", mb)
+	return SaveConfig()
+}
+
+// SetBatchSize sets how many servers each wave of a batch processes (0 runs the whole batch at once)
+func SetBatchSize(size int) error {
+	if size < 0 {
+		return fmt.Errorf("batch size must be at least 0")
+	}
+	set("batch_size", size)
+	return SaveConfig()
+}
+
+// SetKeepPreviousInstall sets whether Rust updates keep the previous install for rollback
+func SetKeepPreviousInstall(keep bool) error {
+	set("keep_previous_install", keep)
+	return SaveConfig()
+}
+
+// SetDiscordMaxAttempts sets how many times a Discord notification is attempted
+func SetDiscordMaxAttempts(attempts int) error {
+	if attempts < 1 {
+		return fmt.Errorf("discord max attempts must be at least 1")
+	}
+	set("discord_max_attempts", attempts)
+	return SaveConfig()
+}
+
+// SetHTTPTimeout sets the timeout for outbound HTTP requests
+func SetHTTPTimeout(seconds int) error {
+	if seconds < 1 {
+		return fmt.Errorf("http timeout must be at least 1 second")
+	}
+	set("http_timeout", seconds)
+	return SaveConfig()
+}
+
+// SetMaxConcurrentSyncs sets how many servers are synced at once
+func SetMaxConcurrentSyncs(limit int) error {
+	if limit < 1 {
+		return fmt.Errorf("max concurrent syncs must be at least 1")
+	}
+	set("max_concurrent_syncs", limit)
+	return SaveConfig()
+}
+
+// SetSafeWipe sets whether wipes back up files instead of deleting them
+func SetSafeWipe(enabled bool) error {
+	set("safe_wipe", enabled)
+	return SaveConfig()
+}
+
+// SetWipeBackupRetention sets how many wipe backups are kept per server
+func SetWipeBackupRetention(count int) error {
+	if count < 1 {
+		return fmt.Errorf("wipe backup retention must be at least 1")
+	}
+	set("wipe_backup_retention", count)
+	return SaveConfig()
+}
+
+// SetMinFreeDiskGB sets the free disk space required before a Rust install
+func SetMinFreeDiskGB(gb int) error {
+	if gb < 0 {
+		return fmt.Errorf("min free disk must be 0 (disabled) or more")
+	}
+	set("min_free_disk_gb", gb)
+	return SaveConfig()
+}
+
+// SetConfigReloadInterval sets how often the daemon reloads the config file
+func SetConfigReloadInterval(seconds int) error {
+	if seconds < 1 {
+		return fmt.Errorf("config reload interval must be at least 1 second")
+	}
+	set("config_reload_interval", seconds)
 	return SaveConfig()
 }
 
-// AddDiscordMentionUser adds a Discord user ID to the mention list
+// SetUpdateCheckInterval sets how often the daemon checks for Rust and Carbon updates
+func SetUpdateCheckInterval(seconds int) error {
+	if seconds < 30 {
+		return fmt.Errorf("update check interval must be at least 30 seconds")
+	}
+	set("update_check_interval", seconds)
+	return SaveConfig()
+}
+
+// SetUpdateCheckSource selects where the latest Rust build IDs come from (steamcmd or http)
+func SetUpdateCheckSource(source string) error {
+	if source != UpdateCheckSteamCMD && source != UpdateCheckHTTP {
+		return fmt.Errorf("update check source must be %s or %s", UpdateCheckSteamCMD, UpdateCheckHTTP)
+	}
+	set("update_check_source", source)
+	return SaveConfig()
+}
+
+// SetUpdateCheckURL sets the web API queried for build IDs (empty to use the default)
+func SetUpdateCheckURL(checkURL string) error {
+	if checkURL != "" {
+		u, err := url.Parse(checkURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid update check URL '%s': must be an http:// or https:// URL", checkURL)
+		}
+	}
+	set("update_check_url", checkURL)
+	return SaveConfig()
+}
+
+// SetUpdateDeferMinutes sets how long before a scheduled event update installs are deferred (0 disables)
+func SetUpdateDeferMinutes(minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("update defer window must be at least 0 minutes")
+	}
+	set("update_defer_minutes", minutes)
+	return SaveConfig()
+}
+
+// SetHistoryFile sets the execution history file (empty to use the default in the config directory)
+func SetHistoryFile(path string) error {
+	if path != "" && !filepath.IsAbs(path) {
+		return fmt.Errorf("history file must be an absolute path")
+	}
+	set("history_file", path)
+	return SaveConfig()
+}
+
+// GetHistoryFile returns the execution history file, defaulting to history.jsonl in the config directory
+func (c Config) GetHistoryFile() (string, error) {
+	if c.HistoryFile != "" {
+		return c.HistoryFile, nil
+	}
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, HistoryFile), nil
+}
+
+// CarbonVersionPins returns the Carbon version each branch is pinned to by its Carbon servers' carbon_version.
+// Servers on a branch share one Carbon install, so a branch whose servers pin different versions
+// is left unpinned and reported in the error.
+func (c Config) CarbonVersionPins() (map[string]string, error) {
+	pins, conflicts := c.branchPins(func(server Server) string {
+		if server.GetFramework() != FrameworkCarbon {
+			return ""
+		}
+		return server.CarbonVersion
+	})
+	if len(conflicts) > 0 {
+		return pins, fmt.Errorf("servers pin different Carbon versions on branch(es) %s, leaving them unpinned", strings.Join(conflicts, ", "))
+	}
+	return pins, nil
+}
+
+// RustBuildIDPins returns the Rust build each branch is held at by its servers' rust_build_id.
+// Servers on a branch share one Rust install, so a branch whose servers pin different builds
+// is left unpinned and reported in the error.
+func (c Config) RustBuildIDPins() (map[string]string, error) {
+	pins, conflicts := c.branchPins(func(server Server) string { return server.RustBuildID })
+	if len(conflicts) > 0 {
+		return pins, fmt.Errorf("servers pin different Rust builds on branch(es) %s, leaving them unpinned", strings.Join(conflicts, ", "))
+	}
+	return pins, nil
+}
+
+// branchPins maps each branch to the value pin returns for its servers ("" for no pin),
+// leaving out and returning, sorted, the branches whose servers disagree
+func (c Config) branchPins(pin func(Server) string) (map[string]string, []string) {
+	pins := make(map[string]string)
+	conflicts := make(map[string]bool)
+	for _, server := range c.Servers {
+		value := pin(server)
+		if value == "" {
+			continue
+		}
+		branch := server.Branch
+		if branch == "" {
+			branch = "main"
+		}
+		if pinned, ok := pins[branch]; ok && pinned != value {
+			conflicts[branch] = true
+		}
+		pins[branch] = value
+	}
+
+	var branches []string
+	for branch := range conflicts {
+		delete(pins, branch)
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+	return pins, branches
+}
+
+// SetStepTimeoutMinutes sets how long each batch step may run before it is killed (0 disables)
+func SetStepTimeoutMinutes(minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("step timeout must be at least 0 minutes")
+	}
+	set("step_timeout_minutes", minutes)
+	return SaveConfig()
+}
+
+// SetSteamCMDTimeoutMinutes sets how long each steamcmd install attempt may run before it is killed (0 disables)
+func SetSteamCMDTimeoutMinutes(minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("steamcmd timeout must be at least 0 minutes")
+	}
+	set("steamcmd_timeout_minutes", minutes)
+	return SaveConfig()
+}
+
+// SetRconWarnings sets how many seconds before servers are stopped each RCON warning is sent
+func SetRconWarnings(seconds []int) error {
+	for _, s := range seconds {
+		if s < 1 {
+			return fmt.Errorf("RCON warnings must be at least 1 second before the event")
+		}
+	}
+	set("rcon_warnings", seconds)
+	return SaveConfig()
+}
+
+// SetRconWarningMessage sets the in-game warning sent over RCON
+func SetRconWarningMessage(message string) error {
+	if strings.TrimSpace(message) == "" {
+		return fmt.Errorf("RCON warning message cannot be empty")
+	}
+	set("rcon_warning_message", message)
+	return SaveConfig()
+}
+
+// SetRecheckCalendarBeforeWipe sets whether wipes re-check the calendar after the event delay
+func SetRecheckCalendarBeforeWipe(enabled bool) error {
+	set("recheck_calendar_before_wipe", enabled)
+	return SaveConfig()
+}
+
+// SetMetricsAddr sets the address the daemon serves /metrics on (empty disables it)
+func SetMetricsAddr(addr string) error {
+	if addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("invalid metrics address '%s' (use host:port, e.g. 127.0.0.1:9100): %w", addr, err)
+		}
+	}
+	set("metrics_addr", addr)
+	return SaveConfig()
+}
+
+// SetHealthAddr sets the address the daemon serves /healthz on (empty disables it)
+func SetHealthAddr(addr string) error {
+	if addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("invalid health address '%s' (use host:port, e.g. 127.0.0.1:9101): %w", addr, err)
+		}
+	}
+	set("health_addr", addr)
+	return SaveConfig()
+}
+
+// AddDiscordMentionUser adds a Discord user ID to the mention list.
+// Adding an ID that is already in the list is a no-op.
 func AddDiscordMentionUser(userID string) error {
+	userID, err := NormalizeDiscordID(userID)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := GetConfig()
 	if err != nil {
 		return err
 	}
 
-	// Check if already exists
+	// Already in the list: nothing to do
 	for _, id := range cfg.DiscordMentionUsers {
 		if id == userID {
-			return fmt.Errorf("user ID %s already in mention list", userID)
+			return nil
 		}
 	}
 
 	cfg.DiscordMentionUsers = append(cfg.DiscordMentionUsers, userID)
-	viper.Set("discord_mention_users", cfg.DiscordMentionUsers)
+	set("discord_mention_users", cfg.DiscordMentionUsers)
+	return SaveConfig()
+}
+
+// SetDiscordMentionUsers replaces the Discord user mention list (an empty list clears it).
+// Every ID is validated before anything is saved.
+func SetDiscordMentionUsers(userIDs []string) error {
+	ids, err := normalizeDiscordIDs(userIDs)
+	if err != nil {
+		return err
+	}
+	set("discord_mention_users", ids)
 	return SaveConfig()
 }
 
 // RemoveDiscordMentionUser removes a Discord user ID from the mention list
 func RemoveDiscordMentionUser(userID string) error {
+	// Accept pasted mentions, but fall back to the raw value so invalid legacy entries can still be removed
+	if normalized, err := NormalizeDiscordID(userID); err == nil {
+		userID = normalized
+	}
+
 	cfg, err := GetConfig()
 	if err != nil {
 		return err
@@ -332,31 +1405,75 @@ func RemoveDiscordMentionUser(userID string) error {
 		return fmt.Errorf("user ID %s not found in mention list", userID)
 	}
 
-	viper.Set("discord_mention_users", newList)
+	set("discord_mention_users", newList)
 	return SaveConfig()
 }
 
-// AddDiscordMentionRole adds a Discord role ID to the mention list
+// AddDiscordMentionRole adds a Discord role ID to the mention list.
+// Adding an ID that is already in the list is a no-op.
 func AddDiscordMentionRole(roleID string) error {
+	roleID, err := NormalizeDiscordID(roleID)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := GetConfig()
 	if err != nil {
 		return err
 	}
 
-	// Check if already exists
+	// Already in the list: nothing to do
 	for _, id := range cfg.DiscordMentionRoles {
 		if id == roleID {
-			return fmt.Errorf("role ID %s already in mention list", roleID)
+			return nil
 		}
 	}
 
 	cfg.DiscordMentionRoles = append(cfg.DiscordMentionRoles, roleID)
-	viper.Set("discord_mention_roles", cfg.DiscordMentionRoles)
+	set("discord_mention_roles", cfg.DiscordMentionRoles)
+	return SaveConfig()
+}
+
+// SetDiscordMentionRoles replaces the Discord role mention list (an empty list clears it).
+// Every ID is validated before anything is saved.
+func SetDiscordMentionRoles(roleIDs []string) error {
+	ids, err := normalizeDiscordIDs(roleIDs)
+	if err != nil {
+		return err
+	}
+	set("discord_mention_roles", ids)
+	return SaveConfig()
+}
+
+// NotificationLevels are the notification severities, from least to most severe
+var NotificationLevels = []string{"success", "info", "warning", "error"}
+
+// SetDiscordMentionOn sets which notification levels mention the Discord users and roles
+// (an empty list never mentions them)
+func SetDiscordMentionOn(levels []string) error {
+	normalized := []string{}
+	for _, level := range levels {
+		level = strings.ToLower(strings.TrimSpace(level))
+		known := false
+		for _, l := range NotificationLevels {
+			known = known || level == l
+		}
+		if !known {
+			return fmt.Errorf("unknown notification level '%s' (use %s)", level, strings.Join(NotificationLevels, ", "))
+		}
+		normalized = append(normalized, level)
+	}
+	set("discord_mention_on", normalized)
 	return SaveConfig()
 }
 
 // RemoveDiscordMentionRole removes a Discord role ID from the mention list
 func RemoveDiscordMentionRole(roleID string) error {
+	// Accept pasted mentions, but fall back to the raw value so invalid legacy entries can still be removed
+	if normalized, err := NormalizeDiscordID(roleID); err == nil {
+		roleID = normalized
+	}
+
 	cfg, err := GetConfig()
 	if err != nil {
 		return err
@@ -376,6 +1493,6 @@ func RemoveDiscordMentionRole(roleID string) error {
 		return fmt.Errorf("role ID %s not found in mention list", roleID)
 	}
 
-	viper.Set("discord_mention_roles", newList)
+	set("discord_mention_roles", newList)
 	return SaveConfig()
 }
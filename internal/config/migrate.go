@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// ConfigVersion is the current config schema version, stored in the file as config_version.
+// Bump it and append to migrations whenever a change needs existing config files rewritten.
+const ConfigVersion = 1
+
+// migrations[i] upgrades a config file from version i to i+1.
+// Global settings missing from a file already fall back to settingDefaults when read,
+// so migrations only need to fill in values that have no default (such as server fields)
+// and rewrite settings that were renamed or changed meaning.
+var migrations = []func(v *viper.Viper){
+	migrateV0,
+}
+
+// migrateV0 fills in the branch of servers added by hand before it had a default
+func migrateV0(v *viper.Viper) {
+	servers, ok := v.Get("servers").([]interface{})
+	if !ok {
+		return
+	}
+	for _, s := range servers {
+		server, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if branch, _ := server["branch"].(string); branch == "" {
+			server["branch"] = "main"
+		}
+	}
+	v.Set("servers", servers)
+}
+
+// migrateConfig brings the config file in use up to ConfigVersion and reloads it.
+// It does nothing for a current (or newer) file.
+func migrateConfig() error {
+	version := viper.GetInt("config_version")
+	configFile := viper.ConfigFileUsed()
+	if version >= ConfigVersion || configFile == "" {
+		return nil
+	}
+
+	// Use a separate instance without defaults so only what the file sets is written back
+	// and settings left at their defaults keep following them
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config for migration: %w", err)
+	}
+
+	for ; version < ConfigVersion; version++ {
+		migrations[version](v)
+	}
+	v.Set("config_version", ConfigVersion)
+
+	if err := v.WriteConfig(); err != nil {
+		return fmt.Errorf("failed to write migrated config: %w", err)
+	}
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to reload migrated config: %w", err)
+	}
+	return nil
+}
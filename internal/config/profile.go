@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile name for the standard config.yaml
+const DefaultProfile = "default"
+
+// activeProfileFile stores the profile selected with 'wipe profile use'
+const activeProfileFile = "active-profile"
+
+// profileNameRegex restricts profile names to safe file names
+var profileNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// GetConfigDir returns the directory holding the config files (~/.config/wiped)
+func GetConfigDir() (string, error) {
+	// When running under sudo, os.UserHomeDir() returns /root.
+	// Use SUDO_USER to resolve the original user's home directory.
+	home, err := getEffectiveHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ConfigDir), nil
+}
+
+// ProfilePath returns the config file path for a named profile
+// The default profile is config.yaml; any other profile is <name>.yaml in the same directory.
+func ProfilePath(profile string) (string, error) {
+	if profile != "" && profile != DefaultProfile && !profileNameRegex.MatchString(profile) {
+		return "", fmt.Errorf("invalid profile name %q: use letters, numbers, '-' and '_'", profile)
+	}
+
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	if profile == "" || profile == DefaultProfile {
+		return filepath.Join(dir, ConfigFile), nil
+	}
+	return filepath.Join(dir, profile+".yaml"), nil
+}
+
+// UseProfile points the config system at a named profile. Must be called before InitConfig.
+// The profile must already exist, so a mistyped name doesn't silently start an empty fleet.
+func UseProfile(profile string) error {
+	if profile == "" || profile == DefaultProfile {
+		return nil
+	}
+
+	path, err := ProfilePath(profile)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("profile '%s' not found at %s (create it with 'wipe profile create %s')", profile, path, profile)
+	}
+	CustomConfigPath = path
+	return nil
+}
+
+// CreateProfile creates an empty config file for a new profile and returns its path
+func CreateProfile(profile string) (string, error) {
+	if profile == "" || profile == DefaultProfile {
+		return "", fmt.Errorf("the %s profile always exists", DefaultProfile)
+	}
+
+	path, err := ProfilePath(profile)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return "", fmt.Errorf("profile '%s' already exists at %s", profile, path)
+		}
+		return "", fmt.Errorf("failed to create profile: %w", err)
+	}
+	if _, err := f.WriteString("servers: []\n"); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write profile: %w", err)
+	}
+	return path, f.Close()
+}
+
+// ListProfiles returns the names of all profiles in the config directory
+func ListProfiles() ([]string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []string{DefaultProfile}
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), ".yaml")
+		if filepath.Base(match) == ConfigFile || !profileNameRegex.MatchString(name) {
+			continue
+		}
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles[1:])
+	return profiles, nil
+}
+
+// ActiveProfile returns the profile selected with 'wipe profile use', or the default profile
+// The daemon reads it once at startup, so a running daemon keeps its profile until restarted.
+func ActiveProfile() string {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return DefaultProfile
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, activeProfileFile))
+	if err != nil {
+		return DefaultProfile
+	}
+
+	profile := strings.TrimSpace(string(data))
+	if profile == "" || !profileNameRegex.MatchString(profile) {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// SetActiveProfile selects the profile the CLI and the daemon use when --profile isn't given
+func SetActiveProfile(profile string) error {
+	path, err := ProfilePath(profile)
+	if err != nil {
+		return err
+	}
+
+	if profile != DefaultProfile {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("profile '%s' not found at %s", profile, path)
+		}
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, activeProfileFile), []byte(profile+"\n"), 0644)
+}
@@ -2,22 +2,50 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
+	"reflect"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/maintc/wipe-cli/internal/calendar"
-	"github.com/maintc/wipe-cli/internal/carbon"
 	"github.com/maintc/wipe-cli/internal/config"
 	"github.com/maintc/wipe-cli/internal/discord"
 	"github.com/maintc/wipe-cli/internal/executor"
+	"github.com/maintc/wipe-cli/internal/framework"
+	"github.com/maintc/wipe-cli/internal/logging"
+	"github.com/maintc/wipe-cli/internal/metrics"
+	"github.com/maintc/wipe-cli/internal/notify"
 	"github.com/maintc/wipe-cli/internal/scheduler"
+	"github.com/maintc/wipe-cli/internal/settings"
 	"github.com/maintc/wipe-cli/internal/steamcmd"
 )
 
+const (
+	// notificationQueueSize is how many Discord notifications can wait for delivery
+	notificationQueueSize = 100
+
+	// notificationFlushTimeout bounds how long shutdown waits for queued notifications
+	notificationFlushTimeout = 15 * time.Second
+
+	// eventDrainTimeout bounds how long shutdown waits for an executing batch so servers aren't left stopped
+	eventDrainTimeout = 10 * time.Minute
+
+	// maxConcurrentUpdateChecks bounds how many branches are checked for updates at once
+	maxConcurrentUpdateChecks = 3
+
+	// updateCheckStagger spaces out the start of each branch's check so steamcmd and the release APIs aren't hit at once
+	updateCheckStagger = 2 * time.Second
+
+	// defaultConfigReloadInterval and defaultUpdateCheckInterval apply when the config leaves them unset
+	defaultConfigReloadInterval = 10 * time.Second
+	defaultUpdateCheckInterval  = 2 * time.Minute
+)
+
 // Daemon represents the long-running service
 type Daemon struct {
 	config           *config.Config
@@ -26,103 +54,242 @@ type Daemon struct {
 	lastUpdateCheck  time.Time
 	mapGenMutex      sync.Mutex
 	mapGenInProgress bool
+	updateMutex      sync.Mutex
+	updateInProgress bool
+	deferredUpdates  map[string]bool // Updates already reported as deferred, by "<framework>/<branch>"; guarded by updateMutex
+	refresh          chan struct{}   // Pending manual refresh requested with RequestRefresh
+	lastHealthCheck  time.Time
+	lastHeartbeat    time.Time
+	healthMutex      sync.Mutex
+	healthInProgress bool
+	serverHealth     map[string]executor.ServerHealth // Latest probe result by server path
+	dryRun           bool                             // Schedule as usual but only log installs, updates, map generation and batches
+	probeMutex       sync.Mutex                       // Guards lastUpdate and the fields below, read by /healthz
+	lastLoop         time.Time                        // When the main loop last ticked
+	loopInterval     time.Duration                    // How often the main loop ticks
+	checkInterval    time.Duration                    // Calendar check interval at the last update
+	probeServers     int                              // Servers configured at the last calendar update
 }
 
+// checkRustUpdatesFunc and installRustBranchFunc check for and install Rust updates (replaced in tests)
+var (
+	checkRustUpdatesFunc  = steamcmd.CheckForUpdates
+	installRustBranchFunc = steamcmd.InstallRustBranch
+)
+
 // New creates a new Daemon instance
 func New() *Daemon {
 	return &Daemon{
 		lastUpdate:      time.Time{},
 		lastUpdateCheck: time.Time{},
+		refresh:         make(chan struct{}, 1),
+	}
+}
+
+// RequestRefresh asks the main loop to update calendars and check for updates now.
+// It never blocks; requests made while one is already pending are merged into it.
+func (d *Daemon) RequestRefresh() {
+	select {
+	case d.refresh <- struct{}{}:
+	default:
 	}
 }
 
+// SetDryRun makes the daemon log the actions it would take instead of running them
+func (d *Daemon) SetDryRun(dryRun bool) {
+	d.dryRun = dryRun
+}
+
 // Run starts the daemon's main loop
 func (d *Daemon) Run(ctx context.Context) error {
-	log.Println("Daemon running...")
+	logging.Infof("Daemon running...")
 
 	// Load initial config
 	cfg, err := config.GetConfig()
 	if err != nil {
-		log.Printf("Error loading initial config: %v", err)
+		logging.Errorf("Error loading initial config: %v", err)
 		return err
 	}
 	d.config = cfg
+	metrics.SetServers(len(cfg.Servers))
+	if err := settings.Apply(cfg); err != nil {
+		logging.Warnf("Warning: %v", err)
+	}
+
+	// Serve Prometheus metrics if enabled (the address is only read at startup)
+	if cfg.MetricsAddr != "" {
+		if addr, err := metrics.Serve(ctx, cfg.MetricsAddr); err != nil {
+			logging.Errorf("Error starting metrics server: %v", err)
+		} else {
+			logging.Infof("Serving metrics on http://%s/metrics", addr)
+		}
+	}
+
+	// Serve the /healthz probe if enabled (the address is only read at startup)
+	if cfg.HealthAddr != "" {
+		if addr, err := d.startHealthServer(ctx, cfg.HealthAddr); err != nil {
+			logging.Errorf("Error starting health endpoint: %v", err)
+		} else {
+			logging.Infof("Serving health probe on http://%s/healthz", addr)
+		}
+	}
+
+	// Deliver Discord and Slack notifications in the background so batches never wait on a webhook
+	queue := discord.NewNotifier(cfg.DiscordWebhook, notificationQueueSize)
+	discord.SetBackground(queue)
+	defer func() {
+		logging.Infof("Flushing notifications...")
+		flushCtx, cancel := context.WithTimeout(context.Background(), notificationFlushTimeout)
+		defer cancel()
+		if err := queue.Flush(flushCtx); err != nil {
+			logging.Warnf("Warning: Notifications still pending at shutdown: %v", err)
+		}
+		discord.SetBackground(nil)
+	}()
 
 	// Create scheduler
-	sched, err := scheduler.New(cfg.LookaheadHours, cfg.DiscordWebhook, cfg.EventDelay)
+	sched, err := scheduler.New(cfg.LookaheadHours, notify.New(cfg), cfg.EventDelay)
 	if err != nil {
-		log.Printf("Error creating scheduler: %v", err)
+		logging.Errorf("Error creating scheduler: %v", err)
 		return err
 	}
+	sched.SetStartStagger(cfg.StartStagger, cfg.StartStaggerSize)
+	sched.SetWipeConfirmationMinutes(cfg.WipeConfirmationMinutes)
+	sched.SetMinFreeMemoryMB(cfg.MinFreeMemoryMB)
+	sched.SetBatchSize(cfg.BatchSize)
+	sched.SetRconWarnings(cfg.RconWarnings, cfg.RconWarningMessage)
+	sched.SetStepTimeoutMinutes(cfg.StepTimeoutMinutes)
+	sched.SetRecheckCalendarBeforeWipe(cfg.RecheckCalendarBeforeWipe)
+	sched.SetDryRun(d.dryRun)
 	d.scheduler = sched
 
+	// Publish an empty schedule so `wipe status` can tell the daemon is up
+	if err := scheduler.WriteStatus(nil); err != nil {
+		logging.Warnf("Warning: Failed to write status file: %v", err)
+	}
+
 	// Ensure scheduler is shut down on exit
 	defer func() {
 		if d.scheduler != nil {
-			log.Println("Shutting down scheduler...")
-			if err := d.scheduler.Shutdown(); err != nil {
-				log.Printf("Error shutting down scheduler: %v", err)
+			logging.Infof("Shutting down scheduler...")
+			if err := d.scheduler.Shutdown(eventDrainTimeout); err != nil {
+				logging.Errorf("Error shutting down scheduler: %v", err)
 			}
 		}
+		if err := scheduler.RemoveStatus(); err != nil {
+			logging.Warnf("Warning: Failed to remove status file: %v", err)
+		}
 	}()
 
 	// Create pre-start hook script
 	if err := executor.EnsureHookScript(); err != nil {
-		log.Printf("Warning: Failed to create hook script: %v", err)
+		logging.Warnf("Warning: Failed to create hook script: %v", err)
 	}
 
 	// Create wipe management scripts (stop-servers.sh, start-servers.sh, generate-maps.sh)
 	if err := executor.EnsureWipeScripts(); err != nil {
-		log.Printf("Warning: Failed to create wipe scripts: %v", err)
+		logging.Warnf("Warning: Failed to create wipe scripts: %v", err)
 	}
 
-	// Send startup notification
-	discord.SendInfo(cfg.DiscordWebhook, "Wipe Service Started",
+	// Send startup notification, which also counts as the first heartbeat
+	notify.New(cfg).Info("Wipe Service Started",
 		fmt.Sprintf("Wipe daemon has started and is monitoring **%d** server(s)", len(cfg.Servers)))
+	d.lastHeartbeat = time.Now()
 
 	// Ensure all servers are installed
 	if len(cfg.Servers) > 0 {
-		log.Printf("Checking server installations...")
+		logging.Infof("Checking server installations...")
 		d.ensureServersInstalled()
 
-		log.Printf("Performing initial calendar update...")
+		logging.Infof("Performing initial calendar update...")
 		d.updateCalendars()
 	} else {
-		log.Printf("No servers configured")
+		logging.Infof("No servers configured")
 	}
 
-	// Ticker for reloading config (every 10 seconds)
-	configTicker := time.NewTicker(10 * time.Second)
+	// Ticker for reloading config (config_reload_interval, default 10 seconds)
+	reloadInterval := intervalSeconds(cfg.ConfigReloadInterval, defaultConfigReloadInterval)
+	configTicker := time.NewTicker(reloadInterval)
 	defer configTicker.Stop()
 
-	// Ticker for checking updates (every 2 minutes)
-	updateCheckTicker := time.NewTicker(2 * time.Minute)
+	// Ticker for checking updates (update_check_interval, default 2 minutes)
+	updateInterval := intervalSeconds(cfg.UpdateCheckInterval, defaultUpdateCheckInterval)
+	updateCheckTicker := time.NewTicker(updateInterval)
 	defer updateCheckTicker.Stop()
 
+	d.markLoop(reloadInterval)
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
 
+		case <-d.refresh:
+			d.markLoop(reloadInterval)
+			d.manualRefresh()
+
 		case <-updateCheckTicker.C:
-			// Check for Rust updates
-			d.checkForUpdates()
+			d.markLoop(reloadInterval)
+
+			// Check for Rust and framework updates off the loop; checks and installs can take minutes
+			go d.checkForUpdates(d.config)
 
 		case <-configTicker.C:
+			d.markLoop(reloadInterval)
+
+			// Re-arm scheduled jobs if the system clock jumped (VM resume, NTP step)
+			d.scheduler.CheckClockJump()
+
 			// Reload config
 			cfg, err := config.GetConfig()
 			if err != nil {
-				log.Printf("Error loading config: %v", err)
+				logging.Errorf("Error loading config: %v", err)
 				continue
 			}
 
-			// Detect server changes (additions/removals)
+			// Detect server changes (additions/removals) and newly triggered one-off events
 			serversChanged := d.detectServerChanges(cfg)
+			oneOffChanged := d.config != nil && !reflect.DeepEqual(d.config.OneOffEvents, cfg.OneOffEvents)
+			cancelledChanged := d.config != nil && !reflect.DeepEqual(d.config.CancelledEvents, cfg.CancelledEvents)
 			d.config = cfg
+			metrics.SetServers(len(cfg.Servers))
+			d.scheduler.SetStartStagger(cfg.StartStagger, cfg.StartStaggerSize)
+			d.scheduler.SetWipeConfirmationMinutes(cfg.WipeConfirmationMinutes)
+			d.scheduler.SetMinFreeMemoryMB(cfg.MinFreeMemoryMB)
+			d.scheduler.SetBatchSize(cfg.BatchSize)
+			d.scheduler.SetRconWarnings(cfg.RconWarnings, cfg.RconWarningMessage)
+			d.scheduler.SetStepTimeoutMinutes(cfg.StepTimeoutMinutes)
+			d.scheduler.SetRecheckCalendarBeforeWipe(cfg.RecheckCalendarBeforeWipe)
+			// Update checks and batches may be running; the settings are safe to replace while they do
+			if err := settings.Apply(cfg); err != nil {
+				logging.Warnf("Warning: %v", err)
+			}
+
+			// Apply changed tick intervals without restarting the daemon
+			if interval := intervalSeconds(cfg.ConfigReloadInterval, defaultConfigReloadInterval); interval != reloadInterval {
+				logging.Infof("Config reload interval changed to %v", interval)
+				reloadInterval = interval
+				configTicker.Reset(interval)
+			}
+			if interval := intervalSeconds(cfg.UpdateCheckInterval, defaultUpdateCheckInterval); interval != updateInterval {
+				logging.Infof("Update check interval changed to %v", interval)
+				updateInterval = interval
+				updateCheckTicker.Reset(interval)
+			}
+
+			// Probe server health if enabled
+			if d.shouldCheckHealth() {
+				d.lastHealthCheck = time.Now()
+				go d.checkServerHealth(cfg)
+			}
+
+			// Send a heartbeat if enabled
+			if d.shouldSendHeartbeat() {
+				d.sendHeartbeat(notify.New(cfg))
+			}
 
 			// If servers changed, immediately update calendars
-			if serversChanged {
-				log.Printf("Server configuration changed, updating schedules...")
+			if serversChanged || oneOffChanged || cancelledChanged {
+				logging.Infof("Server configuration changed, updating schedules...")
 				d.updateCalendars()
 			} else if d.shouldUpdateCalendars() {
 				// Otherwise, check if it's time for periodic update
@@ -132,6 +299,14 @@ func (d *Daemon) Run(ctx context.Context) error {
 	}
 }
 
+// intervalSeconds converts a configured interval in seconds to a duration, using fallback when unset
+func intervalSeconds(seconds int, fallback time.Duration) time.Duration {
+	if seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 // detectServerChanges checks if servers were added or removed
 func (d *Daemon) detectServerChanges(newConfig *config.Config) bool {
 	if d.config == nil {
@@ -155,20 +330,23 @@ func (d *Daemon) detectServerChanges(newConfig *config.Config) bool {
 	// Check for removed servers
 	for path, name := range oldServers {
 		if _, exists := newServers[path]; !exists {
-			log.Printf("Server removed: %s (%s)", name, path)
-			discord.SendWarning(newConfig.DiscordWebhook, "Server Removed",
+			logging.Infof("Server removed: %s (%s)", name, path)
+			notify.New(newConfig).Warning("Server Removed",
 				fmt.Sprintf("Server **%s** has been removed from monitoring\n\nPath: `%s`", name, path))
 			changed = true
 		}
 	}
 
-	// Check for added servers
+	// Check for added servers; the path is the key, so a rename isn't an add and a remove
 	for path, name := range newServers {
-		if _, exists := oldServers[path]; !exists {
-			log.Printf("Server added: %s (%s)", name, path)
-			discord.SendSuccess(newConfig.DiscordWebhook, "Server Added",
+		oldName, exists := oldServers[path]
+		if !exists {
+			logging.Infof("Server added: %s (%s)", name, path)
+			notify.New(newConfig).Success("Server Added",
 				fmt.Sprintf("Server **%s** has been added to monitoring\n\nPath: `%s`", name, path))
 			changed = true
+		} else if oldName != name {
+			logging.Infof("Server renamed: %s -> %s (%s)", oldName, name, path)
 		}
 	}
 
@@ -192,37 +370,236 @@ func (d *Daemon) shouldUpdateCalendars() bool {
 
 // updateCalendars fetches and updates calendar events
 func (d *Daemon) updateCalendars() {
-	log.Printf("Updating calendars for %d server(s)...", len(d.config.Servers))
+	logging.Infof("Updating calendars for %d server(s)...", len(d.config.Servers))
 
 	if d.scheduler == nil {
-		sched, err := scheduler.New(d.config.LookaheadHours, d.config.DiscordWebhook, d.config.EventDelay)
+		sched, err := scheduler.New(d.config.LookaheadHours, notify.New(d.config), d.config.EventDelay)
 		if err != nil {
-			log.Printf("Error creating scheduler: %v", err)
+			logging.Errorf("Error creating scheduler: %v", err)
 			return
 		}
+		sched.SetStartStagger(d.config.StartStagger, d.config.StartStaggerSize)
+		sched.SetWipeConfirmationMinutes(d.config.WipeConfirmationMinutes)
+		sched.SetMinFreeMemoryMB(d.config.MinFreeMemoryMB)
+		sched.SetBatchSize(d.config.BatchSize)
+		sched.SetRconWarnings(d.config.RconWarnings, d.config.RconWarningMessage)
+		sched.SetStepTimeoutMinutes(d.config.StepTimeoutMinutes)
+		sched.SetRecheckCalendarBeforeWipe(d.config.RecheckCalendarBeforeWipe)
+		sched.SetDryRun(d.dryRun)
 		d.scheduler = sched
 	}
 
+	d.scheduler.SetOneOffEvents(d.resolveOneOffEvents())
+	d.applyCancelledEvents()
+
 	// Update scheduler even if no servers (clears all events)
 	if err := d.scheduler.UpdateEvents(d.config.Servers); err != nil {
-		log.Printf("Error updating events: %v", err)
+		logging.Errorf("Error updating events: %v", err)
 		return
 	}
 
-	d.lastUpdate = time.Now()
+	d.markCalendarUpdate(time.Duration(d.config.CheckInterval)*time.Second, len(d.config.Servers))
+
+	// Publish the schedule for `wipe status`
+	if err := scheduler.WriteStatus(d.scheduler.GetEvents()); err != nil {
+		logging.Warnf("Warning: Failed to write status file: %v", err)
+	}
 
 	if len(d.config.Servers) > 0 {
-		log.Printf("Next calendar update in %d seconds", d.config.CheckInterval)
+		logging.Debugf("Next calendar update in %d seconds", d.config.CheckInterval)
 	} else {
-		log.Printf("No servers configured - monitoring stopped")
+		logging.Infof("No servers configured - monitoring stopped")
 	}
 
 	// Check if any maps need to be generated for upcoming wipes
 	go d.prepareWipeMaps()
 }
 
-// ensureServersInstalled ensures all configured Rust branches and Carbon are installed
+// manualRefresh updates calendars and starts an update check out of band, for RequestRefresh
+func (d *Daemon) manualRefresh() {
+	if d.config == nil {
+		return
+	}
+	logging.Infof("Manual refresh requested, updating calendars and checking for updates...")
+	d.updateCalendars()
+	go d.checkForUpdates(d.config)
+}
+
+// shouldCheckHealth checks if health probes are enabled and due
+func (d *Daemon) shouldCheckHealth() bool {
+	if d.config == nil || d.config.HealthCheckInterval <= 0 || len(d.config.Servers) == 0 {
+		return false
+	}
+
+	interval := time.Duration(d.config.HealthCheckInterval) * time.Second
+	return d.lastHealthCheck.IsZero() || time.Since(d.lastHealthCheck) >= interval
+}
+
+// shouldSendHeartbeat checks if heartbeats are enabled and one is due
+func (d *Daemon) shouldSendHeartbeat() bool {
+	if d.config == nil || d.config.HeartbeatHours <= 0 {
+		return false
+	}
+
+	interval := time.Duration(d.config.HeartbeatHours) * time.Hour
+	return time.Since(d.lastHeartbeat) >= interval
+}
+
+// sendHeartbeat notifies that the daemon is alive, with the number of servers and the next event
+func (d *Daemon) sendHeartbeat(notifier notify.Notifier) {
+	d.lastHeartbeat = time.Now()
+
+	description := fmt.Sprintf("Wipe daemon healthy, monitoring **%d** server(s)", len(d.config.Servers))
+	if next := d.nextEvent(); next != nil {
+		description += fmt.Sprintf("\n\nNext event: **%s** %s at %s (in %s)", next.Server.Name, next.Event.Type,
+			next.Scheduled.Format("Mon Jan 02 15:04 MST"), time.Until(next.Scheduled).Round(time.Minute))
+	} else {
+		description += fmt.Sprintf("\n\nNo events in the next %d hours", d.config.LookaheadHours)
+	}
+
+	logging.Infof("Sending heartbeat")
+	notifier.Info("Wipe Service Heartbeat", description)
+}
+
+// checkServerHealth runs healthcheck.sh for every server and records the results
+func (d *Daemon) checkServerHealth(cfg *config.Config) {
+	// Skip if the previous round of probes is still running
+	d.healthMutex.Lock()
+	if d.healthInProgress {
+		d.healthMutex.Unlock()
+		logging.Infof("Health check already in progress, skipping")
+		return
+	}
+	d.healthInProgress = true
+	d.healthMutex.Unlock()
+
+	defer func() {
+		d.healthMutex.Lock()
+		d.healthInProgress = false
+		d.healthMutex.Unlock()
+	}()
+
+	if err := executor.EnsureHealthCheckScript(); err != nil {
+		logging.Warnf("Warning: Failed to create healthcheck script: %v", err)
+		return
+	}
+
+	if d.serverHealth == nil {
+		d.serverHealth = make(map[string]executor.ServerHealth)
+	}
+
+	results := make([]executor.ServerHealth, 0, len(cfg.Servers))
+	for _, server := range cfg.Servers {
+		previous := d.serverHealth[server.Path]
+		result := executor.ServerHealth{
+			Name:        server.Name,
+			Path:        server.Path,
+			LastChecked: time.Now(),
+			LastHealthy: previous.LastHealthy,
+		}
+
+		if err := executor.ProbeServer(server.Path); err != nil {
+			result.Error = err.Error()
+			if previous.Healthy || previous.LastChecked.IsZero() {
+				logging.Warnf("Server %s is unhealthy: %v", server.Name, err)
+			}
+		} else {
+			result.Healthy = true
+			result.LastHealthy = result.LastChecked
+			if !previous.Healthy && !previous.LastChecked.IsZero() {
+				logging.Infof("Server %s is healthy again", server.Name)
+			}
+		}
+
+		d.serverHealth[server.Path] = result
+		results = append(results, result)
+	}
+
+	if err := executor.WriteHealthStatus(results); err != nil {
+		logging.Warnf("Warning: Failed to write health status: %v", err)
+	}
+}
+
+// resolveOneOffEvents converts configured one-off events into scheduled events for known servers
+func (d *Daemon) resolveOneOffEvents() []scheduler.ScheduledEvent {
+	var events []scheduler.ScheduledEvent
+
+	for _, oneOff := range d.config.OneOffEvents {
+		at, err := time.Parse(time.RFC3339, oneOff.At)
+		if err != nil {
+			logging.Warnf("Warning: Ignoring one-off event with invalid time '%s': %v", oneOff.At, err)
+			continue
+		}
+
+		found := false
+		for _, server := range d.config.Servers {
+			if server.Name != oneOff.Server && server.Path != oneOff.Server {
+				continue
+			}
+			found = true
+			events = append(events, scheduler.ScheduledEvent{
+				Server: server,
+				Event: calendar.Event{
+					Type:      calendar.EventType(oneOff.Type),
+					StartTime: at,
+					EndTime:   at,
+					Summary:   oneOff.Type,
+				},
+				Scheduled: at,
+			})
+			break
+		}
+
+		if !found {
+			logging.Warnf("Warning: Ignoring one-off event for unknown server '%s'", oneOff.Server)
+		}
+	}
+
+	return events
+}
+
+// applyCancelledEvents cancels the upcoming events removed with 'wipe cancel'.
+// Cancelling is idempotent, so every calendar update re-applies the whole list.
+func (d *Daemon) applyCancelledEvents() {
+	now := time.Now()
+	for _, cancelled := range d.config.CancelledEvents {
+		at, err := time.Parse(time.RFC3339, cancelled.At)
+		if err != nil {
+			logging.Warnf("Warning: Ignoring cancelled event with invalid time '%s': %v", cancelled.At, err)
+			continue
+		}
+		if at.Before(now) {
+			continue
+		}
+
+		found := false
+		for _, server := range d.config.Servers {
+			if server.Name != cancelled.Server && server.Path != cancelled.Server {
+				continue
+			}
+			found = true
+			err := d.scheduler.CancelEvent(server.Path, at)
+			if errors.Is(err, scheduler.ErrEventNotFound) {
+				logging.Debugf("No event for %s at %s yet, it will be skipped when scheduled", server.Name, cancelled.At)
+			} else if err != nil {
+				logging.Warnf("Warning: Could not cancel event for %s at %s: %v", server.Name, cancelled.At, err)
+			}
+			break
+		}
+
+		if !found {
+			logging.Warnf("Warning: Ignoring cancelled event for unknown server '%s'", cancelled.Server)
+		}
+	}
+}
+
+// ensureServersInstalled ensures all configured Rust branches and each server's mod framework are installed
 func (d *Daemon) ensureServersInstalled() {
+	if d.dryRun {
+		logging.Infof("[dry-run] Skipping Rust and Carbon installation checks")
+		return
+	}
+
 	// Collect unique branches
 	branches := make(map[string]bool)
 	for _, server := range d.config.Servers {
@@ -233,30 +610,57 @@ func (d *Daemon) ensureServersInstalled() {
 
 	// Install each unique Rust branch
 	for branch := range branches {
-		if err := steamcmd.EnsureRustBranchInstalled(branch, d.config.DiscordWebhook); err != nil {
-			log.Printf("Error installing Rust branch '%s': %v", branch, err)
+		if err := steamcmd.EnsureRustBranchInstalled(branch, notify.New(d.config)); err != nil {
+			logging.Errorf("Error installing Rust branch '%s': %v", branch, err)
 		}
 	}
 
-	// Install Carbon for each branch
-	for branch := range branches {
-		if err := carbon.EnsureCarbonInstalled(branch, d.config.DiscordWebhook); err != nil {
-			log.Printf("Error installing Carbon for branch '%s': %v", branch, err)
+	// Install each framework for the branches whose servers run it
+	for name, frameworkBranches := range framework.Branches(d.config.Servers) {
+		fw, err := framework.For(name)
+		if err != nil {
+			logging.Errorf("Error: %v", err)
+			continue
+		}
+		for _, branch := range frameworkBranches {
+			if err := fw.EnsureInstalled(branch, notify.New(d.config)); err != nil {
+				logging.Errorf("Error installing %s for branch '%s': %v", fw.Name(), branch, err)
+			}
 		}
 	}
 }
 
-// checkForUpdates checks all configured branches for available updates
-func (d *Daemon) checkForUpdates() {
-	if d.config == nil {
+// checkForUpdates checks all configured branches for available updates and installs them.
+// It runs in its own goroutine; a pass still running when the next is due is skipped.
+func (d *Daemon) checkForUpdates(cfg *config.Config) {
+	if cfg == nil {
 		return
 	}
 
+	// Skip if the previous pass is still checking or installing
+	d.updateMutex.Lock()
+	if d.updateInProgress {
+		d.updateMutex.Unlock()
+		logging.Infof("Update check already in progress, skipping")
+		return
+	}
+	d.updateInProgress = true
+	d.updateMutex.Unlock()
+
+	defer func() {
+		d.updateMutex.Lock()
+		d.updateInProgress = false
+		d.lastUpdateCheck = time.Now()
+		d.updateMutex.Unlock()
+	}()
+
 	// Collect unique branches
-	branches := make(map[string]bool)
-	for _, server := range d.config.Servers {
-		if server.Branch != "" {
-			branches[server.Branch] = true
+	seen := make(map[string]bool)
+	var branches []string
+	for _, server := range cfg.Servers {
+		if server.Branch != "" && !seen[server.Branch] {
+			seen[server.Branch] = true
+			branches = append(branches, server.Branch)
 		}
 	}
 
@@ -264,54 +668,155 @@ func (d *Daemon) checkForUpdates() {
 		return
 	}
 
-	log.Printf("Checking for Rust updates for %d branch(es)...", len(branches))
+	if d.dryRun {
+		logging.Infof("[dry-run] Skipping Rust and Carbon update checks for %d branch(es)", len(branches))
+		return
+	}
+
+	logging.Infof("Checking for Rust updates for %d branch(es)...", len(branches))
 
 	// Check each branch for Rust updates
-	for branch := range branches {
-		hasUpdate, buildID, err := steamcmd.CheckForUpdates(branch, d.config.DiscordWebhook)
+	forEachStaggered(branches, func(branch string) {
+		hasUpdate, buildID, err := checkRustUpdatesFunc(branch, notify.New(cfg))
 		if err != nil {
-			log.Printf("Error checking Rust updates for branch '%s': %v", branch, err)
-			continue
+			logging.Errorf("Error checking Rust updates for branch '%s': %v", branch, err)
+			return
 		}
 
 		if hasUpdate {
-			log.Printf("Rust update detected for branch '%s', new build ID: %s", branch, buildID)
+			logging.Infof("Rust update detected for branch '%s', new build ID: %s", branch, buildID)
+			if d.deferUpdate(cfg, "Rust", branch) {
+				return
+			}
 			// Install the update
-			log.Printf("Installing Rust update for branch '%s'...", branch)
-			if err := steamcmd.InstallRustBranch(branch, d.config.DiscordWebhook); err != nil {
-				log.Printf("Error installing Rust update for branch '%s': %v", branch, err)
+			logging.Infof("Installing Rust update for branch '%s'...", branch)
+			if err := installRustBranchFunc(branch, notify.New(cfg)); err != nil {
+				logging.Errorf("Error installing Rust update for branch '%s': %v", branch, err)
 			} else {
-				log.Printf("Successfully updated Rust branch '%s' to build %s", branch, buildID)
+				logging.Infof("Successfully updated Rust branch '%s' to build %s", branch, buildID)
 			}
 		} else {
-			log.Printf("Rust branch '%s' is up to date (build: %s)", branch, buildID)
+			logging.Infof("Rust branch '%s' is up to date (build: %s)", branch, buildID)
 		}
-	}
+	})
 
-	// Check each branch for Carbon updates
-	log.Printf("Checking for Carbon updates for %d branch(es)...", len(branches))
-	for branch := range branches {
-		hasUpdate, version, err := carbon.CheckForCarbonUpdates(branch, d.config.DiscordWebhook)
+	// Check each framework's branches for updates
+	for name, frameworkBranches := range framework.Branches(cfg.Servers) {
+		fw, err := framework.For(name)
 		if err != nil {
-			log.Printf("Error checking Carbon updates for branch '%s': %v", branch, err)
+			logging.Errorf("Error: %v", err)
 			continue
 		}
 
-		if hasUpdate {
-			log.Printf("Carbon update detected for branch '%s', new version: %s", branch, version)
-			// Install the update
-			log.Printf("Installing Carbon update for branch '%s'...", branch)
-			if err := carbon.InstallCarbon(branch, d.config.DiscordWebhook); err != nil {
-				log.Printf("Error installing Carbon update for branch '%s': %v", branch, err)
-			} else {
-				log.Printf("Successfully updated Carbon for branch '%s' to version %s", branch, version)
+		logging.Infof("Checking for %s updates for %d branch(es)...", fw.Name(), len(frameworkBranches))
+		forEachStaggered(frameworkBranches, func(branch string) {
+			hasUpdate, version, err := fw.CheckForUpdates(branch, notify.New(cfg))
+			if err != nil {
+				logging.Errorf("Error checking %s updates for branch '%s': %v", fw.Name(), branch, err)
+				return
+			}
+
+			if hasUpdate {
+				logging.Infof("%s update detected for branch '%s', new version: %s", fw.Name(), branch, version)
+				if d.deferUpdate(cfg, fw.Name(), branch) {
+					return
+				}
+				// Install the update
+				logging.Infof("Installing %s update for branch '%s'...", fw.Name(), branch)
+				if err := fw.Install(branch, notify.New(cfg)); err != nil {
+					logging.Errorf("Error installing %s update for branch '%s': %v", fw.Name(), branch, err)
+				} else {
+					logging.Infof("Successfully updated %s for branch '%s' to version %s", fw.Name(), branch, version)
+				}
+			} else if version != "" {
+				logging.Infof("%s for branch '%s' is up to date (version: %s)", fw.Name(), branch, version)
 			}
-		} else if version != "" {
-			log.Printf("Carbon for branch '%s' is up to date (version: %s)", branch, version)
+		})
+	}
+}
+
+// deferUpdate reports whether an update install should wait because an event is scheduled within
+// update_defer_minutes: an install holds the branch lock for minutes and would delay the event's sync.
+// The first deferral of each update is logged as a warning and notified; later passes only log it.
+func (d *Daemon) deferUpdate(cfg *config.Config, name, branch string) bool {
+	key := name + "/" + branch
+	event := d.eventWithin(time.Duration(cfg.UpdateDeferMinutes) * time.Minute)
+
+	d.updateMutex.Lock()
+	alreadyDeferred := d.deferredUpdates[key]
+	if event == nil {
+		delete(d.deferredUpdates, key)
+	} else {
+		if d.deferredUpdates == nil {
+			d.deferredUpdates = make(map[string]bool)
+		}
+		d.deferredUpdates[key] = true
+	}
+	d.updateMutex.Unlock()
+
+	if event == nil {
+		return false
+	}
+
+	timeStr := event.Scheduled.Format("Mon Jan 02 15:04 MST")
+	if alreadyDeferred {
+		logging.Infof("Still deferring %s update for branch '%s': %s for %s at %s", name, branch, event.Event.Type, event.Server.Name, timeStr)
+		return true
+	}
+	logging.Warnf("Deferring %s update for branch '%s': %s for %s is scheduled at %s", name, branch, event.Event.Type, event.Server.Name, timeStr)
+	notify.New(cfg).Info(name+" Update Deferred",
+		fmt.Sprintf("%s update for branch **%s** will be installed after the %s for **%s** at **%s**",
+			name, branch, event.Event.Type, event.Server.Name, timeStr))
+	return true
+}
+
+// eventWithin returns the first scheduled event starting within window from now, or nil if there is
+// none (a window of 0 or less disables the check)
+func (d *Daemon) eventWithin(window time.Duration) *scheduler.ScheduledEvent {
+	if window <= 0 || d.scheduler == nil {
+		return nil
+	}
+	now := time.Now()
+	for _, event := range d.scheduler.GetEvents() {
+		if !event.Scheduled.Before(now) && event.Scheduled.Sub(now) <= window {
+			return &event
 		}
 	}
+	return nil
+}
 
-	d.lastUpdateCheck = time.Now()
+// nextEvent returns the first scheduled event that hasn't started yet, or nil if there is none
+func (d *Daemon) nextEvent() *scheduler.ScheduledEvent {
+	if d.scheduler == nil {
+		return nil
+	}
+	now := time.Now()
+	for _, event := range d.scheduler.GetEvents() {
+		if !event.Scheduled.Before(now) {
+			return &event
+		}
+	}
+	return nil
+}
+
+// forEachStaggered calls fn for each branch, at most maxConcurrentUpdateChecks at once and each
+// started updateCheckStagger after the previous, and returns when all have finished
+func forEachStaggered(branches []string, fn func(branch string)) {
+	sem := make(chan struct{}, maxConcurrentUpdateChecks)
+	var wg sync.WaitGroup
+	for i, branch := range branches {
+		if i > 0 {
+			time.Sleep(updateCheckStagger)
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(branch string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(branch)
+		}(branch)
+	}
+	wg.Wait()
 }
 
 // prepareWipeMaps checks for upcoming wipe events and calls generate-maps.sh if needed
@@ -324,7 +829,7 @@ func (d *Daemon) prepareWipeMaps() {
 	d.mapGenMutex.Lock()
 	if d.mapGenInProgress {
 		d.mapGenMutex.Unlock()
-		log.Printf("Map generation already in progress, skipping")
+		logging.Infof("Map generation already in progress, skipping")
 		return
 	}
 	d.mapGenInProgress = true
@@ -345,8 +850,8 @@ func (d *Daemon) prepareWipeMaps() {
 	serversNeedingMaps := make(map[string]bool)
 
 	for _, event := range events {
-		// Only process WIPE events
-		if event.Event.Type != calendar.EventTypeWipe {
+		// Only process wipe events (any variant generates a new map)
+		if !event.Event.Type.IsWipe() {
 			continue
 		}
 
@@ -377,11 +882,16 @@ func (d *Daemon) prepareWipeMaps() {
 	}
 
 	// Call generate-maps.sh script if there are servers needing map generation
-	if len(serverPathsToGenerate) > 0 {
-		log.Printf("Calling generate-maps.sh for %d server(s)...", len(serverPathsToGenerate))
+	if len(serverPathsToGenerate) > 0 && d.dryRun {
+		logging.Infof("[dry-run] Would call generate-maps.sh for: %s", strings.Join(serverPathsToGenerate, " "))
+	} else if len(serverPathsToGenerate) > 0 {
+		logging.Infof("Calling generate-maps.sh for %d server(s)...", len(serverPathsToGenerate))
+		// Track generation per server so a wipe firing meanwhile waits for the new map
+		done := executor.BeginMapGeneration(serverPathsToGenerate)
+		defer done()
 		if err := d.callGenerateMapsScript(serverPathsToGenerate); err != nil {
-			log.Printf("Error calling generate-maps.sh: %v", err)
-			discord.SendError(d.config.DiscordWebhook, "Map Generation Failed",
+			logging.Errorf("Error calling generate-maps.sh: %v", err)
+			notify.New(d.config).Error("Map Generation Failed",
 				fmt.Sprintf("Failed to generate maps: %v", err))
 		}
 	}
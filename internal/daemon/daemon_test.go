@@ -1,10 +1,19 @@
 package daemon
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/maintc/wipe-cli/internal/calendar"
 	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/notify"
+	"github.com/maintc/wipe-cli/internal/scheduler"
 )
 
 func TestNew(t *testing.T) {
@@ -510,3 +519,285 @@ func TestShouldUpdateCalendars_BoundaryConditions(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldCheckHealth(t *testing.T) {
+	servers := []config.Server{{Name: "server1", Path: "/path1", Branch: "main"}}
+
+	tests := []struct {
+		name            string
+		interval        int
+		servers         []config.Server
+		lastHealthCheck time.Time
+		want            bool
+	}{
+		{"disabled", 0, servers, time.Time{}, false},
+		{"no servers", 60, nil, time.Time{}, false},
+		{"never checked", 60, servers, time.Time{}, true},
+		{"interval passed", 1, servers, time.Now().Add(-2 * time.Second), true},
+		{"interval not passed", 60, servers, time.Now(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New()
+			d.config = &config.Config{Servers: tt.servers, HealthCheckInterval: tt.interval}
+			d.lastHealthCheck = tt.lastHealthCheck
+
+			if got := d.shouldCheckHealth(); got != tt.want {
+				t.Errorf("shouldCheckHealth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// recordingNotifier records info notifications
+type recordingNotifier struct {
+	notify.Notifier
+	infos []string
+}
+
+func (n *recordingNotifier) Info(title, description string) error {
+	n.infos = append(n.infos, title+": "+description)
+	return nil
+}
+
+func TestHeartbeat(t *testing.T) {
+	server := config.Server{Name: "us-weekly", Path: "/srv/us-weekly", Branch: "main"}
+	sched, err := scheduler.New(24, nil, 0)
+	if err != nil {
+		t.Fatalf("scheduler.New() error = %v", err)
+	}
+	defer sched.Shutdown(time.Second)
+	at := time.Now().Add(3 * time.Hour).Truncate(time.Minute)
+	sched.SetOneOffEvents([]scheduler.ScheduledEvent{{
+		Server:    server,
+		Event:     calendar.Event{Summary: "wipe", Type: calendar.EventTypeWipe, StartTime: at},
+		Scheduled: at,
+	}})
+	if err := sched.UpdateEvents(nil); err != nil {
+		t.Fatalf("UpdateEvents() error = %v", err)
+	}
+
+	d := New()
+	d.scheduler = sched
+	d.config = &config.Config{HeartbeatHours: 6, LookaheadHours: 24, Servers: []config.Server{server}}
+
+	// Not due until the interval has passed since the last heartbeat
+	d.lastHeartbeat = time.Now().Add(-5 * time.Hour)
+	if d.shouldSendHeartbeat() {
+		t.Fatal("shouldSendHeartbeat() = true before the interval passed")
+	}
+	d.lastHeartbeat = time.Now().Add(-6 * time.Hour)
+	if !d.shouldSendHeartbeat() {
+		t.Fatal("shouldSendHeartbeat() = false after the interval passed")
+	}
+
+	notifier := &recordingNotifier{Notifier: notify.Discard}
+	d.sendHeartbeat(notifier)
+	if len(notifier.infos) != 1 {
+		t.Fatalf("sent %d heartbeats, want 1", len(notifier.infos))
+	}
+	for _, want := range []string{"monitoring **1** server(s)", "Next event: **us-weekly** wipe at " + at.Format("Mon Jan 02 15:04 MST")} {
+		if !strings.Contains(notifier.infos[0], want) {
+			t.Errorf("heartbeat = %q, want it to contain %q", notifier.infos[0], want)
+		}
+	}
+	if d.shouldSendHeartbeat() {
+		t.Error("shouldSendHeartbeat() = true right after a heartbeat")
+	}
+
+	d.config.HeartbeatHours = 0
+	d.lastHeartbeat = time.Time{}
+	if d.shouldSendHeartbeat() {
+		t.Error("shouldSendHeartbeat() = true with heartbeats disabled")
+	}
+}
+
+func TestServeHealthz(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		lastLoop   time.Time
+		lastUpdate time.Time
+		servers    int
+		wantCode   int
+		wantReason string
+	}{
+		{"healthy", now, now.Add(-30 * time.Second), 1, http.StatusOK, ""},
+		{"no servers needs no calendar update", now, time.Time{}, 0, http.StatusOK, ""},
+		{"calendar update stale", now, now.Add(-2 * time.Hour), 1, http.StatusServiceUnavailable, "last calendar update"},
+		{"calendar never updated", now, time.Time{}, 1, http.StatusServiceUnavailable, "not been updated"},
+		{"loop not started", time.Time{}, now, 1, http.StatusServiceUnavailable, "has not started"},
+		{"loop stuck", now.Add(-time.Hour), now, 1, http.StatusServiceUnavailable, "loop last ran"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New()
+			d.lastLoop = tt.lastLoop
+			d.loopInterval = 10 * time.Second
+			d.lastUpdate = tt.lastUpdate
+			d.checkInterval = 30 * time.Second
+			d.probeServers = tt.servers
+
+			rec := httptest.NewRecorder()
+			d.serveHealthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			var resp HealthzResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("body %q is not JSON: %v", rec.Body.String(), err)
+			}
+			reasons := strings.Join(resp.Reasons, "; ")
+			if tt.wantReason == "" && reasons != "" {
+				t.Errorf("reasons = %q, want none", reasons)
+			}
+			if !strings.Contains(reasons, tt.wantReason) {
+				t.Errorf("reasons = %q, want one mentioning %q", reasons, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCheckForUpdates_SkipsWhileInProgress(t *testing.T) {
+	origCheck := checkRustUpdatesFunc
+	defer func() { checkRustUpdatesFunc = origCheck }()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls int32
+	checkRustUpdatesFunc = func(branch string, notifier notify.Notifier) (bool, string, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return false, "12345", nil
+	}
+
+	d := New()
+	cfg := &config.Config{Servers: []config.Server{{Name: "server1", Path: "/path1", Branch: "main"}}}
+
+	first := make(chan struct{})
+	go func() {
+		d.checkForUpdates(cfg)
+		close(first)
+	}()
+	<-started
+
+	// A second pass while the first is blocked in steamcmd returns without checking
+	second := make(chan struct{})
+	go func() {
+		d.checkForUpdates(cfg)
+		close(second)
+	}()
+	select {
+	case <-second:
+	case <-time.After(time.Second):
+		t.Fatal("second checkForUpdates() did not return while the first was running")
+	}
+
+	close(release)
+	<-first
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("CheckForUpdates called %d times, want 1", got)
+	}
+	if d.lastUpdateCheck.IsZero() {
+		t.Error("lastUpdateCheck not set after the pass finished")
+	}
+
+	// The next pass runs once the first has finished
+	release = make(chan struct{})
+	close(release)
+	d.checkForUpdates(cfg)
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("CheckForUpdates called %d times after the first pass finished, want 2", got)
+	}
+}
+
+func TestCheckForUpdates_DefersInstallBeforeEvent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	origCheck, origInstall := checkRustUpdatesFunc, installRustBranchFunc
+	defer func() { checkRustUpdatesFunc, installRustBranchFunc = origCheck, origInstall }()
+
+	checkRustUpdatesFunc = func(branch string, notifier notify.Notifier) (bool, string, error) {
+		return true, "12345", nil
+	}
+	var installs int32
+	installRustBranchFunc = func(branch string, notifier notify.Notifier) error {
+		atomic.AddInt32(&installs, 1)
+		return nil
+	}
+
+	tests := []struct {
+		name         string
+		eventIn      time.Duration
+		deferMinutes int
+		wantInstalls int32
+	}{
+		{"event within window", 5 * time.Minute, 15, 0},
+		{"event after window", 30 * time.Minute, 15, 1},
+		{"window disabled", 5 * time.Minute, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&installs, 0)
+
+			server := config.Server{Name: "server1", Path: "/path1", Branch: "main"}
+			sched, err := scheduler.New(24, nil, 0)
+			if err != nil {
+				t.Fatalf("scheduler.New() error = %v", err)
+			}
+			defer sched.Shutdown(time.Second)
+			sched.SetOneOffEvents([]scheduler.ScheduledEvent{{
+				Server:    server,
+				Event:     calendar.Event{Summary: "wipe", Type: calendar.EventTypeWipe, StartTime: time.Now().Add(tt.eventIn)},
+				Scheduled: time.Now().Add(tt.eventIn),
+			}})
+			if err := sched.UpdateEvents(nil); err != nil {
+				t.Fatalf("UpdateEvents() error = %v", err)
+			}
+
+			d := New()
+			d.scheduler = sched
+			d.checkForUpdates(&config.Config{UpdateDeferMinutes: tt.deferMinutes, Servers: []config.Server{server}})
+
+			if got := atomic.LoadInt32(&installs); got != tt.wantInstalls {
+				t.Errorf("InstallRustBranch called %d times, want %d", got, tt.wantInstalls)
+			}
+		})
+	}
+}
+
+func TestRequestRefresh(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+	origStatusPath := scheduler.StatusPath
+	defer func() { scheduler.StatusPath = origStatusPath }()
+	scheduler.StatusPath = filepath.Join(t.TempDir(), "status.json")
+
+	d := New()
+
+	// Requests never block and coalesce into one pending refresh
+	for i := 0; i < 3; i++ {
+		d.RequestRefresh()
+	}
+	if got := len(d.refresh); got != 1 {
+		t.Fatalf("pending refreshes = %d, want 1", got)
+	}
+
+	<-d.refresh
+	d.config = &config.Config{CheckInterval: 30}
+	d.manualRefresh()
+	if d.scheduler == nil {
+		t.Fatal("manualRefresh() did not update calendars")
+	}
+	defer d.scheduler.Shutdown(time.Second)
+	if d.lastUpdate.IsZero() {
+		t.Error("lastUpdate not set after manualRefresh()")
+	}
+}
@@ -0,0 +1,112 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/logging"
+)
+
+// healthLoopGrace is how much longer than two ticks the main loop may go quiet,
+// covering a slow calendar update running inside the loop
+const healthLoopGrace = time.Minute
+
+// HealthzResponse is the JSON body of /healthz
+type HealthzResponse struct {
+	Status             string     `json:"status"`                         // ok or unavailable
+	Reasons            []string   `json:"reasons,omitempty"`              // Why the daemon is unavailable
+	LastCalendarUpdate *time.Time `json:"last_calendar_update,omitempty"` // When calendars were last updated
+}
+
+// markLoop records that the main loop ticked; it is expected to tick again within loopInterval
+func (d *Daemon) markLoop(loopInterval time.Duration) {
+	d.probeMutex.Lock()
+	defer d.probeMutex.Unlock()
+	d.lastLoop = time.Now()
+	d.loopInterval = loopInterval
+}
+
+// markCalendarUpdate records a successful calendar update and the interval the next is due in
+func (d *Daemon) markCalendarUpdate(checkInterval time.Duration, servers int) {
+	d.probeMutex.Lock()
+	defer d.probeMutex.Unlock()
+	d.lastUpdate = time.Now()
+	d.checkInterval = checkInterval
+	d.probeServers = servers
+}
+
+// healthz reports whether the main loop is alive (it ticked recently) and calendars were updated within
+// twice the check interval (calendars are only required when servers are configured)
+func (d *Daemon) healthz(now time.Time) HealthzResponse {
+	d.probeMutex.Lock()
+	defer d.probeMutex.Unlock()
+
+	resp := HealthzResponse{Status: "ok"}
+	if !d.lastUpdate.IsZero() {
+		lastUpdate := d.lastUpdate
+		resp.LastCalendarUpdate = &lastUpdate
+	}
+
+	if d.lastLoop.IsZero() {
+		resp.Reasons = append(resp.Reasons, "daemon loop has not started")
+	} else if since := now.Sub(d.lastLoop); since > 2*d.loopInterval+healthLoopGrace {
+		resp.Reasons = append(resp.Reasons, fmt.Sprintf("daemon loop last ran %s ago", since.Round(time.Second)))
+	}
+
+	if d.probeServers > 0 {
+		if d.lastUpdate.IsZero() {
+			resp.Reasons = append(resp.Reasons, "calendars have not been updated yet")
+		} else if since := now.Sub(d.lastUpdate); since > 2*d.checkInterval {
+			resp.Reasons = append(resp.Reasons, fmt.Sprintf("last calendar update was %s ago (check_interval is %s)",
+				since.Round(time.Second), d.checkInterval))
+		}
+	}
+
+	if len(resp.Reasons) > 0 {
+		resp.Status = "unavailable"
+	}
+	return resp
+}
+
+// serveHealthz answers /healthz with 200 when healthy and 503 otherwise
+func (d *Daemon) serveHealthz(w http.ResponseWriter, r *http.Request) {
+	resp := d.healthz(time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(resp.Reasons) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// startHealthServer listens on addr and serves /healthz until ctx is done.
+// It returns the address it listens on, or an error if it can't listen.
+func (d *Daemon) startHealthServer(ctx context.Context, addr string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", d.serveHealthz)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Errorf("Error serving health endpoint: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	return listener.Addr().String(), nil
+}
@@ -7,9 +7,14 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/httpclient"
 )
 
 // Color constants for embed colors
@@ -20,6 +25,43 @@ const (
 	ColorError   = 0xff0000 // Red
 )
 
+// DefaultMaxAttempts is how many times a notification is sent by default
+const DefaultMaxAttempts = 3
+
+// maxAttempts is how many times a notification is sent before giving up on 429/5xx responses
+var maxAttempts atomic.Int64
+
+func init() {
+	maxAttempts.Store(DefaultMaxAttempts)
+}
+
+// SetMaxAttempts sets how many times a notification is sent before giving up; safe to call while sends are in flight
+func SetMaxAttempts(attempts int) {
+	maxAttempts.Store(int64(attempts))
+}
+
+// MaxAttempts returns how many times a notification is sent before giving up
+func MaxAttempts() int {
+	return int(maxAttempts.Load())
+}
+
+var (
+	// retryBaseDelay is the first backoff after a 5xx; it doubles on each retry
+	retryBaseDelay = 1 * time.Second
+
+	// maxRetryDelay caps both Retry-After and exponential backoff waits
+	maxRetryDelay = 30 * time.Second
+)
+
+// Mentions set by the daemon from its loaded config (see SetMentions)
+var (
+	mentionMutex sync.RWMutex
+	mentionsSet  bool
+	mentionUsers []string
+	mentionRoles []string
+	mentionOn    map[Level]bool
+)
+
 // EmbedField represents a field in a Discord embed
 type EmbedField struct {
 	Name   string `json:"name"`
@@ -50,6 +92,15 @@ type EmbedFooter struct {
 	IconURL string `json:"icon_url,omitempty"`
 }
 
+// StatusError is returned when the webhook responds with a non-2xx status
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook returned status %d", e.StatusCode)
+}
+
 // WebhookPayload represents the Discord webhook payload
 type WebhookPayload struct {
 	Content string  `json:"content,omitempty"`
@@ -65,57 +116,101 @@ func GetHostname() string {
 	return hostname
 }
 
-// SendNotification sends a Discord notification with an embed
-func SendNotification(webhookURL, title, description string, color int) error {
+// SendNotification sends a Discord notification with an embed colored by level, mentioning the
+// configured users and roles when level is one of the levels they're mentioned on.
+// Fields are shown in the embed before the hostname.
+func SendNotification(webhookURL, title, description string, level Level, fields ...EmbedField) error {
 	if webhookURL == "" {
 		// Webhook not configured, skip silently
 		return nil
 	}
 
-	hostname := GetHostname()
+	userIDs, roleIDs := currentMentions(level)
+	return SendNotificationWithMentions(webhookURL, title, description, level.Color(), userIDs, roleIDs, fields...)
+}
 
-	// Load config to get mention IDs
-	cfg, err := config.GetConfig()
-	if err == nil {
-		// Build mention string inline if mentions are configured
-		userIDs := cfg.DiscordMentionUsers
-		roleIDs := cfg.DiscordMentionRoles
-
-		if len(userIDs) > 0 || len(roleIDs) > 0 {
-			mentions := []string{}
-			for _, roleID := range roleIDs {
-				mentions = append(mentions, fmt.Sprintf("<@&%s>", roleID))
-			}
-			for _, userID := range userIDs {
-				mentions = append(mentions, fmt.Sprintf("<@%s>", userID))
-			}
+// SetMentions sets the users and roles to mention, and the levels (by name) that mention them,
+// so sends don't reload the config file
+func SetMentions(userIDs, roleIDs, levels []string) {
+	mentionMutex.Lock()
+	defer mentionMutex.Unlock()
+	mentionUsers = userIDs
+	mentionRoles = roleIDs
+	mentionOn = levelSet(levels)
+	mentionsSet = true
+}
 
-			if len(mentions) > 0 {
-				mentionStr := "cc " + mentions[0]
-				for i := 1; i < len(mentions); i++ {
-					mentionStr += " " + mentions[i]
-				}
-				description = mentionStr + "\n\n" + description
-			}
+// levelSet returns the named levels as a set, ignoring unknown names
+func levelSet(names []string) map[Level]bool {
+	set := make(map[Level]bool)
+	for _, name := range names {
+		if level, err := ParseLevel(name); err == nil {
+			set[level] = true
+		}
+	}
+	return set
+}
+
+// currentMentions returns the users and roles to mention on a level's notifications, from
+// SetMentions or else the config file
+func currentMentions(level Level) ([]string, []string) {
+	mentionMutex.RLock()
+	if mentionsSet {
+		defer mentionMutex.RUnlock()
+		if !mentionOn[level] {
+			return nil, nil
 		}
+		return mentionUsers, mentionRoles
+	}
+	mentionMutex.RUnlock()
+
+	cfg, err := config.GetConfig()
+	if err != nil || !levelSet(cfg.DiscordMentionOn)[level] {
+		return nil, nil
+	}
+	return cfg.DiscordMentionUsers, cfg.DiscordMentionRoles
+}
+
+// MentionContent builds the message content that pings the given roles and users
+func MentionContent(userIDs, roleIDs []string) string {
+	mentions := []string{}
+	for _, roleID := range roleIDs {
+		mentions = append(mentions, fmt.Sprintf("<@&%s>", roleID))
+	}
+	for _, userID := range userIDs {
+		mentions = append(mentions, fmt.Sprintf("<@%s>", userID))
 	}
 
+	if len(mentions) == 0 {
+		return ""
+	}
+	return "cc " + strings.Join(mentions, " ")
+}
+
+// SendNotificationWithMentions sends a Discord notification that pings the given users and roles
+func SendNotificationWithMentions(webhookURL, title, description string, color int, userIDs, roleIDs []string, fields ...EmbedField) error {
+	if webhookURL == "" {
+		// Webhook not configured, skip silently
+		return nil
+	}
+
+	hostname := GetHostname()
+
 	embed := Embed{
 		Title:       title,
 		Description: description,
 		Color:       color,
 		Timestamp:   time.Now().Format(time.RFC3339),
-		Fields: []EmbedField{
-			{
-				Name:   "Hostname",
-				Value:  hostname,
-				Inline: true,
-			},
-		},
+		Fields: append(append([]EmbedField{}, fields...), EmbedField{
+			Name:   "Hostname",
+			Value:  hostname,
+			Inline: true,
+		}),
 	}
 
 	payload := WebhookPayload{
-		Embeds: []Embed{embed},
+		Content: MentionContent(userIDs, roleIDs),
+		Embeds:  []Embed{embed},
 	}
 
 	jsonData, err := json.Marshal(payload)
@@ -123,43 +218,140 @@ func SendNotification(webhookURL, title, description string, color int) error {
 		return fmt.Errorf("failed to marshal webhook payload: %w", err)
 	}
 
-	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to send webhook: %w", err)
+	return PostWebhook(webhookURL, jsonData)
+}
+
+// PostWebhook delivers a JSON payload, retrying 429s after Retry-After and 5xx responses with exponential
+// backoff. Slack notifications are posted through it too, so both services share one retry policy.
+func PostWebhook(webhookURL string, jsonData []byte) error {
+	attempts := MaxAttempts()
+	if attempts < 1 {
+		attempts = 1
 	}
-	defer resp.Body.Close()
+	backoff := retryBaseDelay
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		resp, err := httpclient.New().Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to send webhook: %w", err)
+		}
+		resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = &StatusError{StatusCode: resp.StatusCode}
+
+		var wait time.Duration
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			wait = parseRetryAfter(resp.Header.Get("Retry-After"), backoff)
+		case resp.StatusCode >= 500:
+			wait = backoff
+			backoff *= 2
+		default:
+			// Other 4xx responses won't succeed on retry
+			return lastErr
+		}
+
+		if attempt < attempts {
+			if wait > maxRetryDelay {
+				wait = maxRetryDelay
+			}
+			log.Printf("Webhook returned status %d, retrying in %v (attempt %d/%d)", resp.StatusCode, wait, attempt, attempts)
+			time.Sleep(wait)
+		}
 	}
 
-	return nil
+	return fmt.Errorf("%w after %d attempts", lastErr, attempts)
 }
 
-// SendSuccess sends a success notification (green)
-func SendSuccess(webhookURL, title, description string) {
-	if err := SendNotification(webhookURL, title, description, ColorSuccess); err != nil {
+// parseRetryAfter reads the Retry-After header (seconds, possibly fractional as Discord sends it)
+func parseRetryAfter(value string, fallback time.Duration) time.Duration {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return fallback
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// SendSuccess sends a success notification (green).
+// Failures are logged and returned; callers that don't care can ignore the error.
+func SendSuccess(webhookURL, title, description string) error {
+	err := send(webhookURL, LevelSuccess, title, description)
+	if err != nil {
 		log.Printf("Failed to send Discord success notification: %v", err)
 	}
+	return err
 }
 
-// SendInfo sends an info notification (blue)
-func SendInfo(webhookURL, title, description string) {
-	if err := SendNotification(webhookURL, title, description, ColorInfo); err != nil {
+// SendInfo sends an info notification (blue).
+// Failures are logged and returned; callers that don't care can ignore the error.
+func SendInfo(webhookURL, title, description string) error {
+	err := send(webhookURL, LevelInfo, title, description)
+	if err != nil {
 		log.Printf("Failed to send Discord info notification: %v", err)
 	}
+	return err
 }
 
-// SendWarning sends a warning notification (orange)
-func SendWarning(webhookURL, title, description string) {
-	if err := SendNotification(webhookURL, title, description, ColorWarning); err != nil {
+// SendWarning sends a warning notification (orange).
+// Failures are logged and returned; callers that don't care can ignore the error.
+func SendWarning(webhookURL, title, description string) error {
+	err := send(webhookURL, LevelWarning, title, description)
+	if err != nil {
 		log.Printf("Failed to send Discord warning notification: %v", err)
 	}
+	return err
 }
 
-// SendError sends an error notification (red)
-func SendError(webhookURL, title, description string) {
-	if err := SendNotification(webhookURL, title, description, ColorError); err != nil {
+// SendError sends an error notification (red).
+// Failures are logged and returned; callers that don't care can ignore the error.
+func SendError(webhookURL, title, description string) error {
+	err := send(webhookURL, LevelError, title, description)
+	if err != nil {
 		log.Printf("Failed to send Discord error notification: %v", err)
 	}
+	return err
+}
+
+// SendFields sends a notification with embed fields at level.
+// Failures are logged and returned; callers that don't care can ignore the error.
+func SendFields(webhookURL string, level Level, title, description string, fields []EmbedField) error {
+	err := send(webhookURL, level, title, description, fields...)
+	if err != nil {
+		log.Printf("Failed to send Discord %s notification: %v", level, err)
+	}
+	return err
+}
+
+// Webhook sends notifications to a Discord webhook through the Send helpers; an empty URL disables it
+type Webhook struct {
+	URL string
+}
+
+// Success sends a success notification (green)
+func (w Webhook) Success(title, description string) error {
+	return SendSuccess(w.URL, title, description)
+}
+
+// Info sends an info notification (blue)
+func (w Webhook) Info(title, description string) error {
+	return SendInfo(w.URL, title, description)
+}
+
+// Warning sends a warning notification (orange)
+func (w Webhook) Warning(title, description string) error {
+	return SendWarning(w.URL, title, description)
+}
+
+// Error sends an error notification (red)
+func (w Webhook) Error(title, description string) error {
+	return SendError(w.URL, title, description)
+}
+
+// Fields sends a notification at level with embed fields
+func (w Webhook) Fields(level Level, title, description string, fields []EmbedField) error {
+	return SendFields(w.URL, level, title, description, fields)
 }
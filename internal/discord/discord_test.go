@@ -1,7 +1,13 @@
 package discord
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestGetHostname(t *testing.T) {
@@ -37,7 +43,7 @@ func TestColorConstants(t *testing.T) {
 
 func TestSendNotificationWithEmptyWebhook(t *testing.T) {
 	// Test that sending with empty webhook doesn't error
-	err := SendNotification("", "Test", "Test message", ColorInfo)
+	err := SendNotification("", "Test", "Test message", LevelInfo)
 	if err != nil {
 		t.Errorf("SendNotification() with empty webhook should not error, got: %v", err)
 	}
@@ -119,3 +125,236 @@ func TestEmbedImageStructure(t *testing.T) {
 		t.Errorf("EmbedImage.URL = %s, want https://example.com/image.png", image.URL)
 	}
 }
+
+func TestSendNotification_RetriesAfterRateLimit(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := SendNotification(server.URL, "Test", "Test message", LevelInfo); err != nil {
+		t.Fatalf("SendNotification() error = %v, want nil", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestSendNotification_GivesUpAfterMaxAttempts(t *testing.T) {
+	origDelay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = origDelay }()
+
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	if err := SendNotification(server.URL, "Test", "Test message", LevelInfo); err == nil {
+		t.Fatal("SendNotification() should return an error after exhausting retries")
+	}
+	if got := atomic.LoadInt32(&attempts); got != int32(MaxAttempts()) {
+		t.Errorf("attempts = %d, want %d", got, MaxAttempts())
+	}
+}
+
+func TestSendNotification_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := SendNotification(server.URL, "Test", "Test message", LevelInfo)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("SendNotification() error = %v, want a *StatusError", err)
+	}
+	if statusErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("StatusCode = %d, want %d", statusErr.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestSendHelpers_ReturnErrorOnServerError(t *testing.T) {
+	origDelay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = origDelay }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	helpers := map[string]func(webhookURL, title, description string) error{
+		"SendSuccess": SendSuccess,
+		"SendInfo":    SendInfo,
+		"SendWarning": SendWarning,
+		"SendError":   SendError,
+	}
+	for name, send := range helpers {
+		t.Run(name, func(t *testing.T) {
+			err := send(server.URL, "Test", "Test message")
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+				t.Errorf("%s() error = %v, want status 500", name, err)
+			}
+		})
+	}
+}
+
+func TestSendNotificationWithMentions_Content(t *testing.T) {
+	var payload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	users := []string{"123456789012345678"}
+	roles := []string{"111222333444555666"}
+	if err := SendNotificationWithMentions(server.URL, "Test", "Test message", ColorInfo, users, roles); err != nil {
+		t.Fatalf("SendNotificationWithMentions() error = %v", err)
+	}
+
+	want := "cc <@&111222333444555666> <@123456789012345678>"
+	if payload.Content != want {
+		t.Errorf("Content = %q, want %q", payload.Content, want)
+	}
+	if len(payload.Embeds) != 1 || payload.Embeds[0].Description != "Test message" {
+		t.Errorf("Embeds = %+v, want one embed with the original description", payload.Embeds)
+	}
+
+	// Without mentions the content is omitted
+	payload = WebhookPayload{}
+	if err := SendNotificationWithMentions(server.URL, "Test", "Test message", ColorInfo, nil, nil); err != nil {
+		t.Fatalf("SendNotificationWithMentions() error = %v", err)
+	}
+	if payload.Content != "" {
+		t.Errorf("Content = %q, want empty", payload.Content)
+	}
+}
+
+func TestSetMentions_UsedBySendNotification(t *testing.T) {
+	SetMentions([]string{"123456789012345678"}, []string{"111222333444555666"}, []string{"error", "warning"})
+	defer func() {
+		mentionMutex.Lock()
+		mentionsSet, mentionUsers, mentionRoles, mentionOn = false, nil, nil, nil
+		mentionMutex.Unlock()
+	}()
+
+	var payload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		level Level
+		want  string
+	}{
+		{LevelSuccess, ""},
+		{LevelInfo, ""},
+		{LevelWarning, "cc <@&111222333444555666> <@123456789012345678>"},
+		{LevelError, "cc <@&111222333444555666> <@123456789012345678>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			payload = WebhookPayload{}
+			if err := SendNotification(server.URL, "Test", "Test message", tt.level); err != nil {
+				t.Fatalf("SendNotification() error = %v", err)
+			}
+			if payload.Content != tt.want {
+				t.Errorf("Content = %q, want %q", payload.Content, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhook_LevelColors(t *testing.T) {
+	SetMentions(nil, nil, nil)
+	defer func() {
+		mentionMutex.Lock()
+		mentionsSet, mentionUsers, mentionRoles, mentionOn = false, nil, nil, nil
+		mentionMutex.Unlock()
+	}()
+
+	var payload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := Webhook{URL: server.URL}
+	tests := []struct {
+		name string
+		send func(title, description string) error
+		want int
+	}{
+		{"Success", webhook.Success, ColorSuccess},
+		{"Info", webhook.Info, ColorInfo},
+		{"Warning", webhook.Warning, ColorWarning},
+		{"Error", webhook.Error, ColorError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload = WebhookPayload{}
+			if err := tt.send("Test", "Test message"); err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			if len(payload.Embeds) != 1 || payload.Embeds[0].Color != tt.want || payload.Embeds[0].Title != "Test" {
+				t.Errorf("Embeds = %+v, want one %q embed with color %x", payload.Embeds, "Test", tt.want)
+			}
+		})
+	}
+
+	// An empty URL disables the webhook
+	if err := (Webhook{}).Error("Test", "Test message"); err != nil {
+		t.Errorf("Error() with empty URL = %v, want nil", err)
+	}
+}
+
+func TestWebhook_Fields(t *testing.T) {
+	var payload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	fields := []EmbedField{{Name: "Restarts", Value: "• server1"}, {Name: "Count", Value: "1 restart(s), 0 wipe(s)", Inline: true}}
+	if err := (Webhook{URL: server.URL}).Fields(LevelSuccess, "Test", "Test message", fields); err != nil {
+		t.Fatalf("Fields() error = %v", err)
+	}
+
+	if len(payload.Embeds) != 1 {
+		t.Fatalf("Embeds = %+v, want one embed", payload.Embeds)
+	}
+	embed := payload.Embeds[0]
+	if embed.Color != ColorSuccess || embed.Description != "Test message" {
+		t.Errorf("embed = %+v, want success color and the original description", embed)
+	}
+
+	// The given fields come first and the hostname is kept last
+	got := embed.Fields
+	if len(got) != 3 || got[0] != fields[0] || got[1] != fields[1] || got[2].Name != "Hostname" {
+		t.Errorf("Fields = %+v, want %+v followed by Hostname", got, fields)
+	}
+}
@@ -0,0 +1,180 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Level is the severity of a queued notification
+type Level int
+
+const (
+	LevelSuccess Level = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+)
+
+// String returns the level's name as used in discord_mention_on
+func (l Level) String() string {
+	switch l {
+	case LevelSuccess:
+		return "success"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// ParseLevel returns the level with the given name
+func ParseLevel(name string) (Level, error) {
+	for _, l := range []Level{LevelSuccess, LevelInfo, LevelWarning, LevelError} {
+		if l.String() == name {
+			return l, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown notification level '%s'", name)
+}
+
+// Color returns the embed color for a notification level
+func (l Level) Color() int {
+	switch l {
+	case LevelSuccess:
+		return ColorSuccess
+	case LevelWarning:
+		return ColorWarning
+	case LevelError:
+		return ColorError
+	default:
+		return ColorInfo
+	}
+}
+
+// queuedMessage is a notification waiting to be delivered
+type queuedMessage struct {
+	webhookURL  string
+	level       Level
+	title       string
+	description string
+	fields      []EmbedField
+	deliver     func() error // Posts a notification for another service (see Deliver) instead of the fields above
+}
+
+// Notifier delivers notifications from a background goroutine so callers never block on a webhook
+type Notifier struct {
+	webhookURL string
+	queue      chan queuedMessage
+	pending    sync.WaitGroup
+}
+
+// background, when set, makes SendSuccess/SendInfo/SendWarning/SendError enqueue instead of posting
+var (
+	background      *Notifier
+	backgroundMutex sync.RWMutex
+)
+
+// NewNotifier creates a notifier for webhookURL with room for queueSize pending messages
+func NewNotifier(webhookURL string, queueSize int) *Notifier {
+	n := &Notifier{
+		webhookURL: webhookURL,
+		queue:      make(chan queuedMessage, queueSize),
+	}
+	go n.run()
+	return n
+}
+
+// run delivers queued messages in order
+func (n *Notifier) run() {
+	for msg := range n.queue {
+		if msg.deliver != nil {
+			if err := msg.deliver(); err != nil {
+				log.Printf("Failed to send notification %q: %v", msg.title, err)
+			}
+		} else if err := SendNotification(msg.webhookURL, msg.title, msg.description, msg.level, msg.fields...); err != nil {
+			log.Printf("Failed to send Discord notification %q: %v", msg.title, err)
+		}
+		n.pending.Done()
+	}
+}
+
+// Enqueue queues a notification for the notifier's webhook and returns immediately
+func (n *Notifier) Enqueue(level Level, title, description string) {
+	n.enqueue(n.webhookURL, level, title, description)
+}
+
+// enqueue queues a notification for any webhook, dropping it if the queue is full
+func (n *Notifier) enqueue(webhookURL string, level Level, title, description string, fields ...EmbedField) {
+	if webhookURL == "" {
+		return
+	}
+
+	n.push(queuedMessage{webhookURL: webhookURL, level: level, title: title, description: description, fields: fields})
+}
+
+// push queues a message, dropping it if the queue is full
+func (n *Notifier) push(msg queuedMessage) {
+	n.pending.Add(1)
+	select {
+	case n.queue <- msg:
+	default:
+		n.pending.Done()
+		log.Printf("Notification queue full, dropping %q", msg.title)
+	}
+}
+
+// Flush waits until all queued notifications are delivered or ctx is done
+func (n *Notifier) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		n.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetBackground routes the package-level Send helpers through n (nil restores synchronous sends)
+func SetBackground(n *Notifier) {
+	backgroundMutex.Lock()
+	defer backgroundMutex.Unlock()
+	background = n
+}
+
+// send delivers a notification through the background notifier if one is set, otherwise synchronously.
+// Queued notifications return nil; their delivery errors are only logged.
+func send(webhookURL string, level Level, title, description string, fields ...EmbedField) error {
+	backgroundMutex.RLock()
+	n := background
+	backgroundMutex.RUnlock()
+
+	if n != nil {
+		n.enqueue(webhookURL, level, title, description, fields...)
+		return nil
+	}
+	return SendNotification(webhookURL, title, description, level, fields...)
+}
+
+// Deliver runs deliver, which posts a notification titled title to another service, through the
+// background notifier if one is set so the caller never blocks on it; otherwise it runs immediately.
+// Queued notifications return nil; their delivery errors are only logged.
+func Deliver(title string, deliver func() error) error {
+	backgroundMutex.RLock()
+	n := background
+	backgroundMutex.RUnlock()
+
+	if n != nil {
+		n.push(queuedMessage{title: title, deliver: deliver})
+		return nil
+	}
+	return deliver()
+}
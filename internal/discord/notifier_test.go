@@ -0,0 +1,67 @@
+package discord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNotifier_FlushDeliversAll(t *testing.T) {
+	var delivered int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond) // Simulate a slow Discord
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	const n = 20
+	notifier := NewNotifier(server.URL, n)
+
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		notifier.Enqueue(LevelInfo, "Test", "Queued message")
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Enqueue took %v, want it to return immediately", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := notifier.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&delivered); got != n {
+		t.Errorf("delivered = %d, want %d", got, n)
+	}
+}
+
+func TestSetBackground_RoutesSendHelpers(t *testing.T) {
+	var delivered int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier("", 10)
+	SetBackground(notifier)
+	defer SetBackground(nil)
+
+	SendInfo(server.URL, "Test", "Info")
+	SendError(server.URL, "Test", "Error")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := notifier.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&delivered); got != 2 {
+		t.Errorf("delivered = %d, want 2", got)
+	}
+}
@@ -0,0 +1,206 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/logging"
+)
+
+// WipeBackupDir is the directory (inside the server identity folder) that safe wipes move files into
+const WipeBackupDir = ".wipe-backup"
+
+// backupTimeFormat names each backup after the time it was taken, with a -N suffix for
+// later backups in the same second (see backupOrder)
+const backupTimeFormat = "20060102-150405"
+
+// DefaultWipeBackupRetention is how many backups are kept per server by default
+const DefaultWipeBackupRetention = 3
+
+var (
+	// safeWipe moves wiped files into a timestamped backup instead of deleting them
+	safeWipe atomic.Bool
+
+	// wipeBackupRetention is how many backups are kept per server when safe wipes are enabled
+	wipeBackupRetention atomic.Int64
+)
+
+func init() {
+	wipeBackupRetention.Store(DefaultWipeBackupRetention)
+}
+
+// SetSafeWipe sets whether wipes move files into a timestamped backup instead of deleting them
+func SetSafeWipe(enabled bool) {
+	safeWipe.Store(enabled)
+}
+
+// SafeWipe returns whether wipes back up files instead of deleting them
+func SafeWipe() bool {
+	return safeWipe.Load()
+}
+
+// SetWipeBackupRetention sets how many backups are kept per server when safe wipes are enabled
+func SetWipeBackupRetention(count int) {
+	wipeBackupRetention.Store(int64(count))
+}
+
+// WipeBackupRetention returns how many backups are kept per server
+func WipeBackupRetention() int {
+	return int(wipeBackupRetention.Load())
+}
+
+// serverDataPath returns the identity folder holding a server's map and save files
+func serverDataPath(server config.Server) string {
+	return filepath.Join(server.Path, "server", server.GetIdentity())
+}
+
+// newWipeBackup creates a timestamped backup directory for the server's data path
+func newWipeBackup(dataPath string) (string, error) {
+	backupPath := filepath.Join(dataPath, WipeBackupDir, time.Now().Format(backupTimeFormat))
+	// Two wipes in the same second get a numbered suffix rather than sharing a directory
+	for i := 1; ; i++ {
+		if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+			break
+		}
+		backupPath = filepath.Join(dataPath, WipeBackupDir, fmt.Sprintf("%s-%d", time.Now().Format(backupTimeFormat), i))
+	}
+
+	if err := os.MkdirAll(backupPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to create wipe backup directory: %w", err)
+	}
+	return backupPath, nil
+}
+
+// listWipeBackups returns the server's backup directories, oldest first
+func listWipeBackups(dataPath string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dataPath, WipeBackupDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	type backup struct {
+		path string
+		at   time.Time
+		seq  int
+	}
+	var found []backup
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		at, seq, ok := backupOrder(entry.Name())
+		if !ok {
+			// Not named by newWipeBackup; fall back to when it was last modified
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			at = info.ModTime()
+		}
+		found = append(found, backup{filepath.Join(dataPath, WipeBackupDir, entry.Name()), at, seq})
+	}
+
+	// Sort by time, then suffix: comparing names would put -10 before -2
+	sort.SliceStable(found, func(i, j int) bool {
+		if !found[i].at.Equal(found[j].at) {
+			return found[i].at.Before(found[j].at)
+		}
+		return found[i].seq < found[j].seq
+	})
+
+	backups := make([]string, len(found))
+	for i, b := range found {
+		backups[i] = b.path
+	}
+	return backups, nil
+}
+
+// backupOrder parses a backup name from newWipeBackup into the time it was taken and its
+// same-second suffix (0 for none)
+func backupOrder(name string) (time.Time, int, bool) {
+	if len(name) < len(backupTimeFormat) {
+		return time.Time{}, 0, false
+	}
+	at, err := time.ParseInLocation(backupTimeFormat, name[:len(backupTimeFormat)], time.Local)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	suffix := name[len(backupTimeFormat):]
+	if suffix == "" {
+		return at, 0, true
+	}
+	seq, err := strconv.Atoi(strings.TrimPrefix(suffix, "-"))
+	if !strings.HasPrefix(suffix, "-") || err != nil || seq < 1 {
+		return time.Time{}, 0, false
+	}
+	return at, seq, true
+}
+
+// pruneWipeBackups removes the oldest backups so at most keep remain
+func pruneWipeBackups(dataPath string, keep int) error {
+	if keep < 1 {
+		keep = 1
+	}
+
+	backups, err := listWipeBackups(dataPath)
+	if err != nil {
+		return fmt.Errorf("failed to list wipe backups: %w", err)
+	}
+
+	for len(backups) > keep {
+		logging.Infof("  Pruning old wipe backup: %s", backups[0])
+		if err := os.RemoveAll(backups[0]); err != nil {
+			return fmt.Errorf("failed to remove wipe backup %s: %w", backups[0], err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// RestoreWipeBackup moves the files from the server's most recent wipe backup back into place,
+// overwriting any files with the same name. It returns the backup directory and the restored files.
+func RestoreWipeBackup(server config.Server) (string, []string, error) {
+	dataPath := serverDataPath(server)
+
+	backups, err := listWipeBackups(dataPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list wipe backups: %w", err)
+	}
+	if len(backups) == 0 {
+		return "", nil, fmt.Errorf("no wipe backups found in %s", filepath.Join(dataPath, WipeBackupDir))
+	}
+	latest := backups[len(backups)-1]
+
+	entries, err := os.ReadDir(latest)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read wipe backup %s: %w", latest, err)
+	}
+
+	var restored []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		dst := filepath.Join(dataPath, entry.Name())
+		if err := os.Rename(filepath.Join(latest, entry.Name()), dst); err != nil {
+			return latest, restored, fmt.Errorf("failed to restore %s: %w", entry.Name(), err)
+		}
+		restored = append(restored, dst)
+	}
+
+	if err := os.Remove(latest); err != nil {
+		logging.Warnf("Warning: Failed to remove restored backup directory %s: %v", latest, err)
+	}
+	return latest, restored, nil
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/logging"
+	"github.com/maintc/wipe-cli/internal/notify"
+)
+
+var (
+	// WipeConfirmationPath is written by 'wipe confirm' to approve a pending wipe batch
+	WipeConfirmationPath = "/opt/wiped/wipe-confirmed"
+
+	// confirmationPollInterval is how often AwaitWipeConfirmation checks for approval
+	confirmationPollInterval = 5 * time.Second
+)
+
+// ErrWipeNotConfirmed is returned when a wipe batch is not confirmed before the timeout
+var ErrWipeNotConfirmed = errors.New("wipe not confirmed")
+
+// ConfirmWipe approves the wipe batch currently waiting for confirmation
+func ConfirmWipe() error {
+	if err := os.MkdirAll(filepath.Dir(WipeConfirmationPath), 0755); err != nil {
+		return fmt.Errorf("failed to create confirmation directory: %w", err)
+	}
+	content := time.Now().Format(time.RFC3339) + "\n"
+	if err := os.WriteFile(WipeConfirmationPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write confirmation: %w", err)
+	}
+	return nil
+}
+
+// AwaitWipeConfirmation posts a confirmation request and blocks until 'wipe confirm' is run or the timeout expires.
+// Confirmations left over from before the request are discarded so they can't approve a later wipe.
+func AwaitWipeConfirmation(serverNames []string, timeout time.Duration, notifier notify.Notifier) error {
+	if err := os.Remove(WipeConfirmationPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear stale confirmation: %w", err)
+	}
+
+	logging.Infof("Waiting up to %s for wipe confirmation ('wipe confirm')...", timeout)
+	notifier.Warning("Wipe Confirmation Required",
+		fmt.Sprintf("Wipe pending for **%d** server(s):\n• %s\n\nRun `wipe confirm` on the host within **%s** or the batch will be aborted",
+			len(serverNames), strings.Join(serverNames, "\n• "), timeout))
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if _, err := os.Stat(WipeConfirmationPath); err == nil {
+			os.Remove(WipeConfirmationPath)
+			logging.Infof("✓ Wipe confirmed")
+			notifier.Success("Wipe Confirmed", "Wipe confirmed, proceeding with batch")
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			logging.Warnf("Wipe not confirmed within %s, aborting batch", timeout)
+			notifier.Error("Wipe Aborted",
+				fmt.Sprintf("Wipe was not confirmed within **%s**\n\nNo servers were stopped or wiped", timeout))
+			return fmt.Errorf("%w within %s", ErrWipeNotConfirmed, timeout)
+		}
+
+		time.Sleep(confirmationPollInterval)
+	}
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/maintc/wipe-cli/internal/httpclient"
+	"github.com/maintc/wipe-cli/internal/logging"
+)
+
+// eventWebhookURL receives a JSON post when a batch starts, completes or fails (empty disables it)
+var (
+	eventWebhookURL   = ""
+	eventWebhookMutex sync.RWMutex
+)
+
+// SetEventWebhookURL sets the URL that receives batch start/complete/failed posts (empty disables them)
+func SetEventWebhookURL(webhookURL string) {
+	eventWebhookMutex.Lock()
+	defer eventWebhookMutex.Unlock()
+	eventWebhookURL = webhookURL
+}
+
+// EventWebhookURL returns the URL batch events are posted to, or "" if disabled
+func EventWebhookURL() string {
+	eventWebhookMutex.RLock()
+	defer eventWebhookMutex.RUnlock()
+	return eventWebhookURL
+}
+
+// Events posted to EventWebhookURL
+const (
+	EventBatchStart    = "batch_start"
+	EventBatchComplete = "batch_complete"
+	EventBatchFailed   = "batch_failed"
+)
+
+// EventPayload is the JSON body posted to EventWebhookURL
+type EventPayload struct {
+	Event    string   `json:"event"`           // batch_start, batch_complete or batch_failed
+	Servers  []string `json:"servers"`         // Names of the servers in the batch
+	Restarts int      `json:"restarts"`        // Servers restarted without a wipe
+	Wipes    int      `json:"wipes"`           // Servers wiped
+	Error    string   `json:"error,omitempty"` // Why the batch failed
+}
+
+// postEventWebhook posts a batch event to EventWebhookURL.
+// It is best-effort: failures are logged and never fail the batch.
+func postEventWebhook(payload EventPayload) {
+	webhookURL := EventWebhookURL()
+	if webhookURL == "" {
+		return
+	}
+
+	if err := sendEventWebhook(webhookURL, payload); err != nil {
+		logging.Warnf("Warning: Failed to post %s to event webhook: %v", payload.Event, err)
+	}
+}
+
+// sendEventWebhook posts payload as JSON and checks for a 2xx response
+func sendEventWebhook(webhookURL string, payload EventPayload) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	resp, err := httpclient.New().Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to send event webhook: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package executor
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -8,19 +10,24 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
-	"github.com/maintc/wipe-cli/internal/carbon"
 	"github.com/maintc/wipe-cli/internal/config"
-	"github.com/maintc/wipe-cli/internal/discord"
+	"github.com/maintc/wipe-cli/internal/framework"
+	"github.com/maintc/wipe-cli/internal/logging"
+	"github.com/maintc/wipe-cli/internal/metrics"
+	"github.com/maintc/wipe-cli/internal/notify"
 	"github.com/maintc/wipe-cli/internal/steamcmd"
 )
 
 var (
-	HookScriptPath         = "/opt/wiped/pre-start-hook.sh"
-	StopServersScriptPath  = "/opt/wiped/stop-servers.sh"
-	StartServersScriptPath = "/opt/wiped/start-servers.sh"
-	GenerateMapsScriptPath = "/opt/wiped/generate-maps.sh"
+	HookScriptPath          = "/opt/wiped/pre-start-hook.sh"
+	PostStartHookScriptPath = "/opt/wiped/post-start-hook.sh"
+	StopServersScriptPath   = "/opt/wiped/stop-servers.sh"
+	StartServersScriptPath  = "/opt/wiped/start-servers.sh"
+	GenerateMapsScriptPath  = "/opt/wiped/generate-maps.sh"
 )
 
 // EnsureHookScript creates the pre-start hook script if it doesn't exist
@@ -35,34 +42,13 @@ func EnsureHookScript() error {
 		return nil
 	}
 
-	content := `#!/bin/bash
-# Pre-start Hook Script
-# 
-# This script is executed once after all servers have been synced
-# but before any servers are started back up.
-#
-# Arguments passed to this script:
-#   $@ - Space-separated list of server paths involved in this event
-#
-# Example:
-#   /var/www/servers/us-weekly /var/www/servers/eu-monthly
-#
-# You can add any custom logic here that should run before servers start.
-# For example: clearing caches, updating plugins, sending notifications, etc.
-
-SERVER_PATHS="$@"
-
-echo "Pre-start hook executed for servers: $SERVER_PATHS"
-
-# Add your custom logic below this line
-# ...
-`
+	content := defaultHookScript
 
 	if err := os.WriteFile(HookScriptPath, []byte(content), 0755); err != nil {
 		return fmt.Errorf("failed to write hook script: %w", err)
 	}
 
-	log.Printf("Created pre-start hook script at %s", HookScriptPath)
+	logging.Infof("Created pre-start hook script at %s", HookScriptPath)
 	return nil
 }
 
@@ -88,6 +74,11 @@ func EnsureWipeScripts() error {
 		return err
 	}
 
+	// Ensure post-start-hook.sh
+	if err := ensurePostStartHookScript(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -97,45 +88,13 @@ func ensureStopServersScript() error {
 		return nil
 	}
 
-	content := `#!/bin/bash
-# Stop Servers Script
-#
-# This script is called to stop Rust servers before performing updates/wipes.
-#
-# Arguments passed to this script:
-#   $@ - Space-separated list of server paths
-#
-# Example:
-#   /var/www/servers/us-weekly /var/www/servers/eu-monthly
-#
-# Customize this script to match your server management approach.
-
-SERVER_PATHS="$@"
-
-echo "Stopping servers for paths: $SERVER_PATHS"
-
-for SERVER_PATH in $SERVER_PATHS; do
-    # Extract server identity from path (e.g., us-weekly from /var/www/servers/us-weekly)
-    IDENTITY=$(basename "$SERVER_PATH")
-    
-    echo "Stopping server: $IDENTITY (path: $SERVER_PATH)"
-    
-    # Add your server stop logic here
-    # Examples:
-    #   - systemctl stop rs-${IDENTITY}
-    #   - docker stop ${IDENTITY}
-    #   - kill $(cat ${SERVER_PATH}/server.pid)
-    #   - your custom stop command
-done
-
-echo "✓ All servers stopped"
-`
+	content := defaultStopServersScript
 
 	if err := os.WriteFile(StopServersScriptPath, []byte(content), 0755); err != nil {
 		return fmt.Errorf("failed to write stop-servers script: %w", err)
 	}
 
-	log.Printf("Created stop-servers script at %s", StopServersScriptPath)
+	logging.Infof("Created stop-servers script at %s", StopServersScriptPath)
 	return nil
 }
 
@@ -145,45 +104,13 @@ func ensureStartServersScript() error {
 		return nil
 	}
 
-	content := `#!/bin/bash
-# Start Servers Script
-#
-# This script is called to start Rust servers after performing updates/wipes.
-#
-# Arguments passed to this script:
-#   $@ - Space-separated list of server paths
-#
-# Example:
-#   /var/www/servers/us-weekly /var/www/servers/eu-monthly
-#
-# Customize this script to match your server management approach.
-
-SERVER_PATHS="$@"
-
-echo "Starting servers for paths: $SERVER_PATHS"
-
-for SERVER_PATH in $SERVER_PATHS; do
-    # Extract server identity from path (e.g., us-weekly from /var/www/servers/us-weekly)
-    IDENTITY=$(basename "$SERVER_PATH")
-    
-    echo "Starting server: $IDENTITY (path: $SERVER_PATH)"
-    
-    # Add your server start logic here
-    # Examples:
-    #   - systemctl start rs-${IDENTITY}
-    #   - docker start ${IDENTITY}
-    #   - ${SERVER_PATH}/start.sh
-    #   - your custom start command
-done
-
-echo "✓ All servers started"
-`
+	content := defaultStartServersScript
 
 	if err := os.WriteFile(StartServersScriptPath, []byte(content), 0755); err != nil {
 		return fmt.Errorf("failed to write start-servers script: %w", err)
 	}
 
-	log.Printf("Created start-servers script at %s", StartServersScriptPath)
+	logging.Infof("Created start-servers script at %s", StartServersScriptPath)
 	return nil
 }
 
@@ -193,212 +120,585 @@ func ensureGenerateMapsScript() error {
 		return nil
 	}
 
-	content := `#!/bin/bash
-# Generate Maps Script
-#
-# This script is called to prepare maps for Rust servers before wipes.
-# It runs 22 hours before a wipe event (configurable via map_generation_hours).
-#
-# Arguments passed to this script:
-#   $@ - Space-separated list of server paths that need maps prepared
-#
-# Example:
-#   /var/www/servers/us-weekly /var/www/servers/eu-monthly
-#
-# YOUR RESPONSIBILITIES:
-#   1. Pick or generate a map (seed/size, custom map, etc.)
-#   2. Update the server's server.cfg file with map settings:
-#      - server.seed and server.size (for procedural maps)
-#      - OR server.levelurl (for custom map providers)
-#   3. Handle any map-related files as needed
-#   4. Clean up any temporary files after the wipe completes
-#   5. Exit with non-zero status on failure
-#
-# NOTE: This script is called BEFORE the wipe. The actual wipe process will:
-#   - Stop servers
-#   - Sync Rust/Carbon
-#   - Delete map/save files
-#   - Run pre-start-hook.sh
-#   - Start servers
-#
-# You are responsible for updating server.cfg BEFORE the wipe or in pre-start-hook.sh
-
-SERVER_PATHS="$@"
-
-echo "Map preparation requested for paths: $SERVER_PATHS"
-
-for SERVER_PATH in $SERVER_PATHS; do
-    # Extract server identity from path (e.g., us-weekly from /var/www/servers/us-weekly)
-    IDENTITY=$(basename "$SERVER_PATH")
-    
-    echo "Preparing map for: $IDENTITY (path: $SERVER_PATH)"
-    
-    # Add your map preparation logic here
-    # Examples:
-    #
-    # Option 1: Pick random seed/size and update server.cfg
-    #   SEED=$RANDOM
-    #   SIZE=4250
-    #   echo "server.seed \"$SEED\"" >> ${SERVER_PATH}/server/${IDENTITY}/cfg/server.cfg
-    #   echo "server.size $SIZE" >> ${SERVER_PATH}/server/${IDENTITY}/cfg/server.cfg
-    #
-    # Option 2: Generate with a custom map generator and update server.cfg
-    #   /usr/local/bin/map-generator --seed $SEED --size $SIZE --output ${SERVER_PATH}/maps
-    #   LEVELURL=$(cat ${SERVER_PATH}/maps/level_url.txt)
-    #   echo "server.levelurl \"$LEVELURL\"" >> ${SERVER_PATH}/server/${IDENTITY}/cfg/server.cfg
-    #
-    # Option 3: Do nothing, let server use default map
-    #   echo "Using default map for $IDENTITY"
-done
-
-echo "✓ Map preparation complete"
-`
+	content := defaultGenerateMapsScript
 
 	if err := os.WriteFile(GenerateMapsScriptPath, []byte(content), 0755); err != nil {
 		return fmt.Errorf("failed to write generate-maps script: %w", err)
 	}
 
-	log.Printf("Created generate-maps script at %s", GenerateMapsScriptPath)
+	logging.Infof("Created generate-maps script at %s", GenerateMapsScriptPath)
+	return nil
+}
+
+func ensurePostStartHookScript() error {
+	// Check if script already exists
+	if _, err := os.Stat(PostStartHookScriptPath); err == nil {
+		return nil
+	}
+
+	content := defaultPostStartHookScript
+
+	if err := os.WriteFile(PostStartHookScriptPath, []byte(content), 0755); err != nil {
+		return fmt.Errorf("failed to write post-start hook script: %w", err)
+	}
+
+	logging.Infof("Created post-start hook script at %s", PostStartHookScriptPath)
 	return nil
 }
 
-// ExecuteEventBatch processes multiple servers together (mix of restarts and wipes)
-func ExecuteEventBatch(servers []config.Server, wipeServers map[string]bool, webhookURL string, eventDelay int) error {
+// WipeMode selects which files a wipe deletes
+type WipeMode int
+
+const (
+	WipeStandard       WipeMode = iota // Map, saves and player state; blueprints only if the server has wipe_blueprints
+	WipeWithBlueprints                 // Standard wipe plus blueprints regardless of wipe_blueprints
+	WipeMapOnly                        // Map and save files only
+)
+
+// BatchOptions controls how ExecuteEventBatch runs a batch
+type BatchOptions struct {
+	Notifier                notify.Notifier        // Where batch notifications are sent (nil to disable)
+	EventDelay              int                    // Seconds to wait once, after event time and before any server is stopped
+	StartStagger            int                    // Seconds between starting each server (0 starts all at once)
+	StartStaggerSize        int                    // Servers started together per staggered start (0 or 1 starts one at a time)
+	WipeConfirmationMinutes int                    // Minutes to wait for 'wipe confirm' before a batch with wipes (0 disables)
+	MinFreeMemoryMB         int                    // Free memory (MB) required per server before starting (0 disables)
+	BatchSize               int                    // Servers stopped, synced, wiped and started per wave (0 runs the whole batch at once)
+	KeepMaps                bool                   // Keep *.map files on wipe so servers reuse their current map
+	StepTimeout             time.Duration          // How long each stop, sync, pre-start hook and start step may run before it is killed (0 disables)
+	RecheckWipes            func() map[string]bool // Called after EventDelay; returns paths of servers whose wipe left the calendar, which are dropped (nil skips)
+	DryRun                  bool                   // Log what the batch would do without stopping, syncing, wiping or starting anything
+	RconWarnings            []int                  // Seconds before servers are stopped to warn players over RCON; only those within EventDelay are sent
+	RconWarningMessage      string                 // Warning template, see FormatRconWarning (empty for "{event} in {time}")
+}
+
+// ExecuteEventBatch processes multiple servers together (mix of restarts and wipes).
+// wipeServers maps the path of each server to wipe to the files it should lose.
+// Servers listed in noSyncServers are stopped and started but skip the Rust/Carbon sync.
+// The batch sleeps opts.EventDelay once before stopping any server, so every server gets the same
+// in-game warning period; callers mixing servers with different delays pass the longest.
+// Servers with an RCON address are warned in-game at each of opts.RconWarnings during that delay.
+// Every batch that isn't a dry run is appended to HistoryFile.
+// Each step runs under ctx, bounded by opts.StepTimeout; if a step times out the servers are started anyway.
+// With opts.BatchSize set, servers are processed in sequential waves of that size; a failed wave leaves later waves untouched.
+func ExecuteEventBatch(ctx context.Context, servers []config.Server, wipeServers map[string]WipeMode, noSyncServers map[string]bool, opts BatchOptions) error {
+	start := time.Now()
+	err := executeEventBatch(ctx, servers, wipeServers, noSyncServers, opts)
+	if !opts.DryRun {
+		recordHistory(start, servers, len(wipeServers), err)
+		metrics.RecordBatch(len(servers)-len(wipeServers), len(wipeServers), err)
+	}
+	return err
+}
+
+// executeEventBatch runs the steps of ExecuteEventBatch
+func executeEventBatch(ctx context.Context, servers []config.Server, wipeServers map[string]WipeMode, noSyncServers map[string]bool, opts BatchOptions) error {
+	notifier := notify.OrDiscard(opts.Notifier)
 	wipeCount := len(wipeServers)
 	restartCount := len(servers) - wipeCount
 
-	log.Printf("Executing batch event for %d server(s): %d restart(s), %d wipe(s)", len(servers), restartCount, wipeCount)
+	logging.Infof("Executing batch event for %d server(s): %d restart(s), %d wipe(s)", len(servers), restartCount, wipeCount)
 
-	// Wait for configured delay
-	if eventDelay > 0 {
-		log.Printf("Waiting %d seconds before executing...", eventDelay)
-		time.Sleep(time.Duration(eventDelay) * time.Second)
+	if opts.DryRun {
+		if waves := len(splitWaves(servers, opts.BatchSize)); waves > 1 {
+			logging.Infof("[dry-run] Would process %d waves of up to %d server(s)", waves, opts.BatchSize)
+		}
+		return dryRunBatch(servers, wipeServers, noSyncServers, opts.KeepMaps)
+	}
+
+	// Announce wipes that will be re-checked, so a mistaken event can still be deleted
+	if opts.RecheckWipes != nil && wipeCount > 0 {
+		notifier.Info("Wipe Pending",
+			fmt.Sprintf("**%d** wipe(s) start in %d second(s):\n• %s\n\nThe calendar is checked again first; delete the event to cancel.",
+				wipeCount, opts.EventDelay, strings.Join(wipeServerNames(servers, wipeServers), "\n• ")))
+	}
+
+	// Wait for the configured delay once for the whole batch, before any server is stopped
+	if opts.EventDelay > 0 {
+		logging.Infof("Waiting %d seconds before executing...", opts.EventDelay)
+		waitEventDelay(servers, wipeServers, opts)
+	}
+
+	// Drop servers whose wipe event was removed from the calendar during the delay
+	if opts.RecheckWipes != nil && wipeCount > 0 {
+		if cancelled := opts.RecheckWipes(); len(cancelled) > 0 {
+			var cancelledNames []string
+			var kept []config.Server
+			keptWipes := make(map[string]WipeMode)
+			for _, server := range servers {
+				if cancelled[server.Path] {
+					cancelledNames = append(cancelledNames, server.Name)
+					continue
+				}
+				kept = append(kept, server)
+				if mode, wipe := wipeServers[server.Path]; wipe {
+					keptWipes[server.Path] = mode
+				}
+			}
+
+			logging.Warnf("Cancelled wipe for %s: event no longer on the calendar", strings.Join(cancelledNames, ", "))
+			notifier.Warning("Wipe Cancelled",
+				fmt.Sprintf("The wipe event for these server(s) was removed from the calendar, so they were left untouched:\n• %s",
+					strings.Join(cancelledNames, "\n• ")))
+
+			servers, wipeServers = kept, keptWipes
+			wipeCount = len(wipeServers)
+			restartCount = len(servers) - wipeCount
+			if len(servers) == 0 {
+				logging.Infof("No servers left in batch, nothing to do")
+				return nil
+			}
+		}
 	}
 
-	// Send Discord notification: Starting
 	serverNames := make([]string, len(servers))
 	for i, s := range servers {
 		serverNames[i] = s.Name
 	}
-	discord.SendInfo(webhookURL, "Batch Event Starting",
-		fmt.Sprintf("Starting batch event for **%d** server(s):\n• %s\n\n**%d restart(s), %d wipe(s)**",
-			len(servers), strings.Join(serverNames, "\n• "), restartCount, wipeCount))
 
-	// Step 1: Stop all servers at once
+	// postEvent reports the batch to event_webhook_url alongside each notification
+	postEvent := func(event, errMsg string) {
+		postEventWebhook(EventPayload{Event: event, Servers: serverNames, Restarts: restartCount, Wipes: wipeCount, Error: errMsg})
+	}
+
+	// Hold the whole batch until a human confirms the wipe (nothing has been stopped yet)
+	if opts.WipeConfirmationMinutes > 0 && wipeCount > 0 {
+		timeout := time.Duration(opts.WipeConfirmationMinutes) * time.Minute
+		if err := AwaitWipeConfirmation(wipeServerNames(servers, wipeServers), timeout, notifier); err != nil {
+			return err
+		}
+	}
+
+	// Notify: Starting
+	started := time.Now()
+	fields := batchFields(servers, wipeServers)
+	notify.Send(notifier, notify.LevelInfo, "Batch Event Starting",
+		fmt.Sprintf("Starting batch event for **%d** server(s)", len(servers)), fields...)
+	postEvent(EventBatchStart, "")
+
+	// Process the batch in waves of opts.BatchSize so only one wave is down and syncing at a time
+	timings := make(phaseTimings)
+	waves := splitWaves(servers, opts.BatchSize)
+	for i, wave := range waves {
+		if len(waves) > 1 {
+			logging.Infof("Processing wave %d/%d: %d server(s)...", i+1, len(waves), len(wave))
+		}
+		if err := runWave(ctx, wave, wipeServers, noSyncServers, opts, notifier, postEvent, timings); err != nil {
+			if len(waves) == 1 {
+				return err
+			}
+			if remaining := len(waves) - i - 1; remaining > 0 {
+				logging.Warnf("Skipping %d later wave(s); their servers were left running", remaining)
+			}
+			return fmt.Errorf("wave %d/%d: %w", i+1, len(waves), err)
+		}
+	}
+
+	// Success notification, aggregated across waves
+	wavesNote := ""
+	if len(waves) > 1 {
+		wavesNote = fmt.Sprintf(" in %d waves", len(waves))
+	}
+	fields = append(fields, notify.Field{Name: "Duration", Value: time.Since(started).Round(time.Second).String(), Inline: true}, timings.field())
+	notify.Send(notifier, notify.LevelSuccess, "Batch Event Complete",
+		fmt.Sprintf("Successfully completed batch event for **%d** server(s)%s", len(servers), wavesNote), fields...)
+	postEvent(EventBatchComplete, "")
+
+	logging.Infof("✓ Batch event completed successfully in %s (%s)", time.Since(started).Round(time.Second), timings)
+	return nil
+}
+
+// batchPhases are the timed phases of a batch, in the order they run
+var batchPhases = []string{"Stop", "Sync", "Wipe", "Start"}
+
+// phaseTimings accumulates how long each phase of a batch took, across its waves
+type phaseTimings map[string]time.Duration
+
+// String lists each phase's duration on one line, e.g. "stop 5s, sync 1m2s, wipe 1s, start 30s"
+func (t phaseTimings) String() string {
+	parts := make([]string, len(batchPhases))
+	for i, phase := range batchPhases {
+		parts[i] = fmt.Sprintf("%s %s", strings.ToLower(phase), t[phase].Round(time.Second))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// field returns the notification field listing each phase's duration
+func (t phaseTimings) field() notify.Field {
+	lines := make([]string, len(batchPhases))
+	for i, phase := range batchPhases {
+		lines[i] = fmt.Sprintf("%s: %s", phase, t[phase].Round(time.Second))
+	}
+	return notify.Field{Name: "Phases", Value: strings.Join(lines, "\n"), Inline: true}
+}
+
+// batchFields returns the notification fields listing a batch's restarts and wipes and their counts
+func batchFields(servers []config.Server, wipeServers map[string]WipeMode) []notify.Field {
+	var restarts, wipes []string
+	for _, s := range servers {
+		if _, wipe := wipeServers[s.Path]; wipe {
+			wipes = append(wipes, s.Name)
+		} else {
+			restarts = append(restarts, s.Name)
+		}
+	}
+	return []notify.Field{
+		notify.ListField("Restarts", restarts),
+		notify.ListField("Wipes", wipes),
+		{Name: "Count", Value: fmt.Sprintf("%d restart(s), %d wipe(s)", len(restarts), len(wipes)), Inline: true},
+	}
+}
+
+// splitWaves splits servers into consecutive waves of at most size servers (0 keeps one wave)
+func splitWaves(servers []config.Server, size int) [][]config.Server {
+	if size <= 0 || size >= len(servers) {
+		return [][]config.Server{servers}
+	}
+	var waves [][]config.Server
+	for start := 0; start < len(servers); start += size {
+		end := min(start+size, len(servers))
+		waves = append(waves, servers[start:end])
+	}
+	return waves
+}
+
+// runWave stops, syncs, wipes and starts one wave of a batch, notifying on failure
+func runWave(ctx context.Context, servers []config.Server, wipeServers map[string]WipeMode, noSyncServers map[string]bool, opts BatchOptions, notifier notify.Notifier, postEvent func(event, errMsg string), timings phaseTimings) error {
 	serverPaths := make([]string, len(servers))
 	for i, s := range servers {
 		serverPaths[i] = s.Path
 	}
 
-	log.Printf("Stopping %d server(s)...", len(servers))
-	if err := stopServers(serverPaths); err != nil {
-		errMsg := fmt.Sprintf("Failed to stop servers: %v", err)
-		log.Printf("Error: %s", errMsg)
-		discord.SendError(webhookURL, "Batch Event Failed", errMsg)
+	// endPhase adds the time since phaseStart to phase and starts timing the next one
+	phaseStart := time.Now()
+	endPhase := func(phase string) {
+		timings[phase] += time.Since(phaseStart)
+		phaseStart = time.Now()
+	}
+
+	// fail reports a failed phase along with the timings so far
+	fail := func(phase, errMsg string) error {
+		endPhase(phase)
+		logging.Errorf("Error: %s", errMsg)
+		notify.Send(notifier, notify.LevelError, "Batch Event Failed", errMsg,
+			notify.Field{Name: "Failed Phase", Value: phase, Inline: true}, timings.field())
+		postEvent(EventBatchFailed, errMsg)
 		return fmt.Errorf("%s", errMsg)
 	}
 
-	// Step 2: Update Rust and Carbon for all servers (in parallel)
-	log.Printf("Updating Rust and Carbon on servers...")
-	if err := SyncServers(servers); err != nil {
-		errMsg := fmt.Sprintf("Failed to update servers: %v", err)
-		log.Printf("Error: %s", errMsg)
-		discord.SendError(webhookURL, "Batch Event Failed", errMsg)
+	// startAfterTimeout reports a timed-out phase and starts the servers anyway so they don't stay down
+	startAfterTimeout := func(phase, step string) error {
+		endPhase(phase)
+		errMsg := fmt.Sprintf("%s timed out after %s", step, opts.StepTimeout)
+		logging.Errorf("Error: %s, starting servers anyway", errMsg)
+		notify.Send(notifier, notify.LevelError, "Batch Event Timed Out",
+			fmt.Sprintf("%s\n\nStarting servers anyway so they don't stay down.", errMsg),
+			notify.Field{Name: "Failed Phase", Value: phase, Inline: true}, timings.field())
+		postEvent(EventBatchFailed, errMsg)
+
+		startCtx, cancel := stepContext(ctx, opts.StepTimeout)
+		defer cancel()
+		if err := startServers(startCtx, serverPaths); err != nil {
+			logging.Errorf("Error: Failed to start servers after timeout: %v", err)
+			notifier.Error("Batch Event Failed", fmt.Sprintf("Failed to start servers after %s: %v", step, err))
+			return fmt.Errorf("%s; starting servers failed: %w", errMsg, err)
+		}
 		return fmt.Errorf("%s", errMsg)
 	}
 
+	// Step 1: Stop all servers at once
+	logging.Infof("Stopping %d server(s)...", len(servers))
+	stopCtx, cancelStop := stepContext(ctx, opts.StepTimeout)
+	err := stopServers(stopCtx, serverPaths)
+	cancelStop()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return startAfterTimeout("Stop", "Stopping servers")
+	}
+	if err != nil {
+		return fail("Stop", fmt.Sprintf("Failed to stop servers: %v", err))
+	}
+	endPhase("Stop")
+
+	// Step 2: Update Rust and Carbon for all servers (in parallel), except restart-nosync servers
+	var serversToSync []config.Server
+	for _, server := range servers {
+		if noSyncServers[server.Path] {
+			logging.Infof("  Skipping sync for %s (restart-nosync)", server.Name)
+			continue
+		}
+		serversToSync = append(serversToSync, server)
+	}
+
+	if len(serversToSync) > 0 {
+		logging.Infof("Updating Rust and Carbon on servers...")
+		syncCtx, cancelSync := stepContext(ctx, opts.StepTimeout)
+		err := SyncServers(syncCtx, serversToSync)
+		timedOut := syncCtx.Err() == context.DeadlineExceeded
+		cancelSync()
+		if err != nil && timedOut {
+			return startAfterTimeout("Sync", "Updating servers")
+		}
+		if err != nil {
+			return fail("Sync", fmt.Sprintf("Failed to update servers: %v", err))
+		}
+	}
+	endPhase("Sync")
+
+	// Wait for any in-flight map generation so the wipe doesn't start before the new map is ready
+	for _, server := range servers {
+		if _, wipe := wipeServers[server.Path]; !wipe || !server.GenerateMap {
+			continue
+		}
+		if err := WaitForMapGeneration(server.Path, MapGenerationWaitTimeout); err != nil {
+			logging.Warnf("Warning: %s: %v, continuing with wipe", server.Name, err)
+			notifier.Warning("Map Generation Still Running",
+				fmt.Sprintf("Map generation for **%s** did not finish in time (%v)\n\nContinuing with wipe.", server.Name, err))
+		}
+	}
+
 	// Step 3: Wipe data for wipe-servers only
-	if len(wipeServers) > 0 {
-		log.Printf("Performing wipe cleanup for %d server(s)...", len(wipeServers))
+	if waveWipes := len(wipeServerNames(servers, wipeServers)); waveWipes > 0 {
+		logging.Infof("Performing wipe cleanup for %d server(s)...", waveWipes)
 		for _, server := range servers {
-			if wipeServers[server.Path] {
-				log.Printf("  Wiping data for %s", server.Name)
-				if err := wipeServerData(server); err != nil {
-					errMsg := fmt.Sprintf("Failed to wipe data for server %s: %v", server.Name, err)
-					log.Printf("Error: %s", errMsg)
-					discord.SendError(webhookURL, "Batch Event Failed", errMsg)
-					return fmt.Errorf("%s", errMsg)
+			if mode, wipe := wipeServers[server.Path]; wipe {
+				logging.Infof("  Wiping data for %s", server.Name)
+				if err := wipeServerData(server, mode, opts.KeepMaps, false); err != nil {
+					return fail("Wipe", fmt.Sprintf("Failed to wipe data for server %s: %v", server.Name, err))
 				}
 			}
 		}
 	}
+	endPhase("Wipe")
 
 	// Step 4: Run pre-start hook once with all server paths
-	if err := runPreStartHook(serverPaths); err != nil {
-		log.Printf("Warning: Pre-start hook failed: %v", err)
+	hookCtx, cancelHook := stepContext(ctx, opts.StepTimeout)
+	if err := runPreStartHook(hookCtx, serverPaths); err != nil {
+		logging.Warnf("Warning: Pre-start hook failed: %v", err)
 		// Don't fail the entire operation if hook fails
 	}
+	cancelHook()
+
+	// Step 5: Start all servers at once, or in groups of StartStaggerSize when staggered
+	// With min_free_memory_mb set, each start waits until there's room for the servers it launches.
+	// Each start-servers.sh call gets its own StepTimeout; the stagger and memory waits don't count.
+	var startErr error
+	if opts.StartStagger > 0 && len(serverPaths) > max(opts.StartStaggerSize, 1) {
+		logging.Infof("Starting %d server(s) with %ds stagger...", len(servers), opts.StartStagger)
+		var beforeStart func(n int)
+		if opts.MinFreeMemoryMB > 0 {
+			beforeStart = func(n int) { waitForFreeMemory(opts.MinFreeMemoryMB*n, n, notifier) }
+		}
+		startErr = startServersStaggered(ctx, serverPaths, time.Duration(opts.StartStagger)*time.Second, opts.StartStaggerSize, opts.StepTimeout, beforeStart)
+	} else {
+		if opts.MinFreeMemoryMB > 0 {
+			waitForFreeMemory(opts.MinFreeMemoryMB*len(serverPaths), len(serverPaths), notifier)
+		}
+		logging.Infof("Starting %d server(s)...", len(servers))
+		startCtx, cancelStart := stepContext(ctx, opts.StepTimeout)
+		startErr = startServers(startCtx, serverPaths)
+		cancelStart()
+	}
+	if err := startErr; err != nil {
+		return fail("Start", fmt.Sprintf("Failed to start servers: %v", err))
+	}
+	endPhase("Start")
 
-	// Step 5: Start all servers at once
-	log.Printf("Starting %d server(s)...", len(servers))
-	if err := startServers(serverPaths); err != nil {
-		errMsg := fmt.Sprintf("Failed to start servers: %v", err)
-		log.Printf("Error: %s", errMsg)
-		discord.SendError(webhookURL, "Batch Event Failed", errMsg)
-		return fmt.Errorf("%s", errMsg)
+	// Step 6: Run post-start hook once the servers are up
+	postHookCtx, cancelPostHook := stepContext(ctx, opts.StepTimeout)
+	if err := runPostStartHook(postHookCtx, serverPaths); err != nil {
+		logging.Warnf("Warning: Post-start hook failed: %v", err)
+		// The servers are already running, so a failed hook doesn't fail the batch
+	}
+	cancelPostHook()
+	return nil
+}
+
+// wipeServerNames returns the names of the servers in the batch that are wiped
+func wipeServerNames(servers []config.Server, wipeServers map[string]WipeMode) []string {
+	var names []string
+	for _, s := range servers {
+		if _, wipe := wipeServers[s.Path]; wipe {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// dryRunBatch logs each step ExecuteEventBatch would take without running any of them
+func dryRunBatch(servers []config.Server, wipeServers map[string]WipeMode, noSyncServers map[string]bool, keepMaps bool) error {
+	serverPaths := make([]string, len(servers))
+	for i, s := range servers {
+		serverPaths[i] = s.Path
 	}
 
-	// Success notification
-	discord.SendSuccess(webhookURL, "Batch Event Complete",
-		fmt.Sprintf("Successfully completed batch event for **%d** server(s):\n• %s\n\n**%d restart(s), %d wipe(s)**",
-			len(servers), strings.Join(serverNames, "\n• "), restartCount, wipeCount))
+	logging.Infof("[dry-run] Would stop: %s %s", StopServersScriptPath, strings.Join(serverPaths, " "))
+	for _, server := range servers {
+		if noSyncServers[server.Path] {
+			logging.Infof("[dry-run] Would skip sync for %s (restart-nosync)", server.Name)
+		} else {
+			logging.Infof("[dry-run] Would sync Rust (%s) and Carbon to %s", server.Branch, server.Path)
+		}
+	}
+	for _, server := range servers {
+		if mode, wipe := wipeServers[server.Path]; wipe {
+			if err := wipeServerData(server, mode, keepMaps, true); err != nil {
+				return err
+			}
+		}
+	}
+	logging.Infof("[dry-run] Would run pre-start hook: %s %s", HookScriptPath, strings.Join(serverPaths, " "))
+	logging.Infof("[dry-run] Would start: %s %s", StartServersScriptPath, strings.Join(serverPaths, " "))
+	logging.Infof("[dry-run] Would run post-start hook: %s %s", PostStartHookScriptPath, strings.Join(serverPaths, " "))
 
-	log.Printf("✓ Batch event completed successfully")
+	logging.Infof("✓ Dry run of batch event complete (no changes made)")
 	return nil
 }
 
-// stopServers stops servers via stop-servers.sh
-func stopServers(serverPaths []string) error {
-	// Check if script exists
-	if _, err := os.Stat(StopServersScriptPath); err != nil {
-		return fmt.Errorf("stop-servers.sh not found at %s", StopServersScriptPath)
+// stepContext returns the context for one batch step, cancelled after timeout (0 for no timeout)
+func stepContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// killWaitDelay bounds how long a killed command's output is drained before giving up on it
+const killWaitDelay = 5 * time.Second
+
+// commandContext builds a command that is killed, along with any children it started, when ctx is done
+func commandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	// Run in its own process group so a timeout also kills whatever the script is waiting on
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
 	}
+	cmd.WaitDelay = killWaitDelay
+	return cmd
+}
 
-	cmd := exec.Command(StopServersScriptPath, serverPaths...)
+// runScript runs a management script under ctx; a timeout is returned as context.DeadlineExceeded
+func runScript(ctx context.Context, what, path string, args []string) error {
+	cmd := commandContext(ctx, path, args...)
 	cmd.Stdout = log.Writer()
 	cmd.Stderr = log.Writer()
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("stop script failed: %w", err)
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s killed: %w", what, ctx.Err())
+		}
+		return fmt.Errorf("%s failed: %w", what, err)
 	}
-
 	return nil
 }
 
+// stopServers stops servers via stop-servers.sh
+func stopServers(ctx context.Context, serverPaths []string) error {
+	// Check if script exists
+	if _, err := os.Stat(StopServersScriptPath); err != nil {
+		return fmt.Errorf("stop-servers.sh not found at %s", StopServersScriptPath)
+	}
+
+	return runScript(ctx, "stop script", StopServersScriptPath, serverPaths)
+}
+
 // startServers starts servers via start-servers.sh
-func startServers(serverPaths []string) error {
+func startServers(ctx context.Context, serverPaths []string) error {
 	// Check if script exists
 	if _, err := os.Stat(StartServersScriptPath); err != nil {
 		return fmt.Errorf("start-servers.sh not found at %s", StartServersScriptPath)
 	}
 
-	cmd := exec.Command(StartServersScriptPath, serverPaths...)
-	cmd.Stdout = log.Writer()
-	cmd.Stderr = log.Writer()
+	return runScript(ctx, "start script", StartServersScriptPath, serverPaths)
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("start script failed: %w", err)
+// startServersStaggered starts servers size at a time (one when size < 1) via start-servers.sh, in order,
+// waiting stagger between each group. beforeStart, if set, runs before each group with its size.
+// Each group's start is bounded by stepTimeout on its own, so the waits between groups never
+// eat into it; cancelling ctx stops the remaining starts.
+func startServersStaggered(ctx context.Context, serverPaths []string, stagger time.Duration, size int, stepTimeout time.Duration, beforeStart func(n int)) error {
+	if size < 1 {
+		size = 1
+	}
+	for i := 0; i < len(serverPaths); i += size {
+		group := serverPaths[i:min(i+size, len(serverPaths))]
+		if i > 0 {
+			logging.Infof("Waiting %s before starting next server(s)...", stagger)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%s: %w", strings.Join(serverPaths[i:], ", "), ctx.Err())
+			case <-time.After(stagger):
+			}
+		}
+		if beforeStart != nil {
+			beforeStart(len(group))
+		}
+		startCtx, cancel := stepContext(ctx, stepTimeout)
+		err := startServers(startCtx, group)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s: %w", strings.Join(group, ", "), err)
+		}
 	}
-
 	return nil
 }
 
-// SyncServers updates Rust and Carbon installations on multiple servers in parallel
-func SyncServers(servers []config.Server) error {
+// DefaultMaxConcurrentSyncs bounds how many servers SyncServers updates at once by default
+const DefaultMaxConcurrentSyncs = 4
+
+// maxConcurrentSyncs bounds how many servers SyncServers updates at once
+var maxConcurrentSyncs atomic.Int64
+
+func init() {
+	maxConcurrentSyncs.Store(DefaultMaxConcurrentSyncs)
+}
+
+// SetMaxConcurrentSyncs sets how many servers SyncServers updates at once
+func SetMaxConcurrentSyncs(limit int) {
+	maxConcurrentSyncs.Store(int64(limit))
+}
+
+// MaxConcurrentSyncs returns how many servers SyncServers updates at once
+func MaxConcurrentSyncs() int {
+	return int(maxConcurrentSyncs.Load())
+}
+
+var (
+	// syncServerFunc syncs a single server; a variable so tests can stub it
+	syncServerFunc = syncServer
+
+	// sleepFunc waits out a batch's event delay; a variable so tests can stub it
+	sleepFunc = time.Sleep
+
+	// nowFunc reads the clock while waiting out an event delay; a variable so tests can stub it
+	nowFunc = time.Now
+)
+
+// SyncServers updates Rust and Carbon installations on multiple servers in parallel.
+// Cancelling ctx kills any rsync still running.
+func SyncServers(ctx context.Context, servers []config.Server) error {
 	type result struct {
 		server config.Server
 		err    error
 	}
 
+	limit := MaxConcurrentSyncs()
+	if limit < 1 {
+		limit = 1
+	}
+
 	results := make(chan result, len(servers))
+	sem := make(chan struct{}, limit)
 	var wg sync.WaitGroup
 
-	// Launch parallel sync operations
+	// Launch parallel sync operations, at most limit at a time
 	for _, server := range servers {
 		wg.Add(1)
 		go func(s config.Server) {
 			defer wg.Done()
-			err := syncServer(s)
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			err := syncServerFunc(ctx, s)
 			results <- result{server: s, err: err}
 		}(server)
 	}
@@ -424,12 +724,17 @@ func SyncServers(servers []config.Server) error {
 	return nil
 }
 
-// syncServer updates Rust and Carbon installations on the server
-func syncServer(server config.Server) error {
-	log.Printf("Updating server: %s", server.Name)
+// syncServer updates the Rust and mod framework (Carbon or Oxide) installations on the server
+func syncServer(ctx context.Context, server config.Server) error {
+	logging.Infof("Updating server: %s", server.Name)
+
+	fw, err := framework.ForServer(server)
+	if err != nil {
+		return err
+	}
 
 	// Acquire READ locks for this branch to prevent reading during install/update
-	// These will block if InstallRustBranch/InstallCarbon are currently running
+	// These will block if InstallRustBranch or the framework's install are currently running
 	branch := server.Branch
 	if branch == "" {
 		branch = "main"
@@ -438,15 +743,24 @@ func syncServer(server config.Server) error {
 	rustUnlock := steamcmd.AcquireReadLock(branch)
 	defer rustUnlock()
 
-	carbonUnlock := carbon.AcquireReadLock(branch)
-	defer carbonUnlock()
+	frameworkUnlock := fw.AcquireReadLock(branch)
+	defer frameworkUnlock()
 
 	// Determine source paths based on branch
-	rustSource := filepath.Join("/opt/rust", branch)
-	carbonSource := filepath.Join("/opt/carbon", branch)
+	rustSource := filepath.Join(steamcmd.RustInstallBase, branch)
+	frameworkSource := fw.SourcePath(branch)
+
+	// Check both sources before touching the server, so a branch that was never
+	// installed fails with a clear error instead of a cryptic rsync one
+	if !steamcmd.IsBranchInstalled(branch) {
+		return fmt.Errorf("branch '%s' not installed at %s (RustDedicated not found)", branch, rustSource)
+	}
+	if !fw.IsBranchInstalled(branch) {
+		return fmt.Errorf("%s for branch '%s' not installed at %s", strings.ToLower(fw.Name()), branch, frameworkSource)
+	}
 
 	// Update Rust
-	log.Printf("  Updating Rust from %s to %s", rustSource, server.Path)
+	logging.Infof("  Updating Rust from %s to %s", rustSource, server.Path)
 
 	// Remove old Rust files first
 	rustCleanupDirs := []string{
@@ -457,98 +771,128 @@ func syncServer(server config.Server) error {
 	}
 	for _, dir := range rustCleanupDirs {
 		if err := os.RemoveAll(dir); err != nil {
-			log.Printf("  Warning: Failed to remove %s: %v", dir, err)
+			logging.Warnf("  Warning: Failed to remove %s: %v", dir, err)
 		}
 	}
 
 	// Rsync Rust (safe mode: uses temp files for atomic updates)
-	rsyncCmd := exec.Command("rsync", "-a", fmt.Sprintf("%s/", rustSource), fmt.Sprintf("%s/", server.Path))
+	rsyncCmd := commandContext(ctx, "rsync", "-a", fmt.Sprintf("%s/", rustSource), fmt.Sprintf("%s/", server.Path))
 	output, err := rsyncCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("rust rsync failed: %w\nOutput: %s", err, output)
 	}
 
-	// Update Carbon
-	log.Printf("  Updating Carbon from %s to %s", carbonSource, server.Path)
+	// Update the mod framework
+	logging.Infof("  Updating %s from %s to %s", fw.Name(), frameworkSource, server.Path)
 
-	// Remove old Carbon files first
-	carbonCleanupDirs := []string{
-		filepath.Join(server.Path, "carbon", "native"),
-		filepath.Join(server.Path, "carbon", "managed"),
-		filepath.Join(server.Path, "carbon", "tools"),
-	}
-	for _, dir := range carbonCleanupDirs {
+	// Remove old framework files first
+	for _, dir := range fw.CleanupDirs(server.Path) {
 		if err := os.RemoveAll(dir); err != nil {
-			log.Printf("  Warning: Failed to remove %s: %v", dir, err)
+			logging.Warnf("  Warning: Failed to remove %s: %v", dir, err)
 		}
 	}
 
-	// Rsync Carbon (safe mode: uses temp files for atomic updates)
-	rsyncCmd = exec.Command("rsync", "-a", fmt.Sprintf("%s/", carbonSource), fmt.Sprintf("%s/", server.Path))
+	// Rsync the framework (safe mode: uses temp files for atomic updates)
+	rsyncCmd = commandContext(ctx, "rsync", "-a", fmt.Sprintf("%s/", frameworkSource), fmt.Sprintf("%s/", server.Path))
 	output, err = rsyncCmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("carbon rsync failed: %w\nOutput: %s", err, output)
+		return fmt.Errorf("%s rsync failed: %w\nOutput: %s", strings.ToLower(fw.Name()), err, output)
 	}
 
-	log.Printf("  ✓ Updated %s", server.Name)
+	logging.Infof("  ✓ Updated %s", server.Name)
 	return nil
 }
 
-// wipeServerData deletes map/save files for a wipe event
-func wipeServerData(server config.Server) error {
-	log.Printf("Wiping data for server: %s", server.Name)
+// wipeServerData deletes map/save files for a wipe event, or moves them into a
+// .wipe-backup directory when SafeWipe is enabled (in dry-run mode it only logs them)
+func wipeServerData(server config.Server, mode WipeMode, keepMaps bool, dryRun bool) error {
+	if dryRun {
+		logging.Infof("[dry-run] Would wipe data for server: %s", server.Name)
+	} else {
+		logging.Infof("Wiping data for server: %s", server.Name)
+	}
 
-	// Extract server identity from path (last component)
-	identity := filepath.Base(server.Path)
-	serverDataPath := filepath.Join(server.Path, "server", identity)
+	// Server identity defaults to the last path component unless overridden
+	dataPath := serverDataPath(server)
 
-	log.Printf("  Server data path: %s", serverDataPath)
+	logging.Debugf("  Server data path: %s", dataPath)
 
-	// Patterns to delete
-	patterns := []string{
-		"*.map",
-		"*.sav*",
-		"player.states.*.db*",
-		"sv.files.*.db*",
+	// Patterns to delete (the map itself survives when keepMaps is set)
+	patterns := []string{"*.sav*"}
+	if keepMaps {
+		logging.Infof("  Keeping map files")
+	} else {
+		patterns = append(patterns, "*.map")
+	}
+	if mode != WipeMapOnly {
+		patterns = append(patterns, "player.states.*.db*", "sv.files.*.db*")
 	}
 
-	// Conditionally add blueprints
-	if server.WipeBlueprints {
-		log.Printf("  Including blueprints in wipe")
+	// Conditionally add blueprints (always for a blueprint wipe, never for a map-only wipe)
+	if mode == WipeWithBlueprints || (mode == WipeStandard && server.WipeBlueprints) {
+		logging.Infof("  Including blueprints in wipe")
 		patterns = append(patterns, "player.blueprints.*")
 	}
 
+	// With SafeWipe, matched files are moved into a fresh backup instead of deleted
+	backupPath := ""
+	if SafeWipe() && !dryRun {
+		var err error
+		if backupPath, err = newWipeBackup(dataPath); err != nil {
+			return err
+		}
+		logging.Infof("  Backing up wiped files to: %s", backupPath)
+	}
+
 	// Delete matching files
 	for _, pattern := range patterns {
-		matches, err := filepath.Glob(filepath.Join(serverDataPath, pattern))
+		matches, err := filepath.Glob(filepath.Join(dataPath, pattern))
 		if err != nil {
-			log.Printf("  Warning: Failed to glob pattern %s: %v", pattern, err)
+			logging.Warnf("  Warning: Failed to glob pattern %s: %v", pattern, err)
 			continue
 		}
 
 		for _, match := range matches {
-			log.Printf("  Deleting: %s", match)
+			if dryRun {
+				logging.Infof("  [dry-run] Would delete: %s", match)
+				continue
+			}
+			if backupPath != "" {
+				logging.Infof("  Backing up: %s", match)
+				if err := os.Rename(match, filepath.Join(backupPath, filepath.Base(match))); err != nil {
+					logging.Warnf("  Warning: Failed to back up %s: %v", match, err)
+				}
+				continue
+			}
+			logging.Infof("  Deleting: %s", match)
 			if err := os.Remove(match); err != nil {
-				log.Printf("  Warning: Failed to delete %s: %v", match, err)
+				logging.Warnf("  Warning: Failed to delete %s: %v", match, err)
 			}
 		}
 	}
 
-	log.Printf("  ✓ Wiped data for %s", server.Name)
+	if backupPath != "" {
+		if err := pruneWipeBackups(dataPath, WipeBackupRetention()); err != nil {
+			logging.Warnf("  Warning: %v", err)
+		}
+	}
+
+	if !dryRun {
+		logging.Infof("  ✓ Wiped data for %s", server.Name)
+	}
 	return nil
 }
 
 // runPreStartHook executes the pre-start hook script with server paths as arguments
-func runPreStartHook(serverPaths []string) error {
-	log.Printf("Running pre-start hook: %s", HookScriptPath)
+func runPreStartHook(ctx context.Context, serverPaths []string) error {
+	logging.Infof("Running pre-start hook: %s", HookScriptPath)
 
-	cmd := exec.Command(HookScriptPath, serverPaths...)
-	cmd.Stdout = log.Writer()
-	cmd.Stderr = log.Writer()
+	return runScript(ctx, "hook script", HookScriptPath, serverPaths)
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("hook script failed: %w", err)
-	}
+// runPostStartHook executes the post-start hook script with server paths as arguments
+func runPostStartHook(ctx context.Context, serverPaths []string) error {
+	logging.Infof("Running post-start hook: %s", PostStartHookScriptPath)
 
-	return nil
+	return runScript(ctx, "post-start hook script", PostStartHookScriptPath, serverPaths)
 }
@@ -1,13 +1,26 @@
 package executor
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/discord"
+	"github.com/maintc/wipe-cli/internal/notify"
+	"github.com/maintc/wipe-cli/internal/steamcmd"
 )
 
 func TestExecuteEventBatch_Ordering(t *testing.T) {
@@ -74,11 +87,11 @@ exit 0
 		{Name: "server-b", Path: "/test/server-b", Branch: "main"},
 	}
 
-	wipeServers := make(map[string]bool)
+	wipeServers := make(map[string]WipeMode)
 
 	// Execute (will fail on sync step since we don't have actual servers, but we can check order)
 	// Note: This will fail at sync step, but we can verify stop was called first
-	_ = ExecuteEventBatch(servers, wipeServers, "", 0)
+	_ = ExecuteEventBatch(context.Background(), servers, wipeServers, nil, BatchOptions{})
 
 	// Read log file
 	logData, err := os.ReadFile(logFile)
@@ -164,7 +177,7 @@ func TestWipeServerData_FilePatterns(t *testing.T) {
 	}
 
 	// Execute wipe
-	if err := wipeServerData(server); err != nil {
+	if err := wipeServerData(server, WipeStandard, false, false); err != nil {
 		t.Fatalf("wipeServerData failed: %v", err)
 	}
 
@@ -209,7 +222,7 @@ func TestWipeServerData_Blueprints(t *testing.T) {
 		WipeBlueprints: false,
 	}
 
-	if err := wipeServerData(server); err != nil {
+	if err := wipeServerData(server, WipeStandard, false, false); err != nil {
 		t.Fatalf("wipeServerData failed: %v", err)
 	}
 
@@ -226,7 +239,7 @@ func TestWipeServerData_Blueprints(t *testing.T) {
 	// Test with wipe_blueprints=true
 	server.WipeBlueprints = true
 
-	if err := wipeServerData(server); err != nil {
+	if err := wipeServerData(server, WipeStandard, false, false); err != nil {
 		t.Fatalf("wipeServerData failed: %v", err)
 	}
 
@@ -236,6 +249,204 @@ func TestWipeServerData_Blueprints(t *testing.T) {
 	}
 }
 
+func TestWipeServerData_Modes(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          WipeMode
+		keepMaps      bool
+		wantDeleted   []string
+		wantPreserved []string
+	}{
+		{
+			name:          "map-only keeps player data and blueprints",
+			mode:          WipeMapOnly,
+			wantDeleted:   []string{"proceduralmap.map", "proceduralmap.sav"},
+			wantPreserved: []string{"player.states.5.db", "player.blueprints.5.db"},
+		},
+		{
+			name:          "blueprints mode overrides server setting",
+			mode:          WipeWithBlueprints,
+			wantDeleted:   []string{"proceduralmap.map", "proceduralmap.sav", "player.states.5.db", "player.blueprints.5.db"},
+			wantPreserved: []string{},
+		},
+		{
+			name:          "keep maps deletes saves and player data but not the map",
+			mode:          WipeStandard,
+			keepMaps:      true,
+			wantDeleted:   []string{"proceduralmap.sav", "player.states.5.db"},
+			wantPreserved: []string{"proceduralmap.map", "player.blueprints.5.db"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// wipeServerData uses filepath.Base(server.Path) as the identity
+			serverPath := filepath.Join(t.TempDir(), "test-server")
+			identityPath := filepath.Join(serverPath, "server", "test-server")
+			if err := os.MkdirAll(identityPath, 0755); err != nil {
+				t.Fatalf("Failed to create identity path: %v", err)
+			}
+
+			for _, file := range append(append([]string{}, tt.wantDeleted...), tt.wantPreserved...) {
+				if err := os.WriteFile(filepath.Join(identityPath, file), []byte("test"), 0644); err != nil {
+					t.Fatalf("Failed to create %s: %v", file, err)
+				}
+			}
+
+			server := config.Server{
+				Name:           "test-server",
+				Path:           serverPath,
+				Branch:         "main",
+				WipeBlueprints: false,
+			}
+
+			if err := wipeServerData(server, tt.mode, tt.keepMaps, false); err != nil {
+				t.Fatalf("wipeServerData failed: %v", err)
+			}
+
+			for _, file := range tt.wantDeleted {
+				if _, err := os.Stat(filepath.Join(identityPath, file)); !os.IsNotExist(err) {
+					t.Errorf("File %s should have been deleted", file)
+				}
+			}
+			for _, file := range tt.wantPreserved {
+				if _, err := os.Stat(filepath.Join(identityPath, file)); os.IsNotExist(err) {
+					t.Errorf("File %s should have been preserved", file)
+				}
+			}
+		})
+	}
+}
+
+func TestWipeServerData_SafeWipeAndRestore(t *testing.T) {
+	origSafe := SafeWipe()
+	SetSafeWipe(true)
+	defer SetSafeWipe(origSafe)
+
+	serverPath := filepath.Join(t.TempDir(), "test-server")
+	identityPath := filepath.Join(serverPath, "server", "test-server")
+	if err := os.MkdirAll(identityPath, 0755); err != nil {
+		t.Fatalf("Failed to create identity path: %v", err)
+	}
+	wiped := []string{"proceduralmap.map", "proceduralmap.sav"}
+	for _, file := range wiped {
+		if err := os.WriteFile(filepath.Join(identityPath, file), []byte("old "+file), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+	}
+
+	server := config.Server{Name: "test-server", Path: serverPath, Branch: "main"}
+	if err := wipeServerData(server, WipeMapOnly, false, false); err != nil {
+		t.Fatalf("wipeServerData failed: %v", err)
+	}
+
+	backups, err := listWipeBackups(identityPath)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("listWipeBackups() = %v, %v, want one backup", backups, err)
+	}
+	for _, file := range wiped {
+		if _, err := os.Stat(filepath.Join(identityPath, file)); !os.IsNotExist(err) {
+			t.Errorf("File %s should have been moved out of the identity folder", file)
+		}
+		if _, err := os.Stat(filepath.Join(backups[0], file)); err != nil {
+			t.Errorf("File %s should be in the backup: %v", file, err)
+		}
+	}
+
+	// The server generates a new map after the wipe; restore overwrites it
+	if err := os.WriteFile(filepath.Join(identityPath, "proceduralmap.map"), []byte("new map"), 0644); err != nil {
+		t.Fatalf("Failed to write new map: %v", err)
+	}
+
+	backup, restored, err := RestoreWipeBackup(server)
+	if err != nil {
+		t.Fatalf("RestoreWipeBackup() error = %v", err)
+	}
+	if backup != backups[0] || len(restored) != len(wiped) {
+		t.Errorf("RestoreWipeBackup() = %s, %v, want %s and %d files", backup, restored, backups[0], len(wiped))
+	}
+	for _, file := range wiped {
+		data, err := os.ReadFile(filepath.Join(identityPath, file))
+		if err != nil || string(data) != "old "+file {
+			t.Errorf("%s = %q, %v, want the backed up contents", file, data, err)
+		}
+	}
+	if _, err := os.Stat(backups[0]); !os.IsNotExist(err) {
+		t.Error("Restored backup directory should have been removed")
+	}
+
+	if _, _, err := RestoreWipeBackup(server); err == nil {
+		t.Error("RestoreWipeBackup() should fail when no backups remain")
+	}
+}
+
+func TestPruneWipeBackups(t *testing.T) {
+	dataPath := t.TempDir()
+	names := []string{"20250101-120000", "20250108-120000", "20250115-120000", "20250122-120000"}
+	for _, name := range names {
+		if err := os.MkdirAll(filepath.Join(dataPath, WipeBackupDir, name), 0755); err != nil {
+			t.Fatalf("Failed to create backup %s: %v", name, err)
+		}
+	}
+
+	if err := pruneWipeBackups(dataPath, 2); err != nil {
+		t.Fatalf("pruneWipeBackups() error = %v", err)
+	}
+
+	backups, err := listWipeBackups(dataPath)
+	if err != nil {
+		t.Fatalf("listWipeBackups() error = %v", err)
+	}
+	var got []string
+	for _, backup := range backups {
+		got = append(got, filepath.Base(backup))
+	}
+	want := []string{"20250115-120000", "20250122-120000"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("backups after prune = %v, want %v", got, want)
+	}
+}
+
+func TestListWipeBackups_SameSecondSuffixes(t *testing.T) {
+	dataPath := t.TempDir()
+	// Created in this order; lexically -10 and -11 would sort before -2
+	var names []string
+	names = append(names, "20250101-120000")
+	for i := 1; i <= 11; i++ {
+		names = append(names, fmt.Sprintf("20250101-120000-%d", i))
+	}
+	names = append(names, "20250101-120001")
+	for _, name := range names {
+		if err := os.MkdirAll(filepath.Join(dataPath, WipeBackupDir, name), 0755); err != nil {
+			t.Fatalf("Failed to create backup %s: %v", name, err)
+		}
+	}
+
+	backups, err := listWipeBackups(dataPath)
+	if err != nil {
+		t.Fatalf("listWipeBackups() error = %v", err)
+	}
+	var got []string
+	for _, backup := range backups {
+		got = append(got, filepath.Base(backup))
+	}
+	if strings.Join(got, ",") != strings.Join(names, ",") {
+		t.Errorf("listWipeBackups() = %v, want %v", got, names)
+	}
+
+	// Pruning keeps the newest backups
+	if err := pruneWipeBackups(dataPath, 2); err != nil {
+		t.Fatalf("pruneWipeBackups() error = %v", err)
+	}
+	backups, err = listWipeBackups(dataPath)
+	if err != nil {
+		t.Fatalf("listWipeBackups() error = %v", err)
+	}
+	if len(backups) != 2 || filepath.Base(backups[0]) != "20250101-120000-11" || filepath.Base(backups[1]) != "20250101-120001" {
+		t.Errorf("backups after prune = %v, want -11 and the next second", backups)
+	}
+}
+
 func TestSyncServers_Parallel(t *testing.T) {
 	// Test that SyncServers processes servers in parallel
 	// We can't test actual rsync, but we can verify the function signature and error handling
@@ -247,7 +458,7 @@ func TestSyncServers_Parallel(t *testing.T) {
 	}
 
 	// This will fail (no actual servers), but should fail for all 3
-	err := SyncServers(servers)
+	err := SyncServers(context.Background(), servers)
 
 	if err == nil {
 		t.Error("Expected error when syncing nonexistent servers")
@@ -266,18 +477,105 @@ func TestSyncServers_Parallel(t *testing.T) {
 	}
 }
 
+func TestSyncServers_ConcurrencyLimit(t *testing.T) {
+	origLimit, origSync := MaxConcurrentSyncs(), syncServerFunc
+	defer func() {
+		SetMaxConcurrentSyncs(origLimit)
+		syncServerFunc = origSync
+	}()
+
+	var running, peak int32
+	SetMaxConcurrentSyncs(2)
+	syncServerFunc = func(ctx context.Context, server config.Server) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if server.Name == "s4" {
+			return errors.New("rsync failed")
+		}
+		return nil
+	}
+
+	var servers []config.Server
+	for i := 1; i <= 6; i++ {
+		servers = append(servers, config.Server{Name: fmt.Sprintf("s%d", i)})
+	}
+
+	err := SyncServers(context.Background(), servers)
+	if err == nil || !strings.Contains(err.Error(), "s4: rsync failed") {
+		t.Errorf("SyncServers() error = %v, want it to name s4", err)
+	}
+	if got := atomic.LoadInt32(&peak); got != 2 {
+		t.Errorf("peak concurrent syncs = %d, want 2", got)
+	}
+}
+
+func TestSyncServer_BranchNotInstalled(t *testing.T) {
+	serverPath := t.TempDir()
+	dataDir := filepath.Join(serverPath, "RustDedicated_Data")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := syncServer(context.Background(), config.Server{Name: "s1", Path: serverPath, Branch: "no-such-branch"})
+
+	want := fmt.Sprintf("branch 'no-such-branch' not installed at %s", filepath.Join(steamcmd.RustInstallBase, "no-such-branch"))
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("syncServer() error = %v, want it to contain %q", err, want)
+	}
+	// The check runs before cleanup, so the server's install is left alone
+	if _, err := os.Stat(dataDir); err != nil {
+		t.Errorf("RustDedicated_Data was removed before the branch check: %v", err)
+	}
+}
+
+func TestSyncServer_WaitsForInstallWriteLock(t *testing.T) {
+	// Simulate an install holding the branch's write lock
+	unlock := steamcmd.AcquireWriteLock("lock-test")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- syncServer(context.Background(), config.Server{Name: "s1", Path: t.TempDir(), Branch: "lock-test"})
+	}()
+
+	select {
+	case err := <-done:
+		unlock()
+		t.Fatalf("syncServer() returned %v while the install held the write lock", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("syncServer() did not proceed after the write lock was released")
+	}
+}
+
 func TestScriptPaths(t *testing.T) {
 	// Verify script paths are correct
 	expectedPaths := map[string]string{
-		"HookScript":         "/opt/wiped/pre-start-hook.sh",
-		"StopServersScript":  "/opt/wiped/stop-servers.sh",
-		"StartServersScript": "/opt/wiped/start-servers.sh",
-		"GenerateMapsScript": "/opt/wiped/generate-maps.sh",
+		"HookScript":          "/opt/wiped/pre-start-hook.sh",
+		"PostStartHookScript": "/opt/wiped/post-start-hook.sh",
+		"StopServersScript":   "/opt/wiped/stop-servers.sh",
+		"StartServersScript":  "/opt/wiped/start-servers.sh",
+		"GenerateMapsScript":  "/opt/wiped/generate-maps.sh",
 	}
 
 	if HookScriptPath != expectedPaths["HookScript"] {
 		t.Errorf("HookScriptPath = %s, want %s", HookScriptPath, expectedPaths["HookScript"])
 	}
+	if PostStartHookScriptPath != expectedPaths["PostStartHookScript"] {
+		t.Errorf("PostStartHookScriptPath = %s, want %s", PostStartHookScriptPath, expectedPaths["PostStartHookScript"])
+	}
 	if StopServersScriptPath != expectedPaths["StopServersScript"] {
 		t.Errorf("StopServersScriptPath = %s, want %s", StopServersScriptPath, expectedPaths["StopServersScript"])
 	}
@@ -352,3 +650,1352 @@ func TestEnsureHookScript_Creation(t *testing.T) {
 		t.Error("Script should not be overwritten if it already exists")
 	}
 }
+
+func TestExecuteEventBatch_NoSyncSkipsSync(t *testing.T) {
+	// Servers flagged as restart-nosync should be stopped and started without syncing
+	tmpDir := t.TempDir()
+
+	origStopPath := StopServersScriptPath
+	origStartPath := StartServersScriptPath
+	origHookPath := HookScriptPath
+
+	defer func() {
+		StopServersScriptPath = origStopPath
+		StartServersScriptPath = origStartPath
+		HookScriptPath = origHookPath
+	}()
+
+	logFile := filepath.Join(tmpDir, "execution.log")
+
+	for name, label := range map[string]string{"stop.sh": "STOP", "start.sh": "START", "hook.sh": "HOOK"} {
+		content := fmt.Sprintf("#!/bin/bash\necho \"%s: $@\" >> %s\nexit 0\n", label, logFile)
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	StopServersScriptPath = filepath.Join(tmpDir, "stop.sh")
+	StartServersScriptPath = filepath.Join(tmpDir, "start.sh")
+	HookScriptPath = filepath.Join(tmpDir, "hook.sh")
+
+	// Nonexistent paths would fail rsync, so success proves the sync was skipped
+	servers := []config.Server{
+		{Name: "server-a", Path: "/nonexistent/server-a", Branch: "main"},
+	}
+	noSyncServers := map[string]bool{"/nonexistent/server-a": true}
+
+	if err := ExecuteEventBatch(context.Background(), servers, map[string]WipeMode{}, noSyncServers, BatchOptions{}); err != nil {
+		t.Fatalf("ExecuteEventBatch failed: %v", err)
+	}
+
+	logData, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	logLines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	if len(logLines) != 3 {
+		t.Fatalf("Expected STOP, HOOK and START to be logged, got: %v", logLines)
+	}
+	if !strings.HasPrefix(logLines[2], "START:") {
+		t.Errorf("Expected last action to be START, got: %s", logLines[2])
+	}
+}
+
+func TestExecuteEventBatch_PostStartHookRunsAfterStart(t *testing.T) {
+	// The post-start hook runs last, with the batch's server paths, once the servers have started
+	tmpDir := t.TempDir()
+
+	origStopPath := StopServersScriptPath
+	origStartPath := StartServersScriptPath
+	origHookPath := HookScriptPath
+	origPostHookPath := PostStartHookScriptPath
+
+	defer func() {
+		StopServersScriptPath = origStopPath
+		StartServersScriptPath = origStartPath
+		HookScriptPath = origHookPath
+		PostStartHookScriptPath = origPostHookPath
+	}()
+
+	logFile := filepath.Join(tmpDir, "execution.log")
+
+	for name, label := range map[string]string{"stop.sh": "STOP", "start.sh": "START", "hook.sh": "HOOK", "post-hook.sh": "POSTHOOK"} {
+		content := fmt.Sprintf("#!/bin/bash\necho \"%s: $@\" >> %s\nexit 0\n", label, logFile)
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	StopServersScriptPath = filepath.Join(tmpDir, "stop.sh")
+	StartServersScriptPath = filepath.Join(tmpDir, "start.sh")
+	HookScriptPath = filepath.Join(tmpDir, "hook.sh")
+	PostStartHookScriptPath = filepath.Join(tmpDir, "post-hook.sh")
+
+	servers := []config.Server{
+		{Name: "server-a", Path: "/nonexistent/server-a", Branch: "main"},
+		{Name: "server-b", Path: "/nonexistent/server-b", Branch: "main"},
+	}
+	noSyncServers := map[string]bool{"/nonexistent/server-a": true, "/nonexistent/server-b": true}
+
+	if err := ExecuteEventBatch(context.Background(), servers, map[string]WipeMode{}, noSyncServers, BatchOptions{}); err != nil {
+		t.Fatalf("ExecuteEventBatch failed: %v", err)
+	}
+
+	logData, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	want := []string{
+		"STOP: /nonexistent/server-a /nonexistent/server-b",
+		"HOOK: /nonexistent/server-a /nonexistent/server-b",
+		"START: /nonexistent/server-a /nonexistent/server-b",
+		"POSTHOOK: /nonexistent/server-a /nonexistent/server-b",
+	}
+	if got := strings.Split(strings.TrimSpace(string(logData)), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("script calls = %v, want %v", got, want)
+	}
+}
+
+func TestExecuteEventBatch_SleepsOnceBeforeStopping(t *testing.T) {
+	// The event delay is slept once for the whole batch, before any server is stopped
+	tmpDir := t.TempDir()
+
+	origStopPath := StopServersScriptPath
+	origStartPath := StartServersScriptPath
+	origHookPath := HookScriptPath
+	origSleep := sleepFunc
+
+	defer func() {
+		StopServersScriptPath = origStopPath
+		StartServersScriptPath = origStartPath
+		HookScriptPath = origHookPath
+		sleepFunc = origSleep
+	}()
+
+	logFile := filepath.Join(tmpDir, "execution.log")
+
+	for name, label := range map[string]string{"stop.sh": "STOP", "start.sh": "START", "hook.sh": "HOOK"} {
+		content := fmt.Sprintf("#!/bin/bash\necho \"%s: $@\" >> %s\nexit 0\n", label, logFile)
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	StopServersScriptPath = filepath.Join(tmpDir, "stop.sh")
+	StartServersScriptPath = filepath.Join(tmpDir, "start.sh")
+	HookScriptPath = filepath.Join(tmpDir, "hook.sh")
+
+	var slept []time.Duration
+	sleepFunc = func(d time.Duration) {
+		slept = append(slept, d)
+		f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatalf("Failed to open log file: %v", err)
+		}
+		fmt.Fprintf(f, "SLEEP: %s\n", d)
+		f.Close()
+	}
+
+	servers := []config.Server{
+		{Name: "server-a", Path: "/nonexistent/server-a", Branch: "main"},
+		{Name: "server-b", Path: "/nonexistent/server-b", Branch: "main"},
+	}
+	noSyncServers := map[string]bool{"/nonexistent/server-a": true, "/nonexistent/server-b": true}
+
+	if err := ExecuteEventBatch(context.Background(), servers, map[string]WipeMode{}, noSyncServers, BatchOptions{EventDelay: 30}); err != nil {
+		t.Fatalf("ExecuteEventBatch failed: %v", err)
+	}
+
+	if len(slept) != 1 || slept[0] != 30*time.Second {
+		t.Errorf("slept %v, want a single 30s sleep", slept)
+	}
+
+	logData, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	logLines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	if len(logLines) < 2 || !strings.HasPrefix(logLines[0], "SLEEP:") || !strings.HasPrefix(logLines[1], "STOP:") {
+		t.Errorf("Expected SLEEP before STOP, got: %v", logLines)
+	}
+}
+
+func TestExecuteEventBatch_BatchSizeRunsWaves(t *testing.T) {
+	// batch_size splits the batch into waves that each stop, run the hook and start their own servers
+	tests := []struct {
+		name      string
+		batchSize int
+		want      []string
+	}{
+		{"disabled", 0, []string{
+			"STOP: /nonexistent/s1 /nonexistent/s2 /nonexistent/s3 /nonexistent/s4 /nonexistent/s5",
+			"HOOK: /nonexistent/s1 /nonexistent/s2 /nonexistent/s3 /nonexistent/s4 /nonexistent/s5",
+			"START: /nonexistent/s1 /nonexistent/s2 /nonexistent/s3 /nonexistent/s4 /nonexistent/s5",
+		}},
+		{"two per wave", 2, []string{
+			"STOP: /nonexistent/s1 /nonexistent/s2",
+			"HOOK: /nonexistent/s1 /nonexistent/s2",
+			"START: /nonexistent/s1 /nonexistent/s2",
+			"STOP: /nonexistent/s3 /nonexistent/s4",
+			"HOOK: /nonexistent/s3 /nonexistent/s4",
+			"START: /nonexistent/s3 /nonexistent/s4",
+			"STOP: /nonexistent/s5",
+			"HOOK: /nonexistent/s5",
+			"START: /nonexistent/s5",
+		}},
+		{"larger than batch", 10, []string{
+			"STOP: /nonexistent/s1 /nonexistent/s2 /nonexistent/s3 /nonexistent/s4 /nonexistent/s5",
+			"HOOK: /nonexistent/s1 /nonexistent/s2 /nonexistent/s3 /nonexistent/s4 /nonexistent/s5",
+			"START: /nonexistent/s1 /nonexistent/s2 /nonexistent/s3 /nonexistent/s4 /nonexistent/s5",
+		}},
+	}
+
+	origStopPath := StopServersScriptPath
+	origStartPath := StartServersScriptPath
+	origHookPath := HookScriptPath
+
+	defer func() {
+		StopServersScriptPath = origStopPath
+		StartServersScriptPath = origStartPath
+		HookScriptPath = origHookPath
+	}()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			logFile := filepath.Join(tmpDir, "execution.log")
+
+			for name, label := range map[string]string{"stop.sh": "STOP", "start.sh": "START", "hook.sh": "HOOK"} {
+				content := fmt.Sprintf("#!/bin/bash\necho \"%s: $@\" >> %s\nexit 0\n", label, logFile)
+				if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0755); err != nil {
+					t.Fatalf("Failed to create %s: %v", name, err)
+				}
+			}
+
+			StopServersScriptPath = filepath.Join(tmpDir, "stop.sh")
+			StartServersScriptPath = filepath.Join(tmpDir, "start.sh")
+			HookScriptPath = filepath.Join(tmpDir, "hook.sh")
+
+			var servers []config.Server
+			noSyncServers := make(map[string]bool)
+			for i := 1; i <= 5; i++ {
+				path := fmt.Sprintf("/nonexistent/s%d", i)
+				servers = append(servers, config.Server{Name: fmt.Sprintf("s%d", i), Path: path, Branch: "main"})
+				noSyncServers[path] = true
+			}
+
+			opts := BatchOptions{BatchSize: tt.batchSize}
+			if err := ExecuteEventBatch(context.Background(), servers, map[string]WipeMode{}, noSyncServers, opts); err != nil {
+				t.Fatalf("ExecuteEventBatch failed: %v", err)
+			}
+
+			logData, err := os.ReadFile(logFile)
+			if err != nil {
+				t.Fatalf("Failed to read log file: %v", err)
+			}
+			got := strings.Split(strings.TrimSpace(string(logData)), "\n")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("script calls = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWipeServerData_IdentityOverride(t *testing.T) {
+	// Test that an explicit identity is used instead of the path basename
+	tmpDir := t.TempDir()
+
+	serverPath := filepath.Join(tmpDir, "us-weekly")
+	identityDir := filepath.Join(serverPath, "server", "rust_us_weekly")
+	if err := os.MkdirAll(identityDir, 0755); err != nil {
+		t.Fatalf("Failed to create identity dir: %v", err)
+	}
+
+	mapFile := filepath.Join(identityDir, "world.map")
+	if err := os.WriteFile(mapFile, []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create map file: %v", err)
+	}
+
+	// A folder named after the path basename must be left alone
+	basenameDir := filepath.Join(serverPath, "server", "us-weekly")
+	if err := os.MkdirAll(basenameDir, 0755); err != nil {
+		t.Fatalf("Failed to create basename dir: %v", err)
+	}
+	decoyFile := filepath.Join(basenameDir, "world.map")
+	if err := os.WriteFile(decoyFile, []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create decoy map file: %v", err)
+	}
+
+	server := config.Server{
+		Name:     "us-weekly",
+		Path:     serverPath,
+		Branch:   "main",
+		Identity: "rust_us_weekly",
+	}
+
+	if err := wipeServerData(server, WipeStandard, false, false); err != nil {
+		t.Fatalf("wipeServerData failed: %v", err)
+	}
+
+	if _, err := os.Stat(mapFile); !os.IsNotExist(err) {
+		t.Error("Map file in the overridden identity directory should have been deleted")
+	}
+	if _, err := os.Stat(decoyFile); err != nil {
+		t.Error("Map file in the path basename directory should have been preserved")
+	}
+}
+
+func TestCheckScriptDrift(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	origHookPath := HookScriptPath
+	origPostHookPath := PostStartHookScriptPath
+	origStopPath := StopServersScriptPath
+	origStartPath := StartServersScriptPath
+	origGenPath := GenerateMapsScriptPath
+	defer func() {
+		HookScriptPath = origHookPath
+		PostStartHookScriptPath = origPostHookPath
+		StopServersScriptPath = origStopPath
+		StartServersScriptPath = origStartPath
+		GenerateMapsScriptPath = origGenPath
+	}()
+
+	HookScriptPath = filepath.Join(tmpDir, "pre-start-hook.sh")
+	PostStartHookScriptPath = filepath.Join(tmpDir, "post-start-hook.sh")
+	StopServersScriptPath = filepath.Join(tmpDir, "stop-servers.sh")
+	StartServersScriptPath = filepath.Join(tmpDir, "start-servers.sh")
+	GenerateMapsScriptPath = filepath.Join(tmpDir, "generate-maps.sh")
+
+	if err := EnsureHookScript(); err != nil {
+		t.Fatalf("EnsureHookScript failed: %v", err)
+	}
+	if err := EnsureWipeScripts(); err != nil {
+		t.Fatalf("EnsureWipeScripts failed: %v", err)
+	}
+
+	// Customize stop-servers.sh and remove generate-maps.sh
+	custom := defaultStopServersScript + "systemctl stop rs-example\n"
+	if err := os.WriteFile(StopServersScriptPath, []byte(custom), 0755); err != nil {
+		t.Fatalf("Failed to customize script: %v", err)
+	}
+	if err := os.Remove(GenerateMapsScriptPath); err != nil {
+		t.Fatalf("Failed to remove script: %v", err)
+	}
+
+	drifts, err := CheckScriptDrift()
+	if err != nil {
+		t.Fatalf("CheckScriptDrift failed: %v", err)
+	}
+
+	want := map[string]ScriptStatus{
+		HookScriptPath:          ScriptDefault,
+		PostStartHookScriptPath: ScriptDefault,
+		StopServersScriptPath:   ScriptCustomized,
+		StartServersScriptPath:  ScriptDefault,
+		GenerateMapsScriptPath:  ScriptMissing,
+	}
+
+	for _, d := range drifts {
+		if d.Status != want[d.Path] {
+			t.Errorf("%s status = %s, want %s", filepath.Base(d.Path), d.Status, want[d.Path])
+		}
+		if d.Path == StopServersScriptPath && (d.LinesAdded != 1 || d.LinesRemoved != 0) {
+			t.Errorf("stop-servers.sh diff = +%d/-%d, want +1/-0", d.LinesAdded, d.LinesRemoved)
+		}
+	}
+
+	// Checking must not recreate the missing script
+	if _, err := os.Stat(GenerateMapsScriptPath); !os.IsNotExist(err) {
+		t.Error("CheckScriptDrift should not modify scripts")
+	}
+}
+
+func TestLookupScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantPath string
+		wantErr  bool
+	}{
+		{"stop-servers", StopServersScriptPath, false},
+		{"pre-start-hook.sh", HookScriptPath, false},
+		{"post-start-hook", PostStartHookScriptPath, false},
+		{"generate-maps", GenerateMapsScriptPath, false},
+		{"healthcheck", "", true},
+		{"../stop-servers", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := LookupScript(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LookupScript(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if script.Path != tt.wantPath {
+				t.Errorf("LookupScript(%q) path = %q, want %q", tt.name, script.Path, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestReadScript(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	origStopPath, origStartPath := StopServersScriptPath, StartServersScriptPath
+	defer func() { StopServersScriptPath, StartServersScriptPath = origStopPath, origStartPath }()
+	StopServersScriptPath = filepath.Join(tmpDir, "stop-servers.sh")
+	StartServersScriptPath = filepath.Join(tmpDir, "start-servers.sh")
+
+	custom := "#!/bin/bash\nsystemctl stop rs-example\n"
+	if err := os.WriteFile(StopServersScriptPath, []byte(custom), 0755); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+
+	stop, err := LookupScript("stop-servers")
+	if err != nil {
+		t.Fatalf("LookupScript() error = %v", err)
+	}
+	contents, installed, err := ReadScript(stop)
+	if err != nil || !installed || contents != custom {
+		t.Errorf("ReadScript(stop-servers) = %q, %v, %v, want the installed script", contents, installed, err)
+	}
+
+	// A script that hasn't been generated shows the default template
+	start, err := LookupScript("start-servers")
+	if err != nil {
+		t.Fatalf("LookupScript() error = %v", err)
+	}
+	contents, installed, err = ReadScript(start)
+	if err != nil || installed || contents != defaultStartServersScript {
+		t.Errorf("ReadScript(start-servers) = %q, %v, %v, want the default template", contents, installed, err)
+	}
+}
+
+func TestWaitForMapGeneration(t *testing.T) {
+	// No generation in flight returns immediately
+	if err := WaitForMapGeneration("/test/idle", time.Second); err != nil {
+		t.Errorf("WaitForMapGeneration with nothing in flight returned error: %v", err)
+	}
+
+	done := BeginMapGeneration([]string{"/test/server-a"})
+
+	// Times out while generation is still running
+	if err := WaitForMapGeneration("/test/server-a", 10*time.Millisecond); err == nil {
+		t.Error("Expected timeout error while map generation is in flight")
+	}
+
+	// Returns once generation finishes
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		done()
+	}()
+	if err := WaitForMapGeneration("/test/server-a", time.Second); err != nil {
+		t.Errorf("WaitForMapGeneration returned error after generation finished: %v", err)
+	}
+}
+
+func TestStartServersStaggered(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	origStartPath := StartServersScriptPath
+	defer func() {
+		StartServersScriptPath = origStartPath
+	}()
+
+	logFile := filepath.Join(tmpDir, "execution.log")
+	startScript := filepath.Join(tmpDir, "start.sh")
+	startContent := fmt.Sprintf("#!/bin/bash\necho \"START: $@\" >> %s\nexit 0\n", logFile)
+	if err := os.WriteFile(startScript, []byte(startContent), 0755); err != nil {
+		t.Fatalf("Failed to create start script: %v", err)
+	}
+	StartServersScriptPath = startScript
+
+	stagger := 50 * time.Millisecond
+	begin := time.Now()
+	if err := startServersStaggered(context.Background(), []string{"/test/a", "/test/b", "/test/c"}, stagger, 1, 0, nil); err != nil {
+		t.Fatalf("startServersStaggered failed: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed < 2*stagger {
+		t.Errorf("startServersStaggered took %s, want at least %s", elapsed, 2*stagger)
+	}
+
+	logData, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	// Each server should be started by its own script invocation, in order
+	want := []string{"START: /test/a", "START: /test/b", "START: /test/c"}
+	logLines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	if len(logLines) != len(want) {
+		t.Fatalf("Expected %d start calls, got: %v", len(want), logLines)
+	}
+	for i := range want {
+		if logLines[i] != want[i] {
+			t.Errorf("start call %d = %q, want %q", i, logLines[i], want[i])
+		}
+	}
+}
+
+func TestStartServersStaggered_Groups(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	origStartPath := StartServersScriptPath
+	defer func() {
+		StartServersScriptPath = origStartPath
+	}()
+
+	logFile := filepath.Join(tmpDir, "execution.log")
+	startScript := filepath.Join(tmpDir, "start.sh")
+	startContent := fmt.Sprintf("#!/bin/bash\necho \"START: $@\" >> %s\nexit 0\n", logFile)
+	if err := os.WriteFile(startScript, []byte(startContent), 0755); err != nil {
+		t.Fatalf("Failed to create start script: %v", err)
+	}
+	StartServersScriptPath = startScript
+
+	var groupSizes []int
+	paths := []string{"/test/a", "/test/b", "/test/c", "/test/d", "/test/e"}
+	if err := startServersStaggered(context.Background(), paths, time.Millisecond, 2, 0, func(n int) { groupSizes = append(groupSizes, n) }); err != nil {
+		t.Fatalf("startServersStaggered failed: %v", err)
+	}
+
+	logData, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	// Servers are started two per script invocation, in order, with the remainder last
+	want := []string{"START: /test/a /test/b", "START: /test/c /test/d", "START: /test/e"}
+	logLines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	if len(logLines) != len(want) {
+		t.Fatalf("Expected %d start calls, got: %v", len(want), logLines)
+	}
+	for i := range want {
+		if logLines[i] != want[i] {
+			t.Errorf("start call %d = %q, want %q", i, logLines[i], want[i])
+		}
+	}
+	if fmt.Sprint(groupSizes) != "[2 2 1]" {
+		t.Errorf("beforeStart group sizes = %v, want [2 2 1]", groupSizes)
+	}
+}
+
+func TestStartServersStaggered_StaggerOutlastsStepTimeout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	origStartPath := StartServersScriptPath
+	defer func() {
+		StartServersScriptPath = origStartPath
+	}()
+
+	logFile := filepath.Join(tmpDir, "execution.log")
+	startScript := filepath.Join(tmpDir, "start.sh")
+	startContent := fmt.Sprintf("#!/bin/bash\necho \"START: $@\" >> %s\nexit 0\n", logFile)
+	if err := os.WriteFile(startScript, []byte(startContent), 0755); err != nil {
+		t.Fatalf("Failed to create start script: %v", err)
+	}
+	StartServersScriptPath = startScript
+
+	// The stagger and memory waits together run well past the step timeout, but each
+	// start gets its own timeout, so every group still starts
+	paths := []string{"/test/a", "/test/b", "/test/c", "/test/d"}
+	beforeStart := func(n int) { time.Sleep(100 * time.Millisecond) }
+	if err := startServersStaggered(context.Background(), paths, 150*time.Millisecond, 1, 300*time.Millisecond, beforeStart); err != nil {
+		t.Fatalf("startServersStaggered failed: %v", err)
+	}
+
+	logData, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	want := []string{"START: /test/a", "START: /test/b", "START: /test/c", "START: /test/d"}
+	if got := strings.Split(strings.TrimSpace(string(logData)), "\n"); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("start calls = %v, want %v", got, want)
+	}
+}
+
+func TestStartServersStaggered_CancelStopsStagger(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	origStartPath := StartServersScriptPath
+	defer func() {
+		StartServersScriptPath = origStartPath
+	}()
+
+	logFile := filepath.Join(tmpDir, "execution.log")
+	startScript := filepath.Join(tmpDir, "start.sh")
+	startContent := fmt.Sprintf("#!/bin/bash\necho \"START: $@\" >> %s\nexit 0\n", logFile)
+	if err := os.WriteFile(startScript, []byte(startContent), 0755); err != nil {
+		t.Fatalf("Failed to create start script: %v", err)
+	}
+	StartServersScriptPath = startScript
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	err := startServersStaggered(ctx, []string{"/test/a", "/test/b"}, time.Minute, 1, 0, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("startServersStaggered() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 5*time.Second {
+		t.Errorf("startServersStaggered took %s after the batch was cancelled", elapsed)
+	}
+
+	logData, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if got := strings.TrimSpace(string(logData)); got != "START: /test/a" {
+		t.Errorf("start calls = %q, want only /test/a", got)
+	}
+}
+
+func TestAwaitWipeConfirmation(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	origPath := WipeConfirmationPath
+	origInterval := confirmationPollInterval
+	defer func() {
+		WipeConfirmationPath = origPath
+		confirmationPollInterval = origInterval
+	}()
+	WipeConfirmationPath = filepath.Join(tmpDir, "wipe-confirmed")
+	confirmationPollInterval = 5 * time.Millisecond
+
+	// A confirmation left over from before the request must not approve the wipe
+	if err := ConfirmWipe(); err != nil {
+		t.Fatalf("ConfirmWipe() error = %v", err)
+	}
+	err := AwaitWipeConfirmation([]string{"server-a"}, 20*time.Millisecond, notify.Discard)
+	if !errors.Is(err, ErrWipeNotConfirmed) {
+		t.Errorf("AwaitWipeConfirmation() with stale confirmation error = %v, want %v", err, ErrWipeNotConfirmed)
+	}
+
+	// Confirming while waiting approves the wipe and consumes the confirmation
+	confirmed := make(chan error, 1)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		confirmed <- ConfirmWipe()
+	}()
+	if err := AwaitWipeConfirmation([]string{"server-a"}, time.Second, notify.Discard); err != nil {
+		t.Errorf("AwaitWipeConfirmation() error = %v, want nil", err)
+	}
+	// Wait for the goroutine before the deferred restore rewrites WipeConfirmationPath
+	if err := <-confirmed; err != nil {
+		t.Errorf("ConfirmWipe() error = %v", err)
+	}
+	if _, err := os.Stat(WipeConfirmationPath); !os.IsNotExist(err) {
+		t.Error("confirmation file should be removed once consumed")
+	}
+}
+
+func TestProbeServer(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	origScriptPath := HealthCheckScriptPath
+	origStatusPath := HealthStatusPath
+	defer func() {
+		HealthCheckScriptPath = origScriptPath
+		HealthStatusPath = origStatusPath
+	}()
+	HealthCheckScriptPath = filepath.Join(tmpDir, "healthcheck.sh")
+	HealthStatusPath = filepath.Join(tmpDir, "health.json")
+
+	// Healthy only for the "up" server; the last output line is the failure reason
+	script := "#!/bin/bash\nif [ \"$(basename \"$1\")\" = \"up\" ]; then exit 0; fi\necho \"checking $1\"\necho \"port closed\"\nexit 1\n"
+	if err := os.WriteFile(HealthCheckScriptPath, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create healthcheck script: %v", err)
+	}
+
+	if err := ProbeServer("/servers/up"); err != nil {
+		t.Errorf("ProbeServer(up) error = %v, want nil", err)
+	}
+	err := ProbeServer("/servers/down")
+	if err == nil || !strings.Contains(err.Error(), "port closed") {
+		t.Errorf("ProbeServer(down) error = %v, want error containing %q", err, "port closed")
+	}
+
+	// Status round-trips through the status file
+	checked := time.Now().Truncate(time.Second)
+	want := []ServerHealth{
+		{Name: "up", Path: "/servers/up", Healthy: true, LastChecked: checked, LastHealthy: checked},
+		{Name: "down", Path: "/servers/down", LastChecked: checked, Error: "port closed"},
+	}
+	if err := WriteHealthStatus(want); err != nil {
+		t.Fatalf("WriteHealthStatus() error = %v", err)
+	}
+	got, err := ReadHealthStatus()
+	if err != nil {
+		t.Fatalf("ReadHealthStatus() error = %v", err)
+	}
+	if len(got) != 2 || !got[0].Healthy || got[1].Healthy || !got[0].LastHealthy.Equal(checked) || got[1].Error != "port closed" {
+		t.Errorf("ReadHealthStatus() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWaitForFreeMemory(t *testing.T) {
+	origReader := availableMemoryMB
+	origTimeout := MemoryWaitTimeout
+	origInterval := memoryPollInterval
+	defer func() {
+		availableMemoryMB = origReader
+		MemoryWaitTimeout = origTimeout
+		memoryPollInterval = origInterval
+	}()
+	MemoryWaitTimeout = 50 * time.Millisecond
+	memoryPollInterval = 5 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		readings  []int // Successive MemAvailable readings in MB; the last one repeats
+		required  int
+		want      bool
+		minChecks int
+	}{
+		{"enough memory", []int{8000}, 4000, true, 1},
+		{"memory frees up while waiting", []int{1000, 2000, 5000}, 4000, true, 3},
+		{"never enough starts anyway", []int{1000}, 4000, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := 0
+			availableMemoryMB = func() (int, error) {
+				i := checks
+				if i >= len(tt.readings) {
+					i = len(tt.readings) - 1
+				}
+				checks++
+				return tt.readings[i], nil
+			}
+
+			if got := waitForFreeMemory(tt.required, 1, notify.Discard); got != tt.want {
+				t.Errorf("waitForFreeMemory() = %v, want %v", got, tt.want)
+			}
+			if checks < tt.minChecks {
+				t.Errorf("memory checked %d times, want at least %d", checks, tt.minChecks)
+			}
+		})
+	}
+}
+
+func TestExecuteEventBatch_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	origStopPath := StopServersScriptPath
+	origStartPath := StartServersScriptPath
+	defer func() {
+		StopServersScriptPath = origStopPath
+		StartServersScriptPath = origStartPath
+	}()
+
+	// Scripts leave a marker if they run at all
+	marker := filepath.Join(tmpDir, "ran")
+	script := fmt.Sprintf("#!/bin/bash\ntouch %s\n", marker)
+	StopServersScriptPath = filepath.Join(tmpDir, "stop.sh")
+	StartServersScriptPath = filepath.Join(tmpDir, "start.sh")
+	for _, path := range []string{StopServersScriptPath, StartServersScriptPath} {
+		if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+			t.Fatalf("Failed to create script: %v", err)
+		}
+	}
+
+	// wipeServerData uses filepath.Base(server.Path) as the identity
+	serverPath := filepath.Join(tmpDir, "dry-server")
+	identityDir := filepath.Join(serverPath, "server", "dry-server")
+	if err := os.MkdirAll(identityDir, 0755); err != nil {
+		t.Fatalf("Failed to create identity dir: %v", err)
+	}
+	files := []string{"world.map", "world.sav", "player.states.0.db", "player.blueprints.5.db"}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(identityDir, file), []byte("test"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+	}
+
+	servers := []config.Server{{Name: "dry-server", Path: serverPath, Branch: "main", WipeBlueprints: true}}
+	wipeServers := map[string]WipeMode{serverPath: WipeWithBlueprints}
+
+	if err := ExecuteEventBatch(context.Background(), servers, wipeServers, nil, BatchOptions{DryRun: true}); err != nil {
+		t.Fatalf("ExecuteEventBatch() dry run error = %v", err)
+	}
+
+	for _, file := range files {
+		if _, err := os.Stat(filepath.Join(identityDir, file)); err != nil {
+			t.Errorf("File %s should not be deleted in dry run: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("Stop/start scripts should not run in dry run")
+	}
+}
+
+func TestAppendAndReadHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.jsonl")
+
+	// A missing file is an empty history
+	records, err := ReadHistory(path, 0)
+	if err != nil {
+		t.Fatalf("ReadHistory() on missing file error = %v", err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("ReadHistory() on missing file = %d records, want 0", len(records))
+	}
+
+	start := time.Date(2025, 11, 16, 19, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		record := HistoryRecord{
+			Time:            start.Add(time.Duration(i) * time.Hour),
+			Servers:         []string{fmt.Sprintf("server-%d", i)},
+			Restarts:        1,
+			Success:         i != 1,
+			DurationSeconds: 42,
+		}
+		if !record.Success {
+			record.Error = "Failed to stop servers: exit status 1"
+		}
+		if err := AppendHistory(path, record); err != nil {
+			t.Fatalf("AppendHistory() error = %v", err)
+		}
+	}
+
+	// A line cut short by a crash is skipped rather than failing the read
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open history file: %v", err)
+	}
+	f.WriteString(`{"time":"2025-11`)
+	f.Close()
+
+	records, err = ReadHistory(path, 0)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("ReadHistory() = %d records, want 3", len(records))
+	}
+	if !records[0].Time.Equal(start) || records[0].Servers[0] != "server-0" || records[0].DurationSeconds != 42 {
+		t.Errorf("records[0] = %+v, want server-0 at %v taking 42s", records[0], start)
+	}
+	if records[1].Success || records[1].Error == "" {
+		t.Errorf("records[1] = %+v, want a failure with an error", records[1])
+	}
+
+	// The limit keeps the most recent records, oldest first
+	records, err = ReadHistory(path, 2)
+	if err != nil {
+		t.Fatalf("ReadHistory() with limit error = %v", err)
+	}
+	if len(records) != 2 || records[0].Servers[0] != "server-1" || records[1].Servers[0] != "server-2" {
+		t.Errorf("ReadHistory(limit 2) = %+v, want server-1 then server-2", records)
+	}
+}
+
+func TestExecuteEventBatch_RecordsHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	origStop := StopServersScriptPath
+	origHistory := HistoryFile()
+	defer func() {
+		StopServersScriptPath = origStop
+		SetHistoryFile(origHistory)
+	}()
+
+	StopServersScriptPath = filepath.Join(tmpDir, "stop.sh")
+	if err := os.WriteFile(StopServersScriptPath, []byte("#!/bin/bash\nexit 1\n"), 0755); err != nil {
+		t.Fatalf("Failed to create script: %v", err)
+	}
+	historyFile := filepath.Join(tmpDir, "history.jsonl")
+	SetHistoryFile(historyFile)
+
+	servers := []config.Server{
+		{Name: "us-weekly", Path: filepath.Join(tmpDir, "us-weekly")},
+		{Name: "eu-monthly", Path: filepath.Join(tmpDir, "eu-monthly")},
+	}
+	wipeServers := map[string]WipeMode{servers[1].Path: WipeStandard}
+
+	// Dry runs are not recorded
+	if err := ExecuteEventBatch(context.Background(), servers, wipeServers, nil, BatchOptions{DryRun: true}); err != nil {
+		t.Fatalf("ExecuteEventBatch() dry run error = %v", err)
+	}
+	if _, err := os.Stat(historyFile); !os.IsNotExist(err) {
+		t.Fatal("Dry run should not write the history file")
+	}
+
+	if err := ExecuteEventBatch(context.Background(), servers, wipeServers, nil, BatchOptions{}); err == nil {
+		t.Fatal("ExecuteEventBatch() should fail when stop-servers.sh fails")
+	}
+
+	records, err := ReadHistory(historyFile, 0)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("ReadHistory() = %d records, want 1", len(records))
+	}
+	record := records[0]
+	if record.Success {
+		t.Error("Success = true, want false")
+	}
+	if !strings.Contains(record.Error, "Failed to stop servers") {
+		t.Errorf("Error = %q, want it to mention the stop failure", record.Error)
+	}
+	if record.Restarts != 1 || record.Wipes != 1 {
+		t.Errorf("Restarts, Wipes = %d, %d, want 1, 1", record.Restarts, record.Wipes)
+	}
+	if strings.Join(record.Servers, ",") != "us-weekly,eu-monthly" {
+		t.Errorf("Servers = %v, want [us-weekly eu-monthly]", record.Servers)
+	}
+}
+
+func TestExecuteEventBatch_PostsEventWebhook(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var mu sync.Mutex
+	var received []EventPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload EventPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode event payload: %v", err)
+		}
+		mu.Lock()
+		received = append(received, payload)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	origStop, origStart, origHook := StopServersScriptPath, StartServersScriptPath, HookScriptPath
+	origWebhook := EventWebhookURL()
+	defer func() {
+		StopServersScriptPath, StartServersScriptPath, HookScriptPath = origStop, origStart, origHook
+		SetEventWebhookURL(origWebhook)
+	}()
+	SetEventWebhookURL(server.URL)
+
+	writeScript := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		return path
+	}
+	okScript := writeScript("ok.sh", "#!/bin/bash\nexit 0\n")
+	failScript := writeScript("fail.sh", "#!/bin/bash\nexit 1\n")
+	StartServersScriptPath = okScript
+	HookScriptPath = okScript
+
+	// Servers skip the sync and have no data to wipe, so only the scripts run
+	servers := []config.Server{
+		{Name: "us-weekly", Path: filepath.Join(tmpDir, "us-weekly")},
+		{Name: "eu-monthly", Path: filepath.Join(tmpDir, "eu-monthly")},
+	}
+	wipeServers := map[string]WipeMode{servers[1].Path: WipeStandard}
+	noSyncServers := map[string]bool{servers[0].Path: true, servers[1].Path: true}
+
+	tests := []struct {
+		name       string
+		stopScript string
+		wantEvents []string
+		wantError  bool
+	}{
+		{name: "success", stopScript: okScript, wantEvents: []string{EventBatchStart, EventBatchComplete}},
+		{name: "failure", stopScript: failScript, wantEvents: []string{EventBatchStart, EventBatchFailed}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			received = nil
+			mu.Unlock()
+			StopServersScriptPath = tt.stopScript
+
+			err := ExecuteEventBatch(context.Background(), servers, wipeServers, noSyncServers, BatchOptions{})
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ExecuteEventBatch() error = %v, wantError %v", err, tt.wantError)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(received) != len(tt.wantEvents) {
+				t.Fatalf("received %d posts (%+v), want %v", len(received), received, tt.wantEvents)
+			}
+			for i, payload := range received {
+				if payload.Event != tt.wantEvents[i] {
+					t.Errorf("post %d event = %q, want %q", i, payload.Event, tt.wantEvents[i])
+				}
+				if strings.Join(payload.Servers, ",") != "us-weekly,eu-monthly" || payload.Restarts != 1 || payload.Wipes != 1 {
+					t.Errorf("post %d = %+v, want both servers with 1 restart and 1 wipe", i, payload)
+				}
+			}
+			last := received[len(received)-1]
+			if tt.wantError && !strings.Contains(last.Error, "Failed to stop servers") {
+				t.Errorf("failed post error = %q, want the stop failure", last.Error)
+			}
+			if !tt.wantError && last.Error != "" {
+				t.Errorf("complete post error = %q, want empty", last.Error)
+			}
+		})
+	}
+}
+
+func TestExecuteEventBatch_NotifiesPhaseTimings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var mu sync.Mutex
+	var embeds []discord.Embed
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload discord.WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		mu.Lock()
+		embeds = append(embeds, payload.Embeds...)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	origStop, origStart, origHook := StopServersScriptPath, StartServersScriptPath, HookScriptPath
+	defer func() {
+		StopServersScriptPath, StartServersScriptPath, HookScriptPath = origStop, origStart, origHook
+	}()
+
+	writeScript := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		return path
+	}
+	okScript := writeScript("ok.sh", "#!/bin/bash\nexit 0\n")
+	failScript := writeScript("fail.sh", "#!/bin/bash\nexit 1\n")
+	StopServersScriptPath = okScript
+	HookScriptPath = okScript
+
+	servers := []config.Server{{Name: "us-weekly", Path: filepath.Join(tmpDir, "us-weekly")}}
+	noSyncServers := map[string]bool{servers[0].Path: true}
+
+	tests := []struct {
+		name        string
+		startScript string
+		wantTitle   string
+		wantFields  []string
+		wantPhase   string
+	}{
+		{name: "success", startScript: okScript, wantTitle: "Batch Event Complete", wantFields: []string{"Duration", "Phases"}},
+		{name: "start fails", startScript: failScript, wantTitle: "Batch Event Failed", wantFields: []string{"Failed Phase", "Phases"}, wantPhase: "Start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			embeds = nil
+			mu.Unlock()
+			StartServersScriptPath = tt.startScript
+
+			opts := BatchOptions{Notifier: discord.Webhook{URL: server.URL}}
+			err := ExecuteEventBatch(context.Background(), servers, map[string]WipeMode{}, noSyncServers, opts)
+			if (err != nil) != (tt.wantPhase != "") {
+				t.Fatalf("ExecuteEventBatch() error = %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			last := embeds[len(embeds)-1]
+			if last.Title != tt.wantTitle {
+				t.Fatalf("last notification = %q, want %q", last.Title, tt.wantTitle)
+			}
+			fields := make(map[string]string)
+			for _, f := range last.Fields {
+				fields[f.Name] = f.Value
+			}
+			for _, name := range tt.wantFields {
+				if fields[name] == "" {
+					t.Errorf("field %q missing or empty in %+v", name, last.Fields)
+				}
+			}
+			for _, phase := range batchPhases {
+				if !strings.Contains(fields["Phases"], phase+": ") {
+					t.Errorf("Phases = %q, want a duration for %s", fields["Phases"], phase)
+				}
+			}
+			if tt.wantPhase != "" && fields["Failed Phase"] != tt.wantPhase {
+				t.Errorf("Failed Phase = %q, want %q", fields["Failed Phase"], tt.wantPhase)
+			}
+		})
+	}
+}
+
+func TestExecuteEventBatch_StepTimeout(t *testing.T) {
+	tmpDir := t.TempDir()
+	startedFile := filepath.Join(tmpDir, "started")
+
+	origStop, origStart, origHook := StopServersScriptPath, StartServersScriptPath, HookScriptPath
+	defer func() {
+		StopServersScriptPath, StartServersScriptPath, HookScriptPath = origStop, origStart, origHook
+	}()
+
+	writeScript := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		return path
+	}
+	okScript := writeScript("ok.sh", "#!/bin/bash\nexit 0\n")
+	// The sleep runs as a child process, so the whole process group has to be killed
+	hangScript := writeScript("hang.sh", "#!/bin/bash\nsleep 30\n")
+	StartServersScriptPath = writeScript("start.sh", fmt.Sprintf("#!/bin/bash\ntouch %s\n", startedFile))
+
+	servers := []config.Server{{Name: "us-weekly", Path: filepath.Join(tmpDir, "us-weekly")}}
+	noSyncServers := map[string]bool{servers[0].Path: true}
+
+	tests := []struct {
+		name       string
+		stopScript string
+		hookScript string
+		wantError  bool
+	}{
+		// A stuck stop fails the batch, but the servers are still started
+		{name: "stop hangs", stopScript: hangScript, hookScript: okScript, wantError: true},
+		// A stuck hook is killed and the batch carries on as for any hook failure
+		{name: "hook hangs", stopScript: okScript, hookScript: hangScript, wantError: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(startedFile)
+			StopServersScriptPath = tt.stopScript
+			HookScriptPath = tt.hookScript
+
+			start := time.Now()
+			err := ExecuteEventBatch(context.Background(), servers, map[string]WipeMode{}, noSyncServers,
+				BatchOptions{StepTimeout: 300 * time.Millisecond})
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ExecuteEventBatch() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError && !strings.Contains(err.Error(), "timed out") {
+				t.Errorf("ExecuteEventBatch() error = %v, want a timeout", err)
+			}
+			if elapsed > 5*time.Second {
+				t.Errorf("ExecuteEventBatch() took %v, want the stuck step killed at the 300ms deadline", elapsed)
+			}
+			if _, err := os.Stat(startedFile); err != nil {
+				t.Error("start-servers.sh was not run after the step timed out")
+			}
+		})
+	}
+}
+
+func TestExecuteEventBatch_RecheckWipesCancels(t *testing.T) {
+	tmpDir := t.TempDir()
+	logFile := filepath.Join(tmpDir, "stop.log")
+
+	origStop, origStart, origHook := StopServersScriptPath, StartServersScriptPath, HookScriptPath
+	defer func() {
+		StopServersScriptPath, StartServersScriptPath, HookScriptPath = origStop, origStart, origHook
+	}()
+
+	StopServersScriptPath = filepath.Join(tmpDir, "stop.sh")
+	if err := os.WriteFile(StopServersScriptPath, []byte(fmt.Sprintf("#!/bin/bash\necho \"$@\" >> %s\n", logFile)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	StartServersScriptPath = filepath.Join(tmpDir, "ok.sh")
+	if err := os.WriteFile(StartServersScriptPath, []byte("#!/bin/bash\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	HookScriptPath = StartServersScriptPath
+
+	servers := []config.Server{
+		{Name: "us-weekly", Path: filepath.Join(tmpDir, "us-weekly")},
+		{Name: "eu-monthly", Path: filepath.Join(tmpDir, "eu-monthly")},
+	}
+	noSyncServers := map[string]bool{servers[0].Path: true, servers[1].Path: true}
+
+	tests := []struct {
+		name        string
+		wipeServers map[string]WipeMode
+		cancelled   map[string]bool
+		wantStopped string // Arguments passed to stop-servers.sh, empty if it never ran
+	}{
+		{
+			name:        "deleted wipe is dropped",
+			wipeServers: map[string]WipeMode{servers[0].Path: WipeStandard, servers[1].Path: WipeStandard},
+			cancelled:   map[string]bool{servers[0].Path: true},
+			wantStopped: servers[1].Path,
+		},
+		{
+			name:        "confirmed wipe runs",
+			wipeServers: map[string]WipeMode{servers[0].Path: WipeStandard},
+			cancelled:   map[string]bool{},
+			wantStopped: servers[0].Path + " " + servers[1].Path,
+		},
+		{
+			name:        "every wipe deleted",
+			wipeServers: map[string]WipeMode{servers[0].Path: WipeStandard, servers[1].Path: WipeStandard},
+			cancelled:   map[string]bool{servers[0].Path: true, servers[1].Path: true},
+			wantStopped: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(logFile)
+			rechecked := false
+			opts := BatchOptions{RecheckWipes: func() map[string]bool {
+				rechecked = true
+				return tt.cancelled
+			}}
+
+			if err := ExecuteEventBatch(context.Background(), servers, tt.wipeServers, noSyncServers, opts); err != nil {
+				t.Fatalf("ExecuteEventBatch() error = %v", err)
+			}
+			if !rechecked {
+				t.Error("RecheckWipes was not called")
+			}
+
+			data, _ := os.ReadFile(logFile)
+			if got := strings.TrimSpace(string(data)); got != tt.wantStopped {
+				t.Errorf("stopped servers = %q, want %q", got, tt.wantStopped)
+			}
+		})
+	}
+}
+
+func TestWaitEventDelay_RconWarnings(t *testing.T) {
+	origSleep, origSay, origNow := sleepFunc, rconSay, nowFunc
+	defer func() { sleepFunc, rconSay, nowFunc = origSleep, origSay, origNow }()
+
+	servers := []config.Server{
+		{Name: "us-weekly", Path: "/srv/us-weekly", RconAddress: "127.0.0.1:28016", RconPassword: "secret"},
+		{Name: "eu-monthly", Path: "/srv/eu-monthly", RconAddress: "127.0.0.1:28017", RconPassword: "secret"},
+		{Name: "no-rcon", Path: "/srv/no-rcon"},
+	}
+	wipeServers := map[string]WipeMode{"/srv/us-weekly": WipeStandard}
+
+	tests := []struct {
+		name    string
+		servers []config.Server
+		opts    BatchOptions
+		want    []string
+	}{
+		{
+			name:    "warnings within delay",
+			servers: servers,
+			opts:    BatchOptions{EventDelay: 600, RconWarnings: []int{60, 300, 900, 10}},
+			want: []string{
+				"sleep 5m0s",
+				"127.0.0.1:28016: Wipe in 5 minutes", "127.0.0.1:28017: Restart in 5 minutes",
+				"sleep 4m0s",
+				"127.0.0.1:28016: Wipe in 1 minute", "127.0.0.1:28017: Restart in 1 minute",
+				"sleep 50s",
+				"127.0.0.1:28016: Wipe in 10 seconds", "127.0.0.1:28017: Restart in 10 seconds",
+				"sleep 10s",
+			},
+		},
+		{
+			name:    "warning at the start of the delay",
+			servers: servers[:1],
+			opts:    BatchOptions{EventDelay: 60, RconWarnings: []int{60}, RconWarningMessage: "Server {event}s in {time}!"},
+			want:    []string{"127.0.0.1:28016: Server Wipes in 1 minute!", "sleep 1m0s"},
+		},
+		{
+			name:    "no RCON servers",
+			servers: servers[2:],
+			opts:    BatchOptions{EventDelay: 600, RconWarnings: []int{300}},
+			want:    []string{"sleep 10m0s"},
+		},
+		{
+			name:    "no warnings within delay",
+			servers: servers,
+			opts:    BatchOptions{EventDelay: 30, RconWarnings: []int{300, 60}},
+			want:    []string{"sleep 30s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var got, sent []string
+			now := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+			nowFunc = func() time.Time { return now }
+			sleepFunc = func(d time.Duration) {
+				sort.Strings(sent) // Servers are warned in parallel
+				got = append(got, sent...)
+				sent = nil
+				got = append(got, "sleep "+d.String())
+				now = now.Add(d)
+			}
+			rconSay = func(address, password, message string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				if password != "secret" {
+					t.Errorf("rconSay() password = %q, want %q", password, "secret")
+				}
+				sent = append(sent, address+": "+message)
+				return nil
+			}
+
+			waitEventDelay(tt.servers, wipeServers, tt.opts)
+			sort.Strings(sent)
+			got = append(got, sent...)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("waitEventDelay() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitEventDelay_SlowRconSender(t *testing.T) {
+	origSleep, origSay, origNow := sleepFunc, rconSay, nowFunc
+	defer func() { sleepFunc, rconSay, nowFunc = origSleep, origSay, origNow }()
+
+	var mu sync.Mutex
+	var got []string
+	now := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+	nowFunc = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	sleepFunc = func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, "sleep "+d.String())
+		now = now.Add(d)
+	}
+	// Every send takes 20 seconds, e.g. dialing a server that doesn't answer
+	rconSay = func(address, password, message string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, message)
+		now = now.Add(20 * time.Second)
+		return nil
+	}
+
+	servers := []config.Server{{Name: "us-weekly", Path: "/srv/us-weekly", RconAddress: "127.0.0.1:28016"}}
+	start := nowFunc()
+	waitEventDelay(servers, nil, BatchOptions{EventDelay: 600, RconWarnings: []int{300, 60, 10}})
+
+	// Each wait is shortened by the time the previous send took, so every warning goes out at
+	// its mark; the last send runs past the end of the delay, so nothing is left to sleep
+	want := []string{
+		"sleep 5m0s", "Restart in 5 minutes",
+		"sleep 3m40s", "Restart in 1 minute",
+		"sleep 30s", "Restart in 10 seconds",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("waitEventDelay() =\n%q\nwant\n%q", got, want)
+	}
+	if elapsed := nowFunc().Sub(start); elapsed != 610*time.Second {
+		t.Errorf("waitEventDelay() took %s, want 10m10s (the delay plus the last send)", elapsed)
+	}
+}
@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/logging"
+)
+
+var (
+	HealthCheckScriptPath = "/opt/wiped/healthcheck.sh"
+
+	// HealthStatusPath is where the daemon records the latest health probe results
+	HealthStatusPath = "/opt/wiped/health.json"
+)
+
+// ServerHealth is the latest health probe result for a server
+type ServerHealth struct {
+	Name        string    `json:"name"`
+	Path        string    `json:"path"`
+	Healthy     bool      `json:"healthy"`
+	LastChecked time.Time `json:"last_checked"`
+	LastHealthy time.Time `json:"last_healthy,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// EnsureHealthCheckScript creates the health check script if it doesn't exist
+func EnsureHealthCheckScript() error {
+	if err := os.MkdirAll(filepath.Dir(HealthCheckScriptPath), 0755); err != nil {
+		return fmt.Errorf("failed to create scripts directory: %w", err)
+	}
+
+	// Check if script already exists
+	if _, err := os.Stat(HealthCheckScriptPath); err == nil {
+		return nil
+	}
+
+	if err := os.WriteFile(HealthCheckScriptPath, []byte(defaultHealthCheckScript), 0755); err != nil {
+		return fmt.Errorf("failed to write healthcheck script: %w", err)
+	}
+
+	logging.Infof("Created healthcheck script at %s", HealthCheckScriptPath)
+	return nil
+}
+
+// ProbeServer runs the health check script for a single server; exit code 0 means healthy
+func ProbeServer(serverPath string) error {
+	if _, err := os.Stat(HealthCheckScriptPath); err != nil {
+		return fmt.Errorf("healthcheck.sh not found at %s", HealthCheckScriptPath)
+	}
+
+	output, err := exec.Command(HealthCheckScriptPath, serverPath).CombinedOutput()
+	if err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("%w: %s", err, lastLine(string(output)))
+		}
+		return err
+	}
+	return nil
+}
+
+// WriteHealthStatus atomically replaces the health status file
+func WriteHealthStatus(results []ServerHealth) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode health status: %w", err)
+	}
+
+	tmpPath := HealthStatusPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write health status: %w", err)
+	}
+	return os.Rename(tmpPath, HealthStatusPath)
+}
+
+// ReadHealthStatus reads the health status file written by the daemon
+func ReadHealthStatus() ([]ServerHealth, error) {
+	data, err := os.ReadFile(HealthStatusPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ServerHealth
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse health status: %w", err)
+	}
+	return results, nil
+}
+
+// lastLine returns the last line of script output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return lines[len(lines)-1]
+}
@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/logging"
+)
+
+// historyFile is the JSONL file each completed batch is appended to (empty disables history)
+var (
+	historyFile      = ""
+	historyFileMutex sync.RWMutex
+)
+
+// SetHistoryFile sets the JSONL file each completed batch is appended to (empty disables history)
+func SetHistoryFile(path string) {
+	historyFileMutex.Lock()
+	defer historyFileMutex.Unlock()
+	historyFile = path
+}
+
+// HistoryFile returns the file batches are recorded in, or "" if history is disabled
+func HistoryFile() string {
+	historyFileMutex.RLock()
+	defer historyFileMutex.RUnlock()
+	return historyFile
+}
+
+// HistoryRecord is one line of the execution history: the outcome of a single ExecuteEventBatch
+type HistoryRecord struct {
+	Time            time.Time `json:"time"`             // When the batch started
+	Servers         []string  `json:"servers"`          // Names of the servers in the batch
+	Restarts        int       `json:"restarts"`         // Servers restarted without a wipe
+	Wipes           int       `json:"wipes"`            // Servers wiped
+	Success         bool      `json:"success"`          // Whether the batch completed
+	Error           string    `json:"error,omitempty"`  // Why the batch failed
+	DurationSeconds float64   `json:"duration_seconds"` // How long the batch took
+}
+
+// AppendHistory appends a record to the JSONL history file, creating it (and its directory) if needed
+func AppendHistory(path string, record HistoryRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+// ReadHistory returns the most recent records in the history file, oldest first.
+// A limit of 0 or less returns every record. A missing file is an empty history;
+// lines that don't parse (e.g. one cut short by a crash) are skipped.
+func ReadHistory(path string, limit int) ([]HistoryRecord, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	var records []HistoryRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record HistoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	return records, nil
+}
+
+// recordHistory appends the outcome of a batch to HistoryFile, if one is configured
+func recordHistory(start time.Time, servers []config.Server, wipeCount int, batchErr error) {
+	path := HistoryFile()
+	if path == "" {
+		return
+	}
+
+	record := HistoryRecord{
+		Time:            start,
+		Servers:         make([]string, len(servers)),
+		Restarts:        len(servers) - wipeCount,
+		Wipes:           wipeCount,
+		Success:         batchErr == nil,
+		DurationSeconds: time.Since(start).Seconds(),
+	}
+	for i, s := range servers {
+		record.Servers[i] = s.Name
+	}
+	if batchErr != nil {
+		record.Error = batchErr.Error()
+	}
+
+	if err := AppendHistory(path, record); err != nil {
+		logging.Warnf("Warning: Failed to record batch history: %v", err)
+	}
+}
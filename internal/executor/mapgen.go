@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// MapGenerationWaitTimeout bounds how long a wipe waits for in-flight map generation
+	MapGenerationWaitTimeout = 30 * time.Minute
+
+	// mapGenInFlight tracks servers (by path) whose map generation is in progress.
+	// The channel is closed when generation finishes.
+	mapGenInFlight = make(map[string]chan struct{})
+	mapGenMutex    sync.Mutex
+)
+
+// BeginMapGeneration marks map generation as in progress for the given servers.
+// Returns a function that must be called when generation finishes.
+func BeginMapGeneration(serverPaths []string) func() {
+	mapGenMutex.Lock()
+	defer mapGenMutex.Unlock()
+
+	done := make(chan struct{})
+	for _, path := range serverPaths {
+		mapGenInFlight[path] = done
+	}
+
+	return func() {
+		mapGenMutex.Lock()
+		defer mapGenMutex.Unlock()
+
+		for _, path := range serverPaths {
+			if mapGenInFlight[path] == done {
+				delete(mapGenInFlight, path)
+			}
+		}
+		close(done)
+	}
+}
+
+// WaitForMapGeneration blocks until any in-flight map generation for the server finishes.
+// Returns an error if generation is still running after the timeout.
+func WaitForMapGeneration(serverPath string, timeout time.Duration) error {
+	mapGenMutex.Lock()
+	done, inFlight := mapGenInFlight[serverPath]
+	mapGenMutex.Unlock()
+
+	if !inFlight {
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("map generation still running after %s", timeout)
+	}
+}
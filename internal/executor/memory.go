@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/logging"
+	"github.com/maintc/wipe-cli/internal/notify"
+)
+
+var (
+	// MemoryWaitTimeout bounds how long a start waits for enough free memory before starting anyway
+	MemoryWaitTimeout = 5 * time.Minute
+
+	// memoryPollInterval is how often free memory is re-checked while waiting
+	memoryPollInterval = 10 * time.Second
+
+	// availableMemoryMB reads available memory; a variable so tests can stub it
+	availableMemoryMB = readAvailableMemoryMB
+)
+
+// readAvailableMemoryMB returns MemAvailable from /proc/meminfo in megabytes
+func readAvailableMemoryMB() (int, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemAvailable:" {
+			kb, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return 0, fmt.Errorf("invalid MemAvailable value %q: %w", fields[1], err)
+			}
+			return kb / 1024, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("MemAvailable not found in /proc/meminfo")
+}
+
+// waitForFreeMemory delays a start until requiredMB of memory is available or MemoryWaitTimeout passes.
+// It never blocks the start indefinitely: servers are already stopped, so starting late beats not starting.
+// Returns true if enough memory was available.
+func waitForFreeMemory(requiredMB, serverCount int, notifier notify.Notifier) bool {
+	available, err := availableMemoryMB()
+	if err != nil {
+		logging.Warnf("Warning: Could not read available memory, skipping check: %v", err)
+		return true
+	}
+	if available >= requiredMB {
+		return true
+	}
+
+	logging.Warnf("Only %d MB memory available, need %d MB to start %d server(s); waiting up to %s...",
+		available, requiredMB, serverCount, MemoryWaitTimeout)
+	notifier.Warning("Low Memory Before Start",
+		fmt.Sprintf("Only **%d MB** available, **%d MB** needed to start **%d** server(s)\n\nWaiting up to %s for memory to free up",
+			available, requiredMB, serverCount, MemoryWaitTimeout))
+
+	deadline := time.Now().Add(MemoryWaitTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(memoryPollInterval)
+		available, err = availableMemoryMB()
+		if err == nil && available >= requiredMB {
+			logging.Infof("✓ %d MB memory available, starting", available)
+			return true
+		}
+	}
+
+	logging.Warnf("Warning: Still only %d MB memory available after %s, starting anyway", available, MemoryWaitTimeout)
+	notifier.Warning("Starting With Low Memory",
+		fmt.Sprintf("Only **%d MB** available after waiting %s (needed **%d MB**)\n\nStarting anyway", available, MemoryWaitTimeout, requiredMB))
+	return false
+}
@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/logging"
+	"github.com/maintc/wipe-cli/internal/rcon"
+)
+
+// rconSay broadcasts an in-game message over WebRCON; a variable so tests can stub it
+var rconSay = rcon.Say
+
+// waitEventDelay sleeps out a batch's event delay, sending each RCON warning that falls within it
+// to the servers with an RCON address. Without warnings to send it sleeps once.
+// Waits are measured against the end of the delay, so time spent sending warnings doesn't push
+// the stop back; a warning whose mark has already passed is sent straight away.
+func waitEventDelay(servers []config.Server, wipeServers map[string]WipeMode, opts BatchOptions) {
+	delay := opts.EventDelay
+	warnings := warningsWithin(opts.RconWarnings, delay)
+	if len(warnings) == 0 || !anyRcon(servers) {
+		sleepFunc(time.Duration(delay) * time.Second)
+		return
+	}
+
+	deadline := nowFunc().Add(time.Duration(delay) * time.Second)
+	for _, remaining := range warnings {
+		if wait := deadline.Sub(nowFunc()) - time.Duration(remaining)*time.Second; wait > 0 {
+			sleepFunc(wait)
+		}
+		sendRconWarnings(servers, wipeServers, opts.RconWarningMessage, remaining)
+	}
+	if rest := deadline.Sub(nowFunc()); rest > 0 {
+		sleepFunc(rest)
+	}
+}
+
+// warningsWithin returns the distinct warnings (seconds before the event) that fit in delay, longest first
+func warningsWithin(warnings []int, delay int) []int {
+	seen := make(map[int]bool)
+	var within []int
+	for _, w := range warnings {
+		if w > 0 && w <= delay && !seen[w] {
+			seen[w] = true
+			within = append(within, w)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(within)))
+	return within
+}
+
+// anyRcon reports whether any server in the batch has RCON configured
+func anyRcon(servers []config.Server) bool {
+	for _, s := range servers {
+		if s.RconAddress != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// sendRconWarnings sends the warning to every server with RCON at once, so an unreachable
+// server doesn't hold up the countdown; failures are logged and otherwise ignored
+func sendRconWarnings(servers []config.Server, wipeServers map[string]WipeMode, message string, remaining int) {
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		if server.RconAddress == "" {
+			continue
+		}
+
+		event := "Restart"
+		if _, wipe := wipeServers[server.Path]; wipe {
+			event = "Wipe"
+		}
+		text := FormatRconWarning(message, event, remaining)
+
+		wg.Add(1)
+		go func(server config.Server) {
+			defer wg.Done()
+			logging.Infof("Sending RCON warning to %s: %s", server.Name, text)
+			if err := rconSay(server.RconAddress, server.RconPassword, text); err != nil {
+				logging.Warnf("Warning: Failed to send RCON warning to %s: %v", server.Name, err)
+			}
+		}(server)
+	}
+	wg.Wait()
+}
+
+// FormatRconWarning fills in a warning template: {event} becomes event and {time} the time left,
+// e.g. "Wipe in 5 minutes"
+func FormatRconWarning(message, event string, seconds int) string {
+	if message == "" {
+		message = "{event} in {time}"
+	}
+	return strings.NewReplacer("{event}", event, "{time}", formatTimeLeft(seconds)).Replace(message)
+}
+
+// formatTimeLeft renders seconds as whole minutes when even, otherwise seconds
+func formatTimeLeft(seconds int) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	if seconds >= 60 && seconds%60 == 0 {
+		return plural(seconds/60, "minute")
+	}
+	return plural(seconds, "second")
+}
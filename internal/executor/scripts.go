@@ -0,0 +1,351 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ScriptStatus describes how an installed management script compares to its default
+type ScriptStatus string
+
+const (
+	ScriptMissing    ScriptStatus = "missing"
+	ScriptDefault    ScriptStatus = "default"
+	ScriptCustomized ScriptStatus = "customized"
+)
+
+// ScriptDrift reports the state of a single management script
+type ScriptDrift struct {
+	Path         string
+	Status       ScriptStatus
+	LinesAdded   int // Lines present in the installed script but not in the default
+	LinesRemoved int // Lines present in the default but not in the installed script
+}
+
+// DefaultScripts returns the default content of each management script keyed by path
+func DefaultScripts() map[string]string {
+	return map[string]string{
+		HookScriptPath:          defaultHookScript,
+		PostStartHookScriptPath: defaultPostStartHookScript,
+		StopServersScriptPath:   defaultStopServersScript,
+		StartServersScriptPath:  defaultStartServersScript,
+		GenerateMapsScriptPath:  defaultGenerateMapsScript,
+	}
+}
+
+// ManagedScript is a management script and its short name, e.g. stop-servers
+type ManagedScript struct {
+	Name string
+	Path string
+}
+
+// ManagedScripts returns the management scripts in the order they run during a batch, then generate-maps
+func ManagedScripts() []ManagedScript {
+	return []ManagedScript{
+		{Name: "stop-servers", Path: StopServersScriptPath},
+		{Name: "pre-start-hook", Path: HookScriptPath},
+		{Name: "start-servers", Path: StartServersScriptPath},
+		{Name: "post-start-hook", Path: PostStartHookScriptPath},
+		{Name: "generate-maps", Path: GenerateMapsScriptPath},
+	}
+}
+
+// LookupScript returns the management script with the given short name (a trailing .sh is allowed)
+func LookupScript(name string) (ManagedScript, error) {
+	name = strings.TrimSuffix(name, ".sh")
+	var names []string
+	for _, script := range ManagedScripts() {
+		if script.Name == name {
+			return script, nil
+		}
+		names = append(names, script.Name)
+	}
+	return ManagedScript{}, fmt.Errorf("unknown script '%s' (must be one of: %s)", name, strings.Join(names, ", "))
+}
+
+// ReadScript returns the installed contents of a management script, or its default
+// template (and false) when it hasn't been generated yet
+func ReadScript(script ManagedScript) (string, bool, error) {
+	data, err := os.ReadFile(script.Path)
+	if os.IsNotExist(err) {
+		return DefaultScripts()[script.Path], false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s: %w", script.Path, err)
+	}
+	return string(data), true, nil
+}
+
+// EnsureScript generates a management script from its default template if it doesn't exist,
+// reporting whether it was created
+func EnsureScript(script ManagedScript) (bool, error) {
+	if _, err := os.Stat(script.Path); err == nil {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(script.Path), 0755); err != nil {
+		return false, fmt.Errorf("failed to create scripts directory: %w", err)
+	}
+	if err := os.WriteFile(script.Path, []byte(DefaultScripts()[script.Path]), 0755); err != nil {
+		return false, fmt.Errorf("failed to write %s script: %w", script.Name, err)
+	}
+	return true, nil
+}
+
+// CheckScriptDrift compares each installed management script against its default without modifying anything
+func CheckScriptDrift() ([]ScriptDrift, error) {
+	defaults := DefaultScripts()
+	paths := []string{HookScriptPath, PostStartHookScriptPath, StopServersScriptPath, StartServersScriptPath, GenerateMapsScriptPath}
+
+	var results []ScriptDrift
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			results = append(results, ScriptDrift{Path: path, Status: ScriptMissing})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if string(data) == defaults[path] {
+			results = append(results, ScriptDrift{Path: path, Status: ScriptDefault})
+			continue
+		}
+
+		added, removed := diffLineCounts(defaults[path], string(data))
+		results = append(results, ScriptDrift{
+			Path:         path,
+			Status:       ScriptCustomized,
+			LinesAdded:   added,
+			LinesRemoved: removed,
+		})
+	}
+
+	return results, nil
+}
+
+// diffLineCounts returns how many lines were added and removed going from original to modified
+func diffLineCounts(original, modified string) (int, int) {
+	counts := make(map[string]int)
+	for _, line := range strings.Split(original, "\n") {
+		counts[line]++
+	}
+
+	added := 0
+	for _, line := range strings.Split(modified, "\n") {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			added++
+		}
+	}
+
+	removed := 0
+	for _, n := range counts {
+		removed += n
+	}
+
+	return added, removed
+}
+
+const defaultHookScript = `#!/bin/bash
+# Pre-start Hook Script
+# 
+# This script is executed once after all servers have been synced
+# but before any servers are started back up.
+#
+# Arguments passed to this script:
+#   $@ - Space-separated list of server paths involved in this event
+#
+# Example:
+#   /var/www/servers/us-weekly /var/www/servers/eu-monthly
+#
+# You can add any custom logic here that should run before servers start.
+# For example: clearing caches, updating plugins, sending notifications, etc.
+
+SERVER_PATHS="$@"
+
+echo "Pre-start hook executed for servers: $SERVER_PATHS"
+
+# Add your custom logic below this line
+# ...
+`
+
+const defaultPostStartHookScript = `#!/bin/bash
+# Post-start Hook Script
+#
+# This script is executed once after start-servers.sh has started
+# the servers back up.
+#
+# Arguments passed to this script:
+#   $@ - Space-separated list of server paths involved in this event
+#
+# Example:
+#   /var/www/servers/us-weekly /var/www/servers/eu-monthly
+#
+# You can add any custom logic here that should run after servers start.
+# For example: smoke tests, RCON announcements, updating a status page, etc.
+
+SERVER_PATHS="$@"
+
+echo "Post-start hook executed for servers: $SERVER_PATHS"
+
+# Add your custom logic below this line
+# ...
+`
+
+const defaultStopServersScript = `#!/bin/bash
+# Stop Servers Script
+#
+# This script is called to stop Rust servers before performing updates/wipes.
+#
+# Arguments passed to this script:
+#   $@ - Space-separated list of server paths
+#
+# Example:
+#   /var/www/servers/us-weekly /var/www/servers/eu-monthly
+#
+# Customize this script to match your server management approach.
+
+SERVER_PATHS="$@"
+
+echo "Stopping servers for paths: $SERVER_PATHS"
+
+for SERVER_PATH in $SERVER_PATHS; do
+    # Extract server identity from path (e.g., us-weekly from /var/www/servers/us-weekly)
+    IDENTITY=$(basename "$SERVER_PATH")
+    
+    echo "Stopping server: $IDENTITY (path: $SERVER_PATH)"
+    
+    # Add your server stop logic here
+    # Examples:
+    #   - systemctl stop rs-${IDENTITY}
+    #   - docker stop ${IDENTITY}
+    #   - kill $(cat ${SERVER_PATH}/server.pid)
+    #   - your custom stop command
+done
+
+echo "✓ All servers stopped"
+`
+
+const defaultStartServersScript = `#!/bin/bash
+# Start Servers Script
+#
+# This script is called to start Rust servers after performing updates/wipes.
+#
+# Arguments passed to this script:
+#   $@ - Space-separated list of server paths
+#
+# Example:
+#   /var/www/servers/us-weekly /var/www/servers/eu-monthly
+#
+# Customize this script to match your server management approach.
+
+SERVER_PATHS="$@"
+
+echo "Starting servers for paths: $SERVER_PATHS"
+
+for SERVER_PATH in $SERVER_PATHS; do
+    # Extract server identity from path (e.g., us-weekly from /var/www/servers/us-weekly)
+    IDENTITY=$(basename "$SERVER_PATH")
+    
+    echo "Starting server: $IDENTITY (path: $SERVER_PATH)"
+    
+    # Add your server start logic here
+    # Examples:
+    #   - systemctl start rs-${IDENTITY}
+    #   - docker start ${IDENTITY}
+    #   - ${SERVER_PATH}/start.sh
+    #   - your custom start command
+done
+
+echo "✓ All servers started"
+`
+
+const defaultGenerateMapsScript = `#!/bin/bash
+# Generate Maps Script
+#
+# This script is called to prepare maps for Rust servers before wipes.
+# It runs 22 hours before a wipe event (configurable via map_generation_hours).
+#
+# Arguments passed to this script:
+#   $@ - Space-separated list of server paths that need maps prepared
+#
+# Example:
+#   /var/www/servers/us-weekly /var/www/servers/eu-monthly
+#
+# YOUR RESPONSIBILITIES:
+#   1. Pick or generate a map (seed/size, custom map, etc.)
+#   2. Update the server's server.cfg file with map settings:
+#      - server.seed and server.size (for procedural maps)
+#      - OR server.levelurl (for custom map providers)
+#   3. Handle any map-related files as needed
+#   4. Clean up any temporary files after the wipe completes
+#   5. Exit with non-zero status on failure
+#
+# NOTE: This script is called BEFORE the wipe. The actual wipe process will:
+#   - Stop servers
+#   - Sync Rust/Carbon
+#   - Delete map/save files
+#   - Run pre-start-hook.sh
+#   - Start servers
+#
+# You are responsible for updating server.cfg BEFORE the wipe or in pre-start-hook.sh
+
+SERVER_PATHS="$@"
+
+echo "Map preparation requested for paths: $SERVER_PATHS"
+
+for SERVER_PATH in $SERVER_PATHS; do
+    # Extract server identity from path (e.g., us-weekly from /var/www/servers/us-weekly)
+    IDENTITY=$(basename "$SERVER_PATH")
+    
+    echo "Preparing map for: $IDENTITY (path: $SERVER_PATH)"
+    
+    # Add your map preparation logic here
+    # Examples:
+    #
+    # Option 1: Pick random seed/size and update server.cfg
+    #   SEED=$RANDOM
+    #   SIZE=4250
+    #   echo "server.seed \"$SEED\"" >> ${SERVER_PATH}/server/${IDENTITY}/cfg/server.cfg
+    #   echo "server.size $SIZE" >> ${SERVER_PATH}/server/${IDENTITY}/cfg/server.cfg
+    #
+    # Option 2: Generate with a custom map generator and update server.cfg
+    #   /usr/local/bin/map-generator --seed $SEED --size $SIZE --output ${SERVER_PATH}/maps
+    #   LEVELURL=$(cat ${SERVER_PATH}/maps/level_url.txt)
+    #   echo "server.levelurl \"$LEVELURL\"" >> ${SERVER_PATH}/server/${IDENTITY}/cfg/server.cfg
+    #
+    # Option 3: Do nothing, let server use default map
+    #   echo "Using default map for $IDENTITY"
+done
+
+echo "✓ Map preparation complete"
+`
+
+const defaultHealthCheckScript = `#!/bin/bash
+# Health Check Script
+#
+# This script is called periodically for each server when health_check_interval is set.
+# Exit 0 if the server is healthy, non-zero otherwise. The last line of output is
+# recorded as the failure reason.
+#
+# Arguments passed to this script:
+#   $1 - Server path
+#
+# Example:
+#   /var/www/servers/us-weekly
+
+SERVER_PATH="$1"
+IDENTITY=$(basename "$SERVER_PATH")
+
+# Add your health check logic here
+# Examples:
+#   - systemctl is-active --quiet rs-${IDENTITY}
+#   - nc -z -u 127.0.0.1 28015
+#   - curl -sf http://127.0.0.1:28016/status
+
+exit 0
+`
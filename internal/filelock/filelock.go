@@ -0,0 +1,33 @@
+// Package filelock takes advisory locks on files, so separate processes (the daemon and a
+// CLI command) can coordinate on the same install directories.
+package filelock
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Lock blocks until it holds a lock on path, shared or exclusive, creating the file if needed.
+// Any number of shared locks can be held at once; an exclusive lock excludes all others.
+// The returned func releases the lock.
+func Lock(path string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	if err := syscall.Flock(int(f.Fd()), how); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,66 @@
+package filelock
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// acquired reports whether lock returns within a short wait, releasing it if so
+func acquired(t *testing.T, path string, exclusive bool) (bool, func()) {
+	t.Helper()
+
+	done := make(chan func(), 1)
+	go func() {
+		unlock, err := Lock(path, exclusive)
+		if err != nil {
+			t.Errorf("Lock() returned error: %v", err)
+			unlock = func() {}
+		}
+		done <- unlock
+	}()
+
+	select {
+	case unlock := <-done:
+		return true, unlock
+	case <-time.After(200 * time.Millisecond):
+		// Release the lock once it's eventually taken
+		return false, func() { (<-done)() }
+	}
+}
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".main.lock")
+
+	// Shared locks are held together
+	unlockShared, err := Lock(path, false)
+	if err != nil {
+		t.Fatalf("Lock(shared) returned error: %v", err)
+	}
+	ok, unlockSecond := acquired(t, path, false)
+	if !ok {
+		t.Error("second shared lock blocked, want it held alongside the first")
+	}
+	unlockSecond()
+
+	// An exclusive lock waits for the shared lock to be released
+	ok, unlockExclusive := acquired(t, path, true)
+	if ok {
+		t.Error("exclusive lock taken while a shared lock was held")
+	}
+	unlockShared()
+	unlockExclusive()
+
+	// Once released, the exclusive lock is free again
+	ok, unlock := acquired(t, path, true)
+	if !ok {
+		t.Error("exclusive lock blocked after every lock was released")
+	}
+	unlock()
+}
+
+func TestLock_MissingDirectory(t *testing.T) {
+	if _, err := Lock(filepath.Join(t.TempDir(), "missing", ".main.lock"), true); err == nil {
+		t.Error("Lock() in a missing directory succeeded, want error")
+	}
+}
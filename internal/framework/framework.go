@@ -0,0 +1,141 @@
+package framework
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/maintc/wipe-cli/internal/carbon"
+	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/notify"
+	"github.com/maintc/wipe-cli/internal/oxide"
+)
+
+// Framework installs a Rust mod framework per branch and describes how it is synced to servers
+type Framework interface {
+	Name() string                                                                  // Display name, e.g. Carbon
+	Base() string                                                                  // Directory holding an install per branch
+	SourcePath(branch string) string                                               // Install synced to servers on branch
+	IsBranchInstalled(branch string) bool                                          // Whether branch has an install under Base
+	AcquireReadLock(branch string) func()                                          // Blocks installs of branch until the returned unlock is called
+	EnsureInstalled(branch string, notifier notify.Notifier) error                 // Installs branch if it is missing
+	Install(branch string, notifier notify.Notifier) error                         // Installs or updates branch to the latest build
+	CheckForUpdates(branch string, notifier notify.Notifier) (bool, string, error) // Reports whether a newer build exists and its version
+	CleanupDirs(serverPath string) []string                                        // Framework directories removed from a server before syncing
+}
+
+// For returns the framework with the given config name (empty means carbon)
+func For(name string) (Framework, error) {
+	switch name {
+	case "", config.FrameworkCarbon:
+		return Carbon{}, nil
+	case config.FrameworkOxide:
+		return Oxide{}, nil
+	}
+	return nil, fmt.Errorf("unknown framework '%s' (must be %s or %s)", name, config.FrameworkCarbon, config.FrameworkOxide)
+}
+
+// ForServer returns the framework a server runs
+func ForServer(server config.Server) (Framework, error) {
+	return For(server.Framework)
+}
+
+// Branches returns the Rust branches each framework is needed on, keyed by framework name
+// (servers without a branch are skipped, matching how Rust branches are collected)
+func Branches(servers []config.Server) map[string][]string {
+	seen := make(map[string]map[string]bool)
+	for _, server := range servers {
+		if server.Branch == "" {
+			continue
+		}
+		name := server.GetFramework()
+		if seen[name] == nil {
+			seen[name] = make(map[string]bool)
+		}
+		seen[name][server.Branch] = true
+	}
+
+	branches := make(map[string][]string, len(seen))
+	for name, set := range seen {
+		for branch := range set {
+			branches[name] = append(branches[name], branch)
+		}
+		sort.Strings(branches[name])
+	}
+	return branches
+}
+
+// ForBranch returns the frameworks run by servers on branch, or Carbon when no server uses it
+func ForBranch(servers []config.Server, branch string) []Framework {
+	var frameworks []Framework
+	seen := make(map[string]bool)
+	for _, server := range servers {
+		if server.Branch != branch || seen[server.GetFramework()] {
+			continue
+		}
+		seen[server.GetFramework()] = true
+		if fw, err := ForServer(server); err == nil {
+			frameworks = append(frameworks, fw)
+		}
+	}
+	if len(frameworks) == 0 {
+		return []Framework{Carbon{}}
+	}
+	return frameworks
+}
+
+// Carbon is the default framework, installed from the Carbon release builds
+type Carbon struct{}
+
+func (Carbon) Name() string                         { return "Carbon" }
+func (Carbon) Base() string                         { return carbon.CarbonBase }
+func (Carbon) SourcePath(branch string) string      { return carbon.GetCarbonPath(branch) }
+func (Carbon) IsBranchInstalled(branch string) bool { return carbon.IsBranchInstalled(branch) }
+func (Carbon) AcquireReadLock(branch string) func() { return carbon.AcquireReadLock(branch) }
+
+func (Carbon) EnsureInstalled(branch string, notifier notify.Notifier) error {
+	return carbon.EnsureCarbonInstalled(branch, notifier)
+}
+
+func (Carbon) Install(branch string, notifier notify.Notifier) error {
+	return carbon.InstallCarbon(branch, notifier)
+}
+
+func (Carbon) CheckForUpdates(branch string, notifier notify.Notifier) (bool, string, error) {
+	return carbon.CheckForCarbonUpdates(branch, notifier)
+}
+
+func (Carbon) CleanupDirs(serverPath string) []string {
+	return []string{
+		filepath.Join(serverPath, "carbon", "native"),
+		filepath.Join(serverPath, "carbon", "managed"),
+		filepath.Join(serverPath, "carbon", "tools"),
+	}
+}
+
+// Oxide is uMod's framework, installed from the Oxide.Rust releases
+type Oxide struct{}
+
+func (Oxide) Name() string                         { return "Oxide" }
+func (Oxide) Base() string                         { return oxide.OxideBase }
+func (Oxide) SourcePath(branch string) string      { return oxide.GetOxidePath(branch) }
+func (Oxide) IsBranchInstalled(branch string) bool { return oxide.IsBranchInstalled(branch) }
+func (Oxide) AcquireReadLock(branch string) func() { return oxide.AcquireReadLock(branch) }
+
+func (Oxide) EnsureInstalled(branch string, notifier notify.Notifier) error {
+	return oxide.EnsureOxideInstalled(branch, notifier)
+}
+
+func (Oxide) Install(branch string, notifier notify.Notifier) error {
+	return oxide.InstallOxide(branch, notifier)
+}
+
+func (Oxide) CheckForUpdates(branch string, notifier notify.Notifier) (bool, string, error) {
+	return oxide.CheckForOxideUpdates(branch, notifier)
+}
+
+// CleanupDirs is empty: Oxide's assemblies live in RustDedicated_Data, which the Rust sync
+// already replaces, and its oxide/ directory holds the server's plugins and data
+func (Oxide) CleanupDirs(serverPath string) []string {
+	return nil
+}
@@ -0,0 +1,122 @@
+package framework
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/maintc/wipe-cli/internal/carbon"
+	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/oxide"
+)
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", "Carbon", false},
+		{config.FrameworkCarbon, "Carbon", false},
+		{config.FrameworkOxide, "Oxide", false},
+		{"umod", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fw, err := For(tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("For(%q) should return error", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("For(%q) returned error: %v", tt.name, err)
+			}
+			if fw.Name() != tt.want {
+				t.Errorf("For(%q).Name() = %v, want %v", tt.name, fw.Name(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSourcePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		fw     Framework
+		branch string
+		want   string
+	}{
+		{"carbon default branch", Carbon{}, "", filepath.Join(carbon.CarbonBase, "main")},
+		{"carbon staging", Carbon{}, "staging", filepath.Join(carbon.CarbonBase, "staging")},
+		{"oxide default branch", Oxide{}, "", filepath.Join(oxide.OxideBase, "main")},
+		{"oxide staging", Oxide{}, "staging", filepath.Join(oxide.OxideBase, "staging")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fw.SourcePath(tt.branch); got != tt.want {
+				t.Errorf("SourcePath(%q) = %v, want %v", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanupDirs(t *testing.T) {
+	want := []string{"/srv/a/carbon/native", "/srv/a/carbon/managed", "/srv/a/carbon/tools"}
+	if got := (Carbon{}).CleanupDirs("/srv/a"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Carbon CleanupDirs() = %v, want %v", got, want)
+	}
+	// Oxide's plugins and data must survive a sync
+	if got := (Oxide{}).CleanupDirs("/srv/a"); len(got) != 0 {
+		t.Errorf("Oxide CleanupDirs() = %v, want none", got)
+	}
+}
+
+func TestBranches(t *testing.T) {
+	servers := []config.Server{
+		{Name: "a", Branch: "main"},
+		{Name: "b", Branch: "staging", Framework: config.FrameworkCarbon},
+		{Name: "c", Branch: "main", Framework: config.FrameworkOxide},
+		{Name: "d", Branch: "main"},
+		{Name: "e"},
+	}
+
+	want := map[string][]string{
+		config.FrameworkCarbon: {"main", "staging"},
+		config.FrameworkOxide:  {"main"},
+	}
+	if got := Branches(servers); !reflect.DeepEqual(got, want) {
+		t.Errorf("Branches() = %v, want %v", got, want)
+	}
+}
+
+func TestForBranch(t *testing.T) {
+	servers := []config.Server{
+		{Name: "a", Branch: "main", Framework: config.FrameworkOxide},
+		{Name: "b", Branch: "main"},
+		{Name: "c", Branch: "staging", Framework: config.FrameworkOxide},
+	}
+
+	tests := []struct {
+		branch string
+		want   []string
+	}{
+		{"main", []string{"Oxide", "Carbon"}},
+		{"staging", []string{"Oxide"}},
+		{"aux01", []string{"Carbon"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			var got []string
+			for _, fw := range ForBranch(servers, tt.branch) {
+				got = append(got, fw.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ForBranch(%q) = %v, want %v", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
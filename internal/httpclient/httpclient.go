@@ -0,0 +1,35 @@
+package httpclient
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout bounds every outbound request unless http_timeout is configured
+const DefaultTimeout = 30 * time.Second
+
+var timeout atomic.Int64
+
+func init() {
+	timeout.Store(int64(DefaultTimeout))
+}
+
+// SetTimeout sets the timeout used by clients created with New (values <= 0 restore the default)
+func SetTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultTimeout
+	}
+	timeout.Store(int64(d))
+}
+
+// Timeout returns the current outbound request timeout
+func Timeout() time.Duration {
+	return time.Duration(timeout.Load())
+}
+
+// New returns an HTTP client that gives up after the configured timeout.
+// Use it instead of http.Get/http.Post so a hung host can't block the daemon.
+func New() *http.Client {
+	return &http.Client{Timeout: Timeout()}
+}
@@ -0,0 +1,90 @@
+package installer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDownloadFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/archive.zip" {
+			w.Write([]byte("archive"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "archive.zip")
+	if err := DownloadFile(server.URL+"/archive.zip", path); err != nil {
+		t.Fatalf("DownloadFile() returned error: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "archive" {
+		t.Errorf("downloaded %q, %v, want %q", data, err, "archive")
+	}
+
+	if err := DownloadFile(server.URL+"/missing.zip", path); err == nil {
+		t.Error("DownloadFile() for a 404 should return error")
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.zip")
+	if err := os.WriteFile(path, []byte("abc"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	got, err := HashFile(path)
+	if err != nil {
+		t.Fatalf("HashFile() returned error: %v", err)
+	}
+	if want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"; got != want {
+		t.Errorf("HashFile() = %s, want %s", got, want)
+	}
+}
+
+func TestBranchLocks(t *testing.T) {
+	base := t.TempDir()
+	locks := NewBranchLocks("Test", func() string { return base })
+
+	// Only one install of a branch runs at a time
+	if !locks.StartInstall("main") {
+		t.Fatal("StartInstall() = false for an idle branch")
+	}
+	if locks.StartInstall("main") {
+		t.Error("StartInstall() = true while the branch is being installed")
+	}
+	if !locks.StartInstall("staging") {
+		t.Error("StartInstall() = false for another branch")
+	}
+	locks.FinishInstall("main")
+	if !locks.StartInstall("main") {
+		t.Error("StartInstall() = false after FinishInstall()")
+	}
+
+	// A write lock waits for reads of the same branch
+	unlockRead := locks.AcquireRead("main")
+	acquired := make(chan func(), 1)
+	go func() { acquired <- locks.AcquireWrite("main") }()
+
+	select {
+	case <-acquired:
+		t.Fatal("AcquireWrite() returned while a read lock was held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if _, err := os.Stat(filepath.Join(base, ".main.lock")); err != nil {
+		t.Errorf("lock file not created under base: %v", err)
+	}
+
+	unlockRead()
+	select {
+	case unlockWrite := <-acquired:
+		unlockWrite()
+	case <-time.After(2 * time.Second):
+		t.Fatal("AcquireWrite() didn't return after the read lock was released")
+	}
+}
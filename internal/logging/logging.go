@@ -0,0 +1,125 @@
+// Package logging provides leveled logging on top of the standard logger.
+// Text output is written through the log package unchanged, so existing log
+// settings (flags, output) still apply; JSON output writes one object per line.
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log message
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// String returns the level's name as accepted by ParseLevel
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// ParseLevel parses debug, info, warn (or warning) or error, case-insensitively
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("invalid log level '%s' (use debug, info, warn or error)", name)
+}
+
+var (
+	mutex      sync.Mutex
+	minLevel   = LevelInfo
+	jsonOutput = false
+)
+
+// SetLevel sets the lowest level that is logged (default: info)
+func SetLevel(level Level) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	minLevel = level
+}
+
+// SetJSON switches between plain text (the default) and one JSON object per line
+func SetJSON(enabled bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	jsonOutput = enabled
+}
+
+// Debugf logs detail only useful when troubleshooting, such as lock handoffs
+func Debugf(format string, args ...interface{}) {
+	logf(LevelDebug, format, args...)
+}
+
+// Infof logs normal progress
+func Infof(format string, args ...interface{}) {
+	logf(LevelInfo, format, args...)
+}
+
+// Warnf logs a problem that was worked around
+func Warnf(format string, args ...interface{}) {
+	logf(LevelWarn, format, args...)
+}
+
+// Errorf logs a failure
+func Errorf(format string, args ...interface{}) {
+	logf(LevelError, format, args...)
+}
+
+// entry is one line of JSON output
+type entry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Message string `json:"msg"`
+}
+
+func logf(level Level, format string, args ...interface{}) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if level < minLevel {
+		return
+	}
+
+	message := fmt.Sprintf(format, args...)
+	if !jsonOutput {
+		log.Output(3, message)
+		return
+	}
+
+	data, err := json.Marshal(entry{
+		Time:    time.Now().Format(time.RFC3339Nano),
+		Level:   level.String(),
+		Message: message,
+	})
+	if err != nil {
+		log.Output(3, message)
+		return
+	}
+	log.Writer().Write(append(data, '\n'))
+}
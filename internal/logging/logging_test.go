@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+)
+
+// captureLogs sends log output to a buffer for the duration of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	origOutput, origFlags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(origOutput)
+		log.SetFlags(origFlags)
+		SetLevel(LevelInfo)
+		SetJSON(false)
+	})
+	return &buf
+}
+
+func TestSetLevel_SuppressesLowerLevels(t *testing.T) {
+	buf := captureLogs(t)
+	SetLevel(LevelWarn)
+
+	Debugf("Acquired read lock for branch '%s'", "main")
+	Infof("Updating calendar events...")
+	Warnf("Warning: Could not read manifest file: %v", "missing")
+	Errorf("Error fetching calendar for %s", "us-weekly")
+
+	got := buf.String()
+	for _, suppressed := range []string{"Acquired read lock", "Updating calendar events"} {
+		if strings.Contains(got, suppressed) {
+			t.Errorf("output at level warn should not contain %q, got:\n%s", suppressed, got)
+		}
+	}
+	for _, logged := range []string{"Warning: Could not read manifest file: missing", "Error fetching calendar for us-weekly"} {
+		if !strings.Contains(got, logged) {
+			t.Errorf("output at level warn should contain %q, got:\n%s", logged, got)
+		}
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	buf := captureLogs(t)
+	SetJSON(true)
+
+	Warnf("Server %s is unhealthy", "us-weekly")
+
+	var e entry
+	if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+		t.Fatalf("output %q is not JSON: %v", buf.String(), err)
+	}
+	if e.Level != "warn" || e.Message != "Server us-weekly is unhealthy" || e.Time == "" {
+		t.Errorf("entry = %+v, want a timestamped warn entry", e)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Level
+		wantErr bool
+	}{
+		{"debug", LevelDebug, false},
+		{"INFO", LevelInfo, false},
+		{"warning", LevelWarn, false},
+		{"error", LevelError, false},
+		{"verbose", LevelInfo, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLevel(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
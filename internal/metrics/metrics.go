@@ -0,0 +1,123 @@
+// Package metrics exposes daemon metrics in the Prometheus format on /metrics
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Batch results recorded in wiped_batches_total
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	servers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "wiped_servers",
+		Help: "Number of configured servers.",
+	})
+	scheduledEvents = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "wiped_scheduled_events",
+		Help: "Number of events scheduled within the lookahead window.",
+	})
+	lastCalendarUpdate = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "wiped_last_calendar_update_timestamp_seconds",
+		Help: "Unix time of the last completed calendar update.",
+	})
+	calendarFetchErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wiped_calendar_fetch_errors_total",
+		Help: "Calendar fetches that failed, by server.",
+	}, []string{"server"})
+	batches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wiped_batches_total",
+		Help: "Batch events executed, by result.",
+	}, []string{"result"})
+	serverEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wiped_server_events_total",
+		Help: "Servers restarted or wiped by executed batches, by type.",
+	}, []string{"type"})
+)
+
+func init() {
+	registry.MustRegister(
+		servers,
+		scheduledEvents,
+		lastCalendarUpdate,
+		calendarFetchErrors,
+		batches,
+		serverEvents,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// SetServers records the number of configured servers
+func SetServers(count int) {
+	servers.Set(float64(count))
+}
+
+// RecordCalendarUpdate records a completed calendar update and the events it scheduled
+func RecordCalendarUpdate(at time.Time, events int) {
+	scheduledEvents.Set(float64(events))
+	lastCalendarUpdate.Set(float64(at.Unix()))
+}
+
+// RecordCalendarFetchError counts a failed calendar fetch for a server
+func RecordCalendarFetchError(server string) {
+	calendarFetchErrors.WithLabelValues(server).Inc()
+}
+
+// RecordBatch counts an executed batch and the restarts and wipes it contained
+func RecordBatch(restarts, wipes int, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+	batches.WithLabelValues(result).Inc()
+	serverEvents.WithLabelValues("restart").Add(float64(restarts))
+	serverEvents.WithLabelValues("wipe").Add(float64(wipes))
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Serve listens on addr (host:port) and serves /metrics until ctx is done.
+// It returns the address it listens on, or an error if it can't listen.
+func Serve(ctx context.Context, addr string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Errorf("Error serving metrics: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	return listener.Addr().String(), nil
+}
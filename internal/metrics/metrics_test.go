@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// scrape fetches /metrics from addr and returns the body
+func scrape(t *testing.T, addr string) string {
+	t.Helper()
+
+	resp, err := http.Get("http://" + addr + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /metrics status = %d, want 200", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read /metrics: %v", err)
+	}
+	return string(body)
+}
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr, err := Serve(ctx, "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	SetServers(3)
+	RecordBatch(2, 1, nil)
+	RecordBatch(1, 0, errors.New("stop script failed"))
+	RecordCalendarFetchError("us-weekly")
+
+	body := scrape(t, addr)
+	for _, want := range []string{
+		"wiped_servers 3",
+		`wiped_batches_total{result="success"} 1`,
+		`wiped_batches_total{result="failure"} 1`,
+		`wiped_server_events_total{type="restart"} 3`,
+		`wiped_server_events_total{type="wipe"} 1`,
+		`wiped_calendar_fetch_errors_total{server="us-weekly"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics should contain %q", want)
+		}
+	}
+}
+
+func TestServe_InvalidAddress(t *testing.T) {
+	if _, err := Serve(context.Background(), "not-an-address"); err == nil {
+		t.Error("Serve() with an invalid address should return error")
+	}
+}
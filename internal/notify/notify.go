@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/discord"
+	"github.com/maintc/wipe-cli/internal/slack"
+)
+
+// Notifier delivers notifications at one of four severity levels.
+// Failures are logged by the implementation and returned; callers that don't care can ignore the error.
+type Notifier interface {
+	Success(title, description string) error
+	Info(title, description string) error
+	Warning(title, description string) error
+	Error(title, description string) error
+}
+
+// Discard is a Notifier that drops every notification
+var Discard Notifier = discard{}
+
+type discard struct{}
+
+func (discard) Success(title, description string) error { return nil }
+func (discard) Info(title, description string) error    { return nil }
+func (discard) Warning(title, description string) error { return nil }
+func (discard) Error(title, description string) error   { return nil }
+
+// New returns the notifier selected by the config's notifier setting (Discord unless set to slack).
+// An empty webhook URL for the selected service disables notifications.
+func New(cfg *config.Config) Notifier {
+	if cfg.Notifier == config.NotifierSlack {
+		return slack.Webhook{URL: cfg.SlackWebhook, MentionUsers: cfg.SlackMentionUsers}
+	}
+	return discord.Webhook{URL: cfg.DiscordWebhook}
+}
+
+// OrDiscard returns n, or Discard when n is nil
+func OrDiscard(n Notifier) Notifier {
+	if n == nil {
+		return Discard
+	}
+	return n
+}
+
+// Level is a notification's severity
+type Level = discord.Level
+
+// Notification levels
+const (
+	LevelSuccess = discord.LevelSuccess
+	LevelInfo    = discord.LevelInfo
+	LevelWarning = discord.LevelWarning
+	LevelError   = discord.LevelError
+)
+
+// Field is a named value shown alongside a notification's description
+type Field struct {
+	Name   string
+	Value  string
+	Inline bool
+}
+
+// maxFieldValue is the longest value Discord accepts in an embed field
+const maxFieldValue = 1024
+
+// ListField returns a field listing items one per line, truncated to what an embed field holds
+func ListField(name string, items []string) Field {
+	var value strings.Builder
+	for i, item := range items {
+		line := "• " + item
+		if i > 0 {
+			line = "\n" + line
+		}
+		more := fmt.Sprintf("\n… and %d more", len(items)-i)
+		if value.Len()+len(line)+len(more) > maxFieldValue {
+			value.WriteString(more)
+			break
+		}
+		value.WriteString(line)
+	}
+	return Field{Name: name, Value: value.String()}
+}
+
+// fieldSender is implemented by notifiers that render fields natively
+type fieldSender interface {
+	Fields(level discord.Level, title, description string, fields []discord.EmbedField) error
+}
+
+// Send sends a notification at level with fields, skipping fields without a value.
+// Notifiers without native fields get them appended to the description.
+func Send(n Notifier, level Level, title, description string, fields ...Field) error {
+	var kept []Field
+	for _, f := range fields {
+		if f.Value != "" {
+			kept = append(kept, f)
+		}
+	}
+
+	if fs, ok := n.(fieldSender); ok {
+		embedFields := make([]discord.EmbedField, len(kept))
+		for i, f := range kept {
+			embedFields[i] = discord.EmbedField{Name: f.Name, Value: f.Value, Inline: f.Inline}
+		}
+		return fs.Fields(level, title, description, embedFields)
+	}
+
+	for _, f := range kept {
+		description += fmt.Sprintf("\n\n**%s:**\n%s", f.Name, f.Value)
+	}
+	switch level {
+	case LevelSuccess:
+		return n.Success(title, description)
+	case LevelWarning:
+		return n.Warning(title, description)
+	case LevelError:
+		return n.Error(title, description)
+	default:
+		return n.Info(title, description)
+	}
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recordingNotifier records the last notification it was sent
+type recordingNotifier struct {
+	level       string
+	title       string
+	description string
+}
+
+func (n *recordingNotifier) record(level, title, description string) error {
+	n.level, n.title, n.description = level, title, description
+	return nil
+}
+
+func (n *recordingNotifier) Success(title, description string) error {
+	return n.record("success", title, description)
+}
+func (n *recordingNotifier) Info(title, description string) error {
+	return n.record("info", title, description)
+}
+func (n *recordingNotifier) Warning(title, description string) error {
+	return n.record("warning", title, description)
+}
+func (n *recordingNotifier) Error(title, description string) error {
+	return n.record("error", title, description)
+}
+
+func TestSend_AppendsFieldsWithoutNativeSupport(t *testing.T) {
+	n := &recordingNotifier{}
+	err := Send(n, LevelWarning, "Title", "Two events",
+		Field{Name: "Restarts", Value: "• server1"},
+		Field{Name: "Wipes"}, // empty fields are skipped
+		Field{Name: "Count", Value: "1 restart(s)"})
+	if err != nil {
+		t.Fatalf("Send() returned error: %v", err)
+	}
+
+	want := "Two events\n\n**Restarts:**\n• server1\n\n**Count:**\n1 restart(s)"
+	if n.level != "warning" || n.title != "Title" || n.description != want {
+		t.Errorf("sent %s %q %q, want warning %q %q", n.level, n.title, n.description, "Title", want)
+	}
+}
+
+func TestListField(t *testing.T) {
+	if got := ListField("Wipes", []string{"a", "b"}).Value; got != "• a\n• b" {
+		t.Errorf("ListField() = %q, want %q", got, "• a\n• b")
+	}
+	if got := ListField("Wipes", nil).Value; got != "" {
+		t.Errorf("ListField(nil) = %q, want empty", got)
+	}
+
+	// Long lists are cut to fit an embed field, noting how many were left out
+	var items []string
+	for i := range 200 {
+		items = append(items, fmt.Sprintf("server-%03d at Thu Mar 05 18:00 UTC", i))
+	}
+	got := ListField("Wipes", items).Value
+	if len(got) > maxFieldValue {
+		t.Errorf("ListField() value is %d bytes, want at most %d", len(got), maxFieldValue)
+	}
+	if !strings.Contains(got, "• server-000") || !strings.Contains(got, "more") {
+		t.Errorf("ListField() = %q, want the first items and a count of the rest", got)
+	}
+}
@@ -0,0 +1,247 @@
+package oxide
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/maintc/wipe-cli/internal/httpclient"
+	"github.com/maintc/wipe-cli/internal/installer"
+	"github.com/maintc/wipe-cli/internal/logging"
+	"github.com/maintc/wipe-cli/internal/notify"
+)
+
+var (
+	// OxideBase holds an Oxide install per branch
+	OxideBase = "/opt/oxide"
+	// OxideReleasesAPI returns the latest Oxide.Rust release
+	OxideReleasesAPI = "https://api.github.com/repos/OxideMod/Oxide.Rust/releases/latest"
+	// OxideDownloadURL is the Linux build of a release, formatted with its tag
+	OxideDownloadURL = "https://github.com/OxideMod/Oxide.Rust/releases/download/%s/Oxide.Rust-linux.zip"
+)
+
+// branchLocks coordinates installs vs syncs of each branch
+var branchLocks = installer.NewBranchLocks("Oxide", func() string { return OxideBase })
+
+// OxideRelease represents the latest Oxide.Rust release from the GitHub API
+type OxideRelease struct {
+	TagName string `json:"tag_name"`
+}
+
+// normalizeBranch maps the empty branch to main
+func normalizeBranch(branch string) string {
+	if branch == "" {
+		return "main"
+	}
+	return branch
+}
+
+// AcquireReadLock acquires a read lock for a branch (used by syncServer)
+// Returns an unlock function that must be called when done reading
+func AcquireReadLock(branch string) func() {
+	return branchLocks.AcquireRead(normalizeBranch(branch))
+}
+
+// GetOxidePath returns the installation path for a branch
+func GetOxidePath(branch string) string {
+	return filepath.Join(OxideBase, normalizeBranch(branch))
+}
+
+// IsBranchInstalled reports whether Oxide has an install for a branch under OxideBase
+func IsBranchInstalled(branch string) bool {
+	return isOxideInstalled(GetOxidePath(branch))
+}
+
+// isOxideInstalled checks if Oxide is installed
+func isOxideInstalled(path string) bool {
+	oxideDLL := filepath.Join(path, "RustDedicated_Data", "Managed", "Oxide.Rust.dll")
+	_, err := os.Stat(oxideDLL)
+	return err == nil
+}
+
+// CheckForOxideUpdates checks if Oxide has updates available
+func CheckForOxideUpdates(branch string, notifier notify.Notifier) (bool, string, error) {
+	installPath := GetOxidePath(branch)
+
+	// Check if Oxide is installed
+	if !isOxideInstalled(installPath) {
+		return false, "", nil
+	}
+
+	// Get current installed version
+	currentVersionData, err := os.ReadFile(filepath.Join(installPath, "version.txt"))
+	if err != nil {
+		logging.Warnf("Warning: Could not read current Oxide version for %s: %v", branch, err)
+		return false, "", nil
+	}
+	currentVersion := strings.TrimSpace(string(currentVersionData))
+
+	release, err := getLatestOxideRelease()
+	if err != nil {
+		logging.Errorf("Error checking for Oxide updates: %v", err)
+		return false, "", err
+	}
+	latestVersion := release.TagName
+
+	if currentVersion != latestVersion {
+		logging.Infof("Oxide update available for branch %s: %s -> %s", branch, currentVersion, latestVersion)
+
+		notifier.Info("Oxide Update Available",
+			fmt.Sprintf("Oxide has an update available\n\nCurrent: **%s**\nAvailable: **%s**",
+				currentVersion, latestVersion))
+
+		return true, latestVersion, nil
+	}
+
+	return false, currentVersion, nil
+}
+
+// getLatestOxideRelease queries GitHub for the latest Oxide.Rust release
+func getLatestOxideRelease() (OxideRelease, error) {
+	resp, err := httpclient.New().Get(OxideReleasesAPI)
+	if err != nil {
+		return OxideRelease{}, fmt.Errorf("failed to fetch Oxide releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return OxideRelease{}, fmt.Errorf("oxide releases API returned status %d", resp.StatusCode)
+	}
+
+	var release OxideRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return OxideRelease{}, fmt.Errorf("failed to parse Oxide releases response: %w", err)
+	}
+	if release.TagName == "" {
+		return OxideRelease{}, fmt.Errorf("no Oxide release found")
+	}
+	return release, nil
+}
+
+// InstallOxide installs Oxide for a specific branch.
+// Oxide publishes a single Linux build, so every branch installs the latest release.
+func InstallOxide(branch string, notifier notify.Notifier) error {
+	branch = normalizeBranch(branch)
+
+	// Check if this branch is already being installed
+	if !branchLocks.StartInstall(branch) {
+		logging.Infof("Oxide for branch '%s' is already being installed, skipping", branch)
+		return nil
+	}
+	defer branchLocks.FinishInstall(branch)
+
+	// Acquire WRITE lock for this branch to block syncServer reads during install
+	defer branchLocks.AcquireWrite(branch)()
+
+	installPath := GetOxidePath(branch)
+	logging.Infof("Installing Oxide for branch '%s' to %s", branch, installPath)
+
+	// installFailed reports a failed step and returns it as an error
+	installFailed := func(errMsg string) error {
+		notifier.Error("Oxide Installation Failed",
+			fmt.Sprintf("Failed to install Oxide for branch **%s**\n\n%s", branch, errMsg))
+		return fmt.Errorf("%s", errMsg)
+	}
+
+	// Read the old archive hash and version before wiping the directory
+	oldHash := ""
+	hashPath := filepath.Join(installPath, "hash.txt")
+	if data, err := os.ReadFile(hashPath); err == nil {
+		oldHash = strings.TrimSpace(string(data))
+	}
+	oldVersion := ""
+	versionPath := filepath.Join(installPath, "version.txt")
+	if data, err := os.ReadFile(versionPath); err == nil {
+		oldVersion = strings.TrimSpace(string(data))
+	}
+
+	// Look the release up once: its tag picks the archive to download and is the version recorded
+	release, err := getLatestOxideRelease()
+	if err != nil {
+		return installFailed(fmt.Sprintf("failed to get the latest Oxide release: %v", err))
+	}
+	version := release.TagName
+	downloadURL := fmt.Sprintf(OxideDownloadURL, version)
+
+	// Download Oxide to a temp file first so we can hash before committing
+	tmpZipPath := filepath.Join(os.TempDir(), fmt.Sprintf("oxide-%s.zip", branch))
+	logging.Infof("Downloading Oxide from %s...", downloadURL)
+	if err := installer.DownloadFile(downloadURL, tmpZipPath); err != nil {
+		return installFailed(fmt.Sprintf("failed to download Oxide: %v", err))
+	}
+	defer os.Remove(tmpZipPath)
+
+	newHash, err := installer.HashFile(tmpZipPath)
+	if err != nil {
+		logging.Warnf("Warning: Could not hash downloaded archive: %v", err)
+	}
+
+	// The release's archive is already installed (e.g. the tag was re-published): record its
+	// version so the next check doesn't offer the same update again
+	if oldHash != "" && newHash == oldHash {
+		logging.Infof("Oxide for branch '%s' already has the %s archive installed, recording the version", branch, version)
+		if err := os.WriteFile(versionPath, []byte(version), 0644); err != nil {
+			logging.Warnf("Warning: Could not write version file: %v", err)
+		}
+		return nil
+	}
+
+	if err := os.RemoveAll(installPath); err != nil {
+		return installFailed(fmt.Sprintf("failed to remove old Oxide directory: %v", err))
+	}
+	if err := os.MkdirAll(installPath, 0755); err != nil {
+		return installFailed(fmt.Sprintf("failed to create Oxide directory: %v", err))
+	}
+
+	logging.Infof("Extracting Oxide...")
+	if err := extractZip(tmpZipPath, installPath); err != nil {
+		return installFailed(fmt.Sprintf("failed to extract Oxide: %v", err))
+	}
+
+	if err := os.WriteFile(versionPath, []byte(version), 0644); err != nil {
+		logging.Warnf("Warning: Could not write version file: %v", err)
+	}
+	if newHash != "" {
+		if err := os.WriteFile(hashPath, []byte(newHash), 0644); err != nil {
+			logging.Warnf("Warning: Could not write hash file: %v", err)
+		}
+	}
+
+	logging.Infof("✓ Successfully installed Oxide for branch '%s' (version: %s)", branch, version)
+	if oldVersion != "" && oldVersion != version {
+		notifier.Success("Oxide Update Complete",
+			fmt.Sprintf("Oxide for branch **%s** updated\n\nFrom: **%s**\nTo: **%s**", branch, oldVersion, version))
+	} else {
+		notifier.Success("Oxide Installation Complete",
+			fmt.Sprintf("Oxide for branch **%s** installed successfully\n\nVersion: **%s**", branch, version))
+	}
+
+	return nil
+}
+
+// EnsureOxideInstalled checks if Oxide is installed and installs it if not
+func EnsureOxideInstalled(branch string, notifier notify.Notifier) error {
+	installPath := GetOxidePath(branch)
+
+	if isOxideInstalled(installPath) {
+		logging.Infof("Oxide for branch '%s' already installed at %s", branch, installPath)
+		return nil
+	}
+
+	logging.Infof("Oxide for branch '%s' not found at %s, installing...", branch, installPath)
+	return InstallOxide(branch, notifier)
+}
+
+// extractZip extracts a zip file to a destination
+func extractZip(zipPath, destPath string) error {
+	cmd := exec.Command("unzip", "-o", "-q", zipPath, "-d", destPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("unzip failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
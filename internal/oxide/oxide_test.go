@@ -0,0 +1,260 @@
+package oxide
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/maintc/wipe-cli/internal/notify"
+)
+
+// useTestOxideBase points OxideBase at a temp dir for the duration of a test
+func useTestOxideBase(t *testing.T) string {
+	t.Helper()
+
+	origBase := OxideBase
+	t.Cleanup(func() { OxideBase = origBase })
+
+	OxideBase = t.TempDir()
+	return OxideBase
+}
+
+// writeInstall creates a fake Oxide install for branch with the given version.txt
+func writeInstall(t *testing.T, branch, version string) {
+	t.Helper()
+
+	managed := filepath.Join(GetOxidePath(branch), "RustDedicated_Data", "Managed")
+	if err := os.MkdirAll(managed, 0755); err != nil {
+		t.Fatalf("Failed to create install: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(managed, "Oxide.Rust.dll"), []byte("dll"), 0644); err != nil {
+		t.Fatalf("Failed to write Oxide.Rust.dll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(GetOxidePath(branch), "version.txt"), []byte(version), 0644); err != nil {
+		t.Fatalf("Failed to write version.txt: %v", err)
+	}
+}
+
+// oxideZip builds an Oxide release archive containing RustDedicated_Data/Managed/Oxide.Rust.dll
+func oxideZip(t *testing.T, contents string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("RustDedicated_Data/Managed/Oxide.Rust.dll")
+	if err != nil {
+		t.Fatalf("Failed to add zip entry: %v", err)
+	}
+	if _, err := w.Write([]byte(contents)); err != nil {
+		t.Fatalf("Failed to write zip contents: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// releaseServer serves the releases API and a zip per tag, recording every request.
+// The latest tag and each tag's archive can be changed while it runs.
+type releaseServer struct {
+	*httptest.Server
+
+	mutex     sync.Mutex
+	latest    string
+	archives  map[string][]byte
+	requested []string
+}
+
+func newReleaseServer(t *testing.T, latest string, archives map[string][]byte) *releaseServer {
+	t.Helper()
+
+	s := &releaseServer{latest: latest, archives: archives}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.requested = append(s.requested, r.URL.Path)
+		if r.URL.Path == "/releases/latest" {
+			fmt.Fprintf(w, `{"tag_name": %q}`, s.latest)
+			return
+		}
+		for tag, archive := range s.archives {
+			if r.URL.Path == "/download/"+tag+"/Oxide.Rust-linux.zip" {
+				w.Write(archive)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(s.Close)
+
+	origAPI, origURL := OxideReleasesAPI, OxideDownloadURL
+	t.Cleanup(func() { OxideReleasesAPI, OxideDownloadURL = origAPI, origURL })
+	OxideReleasesAPI = s.URL + "/releases/latest"
+	OxideDownloadURL = s.URL + "/download/%s/Oxide.Rust-linux.zip"
+	return s
+}
+
+// setLatest publishes a new latest release
+func (s *releaseServer) setLatest(tag string, archive []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.latest = tag
+	s.archives[tag] = archive
+}
+
+// takeRequests returns the requests made so far and forgets them
+func (s *releaseServer) takeRequests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	requested := s.requested
+	s.requested = nil
+	return requested
+}
+
+// installedVersion returns the version recorded for a branch's install
+func installedVersion(t *testing.T, branch string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(GetOxidePath(branch), "version.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read version.txt: %v", err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func TestCheckForOxideUpdates(t *testing.T) {
+	useTestOxideBase(t)
+	newReleaseServer(t, "v2.0.6500", map[string][]byte{})
+
+	tests := []struct {
+		name        string
+		installed   string
+		wantUpdate  bool
+		wantVersion string
+	}{
+		{"up to date", "v2.0.6500", false, "v2.0.6500"},
+		{"older release", "v2.0.6400", true, "v2.0.6500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeInstall(t, "main", tt.installed)
+
+			hasUpdate, version, err := CheckForOxideUpdates("main", notify.Discard)
+			if err != nil {
+				t.Fatalf("CheckForOxideUpdates() returned error: %v", err)
+			}
+			if hasUpdate != tt.wantUpdate || version != tt.wantVersion {
+				t.Errorf("CheckForOxideUpdates() = %v, %q, want %v, %q", hasUpdate, version, tt.wantUpdate, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestCheckForOxideUpdates_NotInstalled(t *testing.T) {
+	useTestOxideBase(t)
+	server := newReleaseServer(t, "v2.0.6500", map[string][]byte{})
+
+	hasUpdate, version, err := CheckForOxideUpdates("main", notify.Discard)
+	if err != nil || hasUpdate || version != "" {
+		t.Errorf("CheckForOxideUpdates() = %v, %q, %v, want false, \"\", nil", hasUpdate, version, err)
+	}
+	if requested := server.takeRequests(); len(requested) != 0 {
+		t.Errorf("queried %v for a branch without an install", requested)
+	}
+}
+
+func TestInstallOxide(t *testing.T) {
+	useTestOxideBase(t)
+	server := newReleaseServer(t, "v2.0.6400", map[string][]byte{"v2.0.6400": oxideZip(t, "6400")})
+
+	if err := EnsureOxideInstalled("", notify.Discard); err != nil {
+		t.Fatalf("EnsureOxideInstalled() returned error: %v", err)
+	}
+	if !IsBranchInstalled("main") {
+		t.Fatal("IsBranchInstalled() = false after installing")
+	}
+	if got := installedVersion(t, "main"); got != "v2.0.6400" {
+		t.Errorf("version.txt = %q, want %q", got, "v2.0.6400")
+	}
+
+	// The release is looked up once and its tag picks the archive
+	want := []string{"/releases/latest", "/download/v2.0.6400/Oxide.Rust-linux.zip"}
+	if requested := server.takeRequests(); strings.Join(requested, ",") != strings.Join(want, ",") {
+		t.Errorf("requested %v, want %v", requested, want)
+	}
+
+	// Already installed: nothing is downloaded again
+	if err := EnsureOxideInstalled("main", notify.Discard); err != nil {
+		t.Fatalf("EnsureOxideInstalled() returned error: %v", err)
+	}
+	if requested := server.takeRequests(); len(requested) != 0 {
+		t.Errorf("requested %v for a branch that is already installed", requested)
+	}
+
+	// A new release is installed and recorded under its own tag
+	server.setLatest("v2.0.6500", oxideZip(t, "6500"))
+	if err := InstallOxide("main", notify.Discard); err != nil {
+		t.Fatalf("InstallOxide() returned error: %v", err)
+	}
+	if got := installedVersion(t, "main"); got != "v2.0.6500" {
+		t.Errorf("version.txt = %q, want %q", got, "v2.0.6500")
+	}
+	dll, err := os.ReadFile(filepath.Join(GetOxidePath("main"), "RustDedicated_Data", "Managed", "Oxide.Rust.dll"))
+	if err != nil || string(dll) != "6500" {
+		t.Errorf("Oxide.Rust.dll = %q, %v, want the v2.0.6500 build", dll, err)
+	}
+}
+
+func TestInstallOxide_SameArchiveRecordsVersion(t *testing.T) {
+	useTestOxideBase(t)
+	archive := oxideZip(t, "same build")
+	server := newReleaseServer(t, "v2.0.6400", map[string][]byte{"v2.0.6400": archive})
+
+	if err := InstallOxide("main", notify.Discard); err != nil {
+		t.Fatalf("InstallOxide() returned error: %v", err)
+	}
+
+	// The release is re-tagged with an identical archive
+	server.setLatest("v2.0.6401", archive)
+	hasUpdate, _, err := CheckForOxideUpdates("main", notify.Discard)
+	if err != nil || !hasUpdate {
+		t.Fatalf("CheckForOxideUpdates() = %v, %v, want an update", hasUpdate, err)
+	}
+	if err := InstallOxide("main", notify.Discard); err != nil {
+		t.Fatalf("InstallOxide() returned error: %v", err)
+	}
+
+	// The new tag is recorded, so the update isn't offered again on the next cycle
+	if got := installedVersion(t, "main"); got != "v2.0.6401" {
+		t.Errorf("version.txt = %q, want %q", got, "v2.0.6401")
+	}
+	hasUpdate, version, err := CheckForOxideUpdates("main", notify.Discard)
+	if err != nil || hasUpdate {
+		t.Errorf("CheckForOxideUpdates() = %v, %q, %v after installing the same archive, want no update", hasUpdate, version, err)
+	}
+}
+
+func TestInstallOxide_DownloadFailureKeepsInstall(t *testing.T) {
+	useTestOxideBase(t)
+	newReleaseServer(t, "v2.0.6500", map[string][]byte{})
+	writeInstall(t, "main", "v2.0.6400")
+
+	if err := InstallOxide("main", notify.Discard); err == nil {
+		t.Fatal("InstallOxide() with a missing archive should return error")
+	}
+	if !IsBranchInstalled("main") {
+		t.Error("IsBranchInstalled() = false after a failed download, want the old install kept")
+	}
+	if got := installedVersion(t, "main"); got != "v2.0.6400" {
+		t.Errorf("version.txt = %q, want %q", got, "v2.0.6400")
+	}
+}
@@ -0,0 +1,108 @@
+package rcon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout bounds connecting to a server and waiting for a command's reply
+const DefaultTimeout = 5 * time.Second
+
+// Message is a WebRCON request or reply
+type Message struct {
+	Identifier int    `json:"Identifier"`
+	Message    string `json:"Message"`
+	Name       string `json:"Name,omitempty"`
+	Type       string `json:"Type,omitempty"`
+}
+
+// nextIdentifier numbers commands so their replies can be told apart from console output
+var nextIdentifier atomic.Int32
+
+// Client is a connection to a Rust server's WebRCON
+type Client struct {
+	ws      *wsConn
+	timeout time.Duration
+}
+
+// Dial connects to the WebRCON at address (host:port) with password.
+// Rust authenticates WebRCON by the password in the URL path, so a wrong password
+// fails the websocket handshake.
+func Dial(address, password string, timeout time.Duration) (*Client, error) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	u := &url.URL{Scheme: "ws", Host: address, Path: "/" + password}
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	ws, err := handshake(conn, u)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open WebRCON on %s: %w", address, err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	return &Client{ws: ws, timeout: timeout}, nil
+}
+
+// Command runs a console command and returns its reply, skipping console output sent meanwhile
+func (c *Client) Command(command string) (string, error) {
+	id := int(nextIdentifier.Add(1))
+	request, err := json.Marshal(Message{Identifier: id, Message: command, Name: "WebRcon"})
+	if err != nil {
+		return "", err
+	}
+
+	c.ws.conn.SetDeadline(time.Now().Add(c.timeout))
+	defer c.ws.conn.SetDeadline(time.Time{})
+
+	if err := c.ws.writeFrame(opText, request); err != nil {
+		return "", fmt.Errorf("failed to send command: %w", err)
+	}
+
+	for {
+		data, err := c.ws.readMessage()
+		if err != nil {
+			return "", fmt.Errorf("failed to read reply: %w", err)
+		}
+		var reply Message
+		if err := json.Unmarshal(data, &reply); err != nil {
+			continue // Not a WebRCON message
+		}
+		if reply.Identifier == id {
+			return reply.Message, nil
+		}
+	}
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.ws.close()
+}
+
+// Say broadcasts message in chat on the server at address
+func Say(address, password, message string) error {
+	client, err := Dial(address, password, DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	_, err = client.Command("say " + quote(message))
+	return err
+}
+
+// quote wraps a console argument in double quotes so it is passed as one argument
+func quote(arg string) string {
+	return `"` + strings.ReplaceAll(arg, `"`, `'`) + `"`
+}
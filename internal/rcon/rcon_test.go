@@ -0,0 +1,139 @@
+package rcon
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// mockServer is a WebRCON server that records commands and replies to each one,
+// sending a line of unrelated console output first
+type mockServer struct {
+	*httptest.Server
+	password string
+
+	mu       sync.Mutex
+	commands []string
+}
+
+func newMockServer(t *testing.T, password string) *mockServer {
+	t.Helper()
+
+	m := &mockServer{password: password}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.handle))
+	t.Cleanup(m.Close)
+	return m
+}
+
+// address returns the mock's host:port
+func (m *mockServer) address() string {
+	return strings.TrimPrefix(m.URL, "http://")
+}
+
+// received returns the commands received so far
+func (m *mockServer) received() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.commands...)
+}
+
+func (m *mockServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/"+m.password {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conn, rw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	rw.WriteString("Sec-WebSocket-Accept: " + acceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+	rw.Flush()
+
+	// Frames from the client are masked, which readFrame handles on either side
+	ws := &wsConn{conn: conn, r: bufio.NewReader(rw)}
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil || opcode == opClose {
+			return
+		}
+		if !fin || opcode != opText {
+			continue
+		}
+
+		var request Message
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return
+		}
+		m.mu.Lock()
+		m.commands = append(m.commands, request.Message)
+		m.mu.Unlock()
+
+		writeServerFrame(conn, Message{Identifier: -1, Message: "[CHAT] SERVER: unrelated", Type: "Chat"})
+		writeServerFrame(conn, Message{Identifier: request.Identifier, Message: "ok: " + request.Message, Type: "Generic"})
+	}
+}
+
+// writeServerFrame sends an unmasked text frame, as servers do
+func writeServerFrame(conn net.Conn, msg Message) {
+	payload, _ := json.Marshal(msg)
+	header := []byte{0x80 | opText}
+	if len(payload) < 126 {
+		header = append(header, byte(len(payload)))
+	} else {
+		header = append(header, 126, byte(len(payload)>>8), byte(len(payload)))
+	}
+	conn.Write(append(header, payload...))
+}
+
+func TestClientCommand(t *testing.T) {
+	server := newMockServer(t, "secret")
+
+	client, err := Dial(server.address(), "secret", 0)
+	if err != nil {
+		t.Fatalf("Dial() returned error: %v", err)
+	}
+	defer client.Close()
+
+	for _, command := range []string{"status", strings.Repeat("x", 200)} {
+		reply, err := client.Command(command)
+		if err != nil {
+			t.Fatalf("Command(%q) returned error: %v", command, err)
+		}
+		if want := "ok: " + command; reply != want {
+			t.Errorf("Command(%q) = %q, want %q", command, reply, want)
+		}
+	}
+}
+
+func TestSay(t *testing.T) {
+	server := newMockServer(t, "secret")
+
+	if err := Say(server.address(), "secret", `Wipe in "5 minutes"`); err != nil {
+		t.Fatalf("Say() returned error: %v", err)
+	}
+
+	want := []string{`say "Wipe in '5 minutes'"`}
+	if got := server.received(); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("server received %v, want %v", got, want)
+	}
+}
+
+func TestDial_WrongPassword(t *testing.T) {
+	server := newMockServer(t, "secret")
+
+	if _, err := Dial(server.address(), "wrong", 0); err == nil {
+		t.Error("Dial() with the wrong password succeeded, want error")
+	}
+	if got := server.received(); len(got) != 0 {
+		t.Errorf("server received %v, want nothing", got)
+	}
+}
@@ -1,8 +1,9 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"sort"
 	"strings"
 	"sync"
@@ -12,8 +13,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/maintc/wipe-cli/internal/calendar"
 	"github.com/maintc/wipe-cli/internal/config"
-	"github.com/maintc/wipe-cli/internal/discord"
 	"github.com/maintc/wipe-cli/internal/executor"
+	"github.com/maintc/wipe-cli/internal/logging"
+	"github.com/maintc/wipe-cli/internal/metrics"
+	"github.com/maintc/wipe-cli/internal/notify"
 )
 
 // ScheduledEvent represents an event with server context
@@ -28,16 +31,65 @@ type Scheduler struct {
 	gocron         gocron.Scheduler
 	events         []ScheduledEvent
 	lookaheadHours int
-	webhookURL     string
+	notifier       notify.Notifier
 	eventDelay     int
+	startStagger   int
+	staggerSize    int                         // Servers started together per stagger (0 or 1 starts one at a time)
+	wipeConfirm    int                         // Minutes to wait for 'wipe confirm' before wipes (0 disables)
+	minFreeMemory  int                         // Free memory (MB) required per server before starting (0 disables)
+	batchSize      int                         // Servers processed per wave of a batch (0 runs the whole batch at once)
+	rconWarnings   []int                       // Seconds before servers are stopped to warn players over RCON
+	rconMessage    string                      // RCON warning template
+	stepTimeout    time.Duration               // How long each batch step may run before it is killed (0 disables)
+	dryRun         bool                        // Log batches instead of executing them
+	recheckWipes   bool                        // Re-fetch the calendar after the event delay and cancel deleted wipes
 	scheduledJobs  map[string]uuid.UUID        // Track gocron job IDs by time key
 	jobEvents      map[string][]ScheduledEvent // Mutable event list per job (updated on calendar refresh)
 	executingJobs  map[string]bool             // Track which jobs are currently executing (by timeKey)
+	fetchFailures  map[string]int              // Consecutive calendar fetch failures by server path
+	oneOffEvents   []ScheduledEvent            // Ad-hoc events merged into every calendar update
+	firedJobs      map[string]bool             // Jobs that have already started (by timeKey), never re-armed
+	emptyJobAlerts map[string]bool             // Jobs that fired with no events and were reported (by timeKey)
+	cancelled      map[string]time.Time        // Events removed with CancelEvent (by cancelKey), left out of calendar updates
+	lastExecuted   map[string]time.Time        // When each job last started executing (by timeKey), kept after the job is removed
+	lastClockMono  time.Time                   // Monotonic reading at the last clock check
+	lastClockWall  time.Time                   // Wall-clock reading (monotonic stripped) at the last clock check
+	shuttingDown   bool                        // Set by Shutdown; jobs that fire afterwards are skipped
+	calendars      *calendar.Cache             // Parsed calendars revalidated with conditional requests
+	notifications  []func()                    // Notifications to send once mutex is released (see unlockAndNotify)
 	mutex          sync.Mutex
 }
 
-// New creates a new Scheduler
-func New(lookaheadHours int, webhookURL string, eventDelay int) (*Scheduler, error) {
+// calendarFailureAlertThreshold is how many consecutive fetch failures trigger an alert
+const calendarFailureAlertThreshold = 3
+
+// calendarFetchWorkers bounds how many calendars UpdateEvents fetches at once
+var calendarFetchWorkers = 8
+
+// rerunGuardWindow is how long after a job starts executing that another run for the same time key is skipped.
+// It covers jobs armed again after firedJobs was cleared, e.g. when a backward clock jump brings the event back.
+var rerunGuardWindow = 10 * time.Minute
+
+// executeEventBatch runs a batch; a variable so tests can count executions
+var executeEventBatch = executor.ExecuteEventBatch
+
+// drainPollInterval is how often Shutdown checks whether executing jobs have finished
+var drainPollInterval = 100 * time.Millisecond
+
+// sendWarning posts a warning through a notifier; a variable so tests can capture notifications
+var sendWarning = func(notifier notify.Notifier, title, description string) error {
+	return notifier.Warning(title, description)
+}
+
+const (
+	// clockJumpThreshold is how far wall-clock time may drift from monotonic time before jobs are re-armed
+	clockJumpThreshold = 1 * time.Minute
+	// missedEventGrace is how late an event skipped over by a clock jump may still run
+	missedEventGrace = 5 * time.Minute
+)
+
+// New creates a new Scheduler that sends notifications through notifier (nil to disable)
+func New(lookaheadHours int, notifier notify.Notifier, eventDelay int) (*Scheduler, error) {
 	gocronScheduler, err := gocron.NewScheduler()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gocron scheduler: %w", err)
@@ -47,12 +99,21 @@ func New(lookaheadHours int, webhookURL string, eventDelay int) (*Scheduler, err
 		gocron:         gocronScheduler,
 		events:         make([]ScheduledEvent, 0),
 		lookaheadHours: lookaheadHours,
-		webhookURL:     webhookURL,
+		notifier:       notify.OrDiscard(notifier),
 		eventDelay:     eventDelay,
 		scheduledJobs:  make(map[string]uuid.UUID),
 		jobEvents:      make(map[string][]ScheduledEvent),
 		executingJobs:  make(map[string]bool),
+		fetchFailures:  make(map[string]int),
+		firedJobs:      make(map[string]bool),
+		emptyJobAlerts: make(map[string]bool),
+		cancelled:      make(map[string]time.Time),
+		lastExecuted:   make(map[string]time.Time),
+		calendars:      calendar.NewCache(),
 	}
+	now := time.Now()
+	s.lastClockMono = now
+	s.lastClockWall = now.Round(0)
 
 	// Start the gocron scheduler
 	s.gocron.Start()
@@ -60,9 +121,108 @@ func New(lookaheadHours int, webhookURL string, eventDelay int) (*Scheduler, err
 	return s, nil
 }
 
-// Shutdown gracefully shuts down the scheduler
-func (s *Scheduler) Shutdown() error {
-	return s.gocron.Shutdown()
+// SetStartStagger sets the delay in seconds between starting each group of size servers in a batch
+func (s *Scheduler) SetStartStagger(seconds, size int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.startStagger = seconds
+	s.staggerSize = size
+}
+
+// SetWipeConfirmationMinutes sets how long wipe batches wait for manual confirmation (0 disables)
+func (s *Scheduler) SetWipeConfirmationMinutes(minutes int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.wipeConfirm = minutes
+}
+
+// SetMinFreeMemoryMB sets the free memory required per server before starting (0 disables)
+func (s *Scheduler) SetMinFreeMemoryMB(mb int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.minFreeMemory = mb
+}
+
+// SetBatchSize sets how many servers each wave of a batch stops, syncs, wipes and starts (0 runs all at once)
+func (s *Scheduler) SetBatchSize(size int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.batchSize = size
+}
+
+// SetRconWarnings sets when (seconds before servers are stopped) and how players are warned over RCON
+func (s *Scheduler) SetRconWarnings(seconds []int, message string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.rconWarnings = seconds
+	s.rconMessage = message
+}
+
+// SetStepTimeoutMinutes sets how long each stop, sync, hook and start step may run (0 disables)
+func (s *Scheduler) SetStepTimeoutMinutes(minutes int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.stepTimeout = time.Duration(minutes) * time.Minute
+}
+
+// SetRecheckCalendarBeforeWipe sets whether wipes re-check the calendar after the event delay
+func (s *Scheduler) SetRecheckCalendarBeforeWipe(enabled bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.recheckWipes = enabled
+}
+
+// SetDryRun sets whether batches are only logged instead of executed
+func (s *Scheduler) SetDryRun(dryRun bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.dryRun = dryRun
+}
+
+// SetOneOffEvents sets the ad-hoc events merged with calendar events on the next UpdateEvents
+func (s *Scheduler) SetOneOffEvents(events []ScheduledEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.oneOffEvents = events
+}
+
+// Shutdown stops new jobs from starting, waits up to timeout for executing jobs
+// (batches already stopping, syncing or wiping servers) to finish, then shuts down gocron.
+// Jobs still running at the deadline are logged and reported in the returned error.
+func (s *Scheduler) Shutdown(timeout time.Duration) error {
+	s.mutex.Lock()
+	s.shuttingDown = true
+	s.mutex.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	running := s.executingJobKeys()
+	if len(running) > 0 {
+		logging.Infof("Waiting up to %s for %d executing job(s) to finish: %s", timeout, len(running), strings.Join(running, ", "))
+	}
+	for len(running) > 0 && time.Now().Before(deadline) {
+		time.Sleep(drainPollInterval)
+		running = s.executingJobKeys()
+	}
+
+	shutdownErr := s.gocron.Shutdown()
+	if len(running) > 0 {
+		logging.Warnf("Warning: Jobs still executing at shutdown: %s", strings.Join(running, ", "))
+		return fmt.Errorf("timed out after %s waiting for executing jobs: %s", timeout, strings.Join(running, ", "))
+	}
+	return shutdownErr
+}
+
+// executingJobKeys returns the sorted time keys of the jobs currently executing
+func (s *Scheduler) executingJobKeys() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys := make([]string, 0, len(s.executingJobs))
+	for timeKey := range s.executingJobs {
+		keys = append(keys, timeKey)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // GetEvents returns a copy of the current events (thread-safe)
@@ -76,33 +236,133 @@ func (s *Scheduler) GetEvents() []ScheduledEvent {
 	return eventsCopy
 }
 
+// calendarFetchResult is the outcome of fetching and parsing one server's calendar
+type calendarFetchResult struct {
+	events   []calendar.Event
+	fetchErr error
+	parseErr error
+}
+
+// fetchCalendars fetches and parses every server's calendars (merging servers with several), at most calendarFetchWorkers at a time.
+// Each server looks lookaheadHours ahead unless it overrides lookahead_hours. Results are returned in the same order as servers.
+func fetchCalendars(cache *calendar.Cache, servers []config.Server, lookaheadHours int) []calendarFetchResult {
+	results := make([]calendarFetchResult, len(servers))
+	sem := make(chan struct{}, calendarFetchWorkers)
+	var wg sync.WaitGroup
+
+	for i, server := range servers {
+		wg.Add(1)
+		go func(i int, server config.Server) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			logging.Debugf("Fetching calendar for %s...", server.Name)
+			cals, err := cache.FetchAll(server.AllCalendarURLs(), server.CalendarAuthHeader, server.CalendarAuthToken)
+			if err != nil {
+				results[i].fetchErr = err
+				return
+			}
+			now := time.Now()
+			windowEnd := now.Add(time.Duration(server.GetLookaheadHours(lookaheadHours)) * time.Hour)
+			results[i].events, results[i].parseErr = calendar.GetEventsInCalendars(cals, now, windowEnd, server.MatchPatterns)
+		}(i, server)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// wipeRecheckWindow is how far either side of a wipe's time the re-check looks for the event
+const wipeRecheckWindow = time.Minute
+
+// fetchEventsInWindow fetches a server's calendars and returns their merged events between start and end
+func fetchEventsInWindow(cache *calendar.Cache, server config.Server, start, end time.Time) ([]calendar.Event, error) {
+	cals, err := cache.FetchAll(server.AllCalendarURLs(), server.CalendarAuthHeader, server.CalendarAuthToken)
+	if err != nil {
+		return nil, err
+	}
+	return calendar.GetEventsInCalendars(cals, start, end, server.MatchPatterns)
+}
+
+// cancelledWipes fetches the calendar of each wipe in events again and returns the paths of servers
+// whose wipe is no longer there. One-off events and calendars that fail to load are kept.
+func (s *Scheduler) cancelledWipes(events []ScheduledEvent) map[string]bool {
+	s.mutex.Lock()
+	oneOffs := append([]ScheduledEvent(nil), s.oneOffEvents...)
+	s.mutex.Unlock()
+
+	cancelled := make(map[string]bool)
+	for _, event := range events {
+		if !event.Event.Type.IsWipe() || isOneOff(event, oneOffs) {
+			continue
+		}
+
+		fetched, err := fetchEventsInWindow(s.calendars, event.Server, event.Scheduled.Add(-wipeRecheckWindow), event.Scheduled.Add(wipeRecheckWindow))
+		if err != nil {
+			logging.Warnf("Warning: Could not re-check calendar for %s, keeping wipe: %v", event.Server.Name, err)
+			continue
+		}
+
+		found := false
+		for _, e := range fetched {
+			if e.StartTime.Equal(event.Scheduled) && e.Type.IsWipe() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			logging.Infof("Wipe for %s at %s is no longer on the calendar", event.Server.Name, event.Scheduled.Format(time.RFC3339))
+			cancelled[event.Server.Path] = true
+		}
+	}
+	return cancelled
+}
+
+// isOneOff reports whether event came from the one-off events rather than a calendar
+func isOneOff(event ScheduledEvent, oneOffs []ScheduledEvent) bool {
+	for _, o := range oneOffs {
+		if o.Server.Path == event.Server.Path && o.Scheduled.Equal(event.Scheduled) && o.Event.Type == event.Event.Type {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateEvents fetches calendars and updates the schedule
 func (s *Scheduler) UpdateEvents(servers []config.Server) error {
+	logging.Infof("Updating calendar events...")
+
+	// Fetch without holding the lock so slow calendar hosts don't block the scheduler
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	lookaheadHours := s.lookaheadHours
+	s.mutex.Unlock()
 
-	log.Println("Updating calendar events...")
+	results := fetchCalendars(s.calendars, servers, lookaheadHours)
 
-	var allEvents []ScheduledEvent
+	s.mutex.Lock()
+	defer s.unlockAndNotify()
 
-	for _, server := range servers {
-		log.Printf("Fetching calendar for %s...", server.Name)
+	var allEvents []ScheduledEvent
 
-		cal, err := calendar.FetchCalendar(server.CalendarURL)
-		if err != nil {
-			log.Printf("Error fetching calendar for %s: %v", server.Name, err)
+	for i, server := range servers {
+		result := results[i]
+		if result.fetchErr != nil {
+			logging.Errorf("Error fetching calendar for %s: %v", server.Name, result.fetchErr)
+			s.recordFetchFailure(server, result.fetchErr)
+			metrics.RecordCalendarFetchError(server.Name)
 			continue
 		}
+		s.recordFetchSuccess(server)
 
-		events, err := calendar.GetUpcomingEvents(cal, s.lookaheadHours)
-		if err != nil {
-			log.Printf("Error parsing events for %s: %v", server.Name, err)
+		if result.parseErr != nil {
+			logging.Errorf("Error parsing events for %s: %v", server.Name, result.parseErr)
 			continue
 		}
 
-		log.Printf("Found %d upcoming event(s) for %s", len(events), server.Name)
+		logging.Infof("Found %d upcoming event(s) for %s", len(result.events), server.Name)
 
-		for _, event := range events {
+		for _, event := range result.events {
 			allEvents = append(allEvents, ScheduledEvent{
 				Server:    server,
 				Event:     event,
@@ -111,6 +371,31 @@ func (s *Scheduler) UpdateEvents(servers []config.Server) error {
 		}
 	}
 
+	// Merge one-off events that fall within their server's lookahead window
+	now := time.Now()
+	for _, event := range s.oneOffEvents {
+		windowEnd := now.Add(time.Duration(event.Server.GetLookaheadHours(s.lookaheadHours)) * time.Hour)
+		if event.Scheduled.After(now) && event.Scheduled.Before(windowEnd) {
+			allEvents = append(allEvents, event)
+		}
+	}
+
+	// Leave out events cancelled with CancelEvent, forgetting cancellations once their time has passed
+	for key, at := range s.cancelled {
+		if at.Before(now.Truncate(time.Minute)) {
+			delete(s.cancelled, key)
+		}
+	}
+	if len(s.cancelled) > 0 {
+		kept := allEvents[:0]
+		for _, event := range allEvents {
+			if _, cancelled := s.cancelled[cancelKey(event.Server.Path, event.Scheduled)]; !cancelled {
+				kept = append(kept, event)
+			}
+		}
+		allEvents = kept
+	}
+
 	// Resolve conflicts (same server, same time, wipe takes precedence)
 	allEvents = s.resolveConflicts(allEvents)
 
@@ -130,12 +415,60 @@ func (s *Scheduler) UpdateEvents(servers []config.Server) error {
 		return fmt.Errorf("failed to schedule jobs: %w", err)
 	}
 
-	log.Printf("Total scheduled events: %d", len(s.events))
+	logging.Infof("Total scheduled events: %d", len(s.events))
 	s.logUpcomingEvents()
+	metrics.RecordCalendarUpdate(time.Now(), len(s.events))
 
 	return nil
 }
 
+// recordFetchFailure counts consecutive calendar fetch failures and alerts once the threshold is reached
+func (s *Scheduler) recordFetchFailure(server config.Server, err error) {
+	s.fetchFailures[server.Path]++
+	if s.fetchFailures[server.Path] != calendarFailureAlertThreshold {
+		return
+	}
+
+	logging.Warnf("Calendar for %s has failed %d times in a row", server.Name, calendarFailureAlertThreshold)
+	s.queueNotification(func() {
+		s.notifier.Warning("Calendar Fetch Failing",
+			fmt.Sprintf("Calendar for **%s** has failed to fetch **%d** times in a row\n\n%v",
+				server.Name, calendarFailureAlertThreshold, err))
+	})
+}
+
+// recordFetchSuccess resets a server's fetch failure count, sending a recovery notice if it had been alerted on
+func (s *Scheduler) recordFetchSuccess(server config.Server) {
+	failures := s.fetchFailures[server.Path]
+	delete(s.fetchFailures, server.Path)
+	if failures < calendarFailureAlertThreshold {
+		return
+	}
+
+	logging.Infof("Calendar for %s recovered after %d failed fetch(es)", server.Name, failures)
+	s.queueNotification(func() {
+		s.notifier.Success("Calendar Fetch Recovered",
+			fmt.Sprintf("Calendar for **%s** is fetching again after **%d** failed attempt(s)", server.Name, failures))
+	})
+}
+
+// queueNotification records a notification to send once s.mutex is released, so a slow webhook
+// never holds up the scheduler or the batches waiting on it. Callers must hold s.mutex.
+func (s *Scheduler) queueNotification(send func()) {
+	s.notifications = append(s.notifications, send)
+}
+
+// unlockAndNotify releases s.mutex, then sends the notifications queued while it was held
+func (s *Scheduler) unlockAndNotify() {
+	pending := s.notifications
+	s.notifications = nil
+	s.mutex.Unlock()
+
+	for _, send := range pending {
+		send()
+	}
+}
+
 // resolveConflicts removes restart events if a wipe event exists at the same time
 func (s *Scheduler) resolveConflicts(events []ScheduledEvent) []ScheduledEvent {
 	// Group by server path and time
@@ -162,32 +495,52 @@ func (s *Scheduler) resolveConflicts(events []ScheduledEvent) []ScheduledEvent {
 			continue
 		}
 
-		// If multiple events at same time, prefer wipe over restart
+		// If multiple events at same time, prefer wipe over restart (and the most thorough wipe)
 		hasWipe := false
 		var wipeEvent ScheduledEvent
 
 		for _, event := range group {
-			if event.Event.Type == calendar.EventTypeWipe {
+			if event.Event.Type.IsWipe() && (!hasWipe || wipeRank(event.Event.Type) > wipeRank(wipeEvent.Event.Type)) {
 				hasWipe = true
 				wipeEvent = event
-				break
 			}
 		}
 
 		if hasWipe {
 			resolved = append(resolved, wipeEvent)
-			log.Printf("Conflict resolved: Wipe takes precedence for %s at %s",
+			logging.Infof("Conflict resolved: Wipe takes precedence for %s at %s",
 				wipeEvent.Server.Name, wipeEvent.Scheduled.Format(time.RFC3339))
-		} else {
-			// All restarts, just take the first one
-			resolved = append(resolved, group[0])
+			continue
 		}
+
+		// All restarts - a syncing restart takes precedence over restart-nosync
+		chosen := group[0]
+		for _, event := range group {
+			if event.Event.Type == calendar.EventTypeRestart {
+				chosen = event
+				break
+			}
+		}
+		resolved = append(resolved, chosen)
 	}
 
 	return resolved
 }
 
-// detectEventChanges compares old and new events and sends Discord notifications for changes
+// wipeRank orders wipe types by how much they delete, so conflicts keep the most thorough wipe
+func wipeRank(t calendar.EventType) int {
+	switch t {
+	case calendar.EventTypeMapOnly:
+		return 1
+	case calendar.EventTypeWipe:
+		return 2
+	case calendar.EventTypeWipeBlueprints, calendar.EventTypeFullWipe: // full-wipe is an alias for wipe-bp
+		return 3
+	}
+	return 0
+}
+
+// detectEventChanges compares old and new events and sends notifications for changes
 func (s *Scheduler) detectEventChanges(oldEvents, newEvents []ScheduledEvent) {
 	// Build maps for comparison using a unique key for each event
 	oldEventMap := make(map[string]ScheduledEvent)
@@ -221,67 +574,31 @@ func (s *Scheduler) detectEventChanges(oldEvents, newEvents []ScheduledEvent) {
 
 	// Send notifications for added events
 	if len(added) > 0 {
-		s.notifyEventsAdded(added)
+		s.queueNotification(func() { s.notifyEventsAdded(added) })
 	}
 
 	// Send notifications for removed events
 	if len(removed) > 0 {
-		s.notifyEventsRemoved(removed)
+		s.queueNotification(func() { s.notifyEventsRemoved(removed) })
 	}
 }
 
-// notifyEventsAdded sends Discord notification for newly added events
+// notifyEventsAdded sends a notification for newly added events
 func (s *Scheduler) notifyEventsAdded(events []ScheduledEvent) {
-	if s.webhookURL == "" {
-		return
-	}
-
-	// Group by event type
-	restarts := []string{}
-	wipes := []string{}
-
-	for _, event := range events {
-		timeStr := event.Scheduled.Format("Mon Jan 02 15:04 MST")
-		eventStr := fmt.Sprintf("%s at %s", event.Server.Name, timeStr)
-
-		if event.Event.Type == calendar.EventTypeWipe {
-			wipes = append(wipes, eventStr)
-		} else {
-			restarts = append(restarts, eventStr)
-		}
-	}
-
-	var description strings.Builder
-	description.WriteString(fmt.Sprintf("**%d** new event(s) scheduled:\n\n", len(events)))
-
-	if len(restarts) > 0 {
-		description.WriteString("**Restarts:**\n")
-		for _, r := range restarts {
-			description.WriteString(fmt.Sprintf("• %s\n", r))
-		}
-		if len(wipes) > 0 {
-			description.WriteString("\n")
-		}
-	}
-
-	if len(wipes) > 0 {
-		description.WriteString("**Wipes:**\n")
-		for _, w := range wipes {
-			description.WriteString(fmt.Sprintf("• %s\n", w))
-		}
-	}
-
-	log.Printf("Calendar events added: %d", len(events))
-	discord.SendSuccess(s.webhookURL, "Calendar Events Added", description.String())
+	logging.Infof("Calendar events added: %d", len(events))
+	notify.Send(s.notifier, notify.LevelSuccess, "Calendar Events Added",
+		fmt.Sprintf("**%d** new event(s) scheduled", len(events)), eventFields(events)...)
 }
 
-// notifyEventsRemoved sends Discord notification for removed events
+// notifyEventsRemoved sends a notification for removed events
 func (s *Scheduler) notifyEventsRemoved(events []ScheduledEvent) {
-	if s.webhookURL == "" {
-		return
-	}
+	logging.Infof("Calendar events removed: %d", len(events))
+	notify.Send(s.notifier, notify.LevelWarning, "Calendar Events Removed",
+		fmt.Sprintf("**%d** event(s) removed", len(events)), eventFields(events)...)
+}
 
-	// Group by event type
+// eventFields returns the notification fields listing events as restarts and wipes
+func eventFields(events []ScheduledEvent) []notify.Field {
 	restarts := []string{}
 	wipes := []string{}
 
@@ -289,48 +606,48 @@ func (s *Scheduler) notifyEventsRemoved(events []ScheduledEvent) {
 		timeStr := event.Scheduled.Format("Mon Jan 02 15:04 MST")
 		eventStr := fmt.Sprintf("%s at %s", event.Server.Name, timeStr)
 
-		if event.Event.Type == calendar.EventTypeWipe {
+		if event.Event.Type.IsWipe() {
+			if event.Event.Type != calendar.EventTypeWipe {
+				eventStr += fmt.Sprintf(" (%s)", event.Event.Type)
+			}
 			wipes = append(wipes, eventStr)
 		} else {
 			restarts = append(restarts, eventStr)
 		}
 	}
 
-	var description strings.Builder
-	description.WriteString(fmt.Sprintf("**%d** event(s) removed:\n\n", len(events)))
-
-	if len(restarts) > 0 {
-		description.WriteString("**Restarts:**\n")
-		for _, r := range restarts {
-			description.WriteString(fmt.Sprintf("• %s\n", r))
-		}
-		if len(wipes) > 0 {
-			description.WriteString("\n")
-		}
-	}
-
-	if len(wipes) > 0 {
-		description.WriteString("**Wipes:**\n")
-		for _, w := range wipes {
-			description.WriteString(fmt.Sprintf("• %s\n", w))
-		}
+	return []notify.Field{
+		notify.ListField("Restarts", restarts),
+		notify.ListField("Wipes", wipes),
 	}
-
-	log.Printf("Calendar events removed: %d", len(events))
-	discord.SendWarning(s.webhookURL, "Calendar Events Removed", description.String())
 }
 
-// logUpcomingEvents prints a summary of upcoming events
+// maxLoggedEvents is how many upcoming events or jobs are logged one per line before the rest are summarized
+const maxLoggedEvents = 50
+
+// logUpcomingEvents prints a summary of upcoming events, counting those past the first maxLoggedEvents
 func (s *Scheduler) logUpcomingEvents() {
 	if len(s.events) == 0 {
-		log.Println("No upcoming events in the next", s.lookaheadHours, "hours")
+		logging.Infof("No upcoming events in the next %d hours", s.lookaheadHours)
 		return
 	}
 
-	log.Println("Upcoming events:")
-	for _, event := range s.events {
+	logging.Infof("Upcoming events:")
+	for i, event := range s.events {
+		if i == maxLoggedEvents {
+			rest := s.events[i:]
+			wipes := 0
+			for _, e := range rest {
+				if e.Event.Type.IsWipe() {
+					wipes++
+				}
+			}
+			logging.Infof("  ... and %d more (%d restart(s), %d wipe(s)) through %s",
+				len(rest), len(rest)-wipes, wipes, rest[len(rest)-1].Scheduled.Format("Mon Jan 02 15:04 MST"))
+			break
+		}
 		timeUntil := time.Until(event.Scheduled).Round(time.Minute)
-		log.Printf("  %s - %s [%s] (in %s)",
+		logging.Infof("  %s - %s [%s] (in %s)",
 			event.Scheduled.Format("Mon Jan 02 15:04 MST"),
 			event.Server.Name,
 			event.Event.Type,
@@ -338,14 +655,15 @@ func (s *Scheduler) logUpcomingEvents() {
 	}
 }
 
-// scheduleJobs groups events by time and creates gocron jobs for each time-group
+// scheduleJobs groups events by time and creates gocron jobs for each time-group.
+// With more than maxLoggedEvents groups, each job is logged at debug level and a total at info.
 func (s *Scheduler) scheduleJobs() error {
 	// Group events by time (truncated to minute)
 	eventGroups := make(map[string][]ScheduledEvent)
 	timeKeys := make(map[string]time.Time)
 
 	for _, event := range s.events {
-		timeKey := event.Scheduled.Truncate(time.Minute).Format(time.RFC3339)
+		timeKey := jobTimeKey(event.Scheduled)
 		eventGroups[timeKey] = append(eventGroups[timeKey], event)
 		if _, exists := timeKeys[timeKey]; !exists {
 			timeKeys[timeKey] = event.Scheduled.Truncate(time.Minute)
@@ -358,13 +676,19 @@ func (s *Scheduler) scheduleJobs() error {
 		currentTimeKeys[timeKey] = true
 	}
 
+	logJob := logging.Infof
+	if len(eventGroups) > maxLoggedEvents {
+		logJob = logging.Debugf
+	}
+	scheduled, updated := 0, 0
+
 	// Update event lists for existing jobs AND schedule new jobs
 	for timeKey, events := range eventGroups {
 		scheduleTime := timeKeys[timeKey]
 
 		// Skip events in the past
 		if scheduleTime.Before(time.Now()) {
-			log.Printf("Skipping past event at %s", timeKey)
+			logging.Debugf("Skipping past event at %s", timeKey)
 			continue
 		}
 
@@ -376,8 +700,9 @@ func (s *Scheduler) scheduleJobs() error {
 		if _, exists := s.scheduledJobs[timeKey]; exists {
 			// Job exists - UPDATE the event list (allows add/remove of individual servers)
 			s.jobEvents[timeKey] = eventsCopy
-			log.Printf("Updated event list for %s (%d server(s))",
+			logJob("Updated event list for %s (%d server(s))",
 				scheduleTime.Format("Mon Jan 02 15:04 MST"), len(events))
+			updated++
 			continue
 		}
 
@@ -385,47 +710,15 @@ func (s *Scheduler) scheduleJobs() error {
 		// Store the event list
 		s.jobEvents[timeKey] = eventsCopy
 
-		// Schedule one job for this time-group
-		// Pass timeKey so we can look up current events at execution time
-		tk := timeKey // Capture for closure
-		job, err := s.gocron.NewJob(
-			gocron.OneTimeJob(
-				gocron.OneTimeJobStartDateTime(scheduleTime),
-			),
-			gocron.NewTask(
-				func() {
-					// Mark as executing IMMEDIATELY to prevent cancellation during UpdateEvents
-					s.mutex.Lock()
-					s.executingJobs[tk] = true
-					currentEvents, exists := s.jobEvents[tk]
-					s.mutex.Unlock()
-
-					// Ensure we remove the executing mark when done
-					defer func() {
-						s.mutex.Lock()
-						delete(s.executingJobs, tk)
-						s.mutex.Unlock()
-					}()
-
-					if !exists || len(currentEvents) == 0 {
-						log.Printf("No events found for %s at execution time, skipping", tk)
-						return
-					}
-
-					// Execute without re-marking (already marked above)
-					s.executeEventGroupInternal(currentEvents)
-				},
-			),
-			gocron.WithSingletonMode(gocron.LimitModeReschedule),
-		)
-
-		if err != nil {
-			return fmt.Errorf("failed to schedule job for %s: %w", timeKey, err)
+		if err := s.armJob(timeKey, gocron.OneTimeJobStartDateTime(scheduleTime)); err != nil {
+			return err
 		}
-
-		s.scheduledJobs[timeKey] = job.ID()
-		log.Printf("Scheduled job for %s (%d server(s))",
+		logJob("Scheduled job for %s (%d server(s))",
 			scheduleTime.Format("Mon Jan 02 15:04 MST"), len(events))
+		scheduled++
+	}
+	if len(eventGroups) > maxLoggedEvents {
+		logging.Infof("Scheduled %d job(s) and updated %d for %d event(s)", scheduled, updated, len(s.events))
 	}
 
 	// Cancel jobs that are no longer needed (timeKey completely gone)
@@ -434,22 +727,277 @@ func (s *Scheduler) scheduleJobs() error {
 			// Check if this job is currently executing
 			// Never cancel a job that's in progress
 			if s.executingJobs[timeKey] {
-				log.Printf("Keeping job for %s (currently executing)", timeKey)
+				logging.Debugf("Keeping job for %s (currently executing)", timeKey)
 				continue
 			}
 
 			if err := s.gocron.RemoveJob(jobID); err != nil {
-				log.Printf("Warning: failed to remove job for %s: %v", timeKey, err)
+				logging.Warnf("Warning: failed to remove job for %s: %v", timeKey, err)
 			}
 			delete(s.scheduledJobs, timeKey)
 			delete(s.jobEvents, timeKey)
-			log.Printf("Cancelled job for time: %s", timeKey)
+			delete(s.firedJobs, timeKey)
+			delete(s.emptyJobAlerts, timeKey)
+			logging.Infof("Cancelled job for time: %s", timeKey)
 		}
 	}
 
 	return nil
 }
 
+// jobTimeKey returns the key of the job an event at t is grouped into (its time truncated to the minute)
+func jobTimeKey(t time.Time) string {
+	return t.Truncate(time.Minute).Format(time.RFC3339)
+}
+
+// cancelKey identifies a server's event for CancelEvent
+func cancelKey(serverPath string, t time.Time) string {
+	return serverPath + "@" + jobTimeKey(t)
+}
+
+var (
+	// ErrEventNotFound is returned by CancelEvent when the server has no scheduled event at that time
+	ErrEventNotFound = errors.New("no scheduled event for that server and time")
+	// ErrEventStarted is returned by CancelEvent when the event's job is executing or has already run
+	ErrEventStarted = errors.New("event has already started")
+)
+
+// CancelEvent removes a server's event at eventTime (matched to the minute) from its job. The job is
+// cancelled when no servers remain; a job that is executing or has already run is never touched.
+// The cancellation is remembered so calendar updates don't schedule the event again. It is recorded
+// even when the event isn't scheduled yet (ErrEventNotFound), e.g. because it is beyond the lookahead.
+func (s *Scheduler) CancelEvent(serverPath string, eventTime time.Time) error {
+	s.mutex.Lock()
+	defer s.unlockAndNotify()
+
+	timeKey := jobTimeKey(eventTime)
+	if s.executingJobs[timeKey] || s.firedJobs[timeKey] {
+		return fmt.Errorf("%w: job for %s", ErrEventStarted, timeKey)
+	}
+
+	key := cancelKey(serverPath, eventTime)
+	if _, exists := s.cancelled[key]; exists {
+		return nil
+	}
+	s.cancelled[key] = eventTime.Truncate(time.Minute)
+
+	var removed []ScheduledEvent
+	kept := make([]ScheduledEvent, 0, len(s.jobEvents[timeKey]))
+	for _, event := range s.jobEvents[timeKey] {
+		if event.Server.Path == serverPath {
+			removed = append(removed, event)
+			continue
+		}
+		kept = append(kept, event)
+	}
+	if len(removed) == 0 {
+		return ErrEventNotFound
+	}
+
+	events := make([]ScheduledEvent, 0, len(s.events))
+	for _, event := range s.events {
+		if event.Server.Path != serverPath || jobTimeKey(event.Scheduled) != timeKey {
+			events = append(events, event)
+		}
+	}
+	s.events = events
+
+	if len(kept) > 0 {
+		s.jobEvents[timeKey] = kept
+		logging.Infof("Cancelled %s for %s at %s (%d server(s) left in the batch)", removed[0].Event.Type, removed[0].Server.Name, timeKey, len(kept))
+	} else {
+		if jobID, exists := s.scheduledJobs[timeKey]; exists {
+			if err := s.gocron.RemoveJob(jobID); err != nil {
+				logging.Warnf("Warning: failed to remove job for %s: %v", timeKey, err)
+			}
+		}
+		delete(s.scheduledJobs, timeKey)
+		delete(s.jobEvents, timeKey)
+		delete(s.emptyJobAlerts, timeKey)
+		logging.Infof("Cancelled %s for %s and the job for %s", removed[0].Event.Type, removed[0].Server.Name, timeKey)
+	}
+
+	s.queueNotification(func() {
+		s.notifier.Warning("Event Cancelled",
+			fmt.Sprintf("%s for **%s** at **%s** was cancelled",
+				removed[0].Event.Type, removed[0].Server.Name, removed[0].Scheduled.Format("Mon Jan 02 15:04 MST")))
+	})
+	return nil
+}
+
+// armJob creates the gocron one-time job for a time-group
+// The job looks up the current event list for timeKey at execution time
+func (s *Scheduler) armJob(timeKey string, startAt gocron.OneTimeJobStartAtOption) error {
+	tk := timeKey // Capture for closure
+	job, err := s.gocron.NewJob(
+		gocron.OneTimeJob(startAt),
+		gocron.NewTask(func() { s.runJob(tk) }),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to schedule job for %s: %w", timeKey, err)
+	}
+
+	s.scheduledJobs[timeKey] = job.ID()
+	return nil
+}
+
+// runJob is the task of the job for timeKey: it executes the time key's current event list
+func (s *Scheduler) runJob(timeKey string) {
+	// Mark as executing IMMEDIATELY to prevent cancellation during UpdateEvents
+	s.mutex.Lock()
+	if s.firedJobs[timeKey] {
+		// A re-armed job after a clock jump must never run the same group twice
+		s.mutex.Unlock()
+		logging.Infof("Job for %s already ran, skipping", timeKey)
+		return
+	}
+	if last, ran := s.lastExecuted[timeKey]; ran && time.Since(last) < rerunGuardWindow {
+		// The job was armed again after it ran (its time key dropped out and came back)
+		s.mutex.Unlock()
+		logging.Warnf("Job for %s already executed %s ago, skipping", timeKey, time.Since(last).Round(time.Second))
+		return
+	}
+	if s.shuttingDown {
+		s.mutex.Unlock()
+		logging.Infof("Scheduler shutting down, skipping job for %s", timeKey)
+		return
+	}
+	s.firedJobs[timeKey] = true
+	s.executingJobs[timeKey] = true
+	now := time.Now()
+	for key, last := range s.lastExecuted {
+		if now.Sub(last) >= rerunGuardWindow {
+			delete(s.lastExecuted, key)
+		}
+	}
+	s.lastExecuted[timeKey] = now
+	currentEvents, exists := s.jobEvents[timeKey]
+	s.mutex.Unlock()
+
+	// Ensure we remove the executing mark when done
+	defer func() {
+		s.mutex.Lock()
+		delete(s.executingJobs, timeKey)
+		s.mutex.Unlock()
+	}()
+
+	if !exists || len(currentEvents) == 0 {
+		logging.Infof("No events found for %s at execution time, skipping", timeKey)
+		s.notifyEmptyJob(timeKey, exists)
+		return
+	}
+
+	// Execute without re-marking (already marked above)
+	s.executeEventGroupInternal(currentEvents)
+}
+
+// notifyEmptyJob warns that a job fired with no events left to run, once per time key
+func (s *Scheduler) notifyEmptyJob(timeKey string, hadEventList bool) {
+	s.mutex.Lock()
+	if s.emptyJobAlerts[timeKey] {
+		s.mutex.Unlock()
+		return
+	}
+	s.emptyJobAlerts[timeKey] = true
+	notifier := s.notifier
+	s.mutex.Unlock()
+
+	scheduled := timeKey
+	if t, err := time.Parse(time.RFC3339, timeKey); err == nil {
+		scheduled = t.Format("Mon Jan 02 15:04 MST")
+	}
+
+	reason := "Its events were removed from the calendar after the job was scheduled."
+	if !hadEventList {
+		reason = "The job had no event list at all, which usually means it was cancelled while it was firing."
+	}
+
+	sendWarning(notifier, "Scheduled Event Skipped",
+		fmt.Sprintf("The batch scheduled for **%s** fired but had no events left to run, so nothing was executed.\n\n%s\n\nTime key: `%s`", scheduled, reason, timeKey))
+}
+
+// CheckClockJump detects wall-clock jumps (VM pause/resume, NTP step) and re-arms pending jobs
+// gocron timers run on the monotonic clock, so after a jump they no longer fire at the intended wall time
+func (s *Scheduler) CheckClockJump() {
+	s.mutex.Lock()
+	defer s.unlockAndNotify()
+
+	now := time.Now()
+	jump := now.Round(0).Sub(s.lastClockWall) - now.Sub(s.lastClockMono)
+	s.lastClockMono = now
+	s.lastClockWall = now.Round(0)
+
+	if jump > -clockJumpThreshold && jump < clockJumpThreshold {
+		return
+	}
+
+	logging.Warnf("Detected system clock jump of %s, reconciling scheduled jobs", jump.Round(time.Second))
+	s.queueNotification(func() {
+		s.notifier.Warning("Clock Jump Detected",
+			fmt.Sprintf("System clock jumped by **%s**\n\nScheduled events have been re-armed against the new time", jump.Round(time.Second)))
+	})
+
+	s.reconcileJobs(now)
+}
+
+// reconcileJobs re-arms every pending job against the current wall clock
+// Events skipped over by a forward jump run immediately if within missedEventGrace, otherwise they are dropped
+func (s *Scheduler) reconcileJobs(now time.Time) {
+	for timeKey, jobID := range s.scheduledJobs {
+		if s.executingJobs[timeKey] || s.firedJobs[timeKey] {
+			continue
+		}
+
+		scheduleTime, err := time.Parse(time.RFC3339, timeKey)
+		if err != nil {
+			logging.Warnf("Warning: invalid job time key %s: %v", timeKey, err)
+			continue
+		}
+
+		if err := s.gocron.RemoveJob(jobID); err != nil {
+			logging.Warnf("Warning: failed to remove job for %s: %v", timeKey, err)
+		}
+		delete(s.scheduledJobs, timeKey)
+
+		var startAt gocron.OneTimeJobStartAtOption
+		switch {
+		case scheduleTime.After(now):
+			startAt = gocron.OneTimeJobStartDateTime(scheduleTime)
+		case now.Sub(scheduleTime) <= missedEventGrace:
+			logging.Infof("Running event for %s missed during clock jump", timeKey)
+			startAt = gocron.OneTimeJobStartImmediately()
+		default:
+			logging.Warnf("Dropping event for %s missed during clock jump", timeKey)
+			s.queueNotification(func() {
+				s.notifier.Warning("Event Skipped",
+					fmt.Sprintf("Event scheduled for **%s** was skipped because the system clock jumped past it",
+						scheduleTime.Format("Mon Jan 02 15:04 MST")))
+			})
+			delete(s.jobEvents, timeKey)
+			continue
+		}
+
+		if err := s.armJob(timeKey, startAt); err != nil {
+			logging.Errorf("Error re-arming job for %s: %v", timeKey, err)
+			continue
+		}
+		logging.Infof("Re-armed job for %s", timeKey)
+	}
+}
+
+// batchEventDelay returns the event delay for a batch: the longest delay of its servers, each using
+// its own event_delay or global. A single-server batch therefore waits that server's delay.
+func batchEventDelay(servers []config.Server, global int) int {
+	delay := 0
+	for _, server := range servers {
+		if d := server.GetEventDelay(global); d > delay {
+			delay = d
+		}
+	}
+	return delay
+}
+
 // executeEventGroupInternal performs the actual event execution
 // Note: The gocron job closure handles marking executingJobs before calling this
 func (s *Scheduler) executeEventGroupInternal(events []ScheduledEvent) {
@@ -460,17 +1008,44 @@ func (s *Scheduler) executeEventGroupInternal(events []ScheduledEvent) {
 	// Process all events together (restarts and wipes in single batch)
 	// Extract all servers
 	servers := make([]config.Server, len(events))
-	wipeServers := make(map[string]bool) // Track which servers need wipe
+	wipeServers := make(map[string]executor.WipeMode) // Track which servers need wipe, and which files
+	noSyncServers := make(map[string]bool)            // Track which servers skip the Rust/Carbon sync
 
 	for i, event := range events {
 		servers[i] = event.Server
-		if event.Event.Type == calendar.EventTypeWipe {
-			wipeServers[event.Server.Path] = true
+		switch event.Event.Type {
+		case calendar.EventTypeWipe:
+			wipeServers[event.Server.Path] = executor.WipeStandard
+		case calendar.EventTypeWipeBlueprints, calendar.EventTypeFullWipe: // full-wipe is an alias for wipe-bp
+			wipeServers[event.Server.Path] = executor.WipeWithBlueprints
+		case calendar.EventTypeMapOnly:
+			wipeServers[event.Server.Path] = executor.WipeMapOnly
+		case calendar.EventTypeRestartNoSync:
+			noSyncServers[event.Server.Path] = true
 		}
 	}
 
-	// Execute all servers together, passing which ones need wipes
-	if err := executor.ExecuteEventBatch(servers, wipeServers, s.webhookURL, s.eventDelay); err != nil {
-		log.Printf("Error executing event group: %v", err)
+	s.mutex.Lock()
+	opts := executor.BatchOptions{
+		Notifier:                s.notifier,
+		EventDelay:              batchEventDelay(servers, s.eventDelay),
+		StartStagger:            s.startStagger,
+		StartStaggerSize:        s.staggerSize,
+		WipeConfirmationMinutes: s.wipeConfirm,
+		MinFreeMemoryMB:         s.minFreeMemory,
+		BatchSize:               s.batchSize,
+		RconWarnings:            s.rconWarnings,
+		RconWarningMessage:      s.rconMessage,
+		StepTimeout:             s.stepTimeout,
+		DryRun:                  s.dryRun,
+	}
+	if s.recheckWipes {
+		opts.RecheckWipes = func() map[string]bool { return s.cancelledWipes(events) }
+	}
+	s.mutex.Unlock()
+
+	// Execute all servers together, passing which ones need wipes or skip syncing
+	if err := executeEventBatch(context.Background(), servers, wipeServers, noSyncServers, opts); err != nil {
+		logging.Errorf("Error executing event group: %v", err)
 	}
 }
@@ -1,20 +1,37 @@
 package scheduler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
 	"github.com/maintc/wipe-cli/internal/calendar"
 	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/discord"
+	"github.com/maintc/wipe-cli/internal/executor"
+	"github.com/maintc/wipe-cli/internal/metrics"
+	"github.com/maintc/wipe-cli/internal/notify"
 )
 
 func TestNewScheduler(t *testing.T) {
-	s, err := New(48, "https://example.com", 60)
+	notifier := discord.Webhook{URL: "https://example.com"}
+	s, err := New(48, notifier, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	if s == nil {
 		t.Fatal("New() returned nil")
@@ -24,8 +41,8 @@ func TestNewScheduler(t *testing.T) {
 		t.Errorf("lookaheadHours = %d, want 48", s.lookaheadHours)
 	}
 
-	if s.webhookURL != "https://example.com" {
-		t.Errorf("webhookURL = %s, want https://example.com", s.webhookURL)
+	if s.notifier != notify.Notifier(notifier) {
+		t.Errorf("notifier = %v, want %v", s.notifier, notifier)
 	}
 
 	if s.eventDelay != 60 {
@@ -70,11 +87,11 @@ func TestScheduledEventKey(t *testing.T) {
 }
 
 func TestGetEvents(t *testing.T) {
-	s, err := New(48, "", 60)
+	s, err := New(48, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	// Should start with empty events
 	events := s.GetEvents()
@@ -145,11 +162,11 @@ func TestGroupEventsByTime(t *testing.T) {
 }
 
 func TestEventDuplication(t *testing.T) {
-	s, err := New(48, "", 60)
+	s, err := New(48, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	now := time.Now()
 
@@ -168,11 +185,11 @@ func TestEventDuplication(t *testing.T) {
 }
 
 func TestSchedulerThreadSafety(t *testing.T) {
-	s, err := New(48, "", 60)
+	s, err := New(48, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	// Test concurrent reads
 	done := make(chan bool)
@@ -189,11 +206,11 @@ func TestSchedulerThreadSafety(t *testing.T) {
 }
 
 func TestResolveConflicts_WipeTakesPrecedence(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	now := time.Now().Truncate(time.Minute)
 
@@ -222,11 +239,11 @@ func TestResolveConflicts_WipeTakesPrecedence(t *testing.T) {
 }
 
 func TestResolveConflicts_NoConflict(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	now := time.Now().Truncate(time.Minute)
 
@@ -251,11 +268,11 @@ func TestResolveConflicts_NoConflict(t *testing.T) {
 }
 
 func TestResolveConflicts_DifferentTimes(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	now := time.Now().Truncate(time.Minute)
 
@@ -280,11 +297,11 @@ func TestResolveConflicts_DifferentTimes(t *testing.T) {
 }
 
 func TestResolveConflicts_EmptyEvents(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	events := []ScheduledEvent{}
 	resolved := s.resolveConflicts(events)
@@ -295,11 +312,11 @@ func TestResolveConflicts_EmptyEvents(t *testing.T) {
 }
 
 func TestResolveConflicts_MultipleRestarts(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	now := time.Now().Truncate(time.Minute)
 
@@ -325,28 +342,83 @@ func TestResolveConflicts_MultipleRestarts(t *testing.T) {
 }
 
 func TestSchedulerShutdown(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
 
 	// Shutdown should not return error
-	if err := s.Shutdown(); err != nil {
+	if err := s.Shutdown(time.Second); err != nil {
 		t.Errorf("Shutdown() returned error: %v", err)
 	}
 
 	// Multiple shutdowns should be safe
-	if err := s.Shutdown(); err != nil {
+	if err := s.Shutdown(time.Second); err != nil {
 		t.Errorf("Second Shutdown() returned error: %v", err)
 	}
 }
 
+func TestSchedulerShutdown_WaitsForExecutingJobs(t *testing.T) {
+	origPoll := drainPollInterval
+	defer func() { drainPollInterval = origPoll }()
+	drainPollInterval = 10 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		jobTime  time.Duration // How long the simulated batch keeps running
+		timeout  time.Duration
+		wantErr  bool
+		minDelay time.Duration
+	}{
+		{"job finishes before the deadline", 150 * time.Millisecond, 2 * time.Second, false, 150 * time.Millisecond},
+		{"job outlives the deadline", 2 * time.Second, 100 * time.Millisecond, true, 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(24, nil, 60)
+			if err != nil {
+				t.Fatalf("New() returned error: %v", err)
+			}
+
+			// Simulate a batch that is mid-execution, the way armJob marks it
+			timeKey := "2025-06-01T20:00:00Z"
+			s.mutex.Lock()
+			s.executingJobs[timeKey] = true
+			s.mutex.Unlock()
+			go func() {
+				time.Sleep(tt.jobTime)
+				s.mutex.Lock()
+				delete(s.executingJobs, timeKey)
+				s.mutex.Unlock()
+			}()
+
+			start := time.Now()
+			err = s.Shutdown(tt.timeout)
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Shutdown() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), timeKey)) {
+				t.Errorf("Shutdown() error = %v, want it to name %s", err, timeKey)
+			}
+			if elapsed < tt.minDelay {
+				t.Errorf("Shutdown() returned after %v, want at least %v", elapsed, tt.minDelay)
+			}
+			if elapsed > tt.timeout+time.Second {
+				t.Errorf("Shutdown() returned after %v, past the %v deadline", elapsed, tt.timeout)
+			}
+		})
+	}
+}
+
 func TestGetEvents_ReturnsCopy(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	now := time.Now()
 	testEvent := ScheduledEvent{
@@ -391,18 +463,18 @@ func TestSchedulerConfiguration_DifferentValues(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s, err := New(tt.lookaheadHours, tt.webhookURL, tt.eventDelay)
+			s, err := New(tt.lookaheadHours, discord.Webhook{URL: tt.webhookURL}, tt.eventDelay)
 			if err != nil {
 				t.Fatalf("New() returned error: %v", err)
 			}
-			defer s.Shutdown()
+			defer s.Shutdown(0)
 
 			if s.lookaheadHours != tt.lookaheadHours {
 				t.Errorf("lookaheadHours = %d, want %d", s.lookaheadHours, tt.lookaheadHours)
 			}
 
-			if s.webhookURL != tt.webhookURL {
-				t.Errorf("webhookURL = %s, want %s", s.webhookURL, tt.webhookURL)
+			if s.notifier != notify.Notifier(discord.Webhook{URL: tt.webhookURL}) {
+				t.Errorf("notifier = %v, want Discord webhook %s", s.notifier, tt.webhookURL)
 			}
 
 			if s.eventDelay != tt.eventDelay {
@@ -492,11 +564,11 @@ func TestScheduledEvent_FieldAccess(t *testing.T) {
 }
 
 func TestResolveConflicts_LargeScale(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	now := time.Now().Truncate(time.Minute)
 
@@ -534,11 +606,11 @@ func TestResolveConflicts_LargeScale(t *testing.T) {
 }
 
 func TestGetEvents_Concurrency(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	// Add some test data
 	now := time.Now()
@@ -570,11 +642,11 @@ func TestGetEvents_Concurrency(t *testing.T) {
 
 // TestRaceConditionPrevention verifies the exact scenario from Nov 16 cannot happen again
 func TestRaceConditionPrevention(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	// Setup: 5 servers with events scheduled for 2 minutes from now
 	eventTime := time.Now().Add(2 * time.Minute).Truncate(time.Minute)
@@ -651,11 +723,11 @@ func TestRaceConditionPrevention(t *testing.T) {
 
 // TestJobImmutability verifies that once a gocron job is scheduled, it cannot be affected by s.events changes
 func TestJobImmutability(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	eventTime := time.Now().Add(1 * time.Minute).Truncate(time.Minute)
 
@@ -700,11 +772,11 @@ func TestJobImmutability(t *testing.T) {
 
 // TestJobPersistenceDuringCalendarUpdate tests that jobs persist when events remain in calendar
 func TestJobPersistenceDuringCalendarUpdate(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	eventTime := time.Now().Add(2 * time.Minute).Truncate(time.Minute)
 
@@ -760,11 +832,11 @@ func TestJobPersistenceDuringCalendarUpdate(t *testing.T) {
 
 // TestMultipleCalendarUpdatesBeforeExecution simulates rapid calendar updates before event time
 func TestMultipleCalendarUpdatesBeforeExecution(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	eventTime := time.Now().Add(5 * time.Minute).Truncate(time.Minute)
 
@@ -799,11 +871,11 @@ func TestMultipleCalendarUpdatesBeforeExecution(t *testing.T) {
 
 // TestIndividualServerAddRemove verifies individual servers can be added/removed from time group
 func TestIndividualServerAddRemove(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	eventTime := time.Now().Add(3 * time.Minute).Truncate(time.Minute)
 	timeKey := eventTime.Format(time.RFC3339)
@@ -926,11 +998,11 @@ func TestIndividualServerAddRemove(t *testing.T) {
 
 // TestEventListUpdateReflectsInExecution verifies that event list updates affect what executes
 func TestEventListUpdateReflectsInExecution(t *testing.T) {
-	s, err := New(24, "", 60)
+	s, err := New(24, nil, 60)
 	if err != nil {
 		t.Fatalf("New() returned error: %v", err)
 	}
-	defer s.Shutdown()
+	defer s.Shutdown(0)
 
 	eventTime := time.Now().Add(2 * time.Minute).Truncate(time.Minute)
 	timeKey := eventTime.Format(time.RFC3339)
@@ -987,3 +1059,942 @@ func TestEventListUpdateReflectsInExecution(t *testing.T) {
 		t.Error("s2, s4, s5 should not be in stored events")
 	}
 }
+
+func TestResolveConflicts_RestartTakesPrecedenceOverNoSync(t *testing.T) {
+	s, err := New(24, nil, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	now := time.Now().Truncate(time.Minute)
+
+	events := []ScheduledEvent{
+		{
+			Server:    config.Server{Name: "server1", Path: "/path1", Branch: "main"},
+			Event:     calendar.Event{Type: calendar.EventTypeRestartNoSync, StartTime: now},
+			Scheduled: now,
+		},
+		{
+			Server:    config.Server{Name: "server1", Path: "/path1", Branch: "main"},
+			Event:     calendar.Event{Type: calendar.EventTypeRestart, StartTime: now},
+			Scheduled: now,
+		},
+	}
+
+	resolved := s.resolveConflicts(events)
+
+	if len(resolved) != 1 {
+		t.Fatalf("Expected 1 event after conflict resolution, got %d", len(resolved))
+	}
+
+	if resolved[0].Event.Type != calendar.EventTypeRestart {
+		t.Errorf("Expected restart event to take precedence, got %s", resolved[0].Event.Type)
+	}
+}
+
+func TestResolveConflicts_MostThoroughWipeWins(t *testing.T) {
+	s, err := New(24, nil, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	now := time.Now().Truncate(time.Minute)
+	server := config.Server{Name: "server1", Path: "/path1", Branch: "main"}
+
+	events := []ScheduledEvent{
+		{Server: server, Event: calendar.Event{Type: calendar.EventTypeMapOnly, StartTime: now}, Scheduled: now},
+		{Server: server, Event: calendar.Event{Type: calendar.EventTypeWipeBlueprints, StartTime: now}, Scheduled: now},
+		{Server: server, Event: calendar.Event{Type: calendar.EventTypeWipe, StartTime: now}, Scheduled: now},
+		{Server: server, Event: calendar.Event{Type: calendar.EventTypeRestart, StartTime: now}, Scheduled: now},
+	}
+
+	resolved := s.resolveConflicts(events)
+
+	if len(resolved) != 1 {
+		t.Fatalf("Expected 1 event after conflict resolution, got %d", len(resolved))
+	}
+
+	if resolved[0].Event.Type != calendar.EventTypeWipeBlueprints {
+		t.Errorf("Expected wipe-bp event to take precedence, got %s", resolved[0].Event.Type)
+	}
+}
+
+// recordingNotifier records the level and title of every notification it's sent
+type recordingNotifier struct {
+	sent []string
+}
+
+func (n *recordingNotifier) record(level, title string) error {
+	n.sent = append(n.sent, level+": "+title)
+	return nil
+}
+
+func (n *recordingNotifier) Success(title, description string) error {
+	return n.record("success", title)
+}
+func (n *recordingNotifier) Info(title, description string) error { return n.record("info", title) }
+func (n *recordingNotifier) Warning(title, description string) error {
+	return n.record("warning", title)
+}
+func (n *recordingNotifier) Error(title, description string) error { return n.record("error", title) }
+
+// lockCheckingNotifier fails the test if a notification is sent while the scheduler's mutex is held
+type lockCheckingNotifier struct {
+	recordingNotifier
+	t *testing.T
+	s *Scheduler
+}
+
+func (n *lockCheckingNotifier) Warning(title, description string) error {
+	if !n.s.mutex.TryLock() {
+		n.t.Errorf("%q sent while the scheduler mutex was held", title)
+	} else {
+		n.s.mutex.Unlock()
+	}
+	return n.record("warning", title)
+}
+
+func TestUpdateEvents_NotifiesAfterUnlocking(t *testing.T) {
+	notifier := &lockCheckingNotifier{t: t}
+	s, err := New(24, notifier, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+	notifier.s = s
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	servers := []config.Server{{Name: "server1", Path: "/path1", CalendarURL: server.URL}}
+	for i := 0; i < calendarFailureAlertThreshold; i++ {
+		if err := s.UpdateEvents(servers); err != nil {
+			t.Fatalf("UpdateEvents() returned error: %v", err)
+		}
+	}
+	if want := []string{"warning: Calendar Fetch Failing"}; fmt.Sprint(notifier.sent) != fmt.Sprint(want) {
+		t.Errorf("notifications = %q, want %q", notifier.sent, want)
+	}
+}
+
+func TestUpdateEvents_TracksConsecutiveFetchFailures(t *testing.T) {
+	notifier := &recordingNotifier{}
+	s, err := New(24, notifier, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nEND:VCALENDAR\r\n"))
+	}))
+	defer server.Close()
+
+	servers := []config.Server{{Name: "server1", Path: "/path1", CalendarURL: server.URL}}
+
+	// Failing past the threshold alerts only once
+	for i := 1; i <= calendarFailureAlertThreshold+1; i++ {
+		if err := s.UpdateEvents(servers); err != nil {
+			t.Fatalf("UpdateEvents() returned error: %v", err)
+		}
+		if s.fetchFailures["/path1"] != i {
+			t.Errorf("fetchFailures after %d update(s) = %d, want %d", i, s.fetchFailures["/path1"], i)
+		}
+	}
+	if want := []string{"warning: Calendar Fetch Failing"}; fmt.Sprint(notifier.sent) != fmt.Sprint(want) {
+		t.Errorf("notifications after failures = %q, want %q", notifier.sent, want)
+	}
+
+	// A successful fetch resets the counter and sends a recovery notice
+	failing = false
+	if err := s.UpdateEvents(servers); err != nil {
+		t.Fatalf("UpdateEvents() returned error: %v", err)
+	}
+	if _, exists := s.fetchFailures["/path1"]; exists {
+		t.Error("fetchFailures should be cleared after a successful fetch")
+	}
+	want := []string{"warning: Calendar Fetch Failing", "success: Calendar Fetch Recovered"}
+	if fmt.Sprint(notifier.sent) != fmt.Sprint(want) {
+		t.Errorf("notifications after recovery = %q, want %q", notifier.sent, want)
+	}
+
+	// Later successes don't repeat the notice
+	if err := s.UpdateEvents(servers); err != nil {
+		t.Fatalf("UpdateEvents() returned error: %v", err)
+	}
+	if len(notifier.sent) != len(want) {
+		t.Errorf("notifications after another success = %q, want %q", notifier.sent, want)
+	}
+}
+
+func TestUpdateEvents_MergesOneOffEvents(t *testing.T) {
+	s, err := New(24, nil, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	server := config.Server{Name: "server1", Path: "/path1"}
+	oneOff := func(at time.Time) ScheduledEvent {
+		return ScheduledEvent{
+			Server:    server,
+			Event:     calendar.Event{Type: calendar.EventTypeWipe, StartTime: at, EndTime: at},
+			Scheduled: at,
+		}
+	}
+
+	upcoming := time.Now().Add(2 * time.Hour)
+	s.SetOneOffEvents([]ScheduledEvent{
+		oneOff(time.Now().Add(-1 * time.Hour)), // already passed
+		oneOff(upcoming),
+		oneOff(time.Now().Add(48 * time.Hour)), // beyond lookahead
+	})
+
+	if err := s.UpdateEvents(nil); err != nil {
+		t.Fatalf("UpdateEvents() returned error: %v", err)
+	}
+
+	events := s.GetEvents()
+	if len(events) != 1 {
+		t.Fatalf("GetEvents() returned %d events, want 1", len(events))
+	}
+	if !events[0].Scheduled.Equal(upcoming) {
+		t.Errorf("Scheduled = %v, want %v", events[0].Scheduled, upcoming)
+	}
+	if len(s.scheduledJobs) != 1 {
+		t.Errorf("scheduledJobs = %d, want 1", len(s.scheduledJobs))
+	}
+}
+
+func TestCancelledWipes_RechecksCalendar(t *testing.T) {
+	s, err := New(24, nil, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	// The wipe fired a moment ago; after the event delay it is in the past
+	at := time.Now().Add(-30 * time.Second).Truncate(time.Second)
+	ics := func(summary string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+			"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:" + summary + "\r\nDTSTART:" + at.UTC().Format("20060102T150405Z") + "\r\nEND:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"
+	}
+	calendars := map[string]string{
+		"/kept":    ics("wipe"),
+		"/deleted": "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nEND:VCALENDAR\r\n",
+		"/changed": ics("restart"),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/unreachable" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(calendars[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	wipe := func(path string) ScheduledEvent {
+		return ScheduledEvent{
+			Server:    config.Server{Name: path[1:], Path: path, CalendarURL: server.URL + path},
+			Event:     calendar.Event{Type: calendar.EventTypeWipe, StartTime: at},
+			Scheduled: at,
+		}
+	}
+	oneOff := wipe("/one-off")
+	oneOff.Server.CalendarURL = server.URL + "/deleted"
+	s.SetOneOffEvents([]ScheduledEvent{oneOff})
+
+	events := []ScheduledEvent{wipe("/kept"), wipe("/deleted"), wipe("/changed"), wipe("/unreachable"), oneOff}
+	cancelled := s.cancelledWipes(events)
+
+	want := map[string]bool{"/deleted": true, "/changed": true}
+	if len(cancelled) != len(want) {
+		t.Errorf("cancelledWipes() = %v, want %v", cancelled, want)
+	}
+	for path := range want {
+		if !cancelled[path] {
+			t.Errorf("cancelledWipes() = %v, want %s cancelled", cancelled, path)
+		}
+	}
+}
+
+func TestCheckClockJump_ForwardJumpDoesNotDoubleFire(t *testing.T) {
+	s, err := New(24, nil, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	now := time.Now()
+	fired := now.Add(-2 * time.Minute).Truncate(time.Minute)
+	pending := now.Add(30 * time.Minute).Truncate(time.Minute)
+	missed := now.Add(-2 * time.Hour).Truncate(time.Minute)
+
+	server := config.Server{Name: "server1", Path: "/path1"}
+	for _, at := range []time.Time{fired, pending, missed} {
+		s.events = append(s.events, ScheduledEvent{
+			Server:    server,
+			Event:     calendar.Event{Type: calendar.EventTypeRestart, StartTime: at},
+			Scheduled: at,
+		})
+	}
+
+	// Arm the pending job normally, then fake a job that already ran and one the jump skipped over
+	if err := s.scheduleJobs(); err != nil {
+		t.Fatalf("scheduleJobs() returned error: %v", err)
+	}
+	firedKey := fired.Format(time.RFC3339)
+	pendingKey := pending.Format(time.RFC3339)
+	missedKey := missed.Format(time.RFC3339)
+	for _, key := range []string{firedKey, missedKey} {
+		s.scheduledJobs[key] = uuid.New()
+		s.jobEvents[key] = s.events[:1]
+	}
+	s.firedJobs[firedKey] = true
+	firedJobID := s.scheduledJobs[firedKey]
+	pendingJobID := s.scheduledJobs[pendingKey]
+
+	// Simulate a one hour forward jump: wall clock advanced while the monotonic clock did not
+	s.lastClockWall = s.lastClockWall.Add(-1 * time.Hour)
+	s.CheckClockJump()
+
+	if s.scheduledJobs[firedKey] != firedJobID {
+		t.Error("job that already ran should not be re-armed")
+	}
+	if id, exists := s.scheduledJobs[pendingKey]; !exists || id == pendingJobID {
+		t.Error("pending job should be re-armed with a new job ID")
+	}
+	if _, exists := s.scheduledJobs[missedKey]; exists {
+		t.Error("event missed by more than the grace period should be dropped")
+	}
+	if _, exists := s.jobEvents[missedKey]; exists {
+		t.Error("dropped event should be removed from jobEvents")
+	}
+
+	// A second check without a jump must not re-arm anything
+	rearmedID := s.scheduledJobs[pendingKey]
+	s.CheckClockJump()
+	if s.scheduledJobs[pendingKey] != rearmedID {
+		t.Error("job should not be re-armed when no clock jump occurred")
+	}
+}
+
+func TestArmJob_WarnsOnceWhenNoEventsRemain(t *testing.T) {
+	warnings := make(chan string, 10)
+	origSend := sendWarning
+	sendWarning = func(notifier notify.Notifier, title, description string) error {
+		warnings <- description
+		return nil
+	}
+	defer func() { sendWarning = origSend }()
+
+	s, err := New(24, notify.Discard, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	// The calendar refresh emptied this job's event list before it fired
+	timeKey := time.Now().Truncate(time.Minute).Format(time.RFC3339)
+	s.mutex.Lock()
+	s.jobEvents[timeKey] = []ScheduledEvent{}
+	err = s.armJob(timeKey, gocron.OneTimeJobStartImmediately())
+	s.mutex.Unlock()
+	if err != nil {
+		t.Fatalf("armJob() returned error: %v", err)
+	}
+
+	select {
+	case description := <-warnings:
+		if !strings.Contains(description, timeKey) {
+			t.Errorf("warning = %q, want it to mention the time key %s", description, timeKey)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a Discord warning when the job fired with no events")
+	}
+
+	// Reporting the same time key again must not send another warning
+	s.notifyEmptyJob(timeKey, true)
+	select {
+	case description := <-warnings:
+		t.Errorf("unexpected second warning: %q", description)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWriteStatus_RoundTrip(t *testing.T) {
+	origPath := StatusPath
+	StatusPath = filepath.Join(t.TempDir(), "status.json")
+	defer func() { StatusPath = origPath }()
+
+	now := time.Now().Truncate(time.Minute)
+	events := []ScheduledEvent{
+		{
+			Server:    config.Server{Name: "late", Path: "/late"},
+			Event:     calendar.Event{Type: calendar.EventTypeWipe},
+			Scheduled: now.Add(2 * time.Hour),
+		},
+		{
+			Server:    config.Server{Name: "early", Path: "/early"},
+			Event:     calendar.Event{Type: calendar.EventTypeRestart},
+			Scheduled: now.Add(time.Hour),
+		},
+	}
+
+	if err := WriteStatus(events); err != nil {
+		t.Fatalf("WriteStatus() returned error: %v", err)
+	}
+
+	status, err := ReadStatus()
+	if err != nil {
+		t.Fatalf("ReadStatus() returned error: %v", err)
+	}
+
+	if status.PID != os.Getpid() {
+		t.Errorf("PID = %d, want %d", status.PID, os.Getpid())
+	}
+	if len(status.Events) != 2 {
+		t.Fatalf("len(Events) = %d, want 2", len(status.Events))
+	}
+	if status.Events[0].Server != "early" || status.Events[0].Type != "restart" {
+		t.Errorf("Events[0] = %+v, want early restart first", status.Events[0])
+	}
+	if !status.Events[1].Scheduled.Equal(now.Add(2 * time.Hour)) {
+		t.Errorf("Events[1].Scheduled = %v, want %v", status.Events[1].Scheduled, now.Add(2*time.Hour))
+	}
+
+	if err := RemoveStatus(); err != nil {
+		t.Fatalf("RemoveStatus() returned error: %v", err)
+	}
+	if _, err := ReadStatus(); !os.IsNotExist(err) {
+		t.Errorf("ReadStatus() after RemoveStatus error = %v, want not-exist", err)
+	}
+}
+
+func TestUpdateEvents_FetchesCalendarsConcurrently(t *testing.T) {
+	s, err := New(24, nil, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	const slowDelay = 300 * time.Millisecond
+	start := time.Now().Add(2 * time.Hour).UTC().Format("20060102T150405Z")
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:1\r\nSUMMARY:wipe\r\nDTSTART:" + start + "\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(slowDelay)
+		w.Write([]byte(ics))
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(ics))
+	}))
+	defer fast.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	var servers []config.Server
+	for i := 0; i < 6; i++ {
+		url := fast.URL
+		if i%2 == 0 {
+			url = slow.URL
+		}
+		servers = append(servers, config.Server{Name: fmt.Sprintf("server%d", i), Path: fmt.Sprintf("/path%d", i), CalendarURL: url})
+	}
+	servers = append(servers, config.Server{Name: "broken", Path: "/broken", CalendarURL: broken.URL})
+
+	began := time.Now()
+	if err := s.UpdateEvents(servers); err != nil {
+		t.Fatalf("UpdateEvents() returned error: %v", err)
+	}
+	elapsed := time.Since(began)
+
+	// Three slow calendars fetched sequentially would take 3x slowDelay
+	if elapsed >= 2*slowDelay {
+		t.Errorf("UpdateEvents() took %v, want under %v with concurrent fetches", elapsed, 2*slowDelay)
+	}
+
+	// The broken calendar is skipped without affecting the others
+	if got := len(s.GetEvents()); got != 6 {
+		t.Errorf("GetEvents() returned %d events, want 6", got)
+	}
+	if s.fetchFailures["/broken"] != 1 {
+		t.Errorf("fetchFailures[/broken] = %d, want 1", s.fetchFailures["/broken"])
+	}
+}
+
+func TestUpdateEvents_RecordsMetrics(t *testing.T) {
+	s, err := New(24, nil, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	at := time.Now().Add(2 * time.Hour)
+	s.SetOneOffEvents([]ScheduledEvent{
+		{
+			Server:    config.Server{Name: "server1", Path: "/path1"},
+			Event:     calendar.Event{Type: calendar.EventTypeWipe, StartTime: at, EndTime: at},
+			Scheduled: at,
+		},
+		{
+			Server:    config.Server{Name: "server2", Path: "/path2"},
+			Event:     calendar.Event{Type: calendar.EventTypeRestart, StartTime: at, EndTime: at},
+			Scheduled: at,
+		},
+	})
+	if err := s.UpdateEvents(nil); err != nil {
+		t.Fatalf("UpdateEvents() returned error: %v", err)
+	}
+
+	server := httptest.NewServer(metrics.Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("scrape error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+
+	if !strings.Contains(string(body), "wiped_scheduled_events 2") {
+		t.Errorf("metrics should report 2 scheduled events, got:\n%s", body)
+	}
+}
+
+func TestUpdateEvents_MergesCalendars(t *testing.T) {
+	s, err := New(24, nil, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	conflict := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	shared := time.Now().Add(3 * time.Hour).Truncate(time.Minute)
+	restartOnly := time.Now().Add(4 * time.Hour).Truncate(time.Minute)
+	vevent := func(uid, summary string, at time.Time) string {
+		return "BEGIN:VEVENT\r\nUID:" + uid + "\r\nSUMMARY:" + summary + "\r\nDTSTART:" + at.UTC().Format("20060102T150405Z") + "\r\nEND:VEVENT\r\n"
+	}
+	calendars := map[string]string{
+		// Restarts, including one at the same time as a wipe and one duplicated in both feeds
+		"/restarts.ics": vevent("r1", "restart", conflict) + vevent("r2", "restart", shared) + vevent("r3", "restart", restartOnly),
+		"/wipes.ics":    vevent("w1", "wipe", conflict) + vevent("r2-copy", "restart", shared),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" + calendars[r.URL.Path] + "END:VCALENDAR\r\n"))
+	}))
+	defer server.Close()
+
+	servers := []config.Server{{
+		Name:         "server1",
+		Path:         "/path1",
+		CalendarURL:  server.URL + "/restarts.ics",
+		CalendarURLs: []string{server.URL + "/wipes.ics"},
+	}}
+	if err := s.UpdateEvents(servers); err != nil {
+		t.Fatalf("UpdateEvents() returned error: %v", err)
+	}
+
+	events := s.GetEvents()
+	want := []struct {
+		at        time.Time
+		eventType calendar.EventType
+	}{
+		{conflict, calendar.EventTypeWipe},
+		{shared, calendar.EventTypeRestart},
+		{restartOnly, calendar.EventTypeRestart},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("GetEvents() returned %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if !events[i].Scheduled.Equal(w.at) || events[i].Event.Type != w.eventType {
+			t.Errorf("events[%d] = %s at %v, want %s at %v", i, events[i].Event.Type, events[i].Scheduled, w.eventType, w.at)
+		}
+	}
+}
+
+func TestUpdateEvents_PerServerLookahead(t *testing.T) {
+	s, err := New(24, nil, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	soon := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	later := time.Now().Add(10 * time.Hour).Truncate(time.Minute)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+			"BEGIN:VEVENT\r\nUID:soon\r\nSUMMARY:restart\r\nDTSTART:" + soon.UTC().Format("20060102T150405Z") + "\r\nEND:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\nUID:later\r\nSUMMARY:wipe\r\nDTSTART:" + later.UTC().Format("20060102T150405Z") + "\r\nEND:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"))
+	}))
+	defer server.Close()
+
+	short := 4
+	servers := []config.Server{
+		{Name: "global", Path: "/path1", CalendarURL: server.URL},
+		{Name: "short", Path: "/path2", CalendarURL: server.URL, LookaheadHours: &short},
+	}
+	if err := s.UpdateEvents(servers); err != nil {
+		t.Fatalf("UpdateEvents() returned error: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for _, event := range s.GetEvents() {
+		counts[event.Server.Name]++
+	}
+	if counts["global"] != 2 {
+		t.Errorf("global server has %d events, want 2", counts["global"])
+	}
+	if counts["short"] != 1 {
+		t.Errorf("server with lookahead_hours 4 has %d events, want 1", counts["short"])
+	}
+}
+
+func TestBatchEventDelay(t *testing.T) {
+	short, long := 2, 30
+	tests := []struct {
+		name    string
+		servers []config.Server
+		want    int
+	}{
+		{"global only", []config.Server{{Name: "a"}, {Name: "b"}}, 10},
+		{"single server override", []config.Server{{Name: "a", EventDelay: &short}}, 2},
+		{"longest override wins", []config.Server{{Name: "a", EventDelay: &short}, {Name: "b", EventDelay: &long}}, 30},
+		{"global longer than override", []config.Server{{Name: "a", EventDelay: &short}, {Name: "b"}}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := batchEventDelay(tt.servers, 10); got != tt.want {
+				t.Errorf("batchEventDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancelEvent(t *testing.T) {
+	eventTime := time.Now().Add(30 * time.Minute).Truncate(time.Minute)
+	timeKey := eventTime.Format(time.RFC3339)
+	event := func(name string) ScheduledEvent {
+		return ScheduledEvent{
+			Server:    config.Server{Name: name, Path: "/" + name},
+			Event:     calendar.Event{Type: calendar.EventTypeWipe, StartTime: eventTime},
+			Scheduled: eventTime,
+		}
+	}
+
+	t.Run("one of many", func(t *testing.T) {
+		s, err := New(24, nil, 60)
+		if err != nil {
+			t.Fatalf("New() returned error: %v", err)
+		}
+		defer s.Shutdown(0)
+
+		s.events = []ScheduledEvent{event("server-a"), event("server-b"), event("server-c")}
+		if err := s.scheduleJobs(); err != nil {
+			t.Fatalf("scheduleJobs() returned error: %v", err)
+		}
+		jobID := s.scheduledJobs[timeKey]
+
+		// Any time within the minute matches the event
+		if err := s.CancelEvent("/server-b", eventTime.Add(20*time.Second)); err != nil {
+			t.Fatalf("CancelEvent() returned error: %v", err)
+		}
+
+		if s.scheduledJobs[timeKey] != jobID {
+			t.Error("job should be kept while other servers remain")
+		}
+		if len(s.jobEvents[timeKey]) != 2 {
+			t.Fatalf("job has %d server(s), want 2", len(s.jobEvents[timeKey]))
+		}
+		for _, e := range append(s.jobEvents[timeKey], s.GetEvents()...) {
+			if e.Server.Path == "/server-b" {
+				t.Error("server-b should be removed from the job and the schedule")
+			}
+		}
+
+		// A calendar update that still has the event doesn't bring it back
+		s.SetOneOffEvents([]ScheduledEvent{event("server-a"), event("server-b"), event("server-c")})
+		if err := s.UpdateEvents(nil); err != nil {
+			t.Fatalf("UpdateEvents() returned error: %v", err)
+		}
+		if len(s.jobEvents[timeKey]) != 2 {
+			t.Errorf("job has %d server(s) after a calendar update, want 2", len(s.jobEvents[timeKey]))
+		}
+
+		// Cancelling again is a no-op
+		if err := s.CancelEvent("/server-b", eventTime); err != nil {
+			t.Errorf("second CancelEvent() returned error: %v", err)
+		}
+	})
+
+	t.Run("last in group", func(t *testing.T) {
+		s, err := New(24, nil, 60)
+		if err != nil {
+			t.Fatalf("New() returned error: %v", err)
+		}
+		defer s.Shutdown(0)
+
+		s.events = []ScheduledEvent{event("server-a")}
+		if err := s.scheduleJobs(); err != nil {
+			t.Fatalf("scheduleJobs() returned error: %v", err)
+		}
+
+		if err := s.CancelEvent("/server-a", eventTime); err != nil {
+			t.Fatalf("CancelEvent() returned error: %v", err)
+		}
+		if _, exists := s.scheduledJobs[timeKey]; exists {
+			t.Error("job should be cancelled when no servers remain")
+		}
+		if _, exists := s.jobEvents[timeKey]; exists {
+			t.Error("jobEvents should be cleared when no servers remain")
+		}
+		if len(s.GetEvents()) != 0 {
+			t.Errorf("GetEvents() returned %d events, want 0", len(s.GetEvents()))
+		}
+		if len(s.gocron.Jobs()) != 0 {
+			t.Errorf("gocron has %d job(s), want 0", len(s.gocron.Jobs()))
+		}
+	})
+
+	t.Run("not scheduled", func(t *testing.T) {
+		s, err := New(24, nil, 60)
+		if err != nil {
+			t.Fatalf("New() returned error: %v", err)
+		}
+		defer s.Shutdown(0)
+
+		if err := s.CancelEvent("/server-a", eventTime); !errors.Is(err, ErrEventNotFound) {
+			t.Errorf("CancelEvent() error = %v, want %v", err, ErrEventNotFound)
+		}
+
+		// The cancellation still applies once the event is scheduled
+		s.SetOneOffEvents([]ScheduledEvent{event("server-a")})
+		if err := s.UpdateEvents(nil); err != nil {
+			t.Fatalf("UpdateEvents() returned error: %v", err)
+		}
+		if len(s.GetEvents()) != 0 {
+			t.Errorf("GetEvents() returned %d events, want the cancelled event left out", len(s.GetEvents()))
+		}
+	})
+
+	t.Run("executing job is kept", func(t *testing.T) {
+		s, err := New(24, nil, 60)
+		if err != nil {
+			t.Fatalf("New() returned error: %v", err)
+		}
+		defer s.Shutdown(0)
+
+		s.events = []ScheduledEvent{event("server-a")}
+		if err := s.scheduleJobs(); err != nil {
+			t.Fatalf("scheduleJobs() returned error: %v", err)
+		}
+		s.executingJobs[timeKey] = true
+
+		if err := s.CancelEvent("/server-a", eventTime); !errors.Is(err, ErrEventStarted) {
+			t.Errorf("CancelEvent() error = %v, want %v", err, ErrEventStarted)
+		}
+		if len(s.jobEvents[timeKey]) != 1 {
+			t.Error("executing job's events should be untouched")
+		}
+		delete(s.executingJobs, timeKey)
+	})
+}
+
+func TestRunJob_SkipsRerunOfSameTimeKey(t *testing.T) {
+	var runs int
+	origExecute := executeEventBatch
+	executeEventBatch = func(ctx context.Context, servers []config.Server, wipeServers map[string]executor.WipeMode, noSyncServers map[string]bool, opts executor.BatchOptions) error {
+		runs++
+		return nil
+	}
+	defer func() { executeEventBatch = origExecute }()
+
+	s, err := New(24, nil, 0)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	eventTime := time.Now().Truncate(time.Minute)
+	timeKey := eventTime.Format(time.RFC3339)
+	s.jobEvents[timeKey] = []ScheduledEvent{{
+		Server:    config.Server{Name: "server1", Path: "/path1"},
+		Event:     calendar.Event{Type: calendar.EventTypeWipe, StartTime: eventTime},
+		Scheduled: eventTime,
+	}}
+
+	s.runJob(timeKey)
+	if runs != 1 {
+		t.Fatalf("first run executed %d batch(es), want 1", runs)
+	}
+
+	// gocron firing the same job again is a no-op
+	s.runJob(timeKey)
+	if runs != 1 {
+		t.Errorf("second run executed the batch again (%d runs)", runs)
+	}
+
+	// So is a job armed again after its time key dropped out of the schedule and came back
+	delete(s.firedJobs, timeKey)
+	s.runJob(timeKey)
+	if runs != 1 {
+		t.Errorf("re-armed job executed the batch again (%d runs)", runs)
+	}
+
+	// Outside the guard window the time key can run again
+	s.lastExecuted[timeKey] = time.Now().Add(-rerunGuardWindow)
+	delete(s.firedJobs, timeKey)
+	s.runJob(timeKey)
+	if runs != 2 {
+		t.Errorf("run after the guard window executed %d batch(es) in total, want 2", runs)
+	}
+}
+
+// captureLogs sends log output to a buffer for the duration of the test
+func captureLogs(t *testing.T) *strings.Builder {
+	t.Helper()
+
+	var buf strings.Builder
+	origOutput, origFlags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(origOutput)
+		log.SetFlags(origFlags)
+	})
+	return &buf
+}
+
+func TestLogging_SummarizesLargeSchedules(t *testing.T) {
+	buf := captureLogs(t)
+
+	s, err := New(720, discord.Webhook{URL: "https://example.com"}, 0)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	// Five days of hourly restarts with a wipe every tenth hour, each its own job
+	start := time.Now().Add(time.Hour).Truncate(time.Minute)
+	server := config.Server{Name: "us-weekly", Path: "/srv/us-weekly"}
+	for i := 0; i < 120; i++ {
+		eventType := calendar.EventTypeRestart
+		if i%10 == 0 {
+			eventType = calendar.EventTypeWipe
+		}
+		at := start.Add(time.Duration(i) * time.Hour)
+		s.events = append(s.events, ScheduledEvent{Server: server, Event: calendar.Event{Type: eventType, StartTime: at}, Scheduled: at})
+	}
+
+	s.logUpcomingEvents()
+	if err := s.scheduleJobs(); err != nil {
+		t.Fatalf("scheduleJobs() returned error: %v", err)
+	}
+
+	out := buf.String()
+	if got := strings.Count(out, "us-weekly ["); got != maxLoggedEvents {
+		t.Errorf("logged %d upcoming events, want %d", got, maxLoggedEvents)
+	}
+	if !strings.Contains(out, "... and 70 more (63 restart(s), 7 wipe(s))") {
+		t.Errorf("log missing summary of the remaining events:\n%s", out)
+	}
+	if strings.Contains(out, "Scheduled job for") {
+		t.Error("each job was logged at info level, want a summary")
+	}
+	if !strings.Contains(out, "Scheduled 120 job(s) and updated 0 for 120 event(s)") {
+		t.Errorf("log missing job summary:\n%s", out)
+	}
+	if len(s.scheduledJobs) != 120 {
+		t.Errorf("scheduled %d jobs, want 120", len(s.scheduledJobs))
+	}
+}
+
+func TestLogging_ListsSmallSchedules(t *testing.T) {
+	buf := captureLogs(t)
+
+	s, err := New(24, discord.Webhook{URL: "https://example.com"}, 0)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	at := time.Now().Add(time.Hour).Truncate(time.Minute)
+	s.events = []ScheduledEvent{{Server: config.Server{Name: "us-weekly", Path: "/srv/us-weekly"}, Event: calendar.Event{Type: calendar.EventTypeWipe, StartTime: at}, Scheduled: at}}
+
+	s.logUpcomingEvents()
+	if err := s.scheduleJobs(); err != nil {
+		t.Fatalf("scheduleJobs() returned error: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "more (") || !strings.Contains(out, "Scheduled job for") {
+		t.Errorf("small schedule should be logged per event and job:\n%s", out)
+	}
+}
+
+func TestNotifyEventsAdded_Fields(t *testing.T) {
+	var payload discord.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s, err := New(24, discord.Webhook{URL: server.URL}, 60)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer s.Shutdown(0)
+
+	at := time.Date(2026, 3, 5, 18, 0, 0, 0, time.UTC)
+	s.notifyEventsAdded([]ScheduledEvent{
+		{Server: config.Server{Name: "server1"}, Event: calendar.Event{Type: calendar.EventTypeRestart}, Scheduled: at},
+		{Server: config.Server{Name: "server2"}, Event: calendar.Event{Type: calendar.EventTypeWipe}, Scheduled: at},
+	})
+
+	if len(payload.Embeds) != 1 {
+		t.Fatalf("Embeds = %+v, want one embed", payload.Embeds)
+	}
+	embed := payload.Embeds[0]
+	if embed.Description != "**2** new event(s) scheduled" {
+		t.Errorf("Description = %q, want the count only", embed.Description)
+	}
+
+	want := map[string]string{
+		"Restarts": "• server1 at Thu Mar 05 18:00 UTC",
+		"Wipes":    "• server2 at Thu Mar 05 18:00 UTC",
+	}
+	got := make(map[string]string)
+	for _, f := range embed.Fields {
+		got[f.Name] = f.Value
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("field %q = %q, want %q", name, got[name], value)
+		}
+	}
+	if _, ok := got["Hostname"]; !ok {
+		t.Error("Hostname field missing")
+	}
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// StatusPath is where the daemon records its current schedule for `wipe status`
+var StatusPath = "/opt/wiped/status.json"
+
+// Status is the daemon's schedule snapshot, written after each calendar update
+type Status struct {
+	PID       int           `json:"pid"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Events    []StatusEvent `json:"events"`
+}
+
+// StatusEvent is a single scheduled event in the status file
+type StatusEvent struct {
+	Server    string    `json:"server"`
+	Type      string    `json:"type"`
+	Scheduled time.Time `json:"scheduled"`
+}
+
+// WriteStatus atomically replaces the status file with the given events, sorted by time
+func WriteStatus(events []ScheduledEvent) error {
+	status := Status{
+		PID:       os.Getpid(),
+		UpdatedAt: time.Now(),
+		Events:    make([]StatusEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		status.Events = append(status.Events, StatusEvent{
+			Server:    event.Server.Name,
+			Type:      string(event.Event.Type),
+			Scheduled: event.Scheduled,
+		})
+	}
+	sort.SliceStable(status.Events, func(i, j int) bool {
+		return status.Events[i].Scheduled.Before(status.Events[j].Scheduled)
+	})
+
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+
+	tmpPath := StatusPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write status: %w", err)
+	}
+	return os.Rename(tmpPath, StatusPath)
+}
+
+// ReadStatus reads the status file written by the daemon
+func ReadStatus() (*Status, error) {
+	data, err := os.ReadFile(StatusPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse status: %w", err)
+	}
+	return &status, nil
+}
+
+// RemoveStatus deletes the status file so readers know the daemon has stopped
+func RemoveStatus() error {
+	if err := os.Remove(StatusPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/maintc/wipe-cli/systemd"
+)
+
+const (
+	// UnitFile is the systemd template unit; each instance runs the daemon as the user it is named after
+	UnitFile = "wiped@.service"
+
+	// SystemUnitDir holds system units installed by the administrator
+	SystemUnitDir = "/etc/systemd/system"
+
+	// UserUnitDir holds a user's own units, relative to their home directory
+	UserUnitDir = ".config/systemd/user"
+
+	// DefaultDaemonPath is where 'make install' puts the daemon
+	DefaultDaemonPath = "/usr/local/bin/wiped"
+)
+
+// Systemctl runs systemctl with the given arguments (replaced in tests)
+type Systemctl interface {
+	Run(args ...string) error
+}
+
+// execSystemctl runs the systemctl binary, against the user's manager when user is set
+type execSystemctl struct {
+	user bool
+}
+
+// NewSystemctl returns a Systemctl for the system manager, or the user's manager when user is set
+func NewSystemctl(user bool) Systemctl {
+	return execSystemctl{user: user}
+}
+
+func (s execSystemctl) Run(args ...string) error {
+	if s.user {
+		args = append([]string{"--user"}, args...)
+	}
+	output, err := exec.Command("systemctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl %s failed: %w\nOutput: %s", strings.Join(args, " "), err, output)
+	}
+	return nil
+}
+
+// UnitOptions describes the unit to render
+type UnitOptions struct {
+	DaemonPath string // Installed wiped binary
+	ConfigPath string // Config file passed with --config (empty for the daemon's default)
+	User       bool   // Run under the user's systemd manager instead of the system one
+}
+
+// unitTemplate renders the wiped@.service unit from systemd/wiped.service
+var unitTemplate = template.Must(template.New("wiped.service").Parse(systemd.UnitTemplate))
+
+// RenderUnit returns the contents of the wiped@.service template unit
+func RenderUnit(opts UnitOptions) (string, error) {
+	execStart := opts.DaemonPath
+	if opts.ConfigPath != "" {
+		execStart += " --config " + quoteArg(opts.ConfigPath)
+	}
+
+	// A user manager already runs as the user and can't chown /opt, so the template
+	// leaves out User= and ExecStartPre for user units
+	data := struct {
+		ExecStart string
+		User      bool
+	}{execStart, opts.User}
+
+	var b strings.Builder
+	if err := unitTemplate.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", UnitFile, err)
+	}
+	return b.String(), nil
+}
+
+// quoteArg quotes a systemd command-line argument containing spaces
+func quoteArg(arg string) string {
+	if !strings.ContainsAny(arg, " \t\"") {
+		return arg
+	}
+	return `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+}
+
+// UnitDir returns the directory the unit is installed to for the system or the user's manager
+func UnitDir(user bool, home string) string {
+	if user {
+		return filepath.Join(home, UserUnitDir)
+	}
+	return SystemUnitDir
+}
+
+// Install writes the unit to dir and reloads systemd, returning the unit's path
+func Install(dir string, opts UnitOptions, systemctl Systemctl) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	unit, err := RenderUnit(opts)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, UnitFile)
+	if err := os.WriteFile(path, []byte(unit), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := systemctl.Run("daemon-reload"); err != nil {
+		return path, err
+	}
+	return path, nil
+}
+
+// Uninstall stops and disables instance, removes the unit from dir and reloads systemd.
+// Stopping and disabling are best effort since the instance may never have been enabled.
+func Uninstall(dir, instance string, systemctl Systemctl) error {
+	_ = systemctl.Run("stop", instance)
+	_ = systemctl.Run("disable", instance)
+
+	path := filepath.Join(dir, UnitFile)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+
+	return systemctl.Run("daemon-reload")
+}
+
+// InstanceName returns the unit instance for a user, e.g. wiped@alice.service
+func InstanceName(username string) string {
+	return strings.TrimSuffix(UnitFile, "@.service") + "@" + username + ".service"
+}
+
+// CurrentUser returns the user the daemon should run as: the user who invoked sudo, or the current user
+func CurrentUser() (string, error) {
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
+		return sudoUser, nil
+	}
+	u, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+	return u.Username, nil
+}
+
+// DaemonPath returns the installed wiped binary, preferring the one on PATH
+func DaemonPath() string {
+	if path, err := exec.LookPath("wiped"); err == nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+	}
+	return DefaultDaemonPath
+}
+
+// ErrJournalctlNotFound is returned when journalctl isn't installed
+var ErrJournalctlNotFound = errors.New("journalctl not found")
+
+// Journal streams journalctl output (replaced in tests)
+type Journal interface {
+	Stream(args []string, stdout, stderr io.Writer) error
+}
+
+// execJournal runs the journalctl binary
+type execJournal struct{}
+
+// NewJournal returns a Journal that runs journalctl
+func NewJournal() Journal {
+	return execJournal{}
+}
+
+func (execJournal) Stream(args []string, stdout, stderr io.Writer) error {
+	path, err := exec.LookPath("journalctl")
+	if err != nil {
+		return ErrJournalctlNotFound
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// JournalArgs returns the journalctl arguments showing the last lines of instance's logs,
+// following new entries when follow is set (user selects a unit run by the user's manager)
+func JournalArgs(instance string, lines int, follow, user bool) []string {
+	args := []string{"-u", instance}
+	if user {
+		args = []string{"--user-unit", instance}
+	}
+	args = append(args, "-n", strconv.Itoa(lines), "--no-pager")
+	if follow {
+		args = append(args, "-f")
+	}
+	return args
+}
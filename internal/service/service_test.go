@@ -0,0 +1,141 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeSystemctl records systemctl invocations instead of running them
+type fakeSystemctl struct {
+	calls []string
+}
+
+func (f *fakeSystemctl) Run(args ...string) error {
+	f.calls = append(f.calls, strings.Join(args, " "))
+	return nil
+}
+
+func TestRenderUnit(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    UnitOptions
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "system unit",
+			opts: UnitOptions{DaemonPath: "/usr/local/bin/wiped", ConfigPath: "/home/alice/.config/wiped/config.yaml"},
+			want: []string{
+				"User=%i\n",
+				"ExecStartPre=+/bin/sh -c 'mkdir -p /opt/rust /opt/carbon /opt/oxide /opt/wiped",
+				"ExecStart=/usr/local/bin/wiped --config /home/alice/.config/wiped/config.yaml\n",
+				"WantedBy=multi-user.target\n",
+			},
+		},
+		{
+			name:    "user unit",
+			opts:    UnitOptions{DaemonPath: "/usr/local/bin/wiped", User: true},
+			want:    []string{"ExecStart=/usr/local/bin/wiped\n", "WantedBy=default.target\n"},
+			notWant: []string{"User=", "ExecStartPre", "--config"},
+		},
+		{
+			name: "config path with spaces",
+			opts: UnitOptions{DaemonPath: "/opt/bin/wiped", ConfigPath: "/srv/wipe configs/eu.yaml"},
+			want: []string{`ExecStart=/opt/bin/wiped --config "/srv/wipe configs/eu.yaml"` + "\n"},
+		},
+		{
+			// The template's conditionals leave no blank lines or template syntax behind
+			name:    "default system unit",
+			opts:    UnitOptions{DaemonPath: DefaultDaemonPath},
+			want:    []string{"Type=simple\n# Runs as the user", "ExecStart=/usr/local/bin/wiped\nRestart=always\n", "[Install]\nWantedBy=multi-user.target\n"},
+			notWant: []string{"{{", "}}", "\n\n\n", "--config"},
+		},
+		{
+			name:    "default user unit",
+			opts:    UnitOptions{DaemonPath: DefaultDaemonPath, User: true},
+			want:    []string{"Type=simple\nExecStart=/usr/local/bin/wiped\n", "[Install]\nWantedBy=default.target\n"},
+			notWant: []string{"{{", "}}", "\n\n\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit, err := RenderUnit(tt.opts)
+			if err != nil {
+				t.Fatalf("RenderUnit() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(unit, want) {
+					t.Errorf("unit missing %q:\n%s", want, unit)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(unit, notWant) {
+					t.Errorf("unit should not contain %q:\n%s", notWant, unit)
+				}
+			}
+		})
+	}
+}
+
+func TestInstallAndUninstall(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "systemd", "user")
+	systemctl := &fakeSystemctl{}
+	opts := UnitOptions{DaemonPath: "/usr/local/bin/wiped", ConfigPath: "/home/alice/.config/wiped/config.yaml"}
+
+	path, err := Install(dir, opts, systemctl)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if path != filepath.Join(dir, "wiped@.service") {
+		t.Errorf("Install() path = %s, want %s", path, filepath.Join(dir, "wiped@.service"))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unit not written: %v", err)
+	}
+	unit, err := RenderUnit(opts)
+	if err != nil {
+		t.Fatalf("RenderUnit() error = %v", err)
+	}
+	if string(data) != unit {
+		t.Errorf("unit contents = %q, want %q", data, unit)
+	}
+
+	if err := Uninstall(dir, InstanceName("alice"), systemctl); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("unit still present after Uninstall() (stat error = %v)", err)
+	}
+
+	want := "daemon-reload,stop wiped@alice.service,disable wiped@alice.service,daemon-reload"
+	if got := strings.Join(systemctl.calls, ","); got != want {
+		t.Errorf("systemctl calls = %s, want %s", got, want)
+	}
+}
+
+func TestJournalArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  int
+		follow bool
+		user   bool
+		want   string
+	}{
+		{"last lines", 100, false, false, "-u wiped@alice.service -n 100 --no-pager"},
+		{"follow", 50, true, false, "-u wiped@alice.service -n 50 --no-pager -f"},
+		{"user unit", 20, true, true, "--user-unit wiped@alice.service -n 20 --no-pager -f"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(JournalArgs(InstanceName("alice"), tt.lines, tt.follow, tt.user), " ")
+			if got != tt.want {
+				t.Errorf("JournalArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Package settings applies the config's runtime settings to the packages that read them.
+// The daemon applies them at startup and on every config reload; CLI commands that install,
+// sync or run batches apply them first so they behave like the daemon.
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/carbon"
+	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/discord"
+	"github.com/maintc/wipe-cli/internal/executor"
+	"github.com/maintc/wipe-cli/internal/httpclient"
+	"github.com/maintc/wipe-cli/internal/steamcmd"
+)
+
+// Apply copies the config's install, notification and execution settings into the packages
+// that use them. Every setting is applied even when some can't be; the returned error lists
+// the conflicting pins and whether execution history had to be disabled.
+func Apply(cfg *config.Config) error {
+	var errs []error
+
+	steamcmd.SetKeepPreviousInstall(cfg.KeepPreviousInstall)
+	steamcmd.SetMinFreeDiskGB(cfg.MinFreeDiskGB)
+	steamcmd.SetUpdateCheck(cfg.UpdateCheckSource, cfg.UpdateCheckURL)
+	steamcmd.SetInstallTimeout(time.Duration(cfg.SteamCMDTimeoutMinutes) * time.Minute)
+
+	// Pin each branch's Carbon install to its servers' carbon_version
+	carbonPins, err := cfg.CarbonVersionPins()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	carbon.SetPinnedVersions(carbonPins)
+
+	// Hold each branch's Rust install at its servers' rust_build_id
+	rustPins, err := cfg.RustBuildIDPins()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	steamcmd.SetPinnedBuildIDs(rustPins)
+
+	discord.SetMaxAttempts(cfg.DiscordMaxAttempts)
+	discord.SetMentions(cfg.DiscordMentionUsers, cfg.DiscordMentionRoles, cfg.DiscordMentionOn)
+	httpclient.SetTimeout(time.Duration(cfg.HTTPTimeout) * time.Second)
+
+	executor.SetMaxConcurrentSyncs(cfg.MaxConcurrentSyncs)
+	executor.SetSafeWipe(cfg.SafeWipe)
+	executor.SetWipeBackupRetention(cfg.WipeBackupRetention)
+	executor.SetEventWebhookURL(cfg.EventWebhookURL)
+	historyFile, err := cfg.GetHistoryFile()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("execution history disabled: %w", err))
+	} else {
+		executor.SetHistoryFile(historyFile)
+	}
+
+	return errors.Join(errs...)
+}
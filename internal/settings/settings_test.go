@@ -0,0 +1,109 @@
+package settings
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/carbon"
+	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/executor"
+	"github.com/maintc/wipe-cli/internal/httpclient"
+	"github.com/maintc/wipe-cli/internal/steamcmd"
+)
+
+func TestApply_ConcurrentWithReaders(t *testing.T) {
+	origSyncs, origSafe, origHistory := executor.MaxConcurrentSyncs(), executor.SafeWipe(), executor.HistoryFile()
+	origSource, origURL := steamcmd.UpdateCheck()
+	origTimeout := steamcmd.InstallTimeout()
+	defer func() {
+		executor.SetMaxConcurrentSyncs(origSyncs)
+		executor.SetSafeWipe(origSafe)
+		executor.SetHistoryFile(origHistory)
+		steamcmd.SetUpdateCheck(origSource, origURL)
+		steamcmd.SetInstallTimeout(origTimeout)
+	}()
+
+	historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+	cfg := &config.Config{
+		MaxConcurrentSyncs:     2,
+		SafeWipe:               true,
+		HistoryFile:            historyFile,
+		UpdateCheckSource:      config.UpdateCheckHTTP,
+		UpdateCheckURL:         "https://example.com/info",
+		SteamCMDTimeoutMinutes: 5,
+	}
+
+	// Config reloads replace the settings while update checks and batches read them (run with -race)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			Apply(cfg)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		executor.MaxConcurrentSyncs()
+		executor.SafeWipe()
+		executor.HistoryFile()
+		steamcmd.UpdateCheck()
+		steamcmd.InstallTimeout()
+	}
+	<-done
+
+	if got := executor.MaxConcurrentSyncs(); got != 2 {
+		t.Errorf("MaxConcurrentSyncs() = %d, want 2", got)
+	}
+	if !executor.SafeWipe() {
+		t.Error("SafeWipe() = false, want true")
+	}
+	if got := executor.HistoryFile(); got != historyFile {
+		t.Errorf("HistoryFile() = %q, want %q", got, historyFile)
+	}
+	if source, url := steamcmd.UpdateCheck(); source != config.UpdateCheckHTTP || url != "https://example.com/info" {
+		t.Errorf("UpdateCheck() = %q, %q, want http and the configured URL", source, url)
+	}
+	if got := steamcmd.InstallTimeout(); got != 5*time.Minute {
+		t.Errorf("InstallTimeout() = %v, want 5m", got)
+	}
+}
+
+func TestApply_ReportsConflictingPins(t *testing.T) {
+	origSyncs, origTimeout := executor.MaxConcurrentSyncs(), httpclient.Timeout()
+	defer func() {
+		executor.SetMaxConcurrentSyncs(origSyncs)
+		httpclient.SetTimeout(origTimeout)
+		carbon.SetPinnedVersions(nil)
+	}()
+
+	cfg := &config.Config{
+		Servers: []config.Server{
+			{Name: "us-weekly", Path: "/srv/us-weekly", Branch: "main", CarbonVersion: "2.0.100"},
+			{Name: "eu-weekly", Path: "/srv/eu-weekly", Branch: "main", CarbonVersion: "2.0.200"},
+			{Name: "test", Path: "/srv/test", Branch: "staging", CarbonVersion: "2.0.300"},
+		},
+		MaxConcurrentSyncs: 3,
+		HTTPTimeout:        12,
+		HistoryFile:        filepath.Join(t.TempDir(), "history.jsonl"),
+	}
+
+	err := Apply(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Carbon versions on branch(es) main") {
+		t.Fatalf("Apply() error = %v, want the conflicting Carbon pins", err)
+	}
+
+	// The rest of the settings still apply
+	if got := carbon.PinnedVersion("staging"); got != "2.0.300" {
+		t.Errorf("PinnedVersion(staging) = %q, want 2.0.300", got)
+	}
+	if got := carbon.PinnedVersion("main"); got != "" {
+		t.Errorf("PinnedVersion(main) = %q, want unpinned", got)
+	}
+	if got := executor.MaxConcurrentSyncs(); got != 3 {
+		t.Errorf("MaxConcurrentSyncs() = %d, want 3", got)
+	}
+	if got := httpclient.Timeout(); got != 12*time.Second {
+		t.Errorf("Timeout() = %v, want 12s", got)
+	}
+}
@@ -0,0 +1,127 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/discord"
+)
+
+// Attachment colors for each notification level (the same hues as the Discord embeds)
+const (
+	ColorSuccess = "#00ff00" // Green
+	ColorInfo    = "#0099ff" // Blue
+	ColorWarning = "#ff9900" // Orange
+	ColorError   = "#ff0000" // Red
+)
+
+// Attachment represents a Slack message attachment
+type Attachment struct {
+	Color    string   `json:"color,omitempty"`
+	Title    string   `json:"title,omitempty"`
+	Text     string   `json:"text,omitempty"`
+	Footer   string   `json:"footer,omitempty"`
+	Ts       int64    `json:"ts,omitempty"`
+	MrkdwnIn []string `json:"mrkdwn_in,omitempty"`
+}
+
+// WebhookPayload represents the Slack incoming webhook payload
+type WebhookPayload struct {
+	Text        string       `json:"text,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// StatusError is returned when the webhook responds with a non-2xx status
+type StatusError = discord.StatusError
+
+// Webhook sends notifications to a Slack incoming webhook; an empty URL disables it
+type Webhook struct {
+	URL          string
+	MentionUsers []string // Slack member IDs (U…) to mention in every notification
+}
+
+// Success sends a success notification (green)
+func (w Webhook) Success(title, description string) error {
+	return w.notify("success", ColorSuccess, title, description)
+}
+
+// Info sends an info notification (blue)
+func (w Webhook) Info(title, description string) error {
+	return w.notify("info", ColorInfo, title, description)
+}
+
+// Warning sends a warning notification (orange)
+func (w Webhook) Warning(title, description string) error {
+	return w.notify("warning", ColorWarning, title, description)
+}
+
+// Error sends an error notification (red)
+func (w Webhook) Error(title, description string) error {
+	return w.notify("error", ColorError, title, description)
+}
+
+// notify sends a notification and logs any failure. In the daemon it's queued behind the
+// Discord notifications so callers never wait on Slack.
+func (w Webhook) notify(level, color, title, description string) error {
+	if w.URL == "" {
+		return nil
+	}
+	err := discord.Deliver(title, func() error {
+		return SendNotification(w.URL, title, description, color, w.MentionUsers)
+	})
+	if err != nil {
+		log.Printf("Failed to send Slack %s notification: %v", level, err)
+	}
+	return err
+}
+
+// MentionContent builds the message text that pings the given Slack members
+func MentionContent(userIDs []string) string {
+	mentions := []string{}
+	for _, userID := range userIDs {
+		mentions = append(mentions, fmt.Sprintf("<@%s>", userID))
+	}
+
+	if len(mentions) == 0 {
+		return ""
+	}
+	return "cc " + strings.Join(mentions, " ")
+}
+
+// SendNotification posts an attachment to a Slack webhook, mentioning the given members.
+// 429 and 5xx responses are retried like Discord's (see discord.PostWebhook).
+func SendNotification(webhookURL, title, description, color string, userIDs []string) error {
+	if webhookURL == "" {
+		// Webhook not configured, skip silently
+		return nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	payload := WebhookPayload{
+		Text: MentionContent(userIDs),
+		Attachments: []Attachment{{
+			Color: color,
+			Title: title,
+			// Notifications are written with Discord's **bold**; Slack's mrkdwn uses *bold*
+			Text:     strings.ReplaceAll(description, "**", "*"),
+			Footer:   "Hostname: " + hostname,
+			Ts:       time.Now().Unix(),
+			MrkdwnIn: []string{"text"},
+		}},
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	return discord.PostWebhook(webhookURL, jsonData)
+}
@@ -0,0 +1,161 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/discord"
+)
+
+func TestWebhook_LevelColors(t *testing.T) {
+	var payload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhook := Webhook{URL: server.URL}
+	tests := []struct {
+		name string
+		send func(title, description string) error
+		want string
+	}{
+		{"Success", webhook.Success, ColorSuccess},
+		{"Info", webhook.Info, ColorInfo},
+		{"Warning", webhook.Warning, ColorWarning},
+		{"Error", webhook.Error, ColorError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload = WebhookPayload{}
+			if err := tt.send("Test", "Test message"); err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			if len(payload.Attachments) != 1 || payload.Attachments[0].Color != tt.want || payload.Attachments[0].Title != "Test" {
+				t.Errorf("Attachments = %+v, want one %q attachment with color %s", payload.Attachments, "Test", tt.want)
+			}
+		})
+	}
+}
+
+func TestSendNotification_Content(t *testing.T) {
+	var payload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	users := []string{"U0123ABCDEF", "U0456GHIJKL"}
+	if err := SendNotification(server.URL, "Test", "Wiped **us-weekly**", ColorInfo, users); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+
+	want := "cc <@U0123ABCDEF> <@U0456GHIJKL>"
+	if payload.Text != want {
+		t.Errorf("Text = %q, want %q", payload.Text, want)
+	}
+	if len(payload.Attachments) != 1 {
+		t.Fatalf("Attachments = %+v, want one", payload.Attachments)
+	}
+	if got := payload.Attachments[0].Text; got != "Wiped *us-weekly*" {
+		t.Errorf("Attachment text = %q, want Discord bold translated to Slack bold", got)
+	}
+
+	// Without mentions the text is omitted
+	payload = WebhookPayload{}
+	if err := SendNotification(server.URL, "Test", "Test message", ColorInfo, nil); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+	if payload.Text != "" {
+		t.Errorf("Text = %q, want empty", payload.Text)
+	}
+}
+
+func TestSendNotification_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := Webhook{URL: server.URL}.Warning("Test", "Test message")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Warning() error = %v, want status 403", err)
+	}
+}
+
+func TestSendNotification_EmptyWebhook(t *testing.T) {
+	if err := SendNotification("", "Test", "Test message", ColorInfo, nil); err != nil {
+		t.Errorf("SendNotification() with empty webhook should not error, got: %v", err)
+	}
+}
+
+func TestSendNotification_RetriesRateLimit(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := SendNotification(server.URL, "Test", "Test message", ColorInfo, nil); err != nil {
+		t.Fatalf("SendNotification() error = %v, want success after a retry", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestWebhook_QueuedInBackground(t *testing.T) {
+	release := make(chan struct{})
+	var delivered int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	queue := discord.NewNotifier("", 10)
+	discord.SetBackground(queue)
+	defer discord.SetBackground(nil)
+
+	// A slow Slack webhook doesn't hold up the caller
+	sent := make(chan error, 1)
+	go func() { sent <- Webhook{URL: server.URL}.Warning("Test", "Test message") }()
+	select {
+	case err := <-sent:
+		if err != nil {
+			t.Errorf("Warning() error = %v, want nil once queued", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Warning() blocked on the webhook")
+	}
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := queue.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&delivered); got != 1 {
+		t.Errorf("delivered = %d, want 1", got)
+	}
+}
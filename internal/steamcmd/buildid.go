@@ -0,0 +1,84 @@
+package steamcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/httpclient"
+)
+
+// DefaultBuildIDURL is the web API queried for build IDs when no update check URL is set
+const DefaultBuildIDURL = "https://api.steamcmd.net/v1/info/" + RustAppID
+
+var (
+	// updateCheckSource is where CheckForUpdates gets the latest build ID: steamcmd or http
+	updateCheckSource = config.UpdateCheckSteamCMD
+	// updateCheckURL is the web API used when updateCheckSource is http (empty uses DefaultBuildIDURL)
+	updateCheckURL   = ""
+	updateCheckMutex sync.RWMutex
+)
+
+// SetUpdateCheck sets where CheckForUpdates gets the latest build ID (steamcmd or http) and,
+// for http, the web API to query (empty uses DefaultBuildIDURL)
+func SetUpdateCheck(source, url string) {
+	updateCheckMutex.Lock()
+	defer updateCheckMutex.Unlock()
+	updateCheckSource, updateCheckURL = source, url
+}
+
+// UpdateCheck returns where CheckForUpdates gets the latest build ID, and the web API used for http
+func UpdateCheck() (source, url string) {
+	updateCheckMutex.RLock()
+	defer updateCheckMutex.RUnlock()
+	return updateCheckSource, updateCheckURL
+}
+
+// appInfoResponse is the subset of an api.steamcmd.net app info response holding branch build IDs
+type appInfoResponse struct {
+	Status string `json:"status"`
+	Data   map[string]struct {
+		Depots struct {
+			Branches map[string]struct {
+				BuildID string `json:"buildid"`
+			} `json:"branches"`
+		} `json:"depots"`
+	} `json:"data"`
+}
+
+// fetchBuildID returns the build ID of branch from the app info web API at url
+func fetchBuildID(url, branch string) (string, error) {
+	if url == "" {
+		url = DefaultBuildIDURL
+	}
+
+	resp, err := httpclient.New().Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	var info appInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if info.Status != "" && info.Status != "success" {
+		return "", fmt.Errorf("request failed with status %q", info.Status)
+	}
+
+	app, ok := info.Data[RustAppID]
+	if !ok {
+		return "", fmt.Errorf("app %s not found in response", RustAppID)
+	}
+	buildID := app.Depots.Branches[branch].BuildID
+	if buildID == "" {
+		return "", fmt.Errorf("buildid not found for branch %s", branch)
+	}
+	return buildID, nil
+}
@@ -0,0 +1,189 @@
+package steamcmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maintc/wipe-cli/internal/config"
+)
+
+const appInfoJSON = `{
+	"data": {
+		"258550": {
+			"depots": {
+				"branches": {
+					"public": {"buildid": "18765432", "timeupdated": "1741190400"},
+					"staging": {"buildid": "18799999", "timeupdated": "1741276800"}
+				}
+			}
+		}
+	},
+	"status": "success"
+}`
+
+func TestFetchBuildID(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		branch  string
+		want    string
+		wantErr string
+	}{
+		{name: "public", status: http.StatusOK, body: appInfoJSON, branch: "public", want: "18765432"},
+		{name: "staging", status: http.StatusOK, body: appInfoJSON, branch: "staging", want: "18799999"},
+		{name: "unknown branch", status: http.StatusOK, body: appInfoJSON, branch: "aux01", wantErr: "buildid not found for branch aux01"},
+		{name: "server error", status: http.StatusBadGateway, body: "", branch: "public", wantErr: "bad status"},
+		{name: "invalid json", status: http.StatusOK, body: "<html>", branch: "public", wantErr: "failed to decode"},
+		{name: "failed status", status: http.StatusOK, body: `{"data": {}, "status": "failed"}`, branch: "public", wantErr: `status "failed"`},
+		{name: "missing app", status: http.StatusOK, body: `{"data": {}, "status": "success"}`, branch: "public", wantErr: "app 258550 not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			got, err := fetchBuildID(server.URL, tt.branch)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("fetchBuildID() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fetchBuildID() returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("fetchBuildID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLatestBuildID_Source(t *testing.T) {
+	origSource, origURL := UpdateCheck()
+	origAppInfo := appInfoBuildID
+	defer func() {
+		SetUpdateCheck(origSource, origURL)
+		appInfoBuildID = origAppInfo
+	}()
+
+	var steamcmdCalls []string
+	appInfoBuildID = func(branchParam string) (string, error) {
+		steamcmdCalls = append(steamcmdCalls, branchParam)
+		return "steamcmd-build", nil
+	}
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(appInfoJSON))
+	}))
+	defer healthy.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	tests := []struct {
+		name         string
+		source       string
+		url          string
+		branch       string
+		want         string
+		wantSteamcmd bool
+	}{
+		{name: "steamcmd is the default", source: config.UpdateCheckSteamCMD, url: healthy.URL, branch: "main", want: "steamcmd-build", wantSteamcmd: true},
+		{name: "http main", source: config.UpdateCheckHTTP, url: healthy.URL, branch: "main", want: "18765432"},
+		{name: "http branch", source: config.UpdateCheckHTTP, url: healthy.URL, branch: "staging", want: "18799999"},
+		{name: "http failure falls back", source: config.UpdateCheckHTTP, url: broken.URL, branch: "main", want: "steamcmd-build", wantSteamcmd: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steamcmdCalls = nil
+			SetUpdateCheck(tt.source, tt.url)
+
+			got, err := getLatestBuildID(tt.branch)
+			if err != nil {
+				t.Fatalf("getLatestBuildID() returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getLatestBuildID() = %q, want %q", got, tt.want)
+			}
+			if ran := len(steamcmdCalls) > 0; ran != tt.wantSteamcmd {
+				t.Errorf("steamcmd ran = %v, want %v", ran, tt.wantSteamcmd)
+			}
+		})
+	}
+
+	// When both fail, the steamcmd error is returned
+	appInfoBuildID = func(string) (string, error) { return "", errors.New("steamcmd unavailable") }
+	SetUpdateCheck(config.UpdateCheckHTTP, broken.URL)
+	if _, err := getLatestBuildID("main"); err == nil || !strings.Contains(err.Error(), "steamcmd unavailable") {
+		t.Errorf("getLatestBuildID() error = %v, want the steamcmd error", err)
+	}
+}
+
+func TestPinnedUpdate(t *testing.T) {
+	origSource, origURL := UpdateCheck()
+	origAppInfo := appInfoBuildID
+	defer func() {
+		SetUpdateCheck(origSource, origURL)
+		appInfoBuildID = origAppInfo
+	}()
+	SetUpdateCheck(config.UpdateCheckSteamCMD, "")
+
+	tests := []struct {
+		name       string
+		current    string
+		previous   string
+		latest     string
+		latestErr  error
+		wantUpdate bool
+		wantBuild  string
+		wantErr    bool
+	}{
+		{name: "at the pin", current: "100", previous: "90", latest: "110", wantBuild: "100, pinned"},
+		{name: "previous install is the pin", current: "110", previous: "100", latest: "110", wantUpdate: true, wantBuild: "100"},
+		{name: "latest build is the pin", current: "90", previous: "unknown", latest: "100", wantUpdate: true, wantBuild: "100"},
+		{name: "pin unreachable holds", current: "110", previous: "unknown", latest: "120", wantBuild: "110, pinned to 100"},
+		{name: "latest check fails", current: "110", previous: "unknown", latestErr: errors.New("steamcmd unavailable"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appInfoBuildID = func(string) (string, error) { return tt.latest, tt.latestErr }
+
+			update, build, err := pinnedUpdate("main", tt.current, tt.previous, "100")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pinnedUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if update != tt.wantUpdate || build != tt.wantBuild {
+				t.Errorf("pinnedUpdate() = %v, %q, want %v, %q", update, build, tt.wantUpdate, tt.wantBuild)
+			}
+		})
+	}
+}
+
+func TestSetPinnedBuildIDs(t *testing.T) {
+	defer SetPinnedBuildIDs(nil)
+
+	SetPinnedBuildIDs(map[string]string{"": "100", "staging": "200"})
+	if got := PinnedBuildID("main"); got != "100" {
+		t.Errorf("PinnedBuildID(main) = %q, want 100", got)
+	}
+	if got := PinnedBuildID("staging"); got != "200" {
+		t.Errorf("PinnedBuildID(staging) = %q, want 200", got)
+	}
+
+	SetPinnedBuildIDs(map[string]string{"staging": "200"})
+	if got := PinnedBuildID(""); got != "" {
+		t.Errorf("PinnedBuildID(main) after replacing pins = %q, want empty", got)
+	}
+}
@@ -0,0 +1,79 @@
+package steamcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/maintc/wipe-cli/internal/notify"
+)
+
+// bytesPerGB converts the configured threshold to bytes
+const bytesPerGB = 1 << 30
+
+// DefaultMinFreeDiskGB is the free space required before installing a branch by default
+const DefaultMinFreeDiskGB = 12
+
+// minFreeDiskGB is the free space required on RustInstallBase before installing a branch (0 disables the check)
+var minFreeDiskGB atomic.Int64
+
+func init() {
+	minFreeDiskGB.Store(DefaultMinFreeDiskGB)
+}
+
+// SetMinFreeDiskGB sets the free space required before installing a branch (0 disables the check)
+func SetMinFreeDiskGB(gb int) {
+	minFreeDiskGB.Store(int64(gb))
+}
+
+// MinFreeDiskGB returns the free space required before installing a branch
+func MinFreeDiskGB() int {
+	return int(minFreeDiskGB.Load())
+}
+
+var (
+	// statfs reports filesystem usage; a variable so tests can stub it
+	statfs = syscall.Statfs
+)
+
+// checkFreeSpace returns an error if the filesystem holding path has less than minGB free.
+// If path doesn't exist yet, its nearest existing parent is checked.
+func checkFreeSpace(path string, minGB int) error {
+	if minGB <= 0 {
+		return nil
+	}
+
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+
+	var stat syscall.Statfs_t
+	if err := statfs(path, &stat); err != nil {
+		return fmt.Errorf("failed to check free disk space on %s: %w", path, err)
+	}
+
+	free := stat.Bavail * uint64(stat.Bsize)
+	if free < uint64(minGB)*bytesPerGB {
+		return fmt.Errorf("only %.1f GB free on %s, need at least %d GB to install Rust (see min_free_disk_gb)",
+			float64(free)/bytesPerGB, path, minGB)
+	}
+	return nil
+}
+
+// preflightDiskSpace checks free space before a branch install and reports a shortage to the notifier
+func preflightDiskSpace(branch string, notifier notify.Notifier) error {
+	if err := checkFreeSpace(RustInstallBase, MinFreeDiskGB()); err != nil {
+		notifier.Error("Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%v", branch, err))
+		return err
+	}
+	return nil
+}
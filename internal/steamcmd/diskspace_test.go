@@ -0,0 +1,72 @@
+package steamcmd
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestCheckFreeSpace(t *testing.T) {
+	origStatfs := statfs
+	defer func() { statfs = origStatfs }()
+
+	tests := []struct {
+		name    string
+		freeGB  uint64
+		minGB   int
+		statErr error
+		wantErr string
+	}{
+		{name: "enough space", freeGB: 20, minGB: 12},
+		{name: "exactly the threshold", freeGB: 12, minGB: 12},
+		{name: "not enough space", freeGB: 5, minGB: 12, wantErr: "only 5.0 GB free"},
+		{name: "disabled", freeGB: 0, minGB: 0},
+		{name: "statfs fails", minGB: 12, statErr: errors.New("boom"), wantErr: "failed to check free disk space"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statfs = func(path string, stat *syscall.Statfs_t) error {
+				if tt.statErr != nil {
+					return tt.statErr
+				}
+				stat.Bsize = 4096
+				stat.Bavail = tt.freeGB * bytesPerGB / 4096
+				return nil
+			}
+
+			err := checkFreeSpace(t.TempDir(), tt.minGB)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkFreeSpace() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkFreeSpace() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckFreeSpace_MissingPathUsesParent(t *testing.T) {
+	origStatfs := statfs
+	defer func() { statfs = origStatfs }()
+
+	dir := t.TempDir()
+	var checked string
+	statfs = func(path string, stat *syscall.Statfs_t) error {
+		checked = path
+		stat.Bsize = 4096
+		stat.Bavail = 100 * bytesPerGB / 4096
+		return nil
+	}
+
+	if err := checkFreeSpace(dir+"/rust/main", 12); err != nil {
+		t.Fatalf("checkFreeSpace() error = %v", err)
+	}
+	if checked != dir {
+		t.Errorf("checked path = %s, want %s", checked, dir)
+	}
+}
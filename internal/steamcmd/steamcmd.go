@@ -1,74 +1,142 @@
 package steamcmd
 
 import (
+	"bytes"
+	"context"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
-
-	"github.com/maintc/wipe-cli/internal/discord"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/config"
+	"github.com/maintc/wipe-cli/internal/installer"
+	"github.com/maintc/wipe-cli/internal/logging"
+	"github.com/maintc/wipe-cli/internal/notify"
 )
 
 const (
-	SteamCMDURL     = "https://steamcdn-a.akamaihd.net/client/installer/steamcmd_linux.tar.gz"
-	RustAppID       = "258550"
-	RustInstallBase = "/opt/rust"
-	SteamCMDBase    = "/opt/rust/steamcmd"
+	SteamCMDURL  = "https://steamcdn-a.akamaihd.net/client/installer/steamcmd_linux.tar.gz"
+	RustAppID    = "258550"
+	SteamCMDBase = "/opt/rust/steamcmd"
 )
 
+// RustInstallBase holds each branch's install; a variable so tests can point it at a temporary directory
+var RustInstallBase = "/opt/rust"
+
+// DefaultInstallTimeout bounds each steamcmd install attempt by default
+const DefaultInstallTimeout = 60 * time.Minute
+
+var (
+	// keepPreviousInstall keeps the replaced install as <branch>.prev so it can be rolled back
+	keepPreviousInstall atomic.Bool
+
+	// installTimeout bounds each steamcmd install attempt; a stalled attempt is killed and retried (0 disables)
+	installTimeout atomic.Int64
+)
+
+func init() {
+	installTimeout.Store(int64(DefaultInstallTimeout))
+}
+
+// SetKeepPreviousInstall sets whether installs keep the replaced install as <branch>.prev for rollback
+func SetKeepPreviousInstall(keep bool) {
+	keepPreviousInstall.Store(keep)
+}
+
+// KeepPreviousInstall returns whether installs keep the replaced install for rollback
+func KeepPreviousInstall() bool {
+	return keepPreviousInstall.Load()
+}
+
+// SetInstallTimeout bounds each steamcmd install attempt (0 disables the timeout)
+func SetInstallTimeout(d time.Duration) {
+	installTimeout.Store(int64(d))
+}
+
+// InstallTimeout returns the bound on each steamcmd install attempt
+func InstallTimeout() time.Duration {
+	return time.Duration(installTimeout.Load())
+}
+
 var (
+	// steamcmdScript is the steamcmd launcher; a variable so tests can point it at a mock
+	steamcmdScript = filepath.Join(SteamCMDBase, "steamcmd.sh")
+
 	// installMutex prevents concurrent steamcmd operations
 	installMutex sync.Mutex
-	// installingBranches tracks which branches are currently being installed/updated
-	installingBranches = make(map[string]bool)
-	installingMutex    sync.Mutex
-	// branchLocks provides per-branch RW locks to coordinate installs vs syncs
-	branchLocks = make(map[string]*sync.RWMutex)
-	branchMutex sync.Mutex
+	// branchLocks coordinates installs and rollbacks vs syncs of each branch
+	branchLocks = installer.NewBranchLocks("Rust", func() string { return RustInstallBase })
+	// pinnedBuildIDs maps each branch pinned with rust_build_id to its build
+	pinnedBuildIDs = make(map[string]string)
+	pinnedMutex    sync.Mutex
 )
 
+// SetPinnedBuildIDs holds branches at a Rust build (branch -> build ID), replacing any previous pins.
+// Pinned branches never offer updates past that build.
+func SetPinnedBuildIDs(pins map[string]string) {
+	pinnedMutex.Lock()
+	defer pinnedMutex.Unlock()
+
+	pinnedBuildIDs = make(map[string]string, len(pins))
+	for branch, buildID := range pins {
+		if branch == "" {
+			branch = "main"
+		}
+		pinnedBuildIDs[branch] = buildID
+	}
+}
+
+// PinnedBuildID returns the Rust build a branch is pinned to, or "" if it follows the latest build
+func PinnedBuildID(branch string) string {
+	if branch == "" {
+		branch = "main"
+	}
+	pinnedMutex.Lock()
+	defer pinnedMutex.Unlock()
+	return pinnedBuildIDs[branch]
+}
+
 // EnsureRustBranchInstalled checks if a Rust branch is installed and installs it if not
-func EnsureRustBranchInstalled(branch, webhookURL string) error {
+func EnsureRustBranchInstalled(branch string, notifier notify.Notifier) error {
 	installPath := getRustInstallPath(branch)
 
 	// Check if branch is already installed
 	if isRustInstalled(installPath) {
-		log.Printf("Rust branch '%s' already installed at %s", branch, installPath)
+		logging.Infof("Rust branch '%s' already installed at %s", branch, installPath)
 		return nil
 	}
 
-	log.Printf("Rust branch '%s' not found at %s, installing...", branch, installPath)
-	return InstallRustBranch(branch, webhookURL)
+	logging.Infof("Rust branch '%s' not found at %s, installing...", branch, installPath)
+	if err := preflightDiskSpace(branch, notifier); err != nil {
+		return err
+	}
+	return InstallRustBranch(branch, notifier)
 }
 
-// InstallRustBranch installs a Rust branch using steamcmd
-func InstallRustBranch(branch, webhookURL string) error {
+// InstallRustBranch installs a Rust branch using steamcmd.
+// A branch pinned to the build of its previous install is rolled back to it instead.
+func InstallRustBranch(branch string, notifier notify.Notifier) error {
+	if pinned := PinnedBuildID(branch); pinned != "" && readBuildID(getRustInstallPath(branch)) != pinned &&
+		readBuildID(getPreviousInstallPath(branch)) == pinned {
+		logging.Infof("Rust branch '%s' is pinned to build %s, restoring its previous install", branch, pinned)
+		return RollbackRustBranch(branch, notifier)
+	}
+
 	// Check if this branch is already being installed
-	installingMutex.Lock()
-	if installingBranches[branch] {
-		installingMutex.Unlock()
-		log.Printf("Branch '%s' is already being installed, skipping", branch)
+	if !branchLocks.StartInstall(branch) {
+		logging.Infof("Branch '%s' is already being installed, skipping", branch)
 		return nil
 	}
-	installingBranches[branch] = true
-	installingMutex.Unlock()
-
-	// Ensure we mark installation as complete when done
-	defer func() {
-		installingMutex.Lock()
-		delete(installingBranches, branch)
-		installingMutex.Unlock()
-	}()
+	defer branchLocks.FinishInstall(branch)
 
 	// Acquire WRITE lock for this branch to block syncServer reads during install
-	branchLock := getBranchLock(branch)
-	branchLock.Lock()
-	defer branchLock.Unlock()
+	unlock := AcquireWriteLock(branch)
+	defer unlock()
 
 	// Acquire global install mutex to prevent concurrent steamcmd operations
 	installMutex.Lock()
@@ -76,7 +144,7 @@ func InstallRustBranch(branch, webhookURL string) error {
 
 	installPath := getRustInstallPath(branch)
 
-	log.Printf("Installing Rust branch '%s' to %s", branch, installPath)
+	logging.Infof("Installing Rust branch '%s' to %s", branch, installPath)
 
 	// Read old buildid BEFORE wiping the directory
 	oldBuildID := ""
@@ -88,35 +156,49 @@ func InstallRustBranch(branch, webhookURL string) error {
 	// Create base rust directory
 	if err := os.MkdirAll(RustInstallBase, 0755); err != nil {
 		errMsg := fmt.Sprintf("failed to create rust base directory: %v", err)
-		discord.SendError(webhookURL, "Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%s", branch, errMsg))
+		notifier.Error("Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%s", branch, errMsg))
 		return fmt.Errorf("%s", errMsg)
 	}
 
+	// Make sure the download fits before touching the existing install
+	if err := preflightDiskSpace(branch, notifier); err != nil {
+		return err
+	}
+
+	// Keep the existing install as a rollback snapshot (only one snapshot is kept)
+	if KeepPreviousInstall() && isRustInstalled(installPath) {
+		if err := snapshotInstall(installPath); err != nil {
+			logging.Warnf("Warning: Failed to keep previous install of branch '%s': %v", branch, err)
+		} else {
+			logging.Infof("Kept previous install of branch '%s' at %s", branch, getPreviousInstallPath(branch))
+		}
+	}
+
 	// Remove old branch directory to avoid stale files from previous versions
 	if err := os.RemoveAll(installPath); err != nil {
 		errMsg := fmt.Sprintf("failed to remove old branch directory: %v", err)
-		discord.SendError(webhookURL, "Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%s", branch, errMsg))
+		notifier.Error("Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%s", branch, errMsg))
 		return fmt.Errorf("%s", errMsg)
 	}
 
 	// Create fresh branch install directory
 	if err := os.MkdirAll(installPath, 0755); err != nil {
 		errMsg := fmt.Sprintf("failed to create branch directory: %v", err)
-		discord.SendError(webhookURL, "Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%s", branch, errMsg))
+		notifier.Error("Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%s", branch, errMsg))
 		return fmt.Errorf("%s", errMsg)
 	}
 
 	// Setup steamcmd (shared across all branches)
 	if err := setupSteamCMD(); err != nil {
 		errMsg := fmt.Sprintf("failed to setup steamcmd: %v", err)
-		discord.SendError(webhookURL, "Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%s", branch, errMsg))
+		notifier.Error("Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%s", branch, errMsg))
 		return fmt.Errorf("%s", errMsg)
 	}
 
 	// Install/update the branch
 	if err := updateRustBranch(branch, installPath); err != nil {
 		errMsg := fmt.Sprintf("failed to update Rust branch: %v", err)
-		discord.SendError(webhookURL, "Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%s", branch, errMsg))
+		notifier.Error("Rust Installation Failed", fmt.Sprintf("Failed to install Rust branch **%s**\n\n%s", branch, errMsg))
 		return fmt.Errorf("%s", errMsg)
 	}
 
@@ -127,30 +209,96 @@ func InstallRustBranch(branch, webhookURL string) error {
 	}
 
 	// Send success notification
-	log.Printf("✓ Successfully installed Rust branch '%s'", branch)
+	logging.Infof("✓ Successfully installed Rust branch '%s'", branch)
 	if oldBuildID == "" {
-		discord.SendSuccess(webhookURL, "Rust Installation Complete",
+		notifier.Success("Rust Installation Complete",
 			fmt.Sprintf("Rust branch **%s** installed successfully\n\nBuild ID: **%s**", branch, newBuildID))
 	} else if oldBuildID != newBuildID {
-		discord.SendSuccess(webhookURL, "Rust Update Complete",
+		notifier.Success("Rust Update Complete",
 			fmt.Sprintf("Rust branch **%s** updated\n\nFrom: **%s**\nTo: **%s**", branch, oldBuildID, newBuildID))
 	}
 
 	return nil
 }
 
-// getBranchLock gets or creates an RWMutex for a specific branch
-func getBranchLock(branch string) *sync.RWMutex {
-	branchMutex.Lock()
-	defer branchMutex.Unlock()
+// RollbackRustBranch swaps a branch's install with its <branch>.prev snapshot.
+// The replaced install becomes the new snapshot so the rollback can be undone.
+func RollbackRustBranch(branch string, notifier notify.Notifier) error {
+	if !branchLocks.StartInstall(branch) {
+		return fmt.Errorf("branch '%s' is currently being installed", branch)
+	}
+	defer branchLocks.FinishInstall(branch)
+
+	// Acquire WRITE lock for this branch to block syncServer reads during the swap
+	unlock := AcquireWriteLock(branch)
+	defer unlock()
 
-	if lock, exists := branchLocks[branch]; exists {
-		return lock
+	installPath := getRustInstallPath(branch)
+	prevPath := getPreviousInstallPath(branch)
+
+	if !isRustInstalled(prevPath) {
+		return fmt.Errorf("no previous install found for branch '%s' at %s", branch, prevPath)
 	}
 
-	lock := &sync.RWMutex{}
-	branchLocks[branch] = lock
-	return lock
+	currentBuildID := readBuildID(installPath)
+	previousBuildID := readBuildID(prevPath)
+
+	// Swap via a temporary path so a failure never leaves the branch without an install
+	swapPath := installPath + ".rollback"
+	if err := os.RemoveAll(swapPath); err != nil {
+		return fmt.Errorf("failed to clean up %s: %w", swapPath, err)
+	}
+	if _, err := os.Stat(installPath); err == nil {
+		if err := rename(installPath, swapPath); err != nil {
+			return fmt.Errorf("failed to move current install aside: %w", err)
+		}
+	}
+	if err := rename(prevPath, installPath); err != nil {
+		rename(swapPath, installPath)
+		return fmt.Errorf("failed to restore previous install: %w", err)
+	}
+	if _, err := os.Stat(swapPath); err == nil {
+		if err := rename(swapPath, prevPath); err != nil {
+			logging.Warnf("Warning: Failed to keep replaced install as snapshot: %v", err)
+		}
+	}
+
+	logging.Infof("✓ Rolled back Rust branch '%s' from build %s to %s", branch, currentBuildID, previousBuildID)
+	notifier.Warning("Rust Rollback Complete",
+		fmt.Sprintf("Rust branch **%s** rolled back\n\nFrom: **%s**\nTo: **%s**", branch, currentBuildID, previousBuildID))
+
+	return nil
+}
+
+// snapshotInstall moves an install to its .prev path, replacing any older snapshot
+func snapshotInstall(installPath string) error {
+	prevPath := installPath + ".prev"
+	if err := os.RemoveAll(prevPath); err != nil {
+		return fmt.Errorf("failed to remove old snapshot: %w", err)
+	}
+	return rename(installPath, prevPath)
+}
+
+// rename moves installs into place; a variable so tests can make it fail
+var rename = os.Rename
+
+// InstalledBuildID returns the build ID of a branch's current install, or "unknown"
+func InstalledBuildID(branch string) string {
+	return readBuildID(getRustInstallPath(branch))
+}
+
+// getPreviousInstallPath returns the rollback snapshot path for a branch
+func getPreviousInstallPath(branch string) string {
+	return getRustInstallPath(branch) + ".prev"
+}
+
+// readBuildID returns the tracked build ID of an install, or "unknown"
+func readBuildID(installPath string) string {
+	data, err := os.ReadFile(filepath.Join(installPath, "buildid"))
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(data))
 }
 
 // AcquireReadLock acquires a read lock for a branch (used by syncServer)
@@ -159,13 +307,21 @@ func AcquireReadLock(branch string) func() {
 	if branch == "" {
 		branch = "main"
 	}
-	lock := getBranchLock(branch)
-	lock.RLock()
-	log.Printf("Acquired read lock for branch '%s'", branch)
-	return func() {
-		lock.RUnlock()
-		log.Printf("Released read lock for branch '%s'", branch)
+	return branchLocks.AcquireRead(branch)
+}
+
+// AcquireWriteLock acquires the write lock for a branch (held while installing or rolling back)
+// Returns an unlock function that must be called when done writing
+func AcquireWriteLock(branch string) func() {
+	if branch == "" {
+		branch = "main"
 	}
+	return branchLocks.AcquireWrite(branch)
+}
+
+// IsBranchInstalled reports whether a Rust branch has an install under RustInstallBase
+func IsBranchInstalled(branch string) bool {
+	return isRustInstalled(getRustInstallPath(branch))
 }
 
 // getRustInstallPath returns the installation path for a branch
@@ -176,13 +332,12 @@ func getRustInstallPath(branch string) string {
 // setupSteamCMD downloads and extracts steamcmd (shared installation)
 func setupSteamCMD() error {
 	// Check if steamcmd already exists
-	steamcmdBinary := filepath.Join(SteamCMDBase, "steamcmd.sh")
-	if _, err := os.Stat(steamcmdBinary); err == nil {
-		log.Println("SteamCMD already installed")
+	if _, err := os.Stat(steamcmdScript); err == nil {
+		logging.Infof("SteamCMD already installed")
 		return nil
 	}
 
-	log.Println("Downloading SteamCMD...")
+	logging.Infof("Downloading SteamCMD...")
 
 	// Create steamcmd directory
 	if err := os.MkdirAll(SteamCMDBase, 0755); err != nil {
@@ -191,11 +346,11 @@ func setupSteamCMD() error {
 
 	// Download steamcmd
 	tarPath := filepath.Join(RustInstallBase, "steamcmd_linux.tar.gz")
-	if err := downloadFile(SteamCMDURL, tarPath); err != nil {
+	if err := installer.DownloadFile(SteamCMDURL, tarPath); err != nil {
 		return fmt.Errorf("failed to download steamcmd: %w", err)
 	}
 
-	log.Println("Extracting SteamCMD...")
+	logging.Infof("Extracting SteamCMD...")
 
 	// Extract steamcmd (--no-same-owner ensures files are owned by running user)
 	cmd := exec.Command("tar", "--no-same-owner", "-xzf", tarPath, "-C", SteamCMDBase)
@@ -206,50 +361,45 @@ func setupSteamCMD() error {
 	// Clean up tar file
 	os.Remove(tarPath)
 
-	log.Println("✓ SteamCMD installed")
+	logging.Infof("✓ SteamCMD installed")
 	return nil
 }
 
 // updateRustBranch runs steamcmd to install/update Rust
 func updateRustBranch(branch, installPath string) error {
-	steamcmdBinary := filepath.Join(SteamCMDBase, "steamcmd.sh")
-
 	// Determine branch options
 	branchOpts := getBranchOpts(branch)
 
-	log.Printf("Running steamcmd to install Rust (branch: %s)...", branch)
+	logging.Infof("Running steamcmd to install Rust (branch: %s)...", branch)
 
 	// Run command with retries
 	maxRetries := 3
 	for i := 0; i < maxRetries; i++ {
-		log.Printf("Attempt %d/%d...", i+1, maxRetries)
+		logging.Debugf("Attempt %d/%d...", i+1, maxRetries)
 
-		// Build steamcmd command fresh each attempt (exec.Cmd cannot be reused)
 		// +force_install_dir <path> +login anonymous +app_update 258550 <branch_opts> validate +quit
-		cmd := exec.Command(steamcmdBinary,
+		args := []string{
 			"+force_install_dir", installPath,
 			"+login", "anonymous",
-			"+app_update", RustAppID)
+			"+app_update", RustAppID,
+		}
 
 		// Add branch opts if any
 		if branchOpts != "" {
-			cmd.Args = append(cmd.Args, strings.Fields(branchOpts)...)
+			args = append(args, strings.Fields(branchOpts)...)
 		}
 
-		cmd.Args = append(cmd.Args, "validate", "+quit")
+		args = append(args, "validate", "+quit")
 
-		// Set environment to avoid terminal issues
-		cmd.Env = append(os.Environ(), "TERM=xterm")
-
-		output, err := cmd.CombinedOutput()
+		output, err := runSteamCMD(InstallTimeout(), args...)
 		if err == nil {
-			log.Println("✓ Rust branch update complete")
+			logging.Infof("✓ Rust branch update complete")
 			return trackBuildID(installPath)
 		}
 
-		log.Printf("Attempt %d failed: %v", i+1, err)
+		logging.Warnf("Attempt %d failed: %v", i+1, err)
 		if i < maxRetries-1 {
-			log.Println("Retrying...")
+			logging.Infof("Retrying...")
 		} else {
 			return fmt.Errorf("failed to update branch after %d attempts: %w\nOutput: %s", maxRetries, err, output)
 		}
@@ -258,6 +408,86 @@ func updateRustBranch(branch, installPath string) error {
 	return nil
 }
 
+// killWaitDelay bounds how long a killed steamcmd's output is drained before giving up on it
+const killWaitDelay = 5 * time.Second
+
+// runSteamCMD runs steamcmd with args, logging its output line by line as it arrives, and
+// returns the last lines of output. The attempt is killed, along with the steamcmd process
+// the launcher starts, if it runs longer than timeout (0 for no limit).
+func runSteamCMD(timeout time.Duration, args ...string) (string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	}
+	defer cancel()
+
+	output := &outputLogger{}
+	cmd := exec.CommandContext(ctx, steamcmdScript, args...)
+	// Set environment to avoid terminal issues
+	cmd.Env = append(os.Environ(), "TERM=xterm")
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// Run in its own process group so a timeout also kills the steamcmd binary the script starts
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = killWaitDelay
+
+	err := cmd.Run()
+	output.flush()
+	if ctx.Err() == context.DeadlineExceeded {
+		return output.String(), fmt.Errorf("steamcmd timed out after %s and was killed", timeout)
+	}
+	return output.String(), err
+}
+
+// outputTailLines is how many lines of steamcmd output are kept for error messages
+const outputTailLines = 20
+
+// outputLogger logs each line written to it and keeps the last outputTailLines.
+// steamcmd ends progress updates with carriage returns, so those end a line too.
+type outputLogger struct {
+	partial []byte
+	tail    []string
+}
+
+func (o *outputLogger) Write(p []byte) (int, error) {
+	o.partial = append(o.partial, p...)
+	for {
+		i := bytes.IndexAny(o.partial, "\r\n")
+		if i < 0 {
+			break
+		}
+		o.line(string(o.partial[:i]))
+		o.partial = o.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush logs any final line that didn't end in a newline
+func (o *outputLogger) flush() {
+	o.line(string(o.partial))
+	o.partial = nil
+}
+
+func (o *outputLogger) line(s string) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return
+	}
+	logging.Infof("  steamcmd: %s", s)
+	o.tail = append(o.tail, s)
+	if len(o.tail) > outputTailLines {
+		o.tail = o.tail[1:]
+	}
+}
+
+// String returns the last lines of output
+func (o *outputLogger) String() string {
+	return strings.Join(o.tail, "\n")
+}
+
 // getBranchOpts returns steamcmd branch options based on branch name
 func getBranchOpts(branch string) string {
 	if branch == "" || branch == "main" {
@@ -274,7 +504,7 @@ func trackBuildID(installPath string) error {
 	// Read manifest to get build ID
 	data, err := os.ReadFile(manifestPath)
 	if err != nil {
-		log.Printf("Warning: Could not read manifest file: %v", err)
+		logging.Warnf("Warning: Could not read manifest file: %v", err)
 		return nil // Not critical
 	}
 
@@ -293,15 +523,15 @@ func trackBuildID(installPath string) error {
 	}
 
 	if buildid == "" {
-		log.Println("Warning: Could not extract buildid from manifest")
+		logging.Warnf("Warning: Could not extract buildid from manifest")
 		return nil
 	}
 
 	// Write buildid
 	if err := os.WriteFile(buildidPath, []byte(buildid), 0644); err != nil {
-		log.Printf("Warning: Could not write buildid: %v", err)
+		logging.Warnf("Warning: Could not write buildid: %v", err)
 	} else {
-		log.Printf("Build ID: %s", buildid)
+		logging.Infof("Build ID: %s", buildid)
 	}
 
 	return nil
@@ -316,7 +546,7 @@ func isRustInstalled(path string) bool {
 }
 
 // CheckForUpdates checks if a branch has updates available
-func CheckForUpdates(branch, webhookURL string) (bool, string, error) {
+func CheckForUpdates(branch string, notifier notify.Notifier) (bool, string, error) {
 	installPath := getRustInstallPath(branch)
 
 	// Check if branch is installed
@@ -328,24 +558,28 @@ func CheckForUpdates(branch, webhookURL string) (bool, string, error) {
 	buildidPath := filepath.Join(installPath, "buildid")
 	currentBuildData, err := os.ReadFile(buildidPath)
 	if err != nil {
-		log.Printf("Warning: Could not read current buildid for %s: %v", branch, err)
+		logging.Warnf("Warning: Could not read current buildid for %s: %v", branch, err)
 		return false, "", nil
 	}
 	currentBuildID := strings.TrimSpace(string(currentBuildData))
 
+	if pinned := PinnedBuildID(branch); pinned != "" {
+		return pinnedUpdate(branch, currentBuildID, readBuildID(getPreviousInstallPath(branch)), pinned)
+	}
+
 	// Get latest build ID from Steam
 	latestBuildID, err := getLatestBuildID(branch)
 	if err != nil {
-		log.Printf("Error checking for updates for branch %s: %v", branch, err)
+		logging.Errorf("Error checking for updates for branch %s: %v", branch, err)
 		return false, "", err
 	}
 
 	// Compare build IDs
 	if currentBuildID != latestBuildID {
-		log.Printf("Update available for branch %s: %s -> %s", branch, currentBuildID, latestBuildID)
+		logging.Infof("Update available for branch %s: %s -> %s", branch, currentBuildID, latestBuildID)
 
 		// Send notification
-		discord.SendInfo(webhookURL, "Rust Update Available",
+		notifier.Info("Rust Update Available",
 			fmt.Sprintf("Rust branch **%s** has an update available\n\nCurrent: **%s**\nAvailable: **%s**",
 				branch, currentBuildID, latestBuildID))
 
@@ -355,18 +589,58 @@ func CheckForUpdates(branch, webhookURL string) (bool, string, error) {
 	return false, currentBuildID, nil
 }
 
-// getLatestBuildID queries Steam for the latest build ID of a branch
-func getLatestBuildID(branch string) (string, error) {
-	steamcmdBinary := filepath.Join(SteamCMDBase, "steamcmd.sh")
+// pinnedUpdate reports whether a branch held at the pinned build needs installing.
+// steamcmd can only install a branch's latest build, so the pin is reached by restoring the
+// previous install when it has that build, or by installing the latest build when it is the
+// pinned one. Otherwise the branch stays on its current build.
+func pinnedUpdate(branch, currentBuildID, previousBuildID, pinned string) (bool, string, error) {
+	if currentBuildID == pinned {
+		return false, pinned + ", pinned", nil
+	}
+	if previousBuildID == pinned {
+		logging.Infof("Rust branch %s is pinned to build %s, which is its previous install", branch, pinned)
+		return true, pinned, nil
+	}
+
+	latestBuildID, err := getLatestBuildID(branch)
+	if err != nil {
+		logging.Errorf("Error checking for updates for branch %s: %v", branch, err)
+		return false, "", err
+	}
+	if latestBuildID == pinned {
+		logging.Infof("Rust branch %s is pinned to build %s, which is its latest build", branch, pinned)
+		return true, pinned, nil
+	}
+
+	logging.Warnf("Warning: Rust branch %s is pinned to build %s, which is neither its latest build (%s) nor its previous install; holding at %s",
+		branch, pinned, latestBuildID, currentBuildID)
+	return false, fmt.Sprintf("%s, pinned to %s", currentBuildID, pinned), nil
+}
 
+// getLatestBuildID returns the latest build ID of a branch from the UpdateCheck source,
+// falling back to steamcmd when the web API fails
+func getLatestBuildID(branch string) (string, error) {
 	// Determine branch parameter for steamcmd
 	branchParam := "public"
 	if branch != "" && branch != "main" {
 		branchParam = branch
 	}
 
+	if source, url := UpdateCheck(); source == config.UpdateCheckHTTP {
+		buildID, err := fetchBuildID(url, branchParam)
+		if err == nil {
+			return buildID, nil
+		}
+		logging.Warnf("Warning: HTTP build ID check failed for branch %s, falling back to steamcmd: %v", branch, err)
+	}
+	return appInfoBuildID(branchParam)
+}
+
+// appInfoBuildID asks steamcmd for the build ID of branchParam; a variable so tests can stub it
+var appInfoBuildID = func(branchParam string) (string, error) {
+
 	// Run: steamcmd +login anonymous +app_info_update 1 +app_info_print 258550 +quit
-	cmd := exec.Command(steamcmdBinary,
+	cmd := exec.Command(steamcmdScript,
 		"+login", "anonymous",
 		"+app_info_update", "1",
 		"+app_info_print", RustAppID,
@@ -386,85 +660,87 @@ func getLatestBuildID(branch string) (string, error) {
 	return buildID, nil
 }
 
-// parseBuildIDFromAppInfo extracts the build ID for a specific branch from app_info_print output
+// parseBuildIDFromAppInfo extracts the build ID for a specific branch from app_info_print output.
+// The output is nested KeyValues like:
+//
+//	"258550"
+//	{
+//		"depots"
+//		{
+//			"branches"
+//			{
+//				"public"
+//				{
+//					"buildid"		"12345678"
+//				}
+//			}
+//		}
+//	}
+//
+// Only a buildid directly inside the branch's block under "branches" matches, so the same
+// names appearing elsewhere (another section, a nested block, a value) are ignored.
 func parseBuildIDFromAppInfo(output, branch string) (string, error) {
-	lines := strings.Split(output, "\n")
-
-	// Look for the branch section and extract buildid
-	// Format is nested like:
-	// "branches"
-	// {
-	//   "public"
-	//   {
-	//     "buildid"    "12345678"
-	//   }
-	// }
-
-	inBranches := false
-	inTargetBranch := false
-
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		// Find branches section
-		if strings.Contains(trimmed, `"branches"`) {
-			inBranches = true
-			continue
-		}
-
-		// Find our specific branch
-		if inBranches && strings.Contains(trimmed, fmt.Sprintf(`"%s"`, branch)) {
-			inTargetBranch = true
-			continue
-		}
-
-		// Extract buildid from the branch section
-		if inTargetBranch && strings.Contains(trimmed, `"buildid"`) {
-			// Parse: "buildid"    "12345678"
-			parts := strings.Fields(trimmed)
-			if len(parts) >= 2 {
-				buildID := strings.Trim(parts[1], `"`)
-				return buildID, nil
+	// Skip steamcmd's own log lines before the app's KeyValues
+	if i := strings.Index(output, fmt.Sprintf("\"%s\"", RustAppID)); i >= 0 {
+		output = output[i:]
+	}
+
+	tokens := keyValueTokens(output)
+	var path []string
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok == keyValueClose:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
 			}
-		}
-
-		// Exit branch section when we hit a closing brace at the same level
-		if inTargetBranch && trimmed == "}" {
-			// Check if next non-empty line is also a brace (end of branches section)
-			for j := i + 1; j < len(lines); j++ {
-				nextTrimmed := strings.TrimSpace(lines[j])
-				if nextTrimmed != "" {
-					if nextTrimmed == "}" {
-						inBranches = false
-					}
-					break
-				}
+		case tok == keyValueOpen:
+			// A block without a key; track it so its closing brace stays balanced
+			path = append(path, "")
+		case i+1 < len(tokens) && tokens[i+1] == keyValueOpen:
+			// "key" { ... }
+			path = append(path, tok)
+			i++
+		case i+1 < len(tokens) && tokens[i+1] != keyValueClose:
+			// "key" "value"
+			value := tokens[i+1]
+			i++
+			n := len(path)
+			if n >= 2 && strings.EqualFold(path[n-2], "branches") && path[n-1] == branch &&
+				strings.EqualFold(tok, "buildid") && value != "" {
+				return value, nil
 			}
-			inTargetBranch = false
 		}
 	}
 
 	return "", fmt.Errorf("buildid not found for branch %s", branch)
 }
 
-// downloadFile downloads a file from a URL
-func downloadFile(url, filepath string) error {
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
+// Brace tokens returned by keyValueTokens; quoted strings are returned without their quotes
+const (
+	keyValueOpen  = "\x00{"
+	keyValueClose = "\x00}"
+)
 
-	out, err := os.Create(filepath)
-	if err != nil {
-		return err
+// keyValueTokens splits KeyValues text into quoted strings and braces, ignoring anything else
+func keyValueTokens(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			tokens = append(tokens, keyValueOpen)
+		case '}':
+			tokens = append(tokens, keyValueClose)
+		case '"':
+			var text strings.Builder
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				text.WriteByte(s[i])
+			}
+			tokens = append(tokens, text.String())
+		}
 	}
-	defer out.Close()
-
-	_, err = io.Copy(out, resp.Body)
-	return err
+	return tokens
 }
@@ -0,0 +1,374 @@
+package steamcmd
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maintc/wipe-cli/internal/notify"
+)
+
+// appInfoOutput is trimmed app_info_print output for Rust with several branches.
+// Branch names also appear under depot manifests and launch configs, before "branches".
+const appInfoOutput = `Redirecting stderr to '/root/Steam/logs/stderr.txt'
+[  0%] Checking for available updates...
+[----] Verifying installation...
+Steam Console Client (c) Valve Corporation - version 1741046310
+-- type 'quit' to exit --
+Loading Steam API...OK
+Connecting anonymously to Steam Public...OK
+Waiting for client config...OK
+Waiting for user info...OK
+AppID : 258550, change number : 27654321/0, last change : Wed Mar  5 18:00:00 2026
+"258550"
+{
+	"common"
+	{
+		"name"		"Rust Dedicated Server"
+		"type"		"Tool"
+		"oslist"		"windows,linux"
+	}
+	"config"
+	{
+		"launch"
+		{
+			"0"
+			{
+				"executable"		"RustDedicated"
+				"config"
+				{
+					"oslist"		"linux"
+					"betakey"		"staging"
+				}
+			}
+		}
+	}
+	"depots"
+	{
+		"258551"
+		{
+			"config"
+			{
+				"oslist"		"linux"
+			}
+			"manifests"
+			{
+				"public"
+				{
+					"gid"		"4416584321145680003"
+					"size"		"9861243987"
+					"download"		"3410918096"
+				}
+				"staging"
+				{
+					"gid"		"7285061219467829107"
+					"size"		"9960471264"
+					"download"		"3481520336"
+				}
+			}
+		}
+		"branches"
+		{
+			"public"
+			{
+				"buildid"		"18765432"
+				"timeupdated"		"1741190400"
+			}
+			"staging"
+			{
+				"buildid"		"18799999"
+				"description"		"Staging branch \"next\""
+				"timeupdated"		"1741276800"
+			}
+			"aux01"
+			{
+				"extra"
+				{
+					"buildid"		"11111111"
+				}
+				"buildid"		"18800123"
+				"description"		"public"
+				"pwdrequired"		"1"
+				"timeupdated"		"1741363200"
+			}
+			"nobuild"
+			{
+				"timeupdated"		"1741363200"
+			}
+		}
+	}
+}
+`
+
+func TestParseBuildIDFromAppInfo(t *testing.T) {
+	tests := []struct {
+		branch  string
+		want    string
+		wantErr bool
+	}{
+		{branch: "public", want: "18765432"},
+		{branch: "staging", want: "18799999"},
+		{branch: "aux01", want: "18800123"},
+		{branch: "nobuild", wantErr: true},
+		{branch: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			got, err := parseBuildIDFromAppInfo(appInfoOutput, tt.branch)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseBuildIDFromAppInfo(%q) = %q, want error", tt.branch, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBuildIDFromAppInfo(%q) returned error: %v", tt.branch, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseBuildIDFromAppInfo(%q) = %q, want %q", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBuildIDFromAppInfo_BranchOutsideBranches(t *testing.T) {
+	// A buildid under a "public" block that isn't in "branches" doesn't count
+	output := `"258550"
+{
+	"depots"
+	{
+		"public"
+		{
+			"buildid"		"1"
+		}
+		"branches"
+		{
+			"staging"
+			{
+				"buildid"		"2"
+			}
+		}
+	}
+}`
+	if got, err := parseBuildIDFromAppInfo(output, "public"); err == nil {
+		t.Errorf("parseBuildIDFromAppInfo(public) = %q, want error", got)
+	}
+	if got, _ := parseBuildIDFromAppInfo(output, "staging"); got != "2" {
+		t.Errorf("parseBuildIDFromAppInfo(staging) = %q, want %q", got, "2")
+	}
+}
+
+// mockSteamCMD points steamcmdScript at a script with body, returning a file each run appends a line to
+func mockSteamCMD(t *testing.T, body string) string {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	runsFile := filepath.Join(tmpDir, "runs")
+	script := filepath.Join(tmpDir, "steamcmd.sh")
+	content := "#!/bin/bash\necho run >> " + runsFile + "\n" + body
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatalf("Failed to create mock steamcmd: %v", err)
+	}
+
+	origScript := steamcmdScript
+	steamcmdScript = script
+	t.Cleanup(func() { steamcmdScript = origScript })
+	return runsFile
+}
+
+func TestUpdateRustBranch_StreamsOutput(t *testing.T) {
+	mockSteamCMD(t, "echo 'Update state (0x61) downloading, progress: 42.00'\nprintf 'Success! App fully installed.'\n")
+
+	var buf strings.Builder
+	origOutput := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(origOutput)
+
+	if err := updateRustBranch("main", t.TempDir()); err != nil {
+		t.Fatalf("updateRustBranch() returned error: %v", err)
+	}
+
+	for _, want := range []string{"steamcmd: Update state (0x61) downloading, progress: 42.00", "steamcmd: Success! App fully installed."} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestUpdateRustBranch_AttemptTimeout(t *testing.T) {
+	runsFile := mockSteamCMD(t, "echo 'Update state (0x61) downloading, progress: 0.01'\nsleep 30\n")
+
+	origTimeout := InstallTimeout()
+	SetInstallTimeout(200 * time.Millisecond)
+	defer SetInstallTimeout(origTimeout)
+
+	start := time.Now()
+	err := updateRustBranch("main", t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("updateRustBranch() error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("updateRustBranch() took %s, want each attempt killed at the timeout", elapsed)
+	}
+
+	// Each timed-out attempt counts as a failure and is retried
+	data, readErr := os.ReadFile(runsFile)
+	if readErr != nil {
+		t.Fatalf("Failed to read runs file: %v", readErr)
+	}
+	if runs := strings.Count(string(data), "run"); runs != 3 {
+		t.Errorf("steamcmd ran %d times, want 3", runs)
+	}
+	if !strings.Contains(err.Error(), "progress: 0.01") {
+		t.Errorf("error = %v, want the last output", err)
+	}
+}
+
+// useInstallBase points RustInstallBase at a temporary directory for the test
+func useInstallBase(t *testing.T) string {
+	t.Helper()
+
+	base := t.TempDir()
+	origBase := RustInstallBase
+	RustInstallBase = base
+	t.Cleanup(func() { RustInstallBase = origBase })
+	return base
+}
+
+// fakeInstall creates a Rust install at path with the given build ID
+func fakeInstall(t *testing.T, path, buildID string) {
+	t.Helper()
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatalf("Failed to create install: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "RustDedicated"), nil, 0755); err != nil {
+		t.Fatalf("Failed to create RustDedicated: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "buildid"), []byte(buildID+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write buildid: %v", err)
+	}
+}
+
+func TestRollbackRustBranch(t *testing.T) {
+	base := useInstallBase(t)
+	fakeInstall(t, filepath.Join(base, "main"), "222")
+	fakeInstall(t, filepath.Join(base, "main.prev"), "111")
+
+	if err := RollbackRustBranch("main", notify.Discard); err != nil {
+		t.Fatalf("RollbackRustBranch() returned error: %v", err)
+	}
+	if got, prev := InstalledBuildID("main"), readBuildID(getPreviousInstallPath("main")); got != "111" || prev != "222" {
+		t.Errorf("after rollback: install = %s, snapshot = %s, want 111 and 222", got, prev)
+	}
+
+	// Rolling back again undoes the rollback
+	if err := RollbackRustBranch("main", notify.Discard); err != nil {
+		t.Fatalf("RollbackRustBranch() undo returned error: %v", err)
+	}
+	if got, prev := InstalledBuildID("main"), readBuildID(getPreviousInstallPath("main")); got != "222" || prev != "111" {
+		t.Errorf("after undo: install = %s, snapshot = %s, want 222 and 111", got, prev)
+	}
+	if _, err := os.Stat(filepath.Join(base, "main.rollback")); !os.IsNotExist(err) {
+		t.Error("swap directory should not be left behind")
+	}
+}
+
+func TestRollbackRustBranch_MissingSnapshot(t *testing.T) {
+	base := useInstallBase(t)
+	fakeInstall(t, filepath.Join(base, "main"), "222")
+
+	err := RollbackRustBranch("main", notify.Discard)
+	if err == nil || !strings.Contains(err.Error(), "no previous install") {
+		t.Errorf("RollbackRustBranch() error = %v, want no previous install", err)
+	}
+	if got := InstalledBuildID("main"); got != "222" {
+		t.Errorf("install = %s, want 222 untouched", got)
+	}
+}
+
+func TestRollbackRustBranch_FailedRestoreKeepsInstall(t *testing.T) {
+	base := useInstallBase(t)
+	installPath := filepath.Join(base, "main")
+	fakeInstall(t, installPath, "222")
+	fakeInstall(t, installPath+".prev", "111")
+
+	origRename := rename
+	t.Cleanup(func() { rename = origRename })
+	rename = func(oldPath, newPath string) error {
+		if oldPath == installPath+".prev" {
+			return errors.New("disk error")
+		}
+		return origRename(oldPath, newPath)
+	}
+
+	err := RollbackRustBranch("main", notify.Discard)
+	if err == nil || !strings.Contains(err.Error(), "failed to restore previous install") {
+		t.Fatalf("RollbackRustBranch() error = %v, want failed restore", err)
+	}
+	if got, prev := InstalledBuildID("main"), readBuildID(installPath+".prev"); got != "222" || prev != "111" {
+		t.Errorf("after failed rollback: install = %s, snapshot = %s, want 222 and 111", got, prev)
+	}
+}
+
+func TestSnapshotInstall_ReplacesOlderSnapshot(t *testing.T) {
+	base := useInstallBase(t)
+	installPath := filepath.Join(base, "main")
+	fakeInstall(t, installPath, "222")
+	fakeInstall(t, installPath+".prev", "111")
+
+	if err := snapshotInstall(installPath); err != nil {
+		t.Fatalf("snapshotInstall() returned error: %v", err)
+	}
+	if _, err := os.Stat(installPath); !os.IsNotExist(err) {
+		t.Error("install should have moved to the snapshot")
+	}
+	if got := readBuildID(installPath + ".prev"); got != "222" {
+		t.Errorf("snapshot build = %s, want 222", got)
+	}
+}
+
+func TestInstallRustBranch_KeepPreviousInstall(t *testing.T) {
+	// The mock installs build 333 into +force_install_dir
+	mockSteamCMD(t, `mkdir -p "$2/steamapps" && touch "$2/RustDedicated"
+printf '"AppState"\n{\n\t"buildid"\t\t"333"\n}\n' > "$2/steamapps/appmanifest_258550.acf"
+`)
+	origKeep, origMinFree := KeepPreviousInstall(), MinFreeDiskGB()
+	t.Cleanup(func() {
+		SetKeepPreviousInstall(origKeep)
+		SetMinFreeDiskGB(origMinFree)
+	})
+	SetMinFreeDiskGB(0)
+
+	tests := []struct {
+		name     string
+		keep     bool
+		wantPrev string
+	}{
+		{name: "kept", keep: true, wantPrev: "222"},
+		{name: "not kept", keep: false, wantPrev: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := useInstallBase(t)
+			fakeInstall(t, filepath.Join(base, "main"), "222")
+			SetKeepPreviousInstall(tt.keep)
+
+			if err := InstallRustBranch("main", notify.Discard); err != nil {
+				t.Fatalf("InstallRustBranch() returned error: %v", err)
+			}
+			if got := InstalledBuildID("main"); got != "333" {
+				t.Errorf("install build = %s, want 333", got)
+			}
+			if got := readBuildID(getPreviousInstallPath("main")); got != tt.wantPrev {
+				t.Errorf("snapshot build = %s, want %s", got, tt.wantPrev)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+// Package systemd embeds the wiped@.service unit template, rendered by 'wipe install-service'
+// (and so by 'make install').
+package systemd
+
+import _ "embed"
+
+// UnitTemplate is the text/template source of the wiped@.service unit
+//
+//go:embed wiped.service
+var UnitTemplate string
@@ -17,6 +17,10 @@ type CalendarEvent struct {
 	ID        string
 	Summary   string
 	StartTime time.Time
+	// RRule is an optional recurrence rule (e.g. "FREQ=WEEKLY;BYDAY=TH")
+	RRule string
+	// TZID is an optional IANA timezone; when set DTSTART is emitted as local time with a TZID parameter
+	TZID string
 }
 
 // CalendarServer is a test HTTP server that serves ICS calendar files
@@ -42,7 +46,7 @@ func NewCalendarServer(t *testing.T) *CalendarServer {
 	mux.HandleFunc("/", cs.handleCalendar)
 
 	// Endpoint to add events (for test control)
-	// POST /add-event?server=X&id=Y&summary=Z&start=W
+	// POST /add-event?server=X&id=Y&summary=Z&start=W[&rrule=R][&tzid=T]
 	mux.HandleFunc("/add-event", cs.handleAddEvent)
 
 	// Endpoint to remove events (for test control)
@@ -115,6 +119,28 @@ func (cs *CalendarServer) Close() {
 
 // AddEventForServer adds an event to a specific server's calendar
 func (cs *CalendarServer) AddEventForServer(serverName, id, summary string, startTime time.Time) {
+	cs.AddCalendarEventForServer(serverName, CalendarEvent{
+		ID:        id,
+		Summary:   summary,
+		StartTime: startTime,
+	})
+}
+
+// AddRecurringEventForServer adds a recurring event with an optional TZID to a specific server's calendar
+func (cs *CalendarServer) AddRecurringEventForServer(serverName, id, summary string, startTime time.Time, rrule, tzid string) {
+	cs.AddCalendarEventForServer(serverName, CalendarEvent{
+		ID:        id,
+		Summary:   summary,
+		StartTime: startTime,
+		RRule:     rrule,
+		TZID:      tzid,
+	})
+}
+
+// AddCalendarEventForServer adds a fully specified event to a specific server's calendar
+func (cs *CalendarServer) AddCalendarEventForServer(serverName string, event CalendarEvent) {
+	id, summary, startTime := event.ID, event.Summary, event.StartTime
+
 	// If this is a remote server, use HTTP endpoint
 	if cs.events == nil {
 		reqURL := fmt.Sprintf("%s/add-event?server=%s&id=%s&summary=%s&start=%s",
@@ -123,6 +149,12 @@ func (cs *CalendarServer) AddEventForServer(serverName, id, summary string, star
 			url.QueryEscape(id),
 			url.QueryEscape(summary),
 			url.QueryEscape(startTime.Format(time.RFC3339)))
+		if event.RRule != "" {
+			reqURL += "&rrule=" + url.QueryEscape(event.RRule)
+		}
+		if event.TZID != "" {
+			reqURL += "&tzid=" + url.QueryEscape(event.TZID)
+		}
 		resp, err := http.Post(reqURL, "", nil)
 		if err != nil {
 			cs.t.Fatalf("Failed to add event to remote server: %v", err)
@@ -143,11 +175,7 @@ func (cs *CalendarServer) AddEventForServer(serverName, id, summary string, star
 		cs.events[serverName] = make(map[string]CalendarEvent)
 	}
 
-	cs.events[serverName][id] = CalendarEvent{
-		ID:        id,
-		Summary:   summary,
-		StartTime: startTime,
-	}
+	cs.events[serverName][id] = event
 
 	cs.t.Logf("Event added for %s: %s - %s at %s", serverName, id, summary, startTime.Format(time.RFC3339))
 }
@@ -270,6 +298,8 @@ func (cs *CalendarServer) handleAddEvent(w http.ResponseWriter, r *http.Request)
 	eventID := r.URL.Query().Get("id")
 	summary := r.URL.Query().Get("summary")
 	startTime := r.URL.Query().Get("start")
+	rrule := r.URL.Query().Get("rrule")
+	tzid := r.URL.Query().Get("tzid")
 
 	if serverName == "" || eventID == "" || summary == "" || startTime == "" {
 		http.Error(w, "Missing parameters (server, id, summary, start required)", http.StatusBadRequest)
@@ -291,6 +321,13 @@ func (cs *CalendarServer) handleAddEvent(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	if tzid != "" {
+		if _, err := time.LoadLocation(tzid); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid tzid: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
 	cs.mu.Lock()
 	if cs.events[serverName] == nil {
 		cs.events[serverName] = make(map[string]CalendarEvent)
@@ -299,6 +336,8 @@ func (cs *CalendarServer) handleAddEvent(w http.ResponseWriter, r *http.Request)
 		ID:        eventID,
 		Summary:   summary,
 		StartTime: parsedTime,
+		RRule:     rrule,
+		TZID:      tzid,
 	}
 	cs.mu.Unlock()
 
@@ -378,8 +417,8 @@ func (cs *CalendarServer) handleListEvents(w http.ResponseWriter, r *http.Reques
 				fmt.Fprint(w, ",\n")
 			}
 			first = false
-			fmt.Fprintf(w, "    {\n      \"id\": %q,\n      \"summary\": %q,\n      \"start_time\": %q\n    }",
-				event.ID, event.Summary, event.StartTime.Format(time.RFC3339))
+			fmt.Fprintf(w, "    {\n      \"id\": %q,\n      \"summary\": %q,\n      \"start_time\": %q,\n      \"rrule\": %q,\n      \"tzid\": %q\n    }",
+				event.ID, event.Summary, event.StartTime.Format(time.RFC3339), event.RRule, event.TZID)
 		}
 
 		fmt.Fprint(w, "\n  ]\n}\n")
@@ -407,8 +446,8 @@ func (cs *CalendarServer) handleListEvents(w http.ResponseWriter, r *http.Reques
 					fmt.Fprint(w, ",\n")
 				}
 				firstEvent = false
-				fmt.Fprintf(w, "        {\n          \"id\": %q,\n          \"summary\": %q,\n          \"start_time\": %q\n        }",
-					event.ID, event.Summary, event.StartTime.Format(time.RFC3339))
+				fmt.Fprintf(w, "        {\n          \"id\": %q,\n          \"summary\": %q,\n          \"start_time\": %q,\n          \"rrule\": %q,\n          \"tzid\": %q\n        }",
+					event.ID, event.Summary, event.StartTime.Format(time.RFC3339), event.RRule, event.TZID)
 			}
 
 			fmt.Fprint(w, "\n      ]\n    }")
@@ -430,14 +469,22 @@ X-WR-TIMEZONE:UTC
 `
 
 	for _, event := range events {
-		startTime := event.StartTime.UTC().Format("20060102T150405Z")
-		ics += fmt.Sprintf(`BEGIN:VEVENT
-UID:%s
-SUMMARY:%s
-DTSTART:%s
-DTEND:%s
-END:VEVENT
-`, event.ID, event.Summary, startTime, startTime)
+		// UTC by default; TZID'd events use local wall-clock time like Google Calendar exports
+		dtstart := "DTSTART:" + event.StartTime.UTC().Format("20060102T150405Z")
+		dtend := "DTEND:" + event.StartTime.UTC().Format("20060102T150405Z")
+		if event.TZID != "" {
+			if loc, err := time.LoadLocation(event.TZID); err == nil {
+				local := event.StartTime.In(loc).Format("20060102T150405")
+				dtstart = fmt.Sprintf("DTSTART;TZID=%s:%s", event.TZID, local)
+				dtend = fmt.Sprintf("DTEND;TZID=%s:%s", event.TZID, local)
+			}
+		}
+
+		ics += fmt.Sprintf("BEGIN:VEVENT\nUID:%s\nSUMMARY:%s\n%s\n%s\n", event.ID, event.Summary, dtstart, dtend)
+		if event.RRule != "" {
+			ics += fmt.Sprintf("RRULE:%s\n", event.RRule)
+		}
+		ics += "END:VEVENT\n"
 	}
 
 	ics += "END:VCALENDAR\n"
@@ -7,6 +7,8 @@ import (
 	"syscall"
 	"testing"
 	"time"
+
+	"github.com/maintc/wipe-cli/internal/calendar"
 )
 
 // TestCalendarServer_Standalone runs a calendar server that stays up for manual testing.
@@ -47,7 +49,7 @@ func TestCalendarServer_Standalone(t *testing.T) {
 	log.Printf("  GET  /{server}/basic.ics               - Get calendar for server")
 	log.Printf("  GET  /list-events?server=X             - List events for server")
 	log.Printf("  GET  /list-events                      - List all events")
-	log.Printf("  POST /add-event?server=X&id=Y&summary=Z&start=W")
+	log.Printf("  POST /add-event?server=X&id=Y&summary=Z&start=W[&rrule=R][&tzid=T]")
 	log.Printf("  POST /remove-event?server=X&id=Y")
 	log.Printf("  POST /clear-events?server=X            - Clear events for server")
 	log.Printf("  POST /clear-events                     - Clear all events")
@@ -107,3 +109,40 @@ func TestCalendarServer_PerServerAPI(t *testing.T) {
 
 	t.Log("Per-server API methods work correctly")
 }
+
+// TestCalendarServer_RecurringAndTZID verifies RRULE and TZID events round-trip through the calendar parser
+func TestCalendarServer_RecurringAndTZID(t *testing.T) {
+	cs := NewCalendarServer(t)
+	defer cs.Close()
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	// Daily restart that started two days ago; only the next occurrence falls in the window
+	start := time.Now().In(loc).Add(-48 * time.Hour).Add(1 * time.Hour).Truncate(time.Minute)
+	cs.AddRecurringEventForServer("us-weekly", "daily1", "restart", start, "FREQ=DAILY", "America/New_York")
+
+	cal, err := calendar.FetchCalendar(cs.GetServerURL("us-weekly"))
+	if err != nil {
+		t.Fatalf("FetchCalendar() error = %v", err)
+	}
+
+	events, err := calendar.GetUpcomingEvents(cal, 24)
+	if err != nil {
+		t.Fatalf("GetUpcomingEvents() error = %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("GetUpcomingEvents() returned %d events, want 1", len(events))
+	}
+
+	want := start.AddDate(0, 0, 2)
+	if !events[0].StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want %v", events[0].StartTime, want)
+	}
+	if events[0].Type != calendar.EventTypeRestart {
+		t.Errorf("Type = %v, want %v", events[0].Type, calendar.EventTypeRestart)
+	}
+}
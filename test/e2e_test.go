@@ -15,6 +15,7 @@ import (
 	"github.com/maintc/wipe-cli/internal/carbon"
 	"github.com/maintc/wipe-cli/internal/config"
 	"github.com/maintc/wipe-cli/internal/daemon"
+	"github.com/maintc/wipe-cli/internal/notify"
 	"github.com/maintc/wipe-cli/internal/steamcmd"
 	"github.com/spf13/viper"
 )
@@ -151,7 +152,7 @@ func TestE2E_FullIntegration(t *testing.T) {
 		t.Logf("Triggering Rust update check (this will take >1 minute and overlap with event execution)...")
 		go func() {
 			// Trigger update in background - this simulates the daemon's checkForUpdates
-			if err := steamcmd.InstallRustBranch("main", ""); err != nil {
+			if err := steamcmd.InstallRustBranch("main", notify.Discard); err != nil {
 				t.Logf("Warning: Rust install failed during simulation: %v", err)
 			} else {
 				t.Logf("✓ Simulated Rust update completed")
@@ -238,7 +239,7 @@ func ensureRustInstalled(t *testing.T) {
 
 	t.Log("Rust not found, installing... (this may take 5-10 minutes)")
 
-	if err := steamcmd.EnsureRustBranchInstalled("main", ""); err != nil {
+	if err := steamcmd.EnsureRustBranchInstalled("main", notify.Discard); err != nil {
 		t.Fatalf("Failed to install Rust: %v", err)
 	}
 
@@ -255,7 +256,7 @@ func ensureCarbonInstalled(t *testing.T) {
 
 	t.Log("Carbon not found, installing...")
 
-	if err := carbon.EnsureCarbonInstalled("main", ""); err != nil {
+	if err := carbon.EnsureCarbonInstalled("main", notify.Discard); err != nil {
 		t.Fatalf("Failed to install Carbon: %v", err)
 	}
 